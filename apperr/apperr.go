@@ -0,0 +1,165 @@
+package apperr
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Kind groups errors by how the client should react to them, every kind maps
+// to exactly one http status.
+type Kind uint8
+
+const (
+	KindInternal Kind = iota
+	KindInvalid
+	KindNotFound
+	KindConflict
+	KindUnauthorized
+	KindForbidden
+	KindTooManyRequests
+	KindUnavailable
+	KindUnprocessable
+)
+
+var kindStatus = map[Kind]int{
+	KindInternal:        http.StatusInternalServerError,
+	KindInvalid:         http.StatusBadRequest,
+	KindNotFound:        http.StatusNotFound,
+	KindConflict:        http.StatusConflict,
+	KindUnauthorized:    http.StatusUnauthorized,
+	KindForbidden:       http.StatusForbidden,
+	KindTooManyRequests: http.StatusTooManyRequests,
+	KindUnavailable:     http.StatusServiceUnavailable,
+	KindUnprocessable:   http.StatusUnprocessableEntity,
+}
+
+func (k Kind) Status() int {
+	if status, ok := kindStatus[k]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// Stable machine readable codes returned in errors.code, clients may switch on
+// them so existing values must never change meaning.
+const (
+	CodeInternal              = "internal_error"
+	CodeTimeout               = "timeout"
+	CodeCanceled              = "request_canceled"
+	CodeUnavailable           = "service_unavailable"
+	CodeNotFound              = "not_found"
+	CodeNoDataAffected        = "no_data_affected"
+	CodeReferenceNotFound     = "reference_not_found"
+	CodeValidation            = "validation_failed"
+	CodeInvalidCursor         = "invalid_cursor"
+	CodeInvalidDate           = "invalid_date"
+	CodeLoanLimitReached      = "loan_limit_reached"
+	CodeOutOfStock            = "out_of_stock"
+	CodeAlreadyBorrowed       = "already_borrowed"
+	CodeNISRegistered         = "nis_registered"
+	CodeEmailUsed             = "email_used"
+	CodeStudentInactive       = "student_inactive"
+	CodeNotCleared            = "not_cleared"
+	CodeNotReturned           = "not_returned"
+	CodeAlreadySuggested      = "already_suggested"
+	CodeAlreadyInCatalog      = "already_in_catalog"
+	CodeSuggestionClosed      = "suggestion_closed"
+	CodeInvalidTransition     = "invalid_transition"
+	CodeMissingIdempotencyKey = "missing_idempotency_key"
+	CodeDuplicateRequest      = "duplicate_request"
+	CodeIdempotencyKeyReused  = "idempotency_key_reused"
+	CodeRateLimited           = "rate_limited"
+	CodeLoginRequired         = "login_required"
+	CodeInvalidCredentials    = "invalid_credentials"
+	CodeInvalidToken          = "invalid_token"
+	CodeRevokedToken          = "revoked_token"
+	CodeForbidden             = "forbidden"
+)
+
+// Error is the typed error produced by repositories and services. Message is
+// safe to show to the client, Cause keeps the underlying error for the logs.
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+	Cause   error
+}
+
+func New(kind Kind, code string, msg string) *Error {
+	return &Error{Kind: kind, Code: code, Message: msg}
+}
+
+func Wrap(err error, kind Kind, code string, msg string) *Error {
+	return &Error{Kind: kind, Code: code, Message: msg, Cause: err}
+}
+
+// Internal wraps an unexpected failure, its cause never reaches the client.
+func Internal(err error) *Error {
+	return Wrap(err, KindInternal, CodeInternal, "internal server error")
+}
+
+// Unavailable wraps a failure of a dependency that is expected to recover.
+func Unavailable(err error) *Error {
+	return Wrap(err, KindUnavailable, CodeUnavailable, "service unavailable")
+}
+
+var (
+	ErrNotFound          = New(KindNotFound, CodeNotFound, "no data found")
+	ErrNoDataAffected    = New(KindInvalid, CodeNoDataAffected, "no data affected")
+	ErrReferenceNotFound = New(KindInvalid, CodeReferenceNotFound, "id doesn't exist yet")
+	ErrLoginRequired     = New(KindUnauthorized, CodeLoginRequired, "please login")
+)
+
+func (e *Error) Error() string {
+	if e.Cause == nil {
+		return e.Message
+	}
+	return e.Message + ": " + e.Cause.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Cause
+}
+
+// Is matches on the code so errors.Is(err, ErrNotFound) holds for every error
+// carrying that code, not only the exact value.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// Public is the message shown to the client, internal details are hidden.
+func (e *Error) Public() string {
+	switch e.Kind {
+	case KindInternal:
+		return "an error occurred"
+	case KindUnavailable:
+		return "service temporarily unavailable"
+	}
+	return e.Message
+}
+
+// From returns the typed error in the chain of err, anything untyped is
+// treated as internal.
+func From(err error) *Error {
+	if err == nil {
+		return nil
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+	return Internal(err)
+}
+
+func KindOf(err error) Kind {
+	return From(err).Kind
+}
+
+func CodeOf(err error) string {
+	return From(err).Code
+}
+
+func Status(err error) int {
+	return KindOf(err).Status()
+}
@@ -0,0 +1,50 @@
+package apperr
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFrom(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+		public string
+	}{
+		{"Typed", New(KindInvalid, CodeOutOfStock, "out of stock"), http.StatusBadRequest, CodeOutOfStock, "out of stock"},
+		{"Wrapped_With_Op", fmt.Errorf("service - loan: %w", ErrNotFound), http.StatusNotFound, CodeNotFound, "no data found"},
+		{"Internal_Hides_Cause", Internal(errors.New("pq: connection reset")), http.StatusInternalServerError, CodeInternal, "an error occurred"},
+		{"Unavailable_Hides_Cause", Unavailable(errors.New("breaker open")), http.StatusServiceUnavailable, CodeUnavailable, "service temporarily unavailable"},
+		{"Untyped_Is_Internal", errors.New("boom"), http.StatusInternalServerError, CodeInternal, "an error occurred"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := From(tt.err)
+			assert.Equal(t, tt.status, Status(tt.err))
+			assert.Equal(t, tt.code, e.Code)
+			assert.Equal(t, tt.public, e.Public())
+		})
+	}
+	assert.Nil(t, From(nil))
+}
+
+func TestError(t *testing.T) {
+	cause := errors.New("dial tcp: connection refused")
+	err := Internal(cause)
+	assert.Equal(t, "internal server error: dial tcp: connection refused", err.Error())
+	assert.ErrorIs(t, err, cause)
+	assert.Equal(t, "no data found", ErrNotFound.Error())
+}
+
+func TestIs(t *testing.T) {
+	copied := New(KindNotFound, CodeNotFound, "student not found")
+	assert.ErrorIs(t, fmt.Errorf("service - get_student: %w", copied), ErrNotFound)
+	assert.NotErrorIs(t, ErrNoDataAffected, ErrNotFound)
+	assert.NotErrorIs(t, errors.New("no data found"), ErrNotFound)
+}
@@ -0,0 +1,102 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+type key string
+
+const ip key = "client_ip"
+
+const (
+	ActionCreate     = "create"
+	ActionUpdate     = "update"
+	ActionDeactivate = "deactivate"
+	ActionLoan       = "loan"
+	ActionReturn     = "return"
+	ActionLogout     = "logout"
+	ActionReplay     = "replay"
+	ActionSettle     = "settle"
+	ActionDelete     = "delete"
+	ActionImport     = "import"
+	ActionHide       = "hide"
+	ActionUnhide     = "unhide"
+)
+
+func WithClientIP(ctx context.Context, clientIP string) context.Context {
+	return context.WithValue(ctx, ip, clientIP)
+}
+
+func ClientIP(ctx context.Context) string {
+	clientIP, ok := ctx.Value(ip).(string)
+	if !ok {
+		return ""
+	}
+	return clientIP
+}
+
+type Change struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+func toMap(data any) (map[string]any, error) {
+	var m = map[string]any{}
+	if data == nil {
+		return m, nil
+	}
+	b, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed marshal audit data: %w", err)
+	}
+	if string(b) == "null" {
+		return m, nil
+	}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("failed unmarshal audit data: %w", err)
+	}
+	return m, nil
+}
+
+// Diff marshals before and after into JSON and returns them together with
+// the set of fields whose value changed.
+func Diff(before any, after any) (string, string, string, error) {
+	b, err := toMap(before)
+	if err != nil {
+		return "", "", "", err
+	}
+	a, err := toMap(after)
+	if err != nil {
+		return "", "", "", err
+	}
+	var diff = map[string]Change{}
+	for k, v := range a {
+		if old, ok := b[k]; !ok || !reflect.DeepEqual(old, v) {
+			diff[k] = Change{Before: b[k], After: v}
+		}
+	}
+	for k, v := range b {
+		if _, ok := a[k]; !ok {
+			diff[k] = Change{Before: v, After: nil}
+		}
+	}
+	var out = make([]string, 0, 3)
+	for _, i := range []any{nilIfEmpty(b), nilIfEmpty(a), diff} {
+		val, err := json.Marshal(i)
+		if err != nil {
+			return "", "", "", fmt.Errorf("failed marshal audit data: %w", err)
+		}
+		out = append(out, string(val))
+	}
+	return out[0], out[1], out[2], nil
+}
+
+func nilIfEmpty(m map[string]any) any {
+	if len(m) == 0 {
+		return nil
+	}
+	return m
+}
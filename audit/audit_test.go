@@ -0,0 +1,35 @@
+package audit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	type data struct {
+		Name  string `json:"name"`
+		Class string `json:"class"`
+	}
+
+	t.Run("Changed_Fields_Only", func(t *testing.T) {
+		before, after, diff, err := Diff(data{Name: "Budi", Class: "X"}, data{Name: "Budi", Class: "XI"})
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"name":"Budi","class":"X"}`, before)
+		assert.JSONEq(t, `{"name":"Budi","class":"XI"}`, after)
+		assert.JSONEq(t, `{"class":{"before":"X","after":"XI"}}`, diff)
+	})
+
+	t.Run("Create_Without_Before", func(t *testing.T) {
+		before, _, diff, err := Diff(nil, data{Name: "Budi"})
+		assert.NoError(t, err)
+		assert.Equal(t, "null", before)
+		assert.JSONEq(t, `{"name":{"before":null,"after":"Budi"},"class":{"before":null,"after":""}}`, diff)
+	})
+}
+
+func TestClientIP(t *testing.T) {
+	assert.Equal(t, "", ClientIP(context.Background()))
+	assert.Equal(t, "10.0.0.1", ClientIP(WithClientIP(context.Background(), "10.0.0.1")))
+}
@@ -0,0 +1,209 @@
+package calendar
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"stmnplibrary/domain/entity"
+	"strings"
+	"time"
+)
+
+// Horizon is how many days after a due date the closures are loaded to push
+// it to the next open day, long enough to get past a term break.
+const Horizon = 62
+
+// maxSearch stops NextOpen when every day of the week is closed.
+const maxSearch = 366
+
+const dateLayout = "2006-01-02"
+
+// Source reads what a Calendar is built from.
+type Source interface {
+	GetOpeningHours(ctx context.Context) ([]entity.OpeningHours, error)
+	GetClosures(ctx context.Context, from time.Time, to time.Time) ([]entity.Closure, error)
+}
+
+// Calendar knows the open days of the week and the closures of a period, the
+// days outside the loaded closures are only closed by the opening hours.
+type Calendar struct {
+	closed   [7]bool
+	closures []entity.Closure
+}
+
+var _ entity.Calendar = (*Calendar)(nil)
+
+// New builds a calendar, a weekday without opening hours is open.
+func New(hours []entity.OpeningHours, closures []entity.Closure) *Calendar {
+	var c = &Calendar{closures: closures}
+	for _, h := range hours {
+		if h.Weekday >= 0 && h.Weekday < len(c.closed) {
+			c.closed[h.Weekday] = !h.IsOpen
+		}
+	}
+	return c
+}
+
+// Load builds the calendar of the days from from to to.
+func Load(ctx context.Context, src Source, from time.Time, to time.Time) (*Calendar, error) {
+	hours, err := src.GetOpeningHours(ctx)
+	if err != nil {
+		return nil, err
+	}
+	closures, err := src.GetClosures(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return New(hours, closures), nil
+}
+
+// date is the civil date of t in its own location, closures are stored as
+// dates without a zone so they are read the same way.
+func date(t time.Time) string {
+	return t.Format(dateLayout)
+}
+
+// IsOpen reports whether the library is open on the day of t.
+func (c *Calendar) IsOpen(t time.Time) bool {
+	if c.closed[t.Weekday()] {
+		return false
+	}
+	return c.Closure(t) == nil
+}
+
+// Closure returns the closure covering the day of t, if any.
+func (c *Calendar) Closure(t time.Time) *entity.Closure {
+	var d = date(t)
+	for i := range c.closures {
+		if date(c.closures[i].StartDate) <= d && d <= date(c.closures[i].EndDate) {
+			return &c.closures[i]
+		}
+	}
+	return nil
+}
+
+func (c *Calendar) NextOpen(t time.Time) time.Time {
+	for i := range maxSearch {
+		if d := t.AddDate(0, 0, i); c.IsOpen(d) {
+			return d
+		}
+	}
+	return t
+}
+
+func (c *Calendar) ClosedDays(from time.Time, to time.Time) int64 {
+	var n int64
+	to = to.In(from.Location())
+	for d := from.AddDate(0, 0, 1); date(d) <= date(to); d = d.AddDate(0, 0, 1) {
+		if !c.IsOpen(d) {
+			n++
+		}
+	}
+	return n
+}
+
+// ParseICS reads the all-day events of an iCalendar file, like the public
+// holidays of Indonesia published by Google Calendar, as closures of kind.
+// DTEND of an all-day event is exclusive so the closure ends the day before.
+func ParseICS(r io.Reader, kind string) ([]entity.Closure, error) {
+	var (
+		lines    []string
+		scanner  = bufio.NewScanner(r)
+		closures []entity.Closure
+		event    map[string]string
+	)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		// a line starting with a space or a tab continues the one before
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed read calendar: %w", err)
+	}
+	for _, line := range lines {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		name, _, _ = strings.Cut(strings.ToUpper(name), ";")
+		switch {
+		case name == "BEGIN" && value == "VEVENT":
+			event = map[string]string{}
+		case name == "END" && value == "VEVENT":
+			if event == nil {
+				continue
+			}
+			closure, err := toClosure(event, kind)
+			if err != nil {
+				return nil, err
+			}
+			closures = append(closures, closure)
+			event = nil
+		case event != nil:
+			event[name] = value
+		}
+	}
+	sort.SliceStable(closures, func(i, j int) bool { return closures[i].StartDate.Before(closures[j].StartDate) })
+	return closures, nil
+}
+
+func toClosure(event map[string]string, kind string) (entity.Closure, error) {
+	start, err := parseDate(event["DTSTART"])
+	if err != nil {
+		return entity.Closure{}, fmt.Errorf("event %q: %w", event["SUMMARY"], err)
+	}
+	end := start
+	if v, ok := event["DTEND"]; ok {
+		if end, err = parseDate(v); err != nil {
+			return entity.Closure{}, fmt.Errorf("event %q: %w", event["SUMMARY"], err)
+		}
+		end = end.AddDate(0, 0, -1)
+	}
+	if end.Before(start) {
+		end = start
+	}
+	name := unescape(event["SUMMARY"])
+	if name == "" {
+		name = "Holiday"
+	}
+	if r := []rune(name); len(r) > 100 {
+		name = string(r[:100])
+	}
+	var closure = entity.Closure{
+		Name:      name,
+		Kind:      kind,
+		StartDate: start,
+		EndDate:   end,
+	}
+	// events without a uid are told apart by their date and name
+	uid := event["UID"]
+	if uid == "" {
+		uid = start.Format(dateLayout) + ":" + name
+	}
+	closure.UID = &uid
+	return closure, nil
+}
+
+// parseDate takes the date of a DATE or DATE-TIME value, the time of day
+// doesn't matter for a closure.
+func parseDate(v string) (time.Time, error) {
+	if len(v) < 8 {
+		return time.Time{}, fmt.Errorf("invalid date %q", v)
+	}
+	t, err := time.Parse("20060102", v[:8])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q", v)
+	}
+	return t, nil
+}
+
+func unescape(v string) string {
+	return strings.NewReplacer(`\n`, " ", `\N`, " ", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(strings.TrimSpace(v))
+}
@@ -0,0 +1,120 @@
+package calendar
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"stmnplibrary/domain/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func day(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func testCalendar() *Calendar {
+	var hours = []entity.OpeningHours{
+		{Weekday: int(time.Sunday), IsOpen: false},
+		{Weekday: int(time.Monday), IsOpen: true},
+		{Weekday: int(time.Saturday), IsOpen: false},
+	}
+	return New(hours, []entity.Closure{
+		{Name: "Hari Kemerdekaan", Kind: entity.ClosureHoliday, StartDate: day(2026, 8, 17), EndDate: day(2026, 8, 17)},
+		{Name: "Libur Semester", Kind: entity.ClosureTermBreak, StartDate: day(2026, 12, 21), EndDate: day(2027, 1, 1)},
+	})
+}
+
+func TestCalendar_NextOpen(t *testing.T) {
+	cal := testCalendar()
+
+	t.Run("Open_Day_Kept", func(t *testing.T) {
+		assert.Equal(t, day(2026, 8, 18), cal.NextOpen(day(2026, 8, 18)))
+	})
+
+	t.Run("Holiday_On_Monday", func(t *testing.T) {
+		assert.Equal(t, day(2026, 8, 18), cal.NextOpen(day(2026, 8, 17)))
+	})
+
+	t.Run("Weekend_Then_Holiday", func(t *testing.T) {
+		assert.Equal(t, day(2026, 8, 18), cal.NextOpen(day(2026, 8, 15)))
+	})
+
+	t.Run("Term_Break", func(t *testing.T) {
+		assert.Equal(t, day(2027, 1, 4), cal.NextOpen(day(2026, 12, 22)))
+	})
+
+	t.Run("Time_Of_Day_Kept", func(t *testing.T) {
+		due := time.Date(2026, 8, 16, 23, 59, 0, 0, time.UTC)
+		assert.Equal(t, time.Date(2026, 8, 18, 23, 59, 0, 0, time.UTC), cal.NextOpen(due))
+	})
+
+	t.Run("Always_Closed", func(t *testing.T) {
+		var hours []entity.OpeningHours
+		for d := range 7 {
+			hours = append(hours, entity.OpeningHours{Weekday: d})
+		}
+		assert.Equal(t, day(2026, 8, 18), New(hours, nil).NextOpen(day(2026, 8, 18)))
+	})
+}
+
+func TestCalendar_ClosedDays(t *testing.T) {
+	cal := testCalendar()
+
+	// due friday the 14th, returned tuesday the 18th: saturday, sunday and
+	// the monday holiday are closed
+	assert.Equal(t, int64(3), cal.ClosedDays(day(2026, 8, 14), day(2026, 8, 18).Add(10*time.Hour)))
+	assert.Equal(t, int64(0), cal.ClosedDays(day(2026, 8, 18), day(2026, 8, 20)))
+	assert.Equal(t, int64(0), cal.ClosedDays(day(2026, 8, 18), day(2026, 8, 18)))
+}
+
+func TestGiveSanctions_Skips_Closed_Days(t *testing.T) {
+	returned := day(2026, 8, 18).Add(10 * time.Hour)
+	var sanctions int64
+	ldu := entity.LdUpdate{MustReturnedAt: day(2026, 8, 14), ReturnedAt: &returned, Sanctions: &sanctions}
+
+	ldu.GiveSanctions(testCalendar())
+	assert.Equal(t, int64(2000), sanctions)
+
+	ldu.GiveSanctions(nil)
+	assert.Equal(t, int64(8000), sanctions)
+}
+
+const holidays = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"PRODID:-//Google Inc//Google Calendar 70.9054//EN\r\n" +
+	"X-WR-CALNAME:Hari libur di Indonesia\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART;VALUE=DATE:20260817\r\n" +
+	"DTEND;VALUE=DATE:20260818\r\n" +
+	"UID:20260817_id@google.com\r\n" +
+	"SUMMARY:Hari Proklamasi Kemerdekaan R.I.\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART;VALUE=DATE:20260320\r\n" +
+	"DTEND;VALUE=DATE:20260322\r\n" +
+	"UID:20260320_id@google.com\r\n" +
+	"SUMMARY:Hari Raya Idul Fitri\\, \r\n" +
+	" 1447 H\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestParseICS(t *testing.T) {
+	closures, err := ParseICS(strings.NewReader(holidays), entity.ClosureHoliday)
+	require.NoError(t, err)
+	require.Len(t, closures, 2)
+
+	assert.Equal(t, "Hari Raya Idul Fitri, 1447 H", closures[0].Name)
+	assert.Equal(t, day(2026, 3, 20), closures[0].StartDate)
+	assert.Equal(t, day(2026, 3, 21), closures[0].EndDate)
+	assert.Equal(t, "20260320_id@google.com", *closures[0].UID)
+
+	assert.Equal(t, day(2026, 8, 17), closures[1].StartDate)
+	assert.Equal(t, day(2026, 8, 17), closures[1].EndDate)
+	assert.Equal(t, entity.ClosureHoliday, closures[1].Kind)
+
+	_, err = ParseICS(strings.NewReader("BEGIN:VEVENT\nDTSTART:2026\nEND:VEVENT\n"), entity.ClosureHoliday)
+	assert.Error(t, err)
+}
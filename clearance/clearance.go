@@ -0,0 +1,150 @@
+package clearance
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"stmnplibrary/domain/entity"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+	"github.com/skip2/go-qrcode"
+)
+
+const (
+	PurposeGraduation = "graduation"
+	PurposeTransfer   = "transfer"
+)
+
+const (
+	StatusIssued   = "issued"
+	StatusExisting = "existing"
+	StatusBlocked  = "blocked"
+)
+
+// alphabet leaves out 0/O and 1/I so a number read aloud or typed from paper
+// stays unambiguous.
+const alphabet = "23456789ABCDEFGHJKLMNPQRSTUVWXYZ"
+
+// NewNumber returns a random certificate number like BP-2026-7K3M9Q2X, BP
+// standing for bebas pustaka.
+func NewNumber(issuedAt time.Time) (string, error) {
+	var b = make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed generate clearance number: %w", err)
+	}
+	for i := range b {
+		b[i] = alphabet[int(b[i])%len(alphabet)]
+	}
+	return "BP-" + strconv.Itoa(issuedAt.Year()) + "-" + string(b), nil
+}
+
+// Sign returns the HMAC-SHA256 of every printed field of c, so changing any
+// of them in the database or on paper breaks the verification.
+func Sign(key string, c entity.Clearance) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(strings.Join([]string{
+		c.Number,
+		strconv.Itoa(c.NIS),
+		c.Name,
+		c.Class,
+		c.SubClass,
+		c.Major,
+		strconv.Itoa(c.Batch),
+		c.Purpose,
+		strconv.FormatInt(c.IssuedAt.Unix(), 10),
+	}, "\n")))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature is the signature of c and the one it was
+// stored with.
+func Verify(key string, c entity.Clearance, signature string) bool {
+	expected := Sign(key, c)
+	return hmac.Equal([]byte(expected), []byte(signature)) && hmac.Equal([]byte(expected), []byte(c.Signature))
+}
+
+// VerifyURL is the public address printed as a QR code on the certificate.
+func VerifyURL(base string, c entity.Clearance) string {
+	return strings.TrimRight(base, "/") + "/" + url.PathEscape(c.Number) + "/verify?sig=" + c.Signature
+}
+
+// Render draws the certificate of c as a one page A4 PDF.
+func Render(c entity.Clearance, verifyURL string, institution string) ([]byte, error) {
+	qr, err := qrcode.Encode(verifyURL, qrcode.Medium, 512)
+	if err != nil {
+		return nil, fmt.Errorf("failed encode clearance qr code: %w", err)
+	}
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.SetTitle("Library Clearance "+c.Number, true)
+	pdf.SetCreator(institution, true)
+	pdf.SetCreationDate(c.IssuedAt)
+	pdf.SetModificationDate(c.IssuedAt)
+	pdf.SetMargins(25, 25, 25)
+	pdf.AddPage()
+	width, _ := pdf.GetPageSize()
+	var content = width - 50
+
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.CellFormat(content, 8, tr(institution), "", 1, "C", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.CellFormat(content, 6, "Surat Keterangan Bebas Pustaka / Library Clearance Certificate", "", 1, "C", false, 0, "")
+	pdf.SetLineWidth(0.5)
+	pdf.Line(25, pdf.GetY()+2, width-25, pdf.GetY()+2)
+	pdf.Ln(10)
+
+	pdf.SetFont("Helvetica", "B", 12)
+	pdf.CellFormat(content, 7, "No. "+c.Number, "", 1, "C", false, 0, "")
+	pdf.Ln(6)
+
+	pdf.SetFont("Helvetica", "", 11)
+	pdf.MultiCell(content, 6, "This is to certify that the student below has returned every book borrowed from the library and has no outstanding sanctions.", "", "L", false)
+	pdf.Ln(4)
+	for _, row := range [][2]string{
+		{"Name", c.Name},
+		{"NIS", strconv.Itoa(c.NIS)},
+		{"Class", strings.TrimSpace(c.Class + " " + c.Major + " " + c.SubClass)},
+		{"Batch", strconv.Itoa(c.Batch)},
+		{"Purpose", purposeLabel(c.Purpose)},
+		{"Issued at", c.IssuedAt.Format("02 January 2006 15:04 MST")},
+	} {
+		pdf.SetFont("Helvetica", "B", 11)
+		pdf.CellFormat(35, 7, row[0], "", 0, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 11)
+		pdf.CellFormat(content-35, 7, ": "+tr(row[1]), "", 1, "L", false, 0, "")
+	}
+	pdf.Ln(10)
+
+	var y = pdf.GetY()
+	pdf.RegisterImageOptionsReader("qr", gofpdf.ImageOptions{ImageType: "PNG"}, bytes.NewReader(qr))
+	pdf.ImageOptions("qr", 25, y, 40, 40, false, gofpdf.ImageOptions{ImageType: "PNG"}, 0, verifyURL)
+	pdf.SetXY(70, y+4)
+	pdf.SetFont("Helvetica", "", 9)
+	pdf.MultiCell(content-45, 5, "Scan the QR code or open the address below to verify this certificate. A certificate whose data differs from the verification page is not valid.", "", "L", false)
+	pdf.SetX(70)
+	pdf.SetFont("Courier", "", 7)
+	pdf.MultiCell(content-45, 4, verifyURL, "", "L", false)
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("failed render clearance pdf: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func purposeLabel(purpose string) string {
+	switch purpose {
+	case PurposeGraduation:
+		return "Graduation"
+	case PurposeTransfer:
+		return "Transfer to another school"
+	}
+	return purpose
+}
@@ -0,0 +1,68 @@
+package clearance
+
+import (
+	"bytes"
+	"regexp"
+	"stmnplibrary/domain/entity"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const key = "clearance-signing-key"
+
+func testClearance() entity.Clearance {
+	c := entity.Clearance{
+		Number:   "BP-2026-7K3M9Q2X",
+		NIS:      12345,
+		Name:     "Siti Rahayu",
+		Class:    "XII",
+		SubClass: "A",
+		Major:    "RPL",
+		Batch:    2024,
+		Purpose:  PurposeGraduation,
+		IssuedAt: time.Date(2026, 5, 4, 9, 0, 0, 0, time.UTC),
+	}
+	c.Signature = Sign(key, c)
+	return c
+}
+
+func TestNewNumber(t *testing.T) {
+	number, err := NewNumber(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Regexp(t, regexp.MustCompile(`^BP-2026-[2-9A-HJ-NP-Z]{8}$`), number)
+
+	other, err := NewNumber(time.Now())
+	require.NoError(t, err)
+	assert.NotEqual(t, number, other)
+}
+
+func TestVerify(t *testing.T) {
+	c := testClearance()
+	assert.Len(t, c.Signature, 64)
+	assert.True(t, Verify(key, c, c.Signature))
+	assert.False(t, Verify("other-signing-key", c, c.Signature))
+
+	tampered := c
+	tampered.Name = "Budi Santoso"
+	assert.False(t, Verify(key, tampered, c.Signature))
+
+	// a row changed together with a freshly computed signature is still caught
+	tampered.Signature = Sign(key, tampered)
+	assert.False(t, Verify(key, tampered, c.Signature))
+}
+
+func TestVerifyURL(t *testing.T) {
+	c := testClearance()
+	assert.Equal(t, "https://library.example/api/v1/clearances/BP-2026-7K3M9Q2X/verify?sig="+c.Signature, VerifyURL("https://library.example/api/v1/clearances/", c))
+}
+
+func TestRender(t *testing.T) {
+	c := testClearance()
+	pdf, err := Render(c, VerifyURL("https://library.example/api/v1/clearances", c), "STMNP Library")
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(pdf, []byte("%PDF-")))
+	assert.True(t, bytes.Contains(pdf, []byte("%%EOF")))
+}
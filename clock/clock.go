@@ -0,0 +1,99 @@
+package clock
+
+import (
+	"stmnplibrary/config"
+	"sync"
+	"time"
+
+	// the timezone database is embedded so the library timezone loads on
+	// images without tzdata
+	_ "time/tzdata"
+)
+
+// Clock tells the time in the timezone of the library, services take it
+// instead of calling time.Now so tests can stop and move time.
+type Clock interface {
+	Now() time.Time
+	Location() *time.Location
+}
+
+type system struct {
+	loc *time.Location
+}
+
+// New returns the system clock in loc.
+func New(loc *time.Location) Clock {
+	return system{loc: loc}
+}
+
+// FnClock is the system clock in the timezone of the library.
+func FnClock(cfg config.Library) (Clock, error) {
+	loc, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		return nil, err
+	}
+	return New(loc), nil
+}
+
+func (s system) Now() time.Time {
+	return time.Now().In(s.loc)
+}
+
+func (s system) Location() *time.Location {
+	return s.loc
+}
+
+// Fake is a clock standing still until it is set or advanced.
+type Fake struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now}
+}
+
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+func (f *Fake) Location() *time.Location {
+	return f.Now().Location()
+}
+
+func (f *Fake) Set(now time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = now
+}
+
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+}
+
+// StartOfDay is midnight of the day of t in its location.
+func StartOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// EndOfDay is the last second of the day of t in its location, a book due on
+// a day can be returned until the library closes that day.
+func EndOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 23, 59, 59, 0, t.Location())
+}
+
+// Days counts the calendar days from the day of from to the day of to, both
+// read in the location of from, whatever the time of day and DST.
+func Days(from time.Time, to time.Time) int64 {
+	fy, fm, fd := from.Date()
+	ty, tm, td := to.In(from.Location()).Date()
+	f := time.Date(fy, fm, fd, 0, 0, 0, 0, time.UTC)
+	t := time.Date(ty, tm, td, 0, 0, 0, 0, time.UTC)
+	return int64(t.Sub(f).Hours()) / 24
+}
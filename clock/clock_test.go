@@ -0,0 +1,34 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDays(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	require.NoError(t, err)
+
+	due := EndOfDay(time.Date(2026, 8, 14, 0, 0, 0, 0, jakarta))
+	assert.Equal(t, time.Date(2026, 8, 14, 23, 59, 59, 0, jakarta), due)
+
+	// 18:00 UTC of the 14th is already the 15th in Jakarta
+	assert.Equal(t, int64(1), Days(due, time.Date(2026, 8, 14, 18, 0, 0, 0, time.UTC)))
+	assert.Equal(t, int64(0), Days(due, time.Date(2026, 8, 14, 16, 0, 0, 0, time.UTC)))
+	assert.Equal(t, int64(-1), Days(due, StartOfDay(due).Add(-time.Minute)))
+	assert.Equal(t, int64(31), Days(due, time.Date(2026, 9, 14, 8, 0, 0, 0, jakarta)))
+}
+
+func TestFake(t *testing.T) {
+	start := time.Date(2026, 8, 14, 9, 0, 0, 0, time.UTC)
+	c := NewFake(start)
+	assert.Equal(t, start, c.Now())
+	c.Advance(48 * time.Hour)
+	assert.Equal(t, start.Add(48*time.Hour), c.Now())
+	c.Set(start)
+	assert.Equal(t, start, c.Now())
+	assert.Equal(t, time.UTC, c.Location())
+}
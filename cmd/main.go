@@ -2,8 +2,12 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"os/signal"
 	"stmnplibrary/cmd/wire"
+	"stmnplibrary/config"
+	"stmnplibrary/health"
+	"stmnplibrary/tracing"
 	"stmnplibrary/log"
 	"syscall"
 	"time"
@@ -11,21 +15,27 @@ import (
 	// "github.com/gin-gonic/gin"
 	"net/http"
 
-	"github.com/joho/godotenv"
 	"go.uber.org/zap"
 
 	"os"
-	// "fmt"
 )
 
-func initLog() *zap.Logger {
-	zl := zap.NewDevelopmentConfig()
-	zl.DisableStacktrace = true
-	zapLog, err := zl.Build()
+func runCommand(cfg *config.Config, args []string) error {
+	switch args[0] {
+	case "migrate":
+		return runMigrate(cfg, args[1:])
+	case "seed":
+		return runSeed(cfg, args[1:])
+	default:
+		return fmt.Errorf("unknown command %q, available: migrate, seed", args[0])
+	}
+}
+
+func initLog(cfg config.Log) *zap.Logger {
+	zapLog, err := log.Init(cfg)
 	if err != nil {
 		panic(err)
 	}
-	log.LogInit(zapLog)
 	return zapLog
 }
 
@@ -40,20 +50,31 @@ func initLog() *zap.Logger {
 // @in cookie
 // @name tokeniniAccess_0288193761dk
 func main() {
-	zapLog := initLog()
+	cfg, err := config.Load(config.Path())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error while load config: %v\n", err)
+		os.Exit(1)
+	}
+	zapLog := initLog(cfg.Log)
 	defer zapLog.Sync()
-	if err := godotenv.Load(".env"); err != nil {
-		zapLog.Error("failed open .env file")
+	if len(os.Args) > 1 {
+		if err := runCommand(cfg, os.Args[1:]); err != nil {
+			zapLog.Sugar().Fatalf("Error while run %s: %v", os.Args[1], err)
+		}
 		return
-	}	
-	router, stop, err := wiring.InitializeApp()
+	}
+	stopTracing, err := tracing.Init(context.TODO(), cfg.Tracing)
+	if err != nil {
+		zapLog.Sugar().Fatalf("Error while initialize tracing: %v", err)
+	}
+	app, stop, err := wiring.InitializeApp(cfg)
 	if err != nil {
 		zapLog.Sugar().Fatalf("Error while initialize app: %v", err)
 	}
 
 	srv := &http.Server{
-		Addr: os.Getenv("SERVER_PORT"),
-		Handler: router,
+		Addr: cfg.Server.Port,
+		Handler: app.Router,
 	}
 
 	go func() {
@@ -66,12 +87,18 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<- quit
 
-	ctx, cancel := context.WithTimeout(context.TODO(), 1*time.Minute)
+	health.Drain()
+	time.Sleep(cfg.Server.ShutdownDelay)
+
+	ctx, cancel := context.WithTimeout(context.TODO(), cfg.Server.ShutdownTimeout)
 	defer cancel()
 
+	// workers, redis and postgres are still drained and spans flushed when
+	// requests outlive the timeout
 	if err := srv.Shutdown(ctx); err != nil {
-		zapLog.Sugar().Fatalf("Error while shutdown server: %v", err)
+		zapLog.Sugar().Errorf("Error while shutdown server: %v", err)
 	}
 
 	stop()
+	stopTracing()
 }
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"stmnplibrary/config"
+	pgc "stmnplibrary/controller/postgres/config"
+	"stmnplibrary/controller/postgres/migration"
+)
+
+const migrateUsage = "usage: migrate up | down [steps] | status"
+
+func runMigrate(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	db, cleanup := pgc.Init(pgc.ProviderConnStr(cfg.Postgres), cfg.Postgres)
+	defer cleanup()
+	migrator, err := migration.FnMigrator(db)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		done, err := migrator.Up(ctx)
+		for _, m := range done {
+			fmt.Printf("applied %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(done) == 0 {
+			fmt.Println("no pending migration")
+		}
+	case "down":
+		var steps = 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil {
+				return errors.New(migrateUsage)
+			}
+		}
+		done, err := migrator.Down(ctx, steps)
+		for _, m := range done {
+			fmt.Printf("rolled back %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(done) == 0 {
+			fmt.Println("no applied migration")
+		}
+	case "status":
+		status, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, s := range status {
+			appliedAt := "pending"
+			if s.AppliedAt != nil {
+				appliedAt = s.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\n", s.Version, s.Name, appliedAt)
+		}
+		return w.Flush()
+	default:
+		return errors.New(migrateUsage)
+	}
+	return nil
+}
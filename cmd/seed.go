@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"stmnplibrary/config"
+	pgc "stmnplibrary/controller/postgres/config"
+	"stmnplibrary/controller/postgres/seed"
+)
+
+func runSeed(cfg *config.Config, args []string) error {
+	var (
+		opt      seed.Options
+		truncate bool
+		fs       = flag.NewFlagSet("seed", flag.ContinueOnError)
+	)
+	fs.Int64Var(&opt.Seed, "seed", 1, "random seed, the same seed always generates the same data")
+	fs.IntVar(&opt.Categories, "categories", 12, "number of categories")
+	fs.IntVar(&opt.Books, "books", 200, "number of books")
+	fs.IntVar(&opt.Students, "students", 300, "number of students")
+	fs.IntVar(&opt.Loans, "loans", 600, "number of loans (active, overdue and returned)")
+	fs.StringVar(&opt.Password, "password", "password123", "password of every seeded account")
+	fs.BoolVar(&truncate, "truncate", false, "empty students, books, categories, connections and loan before seeding")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if opt.Categories < 0 || opt.Books < 0 || opt.Students < 0 || opt.Loans < 0 {
+		return fmt.Errorf("sizes must not be negative")
+	}
+	opt.Now = time.Now()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	db, cleanup := pgc.Init(pgc.ProviderConnStr(cfg.Postgres), cfg.Postgres)
+	defer cleanup()
+
+	if truncate {
+		if err := db.WithContext(ctx).Exec("TRUNCATE loan, connections, books, categories, students RESTART IDENTITY CASCADE").Error; err != nil {
+			return fmt.Errorf("failed truncate: %w", err)
+		}
+	}
+	start := time.Now()
+	data, err := seed.Run(ctx, db, opt)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("seeded %d categories, %d books, %d students (+1 admin, nis 1, +1 teacher, nis 2), %d loans in %v\n",
+		len(data.Categories), len(data.Books), len(data.Students)-2, len(data.Loans), time.Since(start).Round(time.Millisecond))
+	return nil
+}
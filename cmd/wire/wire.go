@@ -1,24 +1,63 @@
 package wiring
 
 import (
+	"stmnplibrary/clock"
+	"stmnplibrary/config"
+	token "stmnplibrary/security/jwt"
+	"stmnplibrary/metrics"
+	"stmnplibrary/outbox"
+	"stmnplibrary/recommendation"
+	"stmnplibrary/webhook"
 	pgc "stmnplibrary/controller/postgres/config"
 	rdc "stmnplibrary/controller/redis/config"
 	ra "stmnplibrary/controller/repository/admin"
+	rad "stmnplibrary/controller/repository/audit"
+	rc "stmnplibrary/controller/repository/clearance"
+	rcal "stmnplibrary/controller/repository/calendar"
+	rr "stmnplibrary/controller/repository/review"
+	rrec "stmnplibrary/controller/repository/recommendation"
+	rrl "stmnplibrary/controller/repository/readinglist"
+	rs "stmnplibrary/controller/repository/suggestion"
 	ru "stmnplibrary/controller/repository/user"
 	rau "stmnplibrary/controller/repository/auth"
+	ri "stmnplibrary/controller/repository/idempotency"
+	ro "stmnplibrary/controller/repository/outbox"
+	rw "stmnplibrary/controller/repository/webhook"
 	sa "stmnplibrary/controller/service/admin"
+	sad "stmnplibrary/controller/service/audit"
+	sc "stmnplibrary/controller/service/clearance"
+	scal "stmnplibrary/controller/service/calendar"
+	sr "stmnplibrary/controller/service/review"
+	srec "stmnplibrary/controller/service/recommendation"
+	srl "stmnplibrary/controller/service/readinglist"
+	ss "stmnplibrary/controller/service/suggestion"
 	su "stmnplibrary/controller/service/user"
 	sau "stmnplibrary/controller/service/auth"
+	si "stmnplibrary/controller/service/idempotency"
+	so "stmnplibrary/controller/service/outbox"
+	sw "stmnplibrary/controller/service/webhook"
 	ha "stmnplibrary/controller/handler/admin"
+	had "stmnplibrary/controller/handler/audit"
+	hc "stmnplibrary/controller/handler/clearance"
+	hcal "stmnplibrary/controller/handler/calendar"
+	hr "stmnplibrary/controller/handler/review"
+	hrec "stmnplibrary/controller/handler/recommendation"
+	hrl "stmnplibrary/controller/handler/readinglist"
+	hs "stmnplibrary/controller/handler/suggestion"
+	hl "stmnplibrary/controller/handler/logging"
+	hh "stmnplibrary/controller/handler/health"
 	hu "stmnplibrary/controller/handler/user"
 	hau "stmnplibrary/controller/handler/auth"
+	hw "stmnplibrary/controller/handler/webhook"
 
-	"github.com/gin-gonic/gin"
 	"github.com/google/wire"
 )
 
-func initializeApp() (*gin.Engine, func(), error) {
+func initializeApp(cfg *config.Config) (*App, func(), error) {
 	wire.Build(
+		wire.FieldsOf(new(*config.Config), "Postgres", "Redis", "JWT", "Cookie", "RateLimit", "Tracing", "Server", "Degrade", "API", "Idempotency", "Outbox", "Webhook", "Clearance", "Library", "Recommendation"),
+		token.FnJWT,
+		clock.FnClock,
 		pgc.ProviderConnStr,
 		pgc.Init,
 		rdc.ProviderCTX,
@@ -26,13 +65,48 @@ func initializeApp() (*gin.Engine, func(), error) {
 		ra.FnAdminRepository,
 		ru.FnUserRepository,
 		rau.FnAuthRepository,
+		rad.FnAuditRepository,
+		rc.FnClearanceRepository,
+		rcal.FnCalendarRepository,
+		rr.FnReviewRepository,
+		rrec.FnRecommendationRepository,
+		rrl.FnReadingListRepository,
+		rs.FnSuggestionRepository,
+		ri.FnIdempotencyRepository,
+		ro.FnOutboxRepository,
+		rw.FnWebhookRepository,
 		sa.FnAdminService,
 		su.FnUserService,
 		sau.FnAuthService,
+		sad.FnAuditService,
+		sc.FnClearanceService,
+		scal.FnCalendarService,
+		sr.FnReviewService,
+		srec.FnRecommendationService,
+		srl.FnReadingListService,
+		ss.FnSuggestionService,
+		si.FnIdempotencyService,
+		so.FnOutboxService,
+		sw.FnWebhookService,
 		ha.FnAdminHandler,
 		hu.FnUserHandler,
 		hau.FnAuthHandler,
+		had.FnAuditHandler,
+		hc.FnClearanceHandler,
+		hcal.FnCalendarHandler,
+		hr.FnReviewHandler,
+		hrec.FnRecommendationHandler,
+		hrl.FnReadingListHandler,
+		hs.FnSuggestionHandler,
+		hl.FnLogHandler,
+		hh.FnHealthHandler,
+		hw.FnWebhookHandler,
+		metrics.FnStats,
 		WireHandler,
+		outbox.FnRelay,
+		webhook.FnDispatcher,
+		recommendation.FnJob,
+		FnApp,
 	)
 	return nil, nil, nil
 }
\ No newline at end of file
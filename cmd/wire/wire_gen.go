@@ -7,38 +7,134 @@
 package wiring
 
 import (
-	"github.com/gin-gonic/gin"
+	"stmnplibrary/clock"
+	"stmnplibrary/config"
 	"stmnplibrary/controller/handler/admin"
+	handler4 "stmnplibrary/controller/handler/audit"
 	handler2 "stmnplibrary/controller/handler/auth"
+	handler9 "stmnplibrary/controller/handler/calendar"
+	handler8 "stmnplibrary/controller/handler/clearance"
+	handler6 "stmnplibrary/controller/handler/health"
+	handler5 "stmnplibrary/controller/handler/logging"
+	handler12 "stmnplibrary/controller/handler/readinglist"
+	handler11 "stmnplibrary/controller/handler/recommendation"
+	handler10 "stmnplibrary/controller/handler/review"
+	handler13 "stmnplibrary/controller/handler/suggestion"
 	handler3 "stmnplibrary/controller/handler/user"
-	"stmnplibrary/controller/postgres/config"
-	config2 "stmnplibrary/controller/redis/config"
+	handler7 "stmnplibrary/controller/handler/webhook"
+	config2 "stmnplibrary/controller/postgres/config"
+	config3 "stmnplibrary/controller/redis/config"
 	"stmnplibrary/controller/repository/admin"
-	repository2 "stmnplibrary/controller/repository/auth"
-	repository3 "stmnplibrary/controller/repository/user"
+	repository2 "stmnplibrary/controller/repository/audit"
+	repository5 "stmnplibrary/controller/repository/auth"
+	repository4 "stmnplibrary/controller/repository/calendar"
+	repository8 "stmnplibrary/controller/repository/clearance"
+	repository13 "stmnplibrary/controller/repository/idempotency"
+	repository3 "stmnplibrary/controller/repository/outbox"
+	repository11 "stmnplibrary/controller/repository/readinglist"
+	repository10 "stmnplibrary/controller/repository/recommendation"
+	repository9 "stmnplibrary/controller/repository/review"
+	repository12 "stmnplibrary/controller/repository/suggestion"
+	repository6 "stmnplibrary/controller/repository/user"
+	repository7 "stmnplibrary/controller/repository/webhook"
 	"stmnplibrary/controller/service/admin"
+	service4 "stmnplibrary/controller/service/audit"
 	service2 "stmnplibrary/controller/service/auth"
+	service7 "stmnplibrary/controller/service/calendar"
+	service6 "stmnplibrary/controller/service/clearance"
+	service12 "stmnplibrary/controller/service/idempotency"
+	service13 "stmnplibrary/controller/service/outbox"
+	service10 "stmnplibrary/controller/service/readinglist"
+	service9 "stmnplibrary/controller/service/recommendation"
+	service8 "stmnplibrary/controller/service/review"
+	service11 "stmnplibrary/controller/service/suggestion"
 	service3 "stmnplibrary/controller/service/user"
+	service5 "stmnplibrary/controller/service/webhook"
+	"stmnplibrary/metrics"
+	"stmnplibrary/outbox"
+	"stmnplibrary/recommendation"
+	"stmnplibrary/security/jwt"
+	"stmnplibrary/webhook"
 )
 
 // Injectors from wire.go:
 
-func InitializeApp() (*gin.Engine, func(), error) {
-	string2 := config.ProviderConnStr()
-	db, cleanup := config.Init(string2)
-	context := config2.ProviderCTX()
-	client, cleanup2 := config2.ConnectRedis(context)
+func InitializeApp(cfg *config.Config) (*App, func(), error) {
+	postgres := cfg.Postgres
+	string2 := config2.ProviderConnStr(postgres)
+	db, cleanup := config2.Init(string2, postgres)
+	context := config3.ProviderCTX()
+	redis := cfg.Redis
+	client, cleanup2 := config3.ConnectRedis(context, redis)
 	adminRepository := repository.FnAdminRepository(db, client)
-	adminService := service.FnAdminService(adminRepository)
+	auditRepository := repository2.FnAuditRepository(db)
+	outboxRepository := repository3.FnOutboxRepository(db, client)
+	calendarRepository := repository4.FnCalendarRepository(db)
+	library := cfg.Library
+	clockClock, err := clock.FnClock(library)
+	if err != nil {
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	adminService := service.FnAdminService(adminRepository, auditRepository, outboxRepository, calendarRepository, clockClock)
 	adminHandler := handler.FnAdminHandler(adminService)
-	authRepository := repository2.FnAuthRepository(db, client)
-	authService := service2.FnAuthService(authRepository)
-	authHandler := handler2.FnAuthHandler(authService)
-	userRepository := repository3.FnUserRepository(db, client)
-	userService := service3.FnUserService(userRepository)
-	userHandler := handler3.FnUserHandler(userService)
-	engine := WireHandler(adminHandler, authHandler, userHandler, userService)
-	return engine, func() {
+	authRepository := repository5.FnAuthRepository(db, client)
+	jwt := cfg.JWT
+	tokenJWT := token.FnJWT(jwt)
+	authService := service2.FnAuthService(authRepository, tokenJWT)
+	cookie := cfg.Cookie
+	authHandler := handler2.FnAuthHandler(authService, cookie, jwt)
+	rateLimit := cfg.RateLimit
+	degrade := cfg.Degrade
+	userRepository := repository6.FnUserRepository(db, client, rateLimit, degrade)
+	userService := service3.FnUserService(userRepository, auditRepository, outboxRepository, calendarRepository, clockClock, degrade, tokenJWT)
+	userHandler := handler3.FnUserHandler(userService, cookie)
+	auditService := service4.FnAuditService(auditRepository)
+	auditHandler := handler4.FnAuditHandler(auditService)
+	logHandler := handler5.FnLogHandler()
+	server := cfg.Server
+	healthHandler := handler6.FnHealthHandler(db, client, server)
+	webhookRepository := repository7.FnWebhookRepository(db, client)
+	configOutbox := cfg.Outbox
+	configWebhook := cfg.Webhook
+	webhookService := service5.FnWebhookService(webhookRepository, auditRepository, configOutbox, configWebhook)
+	webhookHandler := handler7.FnWebhookHandler(webhookService)
+	clearanceRepository := repository8.FnClearanceRepository(db)
+	clearance := cfg.Clearance
+	clearanceService := service6.FnClearanceService(clearanceRepository, auditRepository, clearance, clockClock)
+	clearanceHandler := handler8.FnClearanceHandler(clearanceService)
+	calendarService := service7.FnCalendarService(calendarRepository, auditRepository, clockClock)
+	calendarHandler := handler9.FnCalendarHandler(calendarService)
+	reviewRepository := repository9.FnReviewRepository(db)
+	reviewService := service8.FnReviewService(reviewRepository, auditRepository)
+	reviewHandler := handler10.FnReviewHandler(reviewService)
+	recommendationRepository := repository10.FnRecommendationRepository(db, client)
+	configRecommendation := cfg.Recommendation
+	recommendationService := service9.FnRecommendationService(recommendationRepository, configRecommendation, clockClock)
+	recommendationHandler := handler11.FnRecommendationHandler(recommendationService)
+	readingListRepository := repository11.FnReadingListRepository(db)
+	readingListService := service10.FnReadingListService(readingListRepository, auditRepository, clockClock)
+	readingListHandler := handler12.FnReadingListHandler(readingListService)
+	suggestionRepository := repository12.FnSuggestionRepository(db)
+	suggestionService := service11.FnSuggestionService(suggestionRepository, auditRepository, outboxRepository, adminService, library, clockClock)
+	suggestionHandler := handler13.FnSuggestionHandler(suggestionService)
+	idempotencyRepository := repository13.FnIdempotencyRepository(client)
+	idempotency := cfg.Idempotency
+	idempotencyService := service12.FnIdempotencyService(idempotencyRepository, idempotency, degrade)
+	stats := metrics.FnStats(adminRepository)
+	tracing := cfg.Tracing
+	api := cfg.API
+	engine := WireHandler(adminHandler, authHandler, userHandler, auditHandler, logHandler, healthHandler, webhookHandler, clearanceHandler, calendarHandler, reviewHandler, recommendationHandler, readingListHandler, suggestionHandler, userService, idempotencyService, tokenJWT, stats, tracing, api)
+	outboxService := service13.FnOutboxService(outboxRepository, configOutbox)
+	relay, cleanup3 := outbox.FnRelay(outboxService, configOutbox)
+	dispatcher, cleanup4 := webhook.FnDispatcher(webhookService, configWebhook)
+	job, cleanup5 := recommendation.FnJob(recommendationService, configRecommendation)
+	app := FnApp(engine, relay, dispatcher, job)
+	return app, func() {
+		cleanup5()
+		cleanup4()
+		cleanup3()
 		cleanup2()
 		cleanup()
 	}, nil
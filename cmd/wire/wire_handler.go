@@ -1,52 +1,166 @@
 package wiring
 
 import (
+	"stmnplibrary/config"
 	ha "stmnplibrary/controller/handler/admin"
+	had "stmnplibrary/controller/handler/audit"
+	hc "stmnplibrary/controller/handler/clearance"
+	hcal "stmnplibrary/controller/handler/calendar"
+	hr "stmnplibrary/controller/handler/review"
+	hrec "stmnplibrary/controller/handler/recommendation"
+	hrl "stmnplibrary/controller/handler/readinglist"
+	hs "stmnplibrary/controller/handler/suggestion"
+	hl "stmnplibrary/controller/handler/logging"
+	hh "stmnplibrary/controller/handler/health"
 	hb "stmnplibrary/controller/handler/auth"
 	h "stmnplibrary/controller/handler/user"
+	hw "stmnplibrary/controller/handler/webhook"
 	"stmnplibrary/domain/interface/service"
+	"stmnplibrary/metrics"
 	"stmnplibrary/middleware"
+	"stmnplibrary/outbox"
+	"stmnplibrary/recommendation"
+	token "stmnplibrary/security/jwt"
+	"stmnplibrary/webhook"
 
 	_ "stmnplibrary/docs"
 	"github.com/gin-gonic/gin"
 	"github.com/swaggo/files"
 	"github.com/swaggo/gin-swagger"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 
 )
 
-func WireHandler(handlerA *ha.AdminHandler, handlerB *hb.AuthHandler, handler *h.UserHandler, s service.UserService) *gin.Engine {
-	router := gin.Default()
+// App is the router together with the background workers running next to it.
+type App struct {
+	Router          *gin.Engine
+	Relay           *outbox.Relay
+	Webhooks        *webhook.Dispatcher
+	Recommendations *recommendation.Job
+}
 
-	middle := middleware.FnNewMiddle(s)
+func FnApp(router *gin.Engine, relay *outbox.Relay, webhooks *webhook.Dispatcher, recommendations *recommendation.Job) *App {
+	return &App{
+		Router:          router,
+		Relay:           relay,
+		Webhooks:        webhooks,
+		Recommendations: recommendations,
+	}
+}
 
+func WireHandler(handlerA *ha.AdminHandler, handlerB *hb.AuthHandler, handler *h.UserHandler, handlerAd *had.AuditHandler, handlerL *hl.LogHandler, handlerH *hh.HealthHandler, handlerW *hw.WebhookHandler, handlerC *hc.ClearanceHandler, handlerCal *hcal.CalendarHandler, handlerR *hr.ReviewHandler, handlerRec *hrec.RecommendationHandler, handlerRL *hrl.ReadingListHandler, handlerS *hs.SuggestionHandler, s service.UserService, idempotency service.IdempotencyService, jwt *token.JWT, stats *metrics.Stats, tracing config.Tracing, api config.API) *gin.Engine {
+	router := gin.New()
+
+	middle := middleware.FnNewMiddle(s, idempotency, jwt)
+	legacy := middleware.Deprecated(api)
+
+	router.GET("/healthz", handlerH.Liveness)
+	router.GET("/readyz", handlerH.Readiness)
+	router.GET("/metrics", gin.WrapH(metrics.Handler(stats)))
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler, ginSwagger.URL("/swagger/doc.json")))
 
+	router.Use(middleware.Metrics())
+	router.Use(otelgin.Middleware(tracing.ServiceName))
+	router.Use(middleware.RequestID())
+	router.Use(middleware.AccessLog())
 	router.Use(middleware.Recovery())
-	router.Use(middleware.GenerateUUID())
+	router.Use(middleware.ClientIP())
 	router.Use(middle.RateLimiter())
 
-	router.POST("/register", handler.Register)
-	router.POST("/login", handlerB.Login)
-	router.GET("/refresh", handlerB.Refresh)
+	v1 := router.Group("/api/v1")
+	v1.POST("/auth/register", handler.Register)
+	v1.POST("/auth/login", handlerB.Login)
+	v1.POST("/auth/refresh", handlerB.Refresh)
+	v1.GET("/clearances/:number/verify", handlerC.Verify)
+
+	auth := v1.Group("", middle.Auth())
+	auth.POST("/auth/logout", handler.Logout)
+
+	auth.GET("/books", middleware.StudentAuth(), handler.ListBooks)
+	auth.POST("/books", middleware.AdminAuth(), middle.Idempotent(true), handlerA.AddBook)
+	auth.POST("/books/:id/loans", middleware.StudentAuth(), middle.Idempotent(false), handler.LoanBook)
+	auth.POST("/books/:id/reviews", middleware.StudentAuth(), middle.Idempotent(false), handlerR.PostReview)
+	auth.GET("/books/:id/reviews", handlerR.GetBookReviews)
+	auth.GET("/student/recommendations", middleware.StudentAuth(), handlerRec.GetRecommendations)
+	auth.GET("/student/reading-lists", middleware.StudentAuth(), handlerRL.GetStudentReadingLists)
+	auth.POST("/categories", middleware.AdminAuth(), middle.Idempotent(true), handlerA.AddCategory)
+	auth.POST("/suggestions", middleware.StudentAuth(), middle.Idempotent(false), handlerS.AddSuggestion)
+	auth.GET("/suggestions", handlerS.GetSuggestions)
+	auth.POST("/suggestions/:id/votes", middleware.StudentAuth(), middle.Idempotent(false), handlerS.VoteSuggestion)
+	auth.GET("/calendar", handlerCal.GetCalendar)
+
+	staff := auth.Group("", middleware.StaffAuth())
+	staff.POST("/reading-lists", middle.Idempotent(false), handlerRL.AddReadingList)
+	staff.GET("/reading-lists", handlerRL.GetReadingLists)
+	staff.GET("/reading-lists/:id", handlerRL.GetReadingList)
+	staff.PUT("/reading-lists/:id", handlerRL.UpdateReadingList)
+	staff.DELETE("/reading-lists/:id", handlerRL.DeleteReadingList)
+	staff.GET("/reading-lists/:id/report", handlerRL.GetReport)
+
+	admin := auth.Group("", middleware.AdminAuth())
+	admin.GET("/loans", handlerA.ListLoans)
+	admin.POST("/loans/:id/return", middle.Idempotent(false), handlerA.ReturnLoan)
+	admin.GET("/students", handlerA.GetStudents)
+	admin.GET("/students/:nis", handlerA.GetStudent)
+	admin.PATCH("/students/:nis", handlerA.UpdateStudent)
+	admin.POST("/students/:nis/deactivate", middle.Idempotent(false), handlerA.DeactivateStudent)
+	admin.PUT("/students/:nis/role", handlerA.UpdateStudentRole)
+	admin.POST("/students/:nis/sanctions/settle", middle.Idempotent(false), handlerC.SettleSanctions)
+	admin.POST("/students/:nis/clearance", middle.Idempotent(false), handlerC.Issue)
+	admin.POST("/clearances/batch", middle.Idempotent(false), handlerC.IssueBatch)
+	admin.GET("/clearances/:number", handlerC.GetClearance)
+	admin.GET("/clearances/:number/pdf", handlerC.GetClearancePDF)
+	admin.PUT("/calendar/opening-hours", handlerCal.SetOpeningHours)
+	admin.POST("/calendar/closures", middle.Idempotent(false), handlerCal.AddClosure)
+	admin.DELETE("/calendar/closures/:id", handlerCal.DeleteClosure)
+	admin.POST("/calendar/import", middle.Idempotent(false), handlerCal.ImportICal)
+	admin.GET("/reviews", handlerR.GetReviews)
+	admin.POST("/reviews/:id/hide", handlerR.HideReview)
+	admin.POST("/reviews/:id/unhide", handlerR.UnhideReview)
+	admin.POST("/suggestions/:id/status", handlerS.MoveSuggestion)
+	admin.POST("/suggestions/:id/receive", middle.Idempotent(false), handlerS.ReceiveSuggestion)
+	admin.GET("/audit-logs", handlerAd.GetAudits)
+	admin.POST("/webhooks", middle.Idempotent(false), handlerW.AddWebhook)
+	admin.GET("/webhooks", handlerW.GetWebhooks)
+	admin.DELETE("/webhooks/:id", handlerW.DeleteWebhook)
+	admin.GET("/webhooks/:id/deliveries", handlerW.GetDeliveries)
+	admin.POST("/webhook-deliveries/:id/replay", middle.Idempotent(false), handlerW.ReplayDelivery)
+	admin.GET("/log/level", handlerL.GetLevel)
+	admin.PUT("/log/level", handlerL.SetLevel)
+
+	// unversioned routes kept for old clients until the sunset date
+	router.POST("/register", legacy("/api/v1/auth/register"), handler.Register)
+	router.POST("/login", legacy("/api/v1/auth/login"), handlerB.Login)
+	router.GET("/refresh", legacy("/api/v1/auth/refresh"), handlerB.Refresh)
 
 	router.Use(middle.Auth())
-	admin := router.Group("admin")
-	admin.Use(middleware.AdminAuth())
-	students := router.Group("student")
-	students.Use(middleware.StudentAuth())
-
-	admin.GET("/loan", handlerA.GetLoanData)
-	admin.GET("/loan/done", handlerA.GetLDDone)
-	admin.GET("/loan/dont", handlerA.GetLDDont)
-	admin.POST("/loan/confirm", handlerA.Confirm)
-	admin.POST("/add/category", middleware.GetIdempotencyKey(), handlerA.AddCategory)
-	admin.POST("/add/book", middleware.GetIdempotencyKey(), handlerA.AddBook)
-
-	students.GET("/logout", handler.Logout)
-	students.GET("/books", handler.GetBooks)
-	students.GET("/books/author", handler.GetBooksByAuthor)
-	students.GET("/books/category", handler.GetBooksByCategory)
-	students.POST("/book/loan", handler.Loan)
+	legacyAdmin := router.Group("admin")
+	legacyAdmin.Use(middleware.AdminAuth())
+	legacyStudents := router.Group("student")
+	legacyStudents.Use(middleware.StudentAuth())
+
+	legacyAdmin.GET("/loan", legacy("/api/v1/loans"), handlerA.GetLoanData)
+	legacyAdmin.GET("/loan/done", legacy("/api/v1/loans?status=returned"), handlerA.GetLDDone)
+	legacyAdmin.GET("/loan/dont", legacy("/api/v1/loans?status=active"), handlerA.GetLDDont)
+	// the ids of confirm and loan come in the body, so the link names the
+	// collection listing the id that POST /api/v1/loans/:id/return takes
+	legacyAdmin.POST("/loan/confirm", legacy("/api/v1/loans"), middle.Idempotent(false), handlerA.Confirm)
+	legacyAdmin.POST("/add/category", legacy("/api/v1/categories"), middle.Idempotent(true), handlerA.AddCategory)
+	legacyAdmin.POST("/add/book", legacy("/api/v1/books"), middle.Idempotent(true), handlerA.AddBook)
+	legacyAdmin.GET("/students", legacy("/api/v1/students"), handlerA.GetStudents)
+	legacyAdmin.GET("/students/:nis", legacy("/api/v1/students/:nis"), handlerA.GetStudent)
+	legacyAdmin.PATCH("/students/:nis", legacy("/api/v1/students/:nis"), handlerA.UpdateStudent)
+	legacyAdmin.PATCH("/students/:nis/deactivate", legacy("/api/v1/students/:nis/deactivate"), handlerA.DeactivateStudent)
+	legacyAdmin.GET("/audit", legacy("/api/v1/audit-logs"), handlerAd.GetAudits)
+	legacyAdmin.GET("/log/level", legacy("/api/v1/log/level"), handlerL.GetLevel)
+	legacyAdmin.PUT("/log/level", legacy("/api/v1/log/level"), handlerL.SetLevel)
+
+	legacyStudents.GET("/logout", legacy("/api/v1/auth/logout"), handler.Logout)
+	legacyStudents.GET("/books", legacy("/api/v1/books"), handler.GetBooks)
+	legacyStudents.GET("/books/author", legacy("/api/v1/books"), handler.GetBooksByAuthor)
+	legacyStudents.GET("/books/category", legacy("/api/v1/books"), handler.GetBooksByCategory)
+	// POST /api/v1/books/:id/loans, the book id is found in the listing
+	legacyStudents.POST("/book/loan", legacy("/api/v1/books"), middle.Idempotent(false), handler.Loan)
 
 	return router
-}
\ No newline at end of file
+}
@@ -0,0 +1,412 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/yaml.v3"
+)
+
+type Server struct {
+	Port             string        `yaml:"port" env:"SERVER_PORT" default:":8080" required:"true"`
+	ReadinessTimeout time.Duration `yaml:"readiness_timeout" env:"SERVER_READINESS_TIMEOUT" default:"2s"`
+	ShutdownDelay    time.Duration `yaml:"shutdown_delay" env:"SERVER_SHUTDOWN_DELAY" default:"5s"`
+	ShutdownTimeout  time.Duration `yaml:"shutdown_timeout" env:"SERVER_SHUTDOWN_TIMEOUT" default:"1m"`
+}
+
+// API dates the unversioned routes kept for old clients, they answer with
+// Deprecation and Sunset headers pointing to their /api/v1 successor.
+type API struct {
+	LegacyDeprecatedAt string `yaml:"legacy_deprecated_at" env:"API_LEGACY_DEPRECATED_AT" default:"2026-10-19"`
+	LegacySunset       string `yaml:"legacy_sunset" env:"API_LEGACY_SUNSET" default:"2027-04-19"`
+}
+
+type Postgres struct {
+	Host         string `yaml:"host" env:"POSTGRES_HOST" required:"true"`
+	User         string `yaml:"user" env:"POSTGRES_USER" required:"true"`
+	Password     string `yaml:"password" env:"POSTGRES_PASSWORD" required:"true"`
+	Name         string `yaml:"name" env:"POSTGRES_NAME" required:"true"`
+	Port         string `yaml:"port" env:"POSTGRES_PORT" default:"5432" required:"true"`
+	SSLMode      string `yaml:"sslmode" env:"POSTGRES_SSLMODE" default:"disable"`
+	TimeZone     string `yaml:"timezone" env:"POSTGRES_TIMEZONE" default:"Asia/Jakarta"`
+	MaxIdleConns int    `yaml:"max_idle_conns" env:"POSTGRES_MAX_IDLE_CONNS" default:"5"`
+	MaxOpenConns int    `yaml:"max_open_conns" env:"POSTGRES_MAX_OPEN_CONNS" default:"20"`
+}
+
+type Redis struct {
+	Addr     string `yaml:"addr" env:"REDIS_ADDR" required:"true"`
+	Password string `yaml:"password" env:"REDIS_PASSWORD"`
+	DB       int    `yaml:"db" env:"REDIS_DB" default:"0"`
+	// BreakerThreshold consecutive failures open the circuit for BreakerCooldown.
+	BreakerThreshold int           `yaml:"breaker_threshold" env:"REDIS_BREAKER_THRESHOLD" default:"5"`
+	BreakerCooldown  time.Duration `yaml:"breaker_cooldown" env:"REDIS_BREAKER_COOLDOWN" default:"10s"`
+}
+
+// Degrade sets how each feature behaves while redis is unavailable, the book
+// cache always fails open to the local LRU then postgres.
+type Degrade struct {
+	RateLimitPolicy   string        `yaml:"rate_limit_policy" env:"DEGRADE_RATE_LIMIT_POLICY" default:"fallback"`
+	BlacklistPolicy   string        `yaml:"blacklist_policy" env:"DEGRADE_BLACKLIST_POLICY" default:"closed"`
+	IdempotencyPolicy string        `yaml:"idempotency_policy" env:"DEGRADE_IDEMPOTENCY_POLICY" default:"open"`
+	LocalCacheSize    int           `yaml:"local_cache_size" env:"DEGRADE_LOCAL_CACHE_SIZE" default:"1000"`
+	LocalCacheTTL     time.Duration `yaml:"local_cache_ttl" env:"DEGRADE_LOCAL_CACHE_TTL" default:"1m"`
+}
+
+type JWT struct {
+	SecretKey  string        `yaml:"secret_key" env:"SecretKey" required:"true"`
+	AccessTTL  time.Duration `yaml:"access_ttl" env:"JWT_ACCESS_TTL" default:"3m"`
+	RefreshTTL time.Duration `yaml:"refresh_ttl" env:"JWT_REFRESH_TTL" default:"120h"`
+}
+
+type Cookie struct {
+	Domain string `yaml:"domain" env:"COOKIE_DOMAIN" default:"localhost"`
+	Secure bool   `yaml:"secure" env:"COOKIE_SECURE" default:"false"`
+}
+
+type RateLimit struct {
+	PerMinute int `yaml:"per_minute" env:"LIMIT" required:"true"`
+}
+
+type Tracing struct {
+	Exporter    string  `yaml:"exporter" env:"TRACING_EXPORTER" default:"none"`
+	Endpoint    string  `yaml:"endpoint" env:"TRACING_ENDPOINT" default:"localhost:4318"`
+	Insecure    bool    `yaml:"insecure" env:"TRACING_INSECURE" default:"true"`
+	ServiceName string  `yaml:"service_name" env:"TRACING_SERVICE_NAME" default:"stmnplibrary"`
+	SampleRatio float64 `yaml:"sample_ratio" env:"TRACING_SAMPLE_RATIO" default:"1"`
+}
+
+// Idempotency keeps the response of a request sent with an Idempotency-Key for
+// TTL, a request still running holds the key for at most LockTTL.
+type Idempotency struct {
+	TTL     time.Duration `yaml:"ttl" env:"IDEMPOTENCY_TTL" default:"24h"`
+	LockTTL time.Duration `yaml:"lock_ttl" env:"IDEMPOTENCY_LOCK_TTL" default:"30s"`
+}
+
+// Outbox drives the relay publishing outbox events to redis streams, an event
+// failing MaxAttempts times is moved to DeadLetterStream.
+type Outbox struct {
+	Stream           string        `yaml:"stream" env:"OUTBOX_STREAM" default:"stmnplibrary:events"`
+	DeadLetterStream string        `yaml:"dead_letter_stream" env:"OUTBOX_DEAD_LETTER_STREAM" default:"stmnplibrary:events:dead"`
+	StreamMaxLen     int64         `yaml:"stream_max_len" env:"OUTBOX_STREAM_MAX_LEN" default:"100000"`
+	Interval         time.Duration `yaml:"interval" env:"OUTBOX_INTERVAL" default:"1s"`
+	BatchSize        int           `yaml:"batch_size" env:"OUTBOX_BATCH_SIZE" default:"100"`
+	MaxAttempts      int           `yaml:"max_attempts" env:"OUTBOX_MAX_ATTEMPTS" default:"10"`
+	RetryBackoff     time.Duration `yaml:"retry_backoff" env:"OUTBOX_RETRY_BACKOFF" default:"1s"`
+	RetryBackoffMax  time.Duration `yaml:"retry_backoff_max" env:"OUTBOX_RETRY_BACKOFF_MAX" default:"5m"`
+}
+
+// Webhook drives the webhook workers: outbox events are read from the outbox
+// stream with consumer group Group and sent to the subscriptions, a delivery
+// failing MaxAttempts times is given up.
+type Webhook struct {
+	Group           string        `yaml:"group" env:"WEBHOOK_GROUP" default:"webhooks"`
+	Consumer        string        `yaml:"consumer" env:"WEBHOOK_CONSUMER"`
+	ClaimIdle       time.Duration `yaml:"claim_idle" env:"WEBHOOK_CLAIM_IDLE" default:"1m"`
+	Interval        time.Duration `yaml:"interval" env:"WEBHOOK_INTERVAL" default:"1s"`
+	BatchSize       int           `yaml:"batch_size" env:"WEBHOOK_BATCH_SIZE" default:"20"`
+	Timeout         time.Duration `yaml:"timeout" env:"WEBHOOK_TIMEOUT" default:"5s"`
+	MaxAttempts     int           `yaml:"max_attempts" env:"WEBHOOK_MAX_ATTEMPTS" default:"8"`
+	RetryBackoff    time.Duration `yaml:"retry_backoff" env:"WEBHOOK_RETRY_BACKOFF" default:"30s"`
+	RetryBackoffMax time.Duration `yaml:"retry_backoff_max" env:"WEBHOOK_RETRY_BACKOFF_MAX" default:"1h"`
+}
+
+// Clearance signs the library clearance certificates, VerifyURL is the public
+// base address the QR code of a certificate points to.
+type Clearance struct {
+	SigningKey  string `yaml:"signing_key" env:"CLEARANCE_SIGNING_KEY" required:"true"`
+	VerifyURL   string `yaml:"verify_url" env:"CLEARANCE_VERIFY_URL" default:"http://localhost:8080/api/v1/clearances"`
+	Institution string `yaml:"institution" env:"CLEARANCE_INSTITUTION" default:"STMNP Library"`
+}
+
+// Library is where the library is, due dates end at midnight and fines are
+// counted by the days of Timezone. A copy is held for a student asking for a
+// book during HoldTTL.
+type Library struct {
+	Timezone string        `yaml:"timezone" env:"LIBRARY_TIMEZONE" default:"Asia/Jakarta"`
+	HoldTTL  time.Duration `yaml:"hold_ttl" env:"LIBRARY_HOLD_TTL" default:"72h"`
+}
+
+type Log struct {
+	Mode             string `yaml:"mode" env:"LOG_MODE" default:"development"`
+	Level            string `yaml:"level" env:"LOG_LEVEL" default:"info"`
+	SampleInitial    int    `yaml:"sample_initial" env:"LOG_SAMPLE_INITIAL" default:"100"`
+	SampleThereafter int    `yaml:"sample_thereafter" env:"LOG_SAMPLE_THEREAFTER" default:"100"`
+}
+
+// Recommendation drives the job computing the recommended books of every
+// student: a run walks the students BatchSize at a time every Interval and
+// keeps Limit books per student in redis for TTL. Window is how far back the
+// loans of a class count for the books popular in it.
+type Recommendation struct {
+	Interval  time.Duration `yaml:"interval" env:"RECOMMENDATION_INTERVAL" default:"1h"`
+	BatchSize int           `yaml:"batch_size" env:"RECOMMENDATION_BATCH_SIZE" default:"100"`
+	Limit     int           `yaml:"limit" env:"RECOMMENDATION_LIMIT" default:"20"`
+	TTL       time.Duration `yaml:"ttl" env:"RECOMMENDATION_TTL" default:"3h"`
+	Window    time.Duration `yaml:"window" env:"RECOMMENDATION_WINDOW" default:"4320h"`
+}
+
+type Config struct {
+	Server         Server         `yaml:"server"`
+	API            API            `yaml:"api"`
+	Postgres       Postgres       `yaml:"postgres"`
+	Redis          Redis          `yaml:"redis"`
+	JWT            JWT            `yaml:"jwt"`
+	Cookie         Cookie         `yaml:"cookie"`
+	RateLimit      RateLimit      `yaml:"rate_limit"`
+	Idempotency    Idempotency    `yaml:"idempotency"`
+	Outbox         Outbox         `yaml:"outbox"`
+	Webhook        Webhook        `yaml:"webhook"`
+	Clearance      Clearance      `yaml:"clearance"`
+	Library        Library        `yaml:"library"`
+	Recommendation Recommendation `yaml:"recommendation"`
+	Tracing        Tracing        `yaml:"tracing"`
+	Log            Log            `yaml:"log"`
+	Degrade        Degrade        `yaml:"degrade"`
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+func setValue(field reflect.Value, raw string) error {
+	if field.Type() == durationType {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+		return nil
+	}
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Int, reflect.Int64:
+		i, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return err
+		}
+		field.SetInt(i)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	default:
+		return fmt.Errorf("unsupported type %s", field.Type())
+	}
+	return nil
+}
+
+// walk calls fn for every leaf field of v, depth first.
+func walk(v reflect.Value, fn func(field reflect.Value, tag reflect.StructField) error) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		var (
+			field = v.Field(i)
+			tag   = t.Field(i)
+		)
+		if field.Kind() == reflect.Struct && field.Type() != durationType {
+			if err := walk(field, fn); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := fn(field, tag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Load builds the config from, in increasing priority: the default tags,
+// the YAML file at path (skipped when it doesn't exist), .env and the
+// process environment. Every missing required value is reported at once.
+func Load(path string) (*Config, error) {
+	var cfg Config
+	if err := walk(reflect.ValueOf(&cfg).Elem(), func(field reflect.Value, tag reflect.StructField) error {
+		if def, ok := tag.Tag.Lookup("default"); ok {
+			if err := setValue(field, def); err != nil {
+				return fmt.Errorf("invalid default for %s: %w", tag.Tag.Get("env"), err)
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if path != "" {
+		content, err := os.ReadFile(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("failed read %s: %w", path, err)
+		}
+		if err == nil {
+			if err := yaml.Unmarshal(content, &cfg); err != nil {
+				return nil, fmt.Errorf("failed parse %s: %w", path, err)
+			}
+		}
+	}
+
+	if err := godotenv.Load(".env"); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed read .env: %w", err)
+	}
+
+	var invalid, missing []string
+	if err := walk(reflect.ValueOf(&cfg).Elem(), func(field reflect.Value, tag reflect.StructField) error {
+		var env = tag.Tag.Get("env")
+		if raw, ok := os.LookupEnv(env); ok && raw != "" {
+			if err := setValue(field, raw); err != nil {
+				invalid = append(invalid, env+" ("+err.Error()+")")
+				return nil
+			}
+		}
+		if tag.Tag.Get("required") == "true" && field.IsZero() {
+			missing = append(missing, env)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	var problems []string
+	if len(missing) > 0 {
+		problems = append(problems, "missing: "+strings.Join(missing, ", "))
+	}
+	if len(invalid) > 0 {
+		problems = append(problems, "invalid: "+strings.Join(invalid, ", "))
+	}
+	problems = append(problems, cfg.validate()...)
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("invalid config: %s", strings.Join(problems, "; "))
+	}
+	return &cfg, nil
+}
+
+func (c *Config) validate() []string {
+	var problems []string
+	if c.RateLimit.PerMinute <= 0 {
+		problems = append(problems, "LIMIT must be greater than 0")
+	}
+	if c.JWT.AccessTTL <= 0 || c.JWT.RefreshTTL <= 0 {
+		problems = append(problems, "JWT_ACCESS_TTL and JWT_REFRESH_TTL must be greater than 0")
+	}
+	if c.JWT.AccessTTL >= c.JWT.RefreshTTL {
+		problems = append(problems, "JWT_ACCESS_TTL must be shorter than JWT_REFRESH_TTL")
+	}
+	if c.Postgres.MaxOpenConns < c.Postgres.MaxIdleConns {
+		problems = append(problems, "POSTGRES_MAX_OPEN_CONNS must not be lower than POSTGRES_MAX_IDLE_CONNS")
+	}
+	if c.Server.ReadinessTimeout <= 0 {
+		problems = append(problems, "SERVER_READINESS_TIMEOUT must be greater than 0")
+	}
+	if c.Server.ShutdownDelay < 0 {
+		problems = append(problems, "SERVER_SHUTDOWN_DELAY must not be negative")
+	}
+	if c.Server.ShutdownTimeout <= 0 {
+		problems = append(problems, "SERVER_SHUTDOWN_TIMEOUT must be greater than 0")
+	}
+	deprecatedAt, errD := time.Parse(time.DateOnly, c.API.LegacyDeprecatedAt)
+	sunset, errS := time.Parse(time.DateOnly, c.API.LegacySunset)
+	if errD != nil || errS != nil {
+		problems = append(problems, "API_LEGACY_DEPRECATED_AT and API_LEGACY_SUNSET must be dates like yyyy-mm-dd")
+	} else if !deprecatedAt.Before(sunset) {
+		problems = append(problems, "API_LEGACY_DEPRECATED_AT must be before API_LEGACY_SUNSET")
+	}
+	if c.Redis.BreakerThreshold <= 0 || c.Redis.BreakerCooldown <= 0 {
+		problems = append(problems, "REDIS_BREAKER_THRESHOLD and REDIS_BREAKER_COOLDOWN must be greater than 0")
+	}
+	switch c.Degrade.RateLimitPolicy {
+	case "fallback", "open", "closed":
+	default:
+		problems = append(problems, "DEGRADE_RATE_LIMIT_POLICY must be one of fallback, open, closed")
+	}
+	switch c.Degrade.BlacklistPolicy {
+	case "open", "closed":
+	default:
+		problems = append(problems, "DEGRADE_BLACKLIST_POLICY must be one of open, closed")
+	}
+	switch c.Degrade.IdempotencyPolicy {
+	case "open", "closed":
+	default:
+		problems = append(problems, "DEGRADE_IDEMPOTENCY_POLICY must be one of open, closed")
+	}
+	if c.Idempotency.LockTTL <= 0 || c.Idempotency.TTL <= c.Idempotency.LockTTL {
+		problems = append(problems, "IDEMPOTENCY_LOCK_TTL must be greater than 0 and shorter than IDEMPOTENCY_TTL")
+	}
+	if c.Degrade.LocalCacheSize <= 0 || c.Degrade.LocalCacheTTL <= 0 {
+		problems = append(problems, "DEGRADE_LOCAL_CACHE_SIZE and DEGRADE_LOCAL_CACHE_TTL must be greater than 0")
+	}
+	if c.Outbox.Stream == "" || c.Outbox.DeadLetterStream == "" || c.Outbox.Stream == c.Outbox.DeadLetterStream {
+		problems = append(problems, "OUTBOX_STREAM and OUTBOX_DEAD_LETTER_STREAM must be set and different")
+	}
+	if c.Outbox.Interval <= 0 || c.Outbox.BatchSize <= 0 || c.Outbox.MaxAttempts <= 0 || c.Outbox.StreamMaxLen <= 0 {
+		problems = append(problems, "OUTBOX_INTERVAL, OUTBOX_BATCH_SIZE, OUTBOX_MAX_ATTEMPTS and OUTBOX_STREAM_MAX_LEN must be greater than 0")
+	}
+	if c.Outbox.RetryBackoff <= 0 || c.Outbox.RetryBackoffMax < c.Outbox.RetryBackoff {
+		problems = append(problems, "OUTBOX_RETRY_BACKOFF must be greater than 0 and not longer than OUTBOX_RETRY_BACKOFF_MAX")
+	}
+	if c.Webhook.Group == "" || c.Webhook.ClaimIdle <= 0 || c.Webhook.Interval <= 0 || c.Webhook.BatchSize <= 0 || c.Webhook.Timeout <= 0 || c.Webhook.MaxAttempts <= 0 {
+		problems = append(problems, "WEBHOOK_GROUP must be set, WEBHOOK_CLAIM_IDLE, WEBHOOK_INTERVAL, WEBHOOK_BATCH_SIZE, WEBHOOK_TIMEOUT and WEBHOOK_MAX_ATTEMPTS must be greater than 0")
+	}
+	if c.Webhook.RetryBackoff <= 0 || c.Webhook.RetryBackoffMax < c.Webhook.RetryBackoff {
+		problems = append(problems, "WEBHOOK_RETRY_BACKOFF must be greater than 0 and not longer than WEBHOOK_RETRY_BACKOFF_MAX")
+	}
+	if len(c.Clearance.SigningKey) < 16 {
+		problems = append(problems, "CLEARANCE_SIGNING_KEY must be at least 16 characters")
+	}
+	if u, err := url.Parse(c.Clearance.VerifyURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		problems = append(problems, "CLEARANCE_VERIFY_URL must be an absolute http(s) url")
+	}
+	if _, err := time.LoadLocation(c.Library.Timezone); err != nil {
+		problems = append(problems, "LIBRARY_TIMEZONE must be an IANA timezone like Asia/Jakarta")
+	}
+	if c.Library.HoldTTL <= 0 {
+		problems = append(problems, "LIBRARY_HOLD_TTL must be greater than 0")
+	}
+	if c.Recommendation.Interval <= 0 || c.Recommendation.BatchSize <= 0 || c.Recommendation.Limit <= 0 || c.Recommendation.Window <= 0 {
+		problems = append(problems, "RECOMMENDATION_INTERVAL, RECOMMENDATION_BATCH_SIZE, RECOMMENDATION_LIMIT and RECOMMENDATION_WINDOW must be greater than 0")
+	}
+	if c.Recommendation.TTL <= c.Recommendation.Interval {
+		problems = append(problems, "RECOMMENDATION_TTL must be longer than RECOMMENDATION_INTERVAL")
+	}
+	switch c.Tracing.Exporter {
+	case "otlp", "stdout", "none":
+	default:
+		problems = append(problems, "TRACING_EXPORTER must be one of otlp, stdout, none")
+	}
+	switch c.Log.Mode {
+	case "development", "production":
+	default:
+		problems = append(problems, "LOG_MODE must be one of development, production")
+	}
+	if _, err := zapcore.ParseLevel(c.Log.Level); err != nil {
+		problems = append(problems, "LOG_LEVEL must be one of debug, info, warn, error")
+	}
+	if c.Log.SampleInitial < 0 || c.Log.SampleThereafter < 0 {
+		problems = append(problems, "LOG_SAMPLE_INITIAL and LOG_SAMPLE_THEREAFTER must not be negative")
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		problems = append(problems, "TRACING_SAMPLE_RATIO must be between 0 and 1")
+	}
+	return problems
+}
+
+// Path returns the YAML config path from CONFIG_FILE, defaulting to config.yaml.
+func Path() string {
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		return path
+	}
+	return "config.yaml"
+}
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var requiredEnv = map[string]string{
+	"POSTGRES_HOST":         "localhost",
+	"POSTGRES_USER":         "postgres",
+	"POSTGRES_PASSWORD":     "postgres",
+	"POSTGRES_NAME":         "library",
+	"REDIS_ADDR":            "localhost:6379",
+	"SecretKey":             "secret",
+	"LIMIT":                 "60",
+	"CLEARANCE_SIGNING_KEY": "clearance-signing-key",
+}
+
+// isolate runs the test in an empty dir so a developer's .env isn't picked up.
+func isolate(t *testing.T) string {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	for key := range requiredEnv {
+		t.Setenv(key, "")
+	}
+	return dir
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	isolate(t)
+	for key, val := range requiredEnv {
+		t.Setenv(key, val)
+	}
+
+	cfg, err := Load("")
+	require.NoError(t, err)
+	assert.Equal(t, ":8080", cfg.Server.Port)
+	assert.Equal(t, time.Minute, cfg.Server.ShutdownTimeout)
+	assert.Equal(t, "5432", cfg.Postgres.Port)
+	assert.Equal(t, 3*time.Minute, cfg.JWT.AccessTTL)
+	assert.Equal(t, 120*time.Hour, cfg.JWT.RefreshTTL)
+	assert.Equal(t, 60, cfg.RateLimit.PerMinute)
+}
+
+func TestLoad_ReportsAllMissing(t *testing.T) {
+	isolate(t)
+
+	_, err := Load("")
+	require.Error(t, err)
+	for key := range requiredEnv {
+		assert.Contains(t, err.Error(), key)
+	}
+}
+
+func TestLoad_EnvOverridesYAML(t *testing.T) {
+	dir := isolate(t)
+	for key, val := range requiredEnv {
+		t.Setenv(key, val)
+	}
+	path := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("server:\n  port: \":9000\"\nrate_limit:\n  per_minute: 10\njwt:\n  access_ttl: 5m\n"), 0o600))
+	t.Setenv("LIMIT", "")
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, ":9000", cfg.Server.Port)
+	assert.Equal(t, 10, cfg.RateLimit.PerMinute)
+	assert.Equal(t, 5*time.Minute, cfg.JWT.AccessTTL)
+
+	t.Setenv("LIMIT", "30")
+	cfg, err = Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, 30, cfg.RateLimit.PerMinute)
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	isolate(t)
+	for key, val := range requiredEnv {
+		t.Setenv(key, val)
+	}
+	t.Setenv("LIMIT", "many")
+	t.Setenv("JWT_ACCESS_TTL", "200h")
+	t.Setenv("API_LEGACY_SUNSET", "2020-01-01")
+	t.Setenv("SERVER_SHUTDOWN_TIMEOUT", "0s")
+
+	_, err := Load("")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "LIMIT")
+	assert.Contains(t, err.Error(), "JWT_ACCESS_TTL must be shorter")
+	assert.Contains(t, err.Error(), "API_LEGACY_DEPRECATED_AT must be before API_LEGACY_SUNSET")
+	assert.Contains(t, err.Error(), "SERVER_SHUTDOWN_TIMEOUT must be greater than 0")
+}
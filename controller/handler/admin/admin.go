@@ -3,6 +3,7 @@ package handler
 import (
 	"fmt"
 	"net/http"
+	"stmnplibrary/apperr"
 	"stmnplibrary/domain/interface/service"
 	"stmnplibrary/dto"
 	"stmnplibrary/controller/handler/utils"
@@ -20,38 +21,59 @@ func FnAdminHandler(service service.AdminService) *AdminHandler {
 	return &AdminHandler{adminService: service}
 }
 
-func getPage(c *gin.Context) (int, error) {
-	page, err := strconv.Atoi(c.Query("page"))
-	if err != nil {
-		return 0, fmt.Errorf("an error occured")
+func getNIS(c *gin.Context) (int, error) {
+	nis, err := strconv.Atoi(c.Param("nis"))
+	if err != nil || nis <= 0 {
+		return 0, fmt.Errorf("nis must be a number")
 	}
-	if page == 0 {
-		return 0, fmt.Errorf("page must be filled")
+	return nis, nil
+}
+
+// ListLoans godoc
+// @Summary Get loan data
+// @Description Get loan data, all of them or only the returned / active ones
+// @Produce json
+// @Param status query string false "Loan status (default all)" Enums(all, returned, active)
+// @Param cursor query string false "Cursor, the next_cursor of the previous page"
+// @Param page_size query int false "Page size (default 35, max 100)"
+// @Tags Admin
+// @Success 200 {object} dto.Response "Successfully get loan data"
+// @Failure 400 {object} dto.Response "Incorrect client input"
+// @Failure 500 {object} dto.Response "Internal server error"
+// @Router /api/v1/loans [get]
+func (ah *AdminHandler) ListLoans(c *gin.Context) {
+	switch c.DefaultQuery("status", "all") {
+	case "all":
+		ah.GetLoanData(c)
+	case "returned":
+		ah.GetLDDone(c)
+	case "active":
+		ah.GetLDDont(c)
+	default:
+		c.JSON(http.StatusBadRequest, utils.Fail("failed get loan data", apperr.CodeValidation, "status must be one of all, returned, active"))
 	}
-	return page, err
 }
 
 // GetLoanData godoc
 // @Summary Get loan data
 // @Description Get all loan data, whether it has been returned or not
 // @Produce json
-// @Param page query int true "Page"
+// @Param cursor query string false "Cursor, the next_cursor of the previous page"
+// @Param page_size query int false "Page size (default 35, max 100)"
 // @Tags Admin
 // @Success 200 {object} dto.Response "Successfully get loan data"
 // @Failure 400 {object} dto.Response "Incorrect client input"
 // @Failure 500 {object} dto.Response "Internal server error"
+// @Deprecated
 // @Router /admin/loan [get]
 func (ah *AdminHandler) GetLoanData(c *gin.Context) {
 	var ctx = c.Request.Context()
-	page, err := getPage(c)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.Response{
-			Status:  "failed - get loan data",
-			Message: err.Error(),
-		})
+	var query dto.PageQuery
+	if errMsg := utils.GetData(func() error { return c.ShouldBindQuery(&query) }, "failed get loan data"); errMsg != nil {
+		c.JSON(http.StatusBadRequest, errMsg)
 		return
 	}
-	ld, err := ah.adminService.GetLoanData(ctx, page)
+	ld, meta, err := ah.adminService.GetLoanData(ctx, query)
 	if err != nil {
 		var resMsg = "failed get loan data"
 		status, errMsg := utils.ValidateErr(err, resMsg, "")
@@ -61,33 +83,29 @@ func (ah *AdminHandler) GetLoanData(c *gin.Context) {
 		c.JSON(status, errMsg)
 		return
 	}
-	c.JSON(http.StatusOK, dto.Response{
-		Status: "success get loan data",
-		Data:   ld,
-	})
+	c.JSON(http.StatusOK, utils.Success("success get loan data", ld, meta))
 }
 
 // GetLDDone godoc
 // @Summary Get loan data
 // @Description Get all loan data that has been returned
 // @Produce json
-// @Param page query int true "Page"
+// @Param cursor query string false "Cursor, the next_cursor of the previous page"
+// @Param page_size query int false "Page size (default 35, max 100)"
 // @Tags Admin
 // @Success 200 {object} dto.Response "Successfully get loan data"
 // @Failure 400 {object} dto.Response "Incorrect client input"
 // @Failure 500 {object} dto.Response "Internal server error"
+// @Deprecated
 // @Router /admin/loan/done [get]
 func (ah *AdminHandler) GetLDDone(c *gin.Context) {
 	var ctx = c.Request.Context()
-	page, err := getPage(c)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.Response{
-			Status:  "failed - get loan data done",
-			Message: err.Error(),
-		})
+	var query dto.PageQuery
+	if errMsg := utils.GetData(func() error { return c.ShouldBindQuery(&query) }, "failed get loan data done"); errMsg != nil {
+		c.JSON(http.StatusBadRequest, errMsg)
 		return
 	}
-	ld, err := ah.adminService.GetLDDone(ctx, page)
+	ld, meta, err := ah.adminService.GetLDDone(ctx, query)
 	if err != nil {
 		var resMsg = "failed get loan data done"
 		status, errMsg := utils.ValidateErr(err, resMsg, "")
@@ -97,40 +115,29 @@ func (ah *AdminHandler) GetLDDone(c *gin.Context) {
 		c.JSON(status, errMsg)
 		return
 	}
-	c.JSON(http.StatusOK, dto.Response{
-		Status: "success get loan data done",
-		Data:   ld,
-	})
+	c.JSON(http.StatusOK, utils.Success("success get loan data done", ld, meta))
 }
 
 // GetLDDont godoc
 // @Summary Get loan data
 // @Description Get all loan data that has not been returned
 // @Produce json
-// @Param page query int true "Page"
+// @Param cursor query string false "Cursor, the next_cursor of the previous page"
+// @Param page_size query int false "Page size (default 35, max 100)"
 // @Tags Admin
 // @Success 200 {object} dto.Response "Successfully get loan data"
 // @Failure 400 {object} dto.Response "Incorrect client input"
 // @Failure 500 {object} dto.Response "Internal server error"
+// @Deprecated
 // @Router /admin/loan/dont [get]
 func (ah *AdminHandler) GetLDDont(c *gin.Context) {
 	var ctx = c.Request.Context()
-	page, err := getPage(c)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.Response{
-			Status:  "failed - get loan data dont",
-			Message: err.Error(),
-		})
-		return
-	}
-	if page == 0 {
-		c.JSON(http.StatusBadRequest, dto.Response{
-			Status:  "failed - get loan data",
-			Message: "page must be filled",
-		})
+	var query dto.PageQuery
+	if errMsg := utils.GetData(func() error { return c.ShouldBindQuery(&query) }, "failed get loan data dont"); errMsg != nil {
+		c.JSON(http.StatusBadRequest, errMsg)
 		return
 	}
-	ld, err := ah.adminService.GetLDDont(ctx, page)
+	ld, meta, err := ah.adminService.GetLDDont(ctx, query)
 	if err != nil {
 		var resMsg = "failed get loan data dont"
 		status, errMsg := utils.ValidateErr(err, resMsg, "")
@@ -140,10 +147,7 @@ func (ah *AdminHandler) GetLDDont(c *gin.Context) {
 		c.JSON(status, errMsg)
 		return
 	}
-	c.JSON(http.StatusOK, dto.Response{
-		Status: "success get loan data dont",
-		Data:   ld,
-	})
+	c.JSON(http.StatusOK, utils.Success("success get loan data dont", ld, meta))
 }
 
 // AddCategory godoc
@@ -152,11 +156,14 @@ func (ah *AdminHandler) GetLDDont(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param category body dto.Category true "Category name"
+// @Param Idempotency-Key header string true "Key replaying the first response for retries"
 // @Tags Admin
 // @Success 200 {object} dto.Response "Successfully add new category"
 // @Failure 400 {object} dto.Response "Incorrect client input"
+// @Failure 409 {object} dto.Response "Same idempotency key still in progress"
+// @Failure 422 {object} dto.Response "Idempotency key reused with a different body"
 // @Failure 500 {object} dto.Response "Internal server error"
-// @Router /admin/add/category [post]
+// @Router /api/v1/categories [post]
 func (ah *AdminHandler) AddCategory(c *gin.Context) {
 	var (
 		data dto.Category
@@ -175,10 +182,7 @@ func (ah *AdminHandler) AddCategory(c *gin.Context) {
 		c.JSON(status, errMsg)
 		return
 	}
-	c.JSON(http.StatusCreated, dto.Response{
-		Status:  "success",
-		Message: "success add category to database",
-	})
+	c.JSON(http.StatusCreated, utils.Success("success add category to database", nil, nil))
 }
 
 // AddBook godoc
@@ -187,11 +191,14 @@ func (ah *AdminHandler) AddCategory(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param book body dto.BookData true "Book data"
+// @Param Idempotency-Key header string true "Key replaying the first response for retries"
 // @Tags Admin
 // @Success 200 {object} dto.Response "Successfully add new book"
 // @Failure 400 {object} dto.Response "Incorrect client input"
+// @Failure 409 {object} dto.Response "Same idempotency key still in progress"
+// @Failure 422 {object} dto.Response "Idempotency key reused with a different body"
 // @Failure 500 {object} dto.Response "Internal server error"
-// @Router /admin/add/book [post]
+// @Router /api/v1/books [post]
 func (ah *AdminHandler) AddBook(c *gin.Context) {
 	var (
 		data dto.BookData
@@ -210,21 +217,21 @@ func (ah *AdminHandler) AddBook(c *gin.Context) {
 		c.JSON(status, errMsg)
 		return
 	}
-	c.JSON(http.StatusCreated, dto.Response{
-		Status: "success add book",
-	})
+	c.JSON(http.StatusCreated, utils.Success("success add book", nil, nil))
 }
 
 // Confirm godoc
 // @Summary Confirm
-// @Description Confirm book loan
+// @Description Confirm book loan, with an optional outcome to return a damaged or lost book
 // @Accept json
 // @Produce json
-// @Param confirm body dto.Confirm true "Student and book data"
+// @Param confirm body dto.Confirm true "Student NIS and book ISBN"
+// @Param Idempotency-Key header string false "Key replaying the first response for retries"
 // @Tags Admin
 // @Success 200 {object} dto.Response "Successfully confirm book loan"
 // @Failure 400 {object} dto.Response "Incorrect client input"
 // @Failure 500 {object} dto.Response "Internal server error"
+// @Deprecated
 // @Router /admin/loan/confirm [post]
 func (ah *AdminHandler) Confirm(c *gin.Context) {
 	var (
@@ -245,7 +252,232 @@ func (ah *AdminHandler) Confirm(c *gin.Context) {
 		fmt.Printf("debug: err: %v \n", err)
 		return
 	}
-	c.JSON(http.StatusOK, dto.Response{
-		Status: "success confirm loan",
-	})
-}
\ No newline at end of file
+	c.JSON(http.StatusOK, utils.Success("success confirm loan", nil, nil))
+}
+
+// ReturnLoan godoc
+// @Summary Return loan
+// @Description Confirm that the book of an active loan has been returned. A damaged book is charged its repair fee, a lost one its replacement price (the book price unless a fee is sent) and leaves the stock for good, the fee is added to the sanctions
+// @Accept json
+// @Produce json
+// @Param id path int true "Loan id"
+// @Param outcome body dto.ReturnOutcome false "Return outcome, a normal return when empty"
+// @Param Idempotency-Key header string false "Key replaying the first response for retries"
+// @Tags Admin
+// @Success 200 {object} dto.Response "Successfully return loan"
+// @Failure 400 {object} dto.Response "Incorrect client input"
+// @Failure 404 {object} dto.Response "No active loan with that id"
+// @Failure 500 {object} dto.Response "Internal server error"
+// @Router /api/v1/loans/{id}/return [post]
+func (ah *AdminHandler) ReturnLoan(c *gin.Context) {
+	var (
+		data   dto.ReturnOutcome
+		ctx    = c.Request.Context()
+		resMsg = "failed return loan"
+	)
+	id, ok := utils.PathID(c, resMsg, "loan")
+	if !ok {
+		return
+	}
+	if c.Request.ContentLength != 0 {
+		if errMsg := utils.GetData(func() error { return c.ShouldBindJSON(&data) }, resMsg); errMsg != nil {
+			c.JSON(http.StatusBadRequest, errMsg)
+			return
+		}
+	}
+	if err := ah.adminService.ReturnLoan(ctx, id, data); err != nil {
+		status, errMsg := utils.ValidateErr(err, resMsg, "")
+		if status == 500 {
+			log.LogHSR(ctx, resMsg, "return loan", c.Request.URL.Path, c.Request.Method, err.Error())
+		}
+		c.JSON(status, errMsg)
+		return
+	}
+	c.JSON(http.StatusOK, utils.Success("success return loan", nil, nil))
+}
+
+// GetStudents godoc
+// @Summary Get students
+// @Description Get students, filtered by nis, name, class, major or batch
+// @Produce json
+// @Param nis query int false "NIS"
+// @Param name query string false "Name"
+// @Param class query string false "Class"
+// @Param major query string false "Major"
+// @Param batch query int false "Batch"
+// @Param cursor query string false "Cursor, the next_cursor of the previous page"
+// @Param page_size query int false "Page size (default 35, max 100)"
+// @Tags Admin
+// @Success 200 {object} dto.Response "Successfully get students"
+// @Failure 400 {object} dto.Response "Incorrect client input"
+// @Failure 500 {object} dto.Response "Internal server error"
+// @Router /api/v1/students [get]
+func (ah *AdminHandler) GetStudents(c *gin.Context) {
+	var (
+		filter dto.StudentFilter
+		ctx    = c.Request.Context()
+		resMsg = "failed get students"
+	)
+	if err := utils.GetData(func() error { return c.ShouldBindQuery(&filter) }, resMsg); err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+	students, meta, err := ah.adminService.GetStudents(ctx, filter)
+	if err != nil {
+		status, errMsg := utils.ValidateErr(err, resMsg, "")
+		if status == 500 {
+			log.LogHSR(ctx, resMsg, "get students", c.Request.URL.Path, c.Request.Method, err.Error())
+		}
+		c.JSON(status, errMsg)
+		return
+	}
+	c.JSON(http.StatusOK, utils.Success("success get students", students, meta))
+}
+
+// GetStudent godoc
+// @Summary Get student profile
+// @Description Get student detail with active loans, loan history, max book usage and sanctions
+// @Produce json
+// @Param nis path int true "NIS"
+// @Tags Admin
+// @Success 200 {object} dto.Response "Successfully get student"
+// @Failure 400 {object} dto.Response "Incorrect client input"
+// @Failure 404 {object} dto.Response "Student not found"
+// @Failure 500 {object} dto.Response "Internal server error"
+// @Router /api/v1/students/{nis} [get]
+func (ah *AdminHandler) GetStudent(c *gin.Context) {
+	var (
+		ctx    = c.Request.Context()
+		resMsg = "failed get student"
+	)
+	nis, err := getNIS(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.Fail(resMsg, apperr.CodeValidation, err.Error()))
+		return
+	}
+	profile, err := ah.adminService.GetStudent(ctx, nis)
+	if err != nil {
+		status, errMsg := utils.ValidateErr(err, resMsg, "")
+		if status == 500 {
+			log.LogHSR(ctx, resMsg, "get student", c.Request.URL.Path, c.Request.Method, err.Error())
+		}
+		c.JSON(status, errMsg)
+		return
+	}
+	c.JSON(http.StatusOK, utils.Success("success get student", profile, nil))
+}
+
+// UpdateStudent godoc
+// @Summary Update student
+// @Description Update personal and academic data of an active student
+// @Accept json
+// @Produce json
+// @Param nis path int true "NIS"
+// @Param student body dto.UpdateStudent true "Fields to update"
+// @Tags Admin
+// @Success 200 {object} dto.Response "Successfully update student"
+// @Failure 400 {object} dto.Response "Incorrect client input"
+// @Failure 404 {object} dto.Response "Student not found"
+// @Failure 500 {object} dto.Response "Internal server error"
+// @Router /api/v1/students/{nis} [patch]
+func (ah *AdminHandler) UpdateStudent(c *gin.Context) {
+	var (
+		data   dto.UpdateStudent
+		ctx    = c.Request.Context()
+		resMsg = "failed update student"
+	)
+	nis, err := getNIS(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.Fail(resMsg, apperr.CodeValidation, err.Error()))
+		return
+	}
+	if err := utils.GetData(func() error { return c.ShouldBindJSON(&data) }, resMsg); err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+	msg, err := ah.adminService.UpdateStudent(ctx, nis, data)
+	if len(msg) > 0 {
+		c.JSON(http.StatusBadRequest, utils.ErrorMsg(resMsg, msg))
+		return
+	}
+	if err != nil {
+		status, errMsg := utils.ValidateErr(err, resMsg, "student not found or already deactivated")
+		if status == 500 {
+			log.LogHSR(ctx, resMsg, "update student", c.Request.URL.Path, c.Request.Method, err.Error())
+		}
+		c.JSON(status, errMsg)
+		return
+	}
+	c.JSON(http.StatusOK, utils.Success("success update student", nil, nil))
+}
+
+// DeactivateStudent godoc
+// @Summary Deactivate student
+// @Description Deactivate a student account, the student can no longer login
+// @Produce json
+// @Param nis path int true "NIS"
+// @Param Idempotency-Key header string false "Key replaying the first response for retries"
+// @Tags Admin
+// @Success 200 {object} dto.Response "Successfully deactivate student"
+// @Failure 400 {object} dto.Response "Incorrect client input"
+// @Failure 500 {object} dto.Response "Internal server error"
+// @Router /api/v1/students/{nis}/deactivate [post]
+func (ah *AdminHandler) DeactivateStudent(c *gin.Context) {
+	var (
+		ctx    = c.Request.Context()
+		resMsg = "failed deactivate student"
+	)
+	nis, err := getNIS(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.Fail(resMsg, apperr.CodeValidation, err.Error()))
+		return
+	}
+	if err := ah.adminService.DeactivateStudent(ctx, nis); err != nil {
+		status, errMsg := utils.ValidateErr(err, resMsg, "student not found or already deactivated")
+		if status == 500 {
+			log.LogHSR(ctx, resMsg, "deactivate student", c.Request.URL.Path, c.Request.Method, err.Error())
+		}
+		c.JSON(status, errMsg)
+		return
+	}
+	c.JSON(http.StatusOK, utils.Success("success deactivate student", nil, nil))
+}
+
+// UpdateStudentRole godoc
+// @Summary Update student role
+// @Description Promote an active student to teacher or move a teacher back to students, the account has to login again
+// @Accept json
+// @Produce json
+// @Param nis path int true "NIS"
+// @Param role body dto.UpdateRole true "New role"
+// @Tags Admin
+// @Success 200 {object} dto.Response "Successfully update student role"
+// @Failure 400 {object} dto.Response "Incorrect client input"
+// @Failure 404 {object} dto.Response "Student not found"
+// @Failure 500 {object} dto.Response "Internal server error"
+// @Router /api/v1/students/{nis}/role [put]
+func (ah *AdminHandler) UpdateStudentRole(c *gin.Context) {
+	var (
+		data   dto.UpdateRole
+		ctx    = c.Request.Context()
+		resMsg = "failed update student role"
+	)
+	nis, err := getNIS(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.Fail(resMsg, apperr.CodeValidation, err.Error()))
+		return
+	}
+	if err := utils.GetData(func() error { return c.ShouldBindJSON(&data) }, resMsg); err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+	if err := ah.adminService.UpdateStudentRole(ctx, nis, data); err != nil {
+		status, errMsg := utils.ValidateErr(err, resMsg, "account is inactive, an admin or already has that role")
+		if status == 500 {
+			log.LogHSR(ctx, resMsg, "update student role", c.Request.URL.Path, c.Request.Method, err.Error())
+		}
+		c.JSON(status, errMsg)
+		return
+	}
+	c.JSON(http.StatusOK, utils.Success("success update student role", nil, nil))
+}
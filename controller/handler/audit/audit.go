@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"net/http"
+	"stmnplibrary/controller/handler/utils"
+	"stmnplibrary/domain/interface/service"
+	"stmnplibrary/dto"
+	"stmnplibrary/log"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuditHandler struct {
+	auditService service.AuditService
+}
+
+func FnAuditHandler(service service.AuditService) *AuditHandler {
+	return &AuditHandler{auditService: service}
+}
+
+// GetAudits godoc
+// @Summary Get audit log
+// @Description Get state-changing operations, newest first, filtered by actor, entity and date
+// @Produce json
+// @Param actor query int false "Actor (user id)"
+// @Param entity query string false "Entity" Enums(book, category, clearance, closure, loan, opening_hours, reading_list, review, student, suggestion, webhook, webhook_delivery)
+// @Param from query string false "From date (dd-mm-yyyy)"
+// @Param to query string false "To date, inclusive (dd-mm-yyyy)"
+// @Param cursor query string false "Cursor, the next_cursor of the previous page"
+// @Param page_size query int false "Page size (default 35, max 100)"
+// @Tags Admin
+// @Success 200 {object} dto.Response "Successfully get audit log"
+// @Failure 400 {object} dto.Response "Incorrect client input"
+// @Failure 500 {object} dto.Response "Internal server error"
+// @Router /api/v1/audit-logs [get]
+func (ah *AuditHandler) GetAudits(c *gin.Context) {
+	var (
+		filter dto.AuditFilter
+		ctx    = c.Request.Context()
+		resMsg = "failed get audit log"
+	)
+	if err := utils.GetData(func() error { return c.ShouldBindQuery(&filter) }, resMsg); err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+	audits, meta, err := ah.auditService.GetAudits(ctx, filter)
+	if err != nil {
+		status, errMsg := utils.ValidateErr(err, resMsg, "")
+		if status == 500 {
+			log.LogHSR(ctx, resMsg, "get audit log", c.Request.URL.Path, c.Request.Method, err.Error())
+		}
+		c.JSON(status, errMsg)
+		return
+	}
+	c.JSON(http.StatusOK, utils.Success("success get audit log", audits, meta))
+}
@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"stmnplibrary/apperr"
+	"stmnplibrary/config"
 	"stmnplibrary/domain/interface/service"
 	"stmnplibrary/controller/handler/utils"
 	"stmnplibrary/dto"
@@ -14,47 +16,18 @@ import (
 
 type AuthHandler struct {
 	service service.AuthService
+	cookie  config.Cookie
+	jwt     config.JWT
 }
 
-func FnAuthHandler(service service.AuthService) *AuthHandler {
+func FnAuthHandler(service service.AuthService, cookie config.Cookie, jwt config.JWT) *AuthHandler {
 	return &AuthHandler{
 		service: service,
+		cookie:  cookie,
+		jwt:     jwt,
 	}
 }
 
-func delCookieToken(c *gin.Context, key string) {
-	const maxAge = -1
-	c.SetSameSite(http.SameSiteStrictMode)
-	http.SetCookie(c.Writer, &http.Cookie{
-		Name:     key,
-		Value:    "token",
-		Path:     "/",
-		Domain:   "localhost",
-		MaxAge:   maxAge,
-		Secure:   false,
-		HttpOnly: true,
-		SameSite: http.SameSiteStrictMode,
-	})
-}
-
-func setCookieToken(c *gin.Context, key string, token string, tokenType string) {
-	var maxAge = 180
-	if tokenType == "refresh" {
-		maxAge = 604800
-	}
-	c.SetSameSite(http.SameSiteStrictMode)
-	http.SetCookie(c.Writer, &http.Cookie{
-		Name:     key,
-		Value:    token,
-		Path:     "/",
-		Domain:   "localhost",
-		MaxAge:   maxAge,
-		Secure:   false,
-		HttpOnly: true,
-		SameSite: http.SameSiteStrictMode,
-	})
-}
-
 // Refresh godoc
 // @Summary Refresh session user
 // @Description Get the refresh token in the cookie and if it is valid, generate a new access and refresh token.
@@ -63,16 +36,13 @@ func setCookieToken(c *gin.Context, key string, token string, tokenType string)
 // @Success 200 {object} dto.Response "Successfully refreshed token"
 // @Failure 401 {object} dto.Response "Not logged in yet"
 // @Failure 500 {object} dto.Response "Internal server error"
-// @Router /refresh [get]
+// @Router /api/v1/auth/refresh [post]
 func (ah *AuthHandler) Refresh(c *gin.Context) {
 	const resMsg = "failed refresh token"
 	var ctx = c.Request.Context()
 	refreshTkn, err := c.Cookie(string(constanta.TokenR))
 	if err != nil {
-		c.JSON(401, dto.Response{
-			Status:  "false / failed Authentication",
-			Message: "please login or maybe cookie are missing",
-		})
+		c.JSON(http.StatusUnauthorized, utils.Fail(resMsg, apperr.CodeLoginRequired, "please login or maybe cookie are missing"))
 		return
 	}
 	token, err := ah.service.Refresh(ctx, refreshTkn)
@@ -84,12 +54,9 @@ func (ah *AuthHandler) Refresh(c *gin.Context) {
 		c.JSON(status, errMsg)
 		return
 	}
-	setCookieToken(c, string(constanta.TokenA), token.AccessToken, "access")
-	setCookieToken(c, string(constanta.TokenR), token.RefreshToken, "refresh")
-	c.JSON(http.StatusCreated, &dto.Response{
-		Status:  "true / success",
-		Message: "success refresh",
-	})
+	utils.SetCookieToken(c, ah.cookie, string(constanta.TokenA), token.AccessToken, ah.jwt.AccessTTL)
+	utils.SetCookieToken(c, ah.cookie, string(constanta.TokenR), token.RefreshToken, ah.jwt.RefreshTTL)
+	c.JSON(http.StatusCreated, utils.Success("success refresh", nil, nil))
 }
 
 // Login godoc
@@ -102,7 +69,7 @@ func (ah *AuthHandler) Refresh(c *gin.Context) {
 // @Success 200 {object} dto.Response "Successfully Login"
 // @Failure 400 {object} dto.Response "Incorrect client input"
 // @Failure 500 {object} dto.Response "Internal server error"
-// @Router /login [post]
+// @Router /api/v1/auth/login [post]
 func (ah *AuthHandler) Login(c *gin.Context) {
 	const resMsg = "failed login"
 	var data dto.Login
@@ -121,10 +88,7 @@ func (ah *AuthHandler) Login(c *gin.Context) {
 		c.JSON(status, errMsg)
 		return
 	}
-	setCookieToken(c, string(constanta.TokenA), token.AccessToken, "access")
-	setCookieToken(c, string(constanta.TokenR), token.RefreshToken, "refresh")
-	c.JSON(http.StatusOK, &dto.Response{
-		Status:  "true / success",
-		Message: "success login",
-	})
+	utils.SetCookieToken(c, ah.cookie, string(constanta.TokenA), token.AccessToken, ah.jwt.AccessTTL)
+	utils.SetCookieToken(c, ah.cookie, string(constanta.TokenR), token.RefreshToken, ah.jwt.RefreshTTL)
+	c.JSON(http.StatusOK, utils.Success("success login", nil, nil))
 }
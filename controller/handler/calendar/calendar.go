@@ -0,0 +1,209 @@
+package handler
+
+import (
+	"net/http"
+	"stmnplibrary/apperr"
+	"stmnplibrary/controller/handler/utils"
+	"stmnplibrary/domain/entity"
+	"stmnplibrary/domain/interface/service"
+	"stmnplibrary/dto"
+	"stmnplibrary/log"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxICalSize is the largest iCal file accepted, a year of holidays is a few
+// kilobytes.
+const maxICalSize = 1 << 20
+
+type CalendarHandler struct {
+	calendarService service.CalendarService
+}
+
+func FnCalendarHandler(service service.CalendarService) *CalendarHandler {
+	return &CalendarHandler{calendarService: service}
+}
+
+// GetCalendar godoc
+// @Summary Get calendar
+// @Description Get the opening hours of every day of the week and the holidays and term breaks from from to to (the coming year by default), a book is never due on a closed day and closed days are not fined
+// @Produce json
+// @Param from query string false "First day, yyyy-mm-dd"
+// @Param to query string false "Last day, yyyy-mm-dd"
+// @Tags Calendar
+// @Success 200 {object} dto.Response "Successfully get calendar"
+// @Failure 400 {object} dto.Response "Incorrect client input"
+// @Failure 500 {object} dto.Response "Internal server error"
+// @Router /api/v1/calendar [get]
+func (ch *CalendarHandler) GetCalendar(c *gin.Context) {
+	var (
+		query  dto.CalendarQuery
+		ctx    = c.Request.Context()
+		resMsg = "failed get calendar"
+	)
+	if errMsg := utils.GetData(func() error { return c.ShouldBindQuery(&query) }, resMsg); errMsg != nil {
+		c.JSON(http.StatusBadRequest, errMsg)
+		return
+	}
+	result, err := ch.calendarService.GetCalendar(ctx, query)
+	if err != nil {
+		status, errMsg := utils.ValidateErr(err, resMsg, "")
+		if status == 500 {
+			log.LogHSR(ctx, resMsg, "get calendar", c.Request.URL.Path, c.Request.Method, err.Error())
+		}
+		c.JSON(status, errMsg)
+		return
+	}
+	c.JSON(http.StatusOK, utils.Success("success get calendar", result, nil))
+}
+
+// SetOpeningHours godoc
+// @Summary Set opening hours
+// @Description Set the opening hours of some days of the week, the days not sent are kept, a closed day needs no hours
+// @Accept json
+// @Produce json
+// @Param hours body dto.SetOpeningHours true "Opening hours"
+// @Tags Admin
+// @Success 200 {object} dto.Response "Successfully set opening hours"
+// @Failure 400 {object} dto.Response "Incorrect client input"
+// @Failure 500 {object} dto.Response "Internal server error"
+// @Router /api/v1/calendar/opening-hours [put]
+func (ch *CalendarHandler) SetOpeningHours(c *gin.Context) {
+	var (
+		data   dto.SetOpeningHours
+		ctx    = c.Request.Context()
+		resMsg = "failed set opening hours"
+	)
+	if errMsg := utils.GetData(func() error { return c.ShouldBindJSON(&data) }, resMsg); errMsg != nil {
+		c.JSON(http.StatusBadRequest, errMsg)
+		return
+	}
+	result, err := ch.calendarService.SetOpeningHours(ctx, data)
+	if err != nil {
+		status, errMsg := utils.ValidateErr(err, resMsg, "")
+		if status == 500 {
+			log.LogHSR(ctx, resMsg, "set opening hours", c.Request.URL.Path, c.Request.Method, err.Error())
+		}
+		c.JSON(status, errMsg)
+		return
+	}
+	c.JSON(http.StatusOK, utils.Success("success set opening hours", result, nil))
+}
+
+// AddClosure godoc
+// @Summary Add closure
+// @Description Close the library for a holiday or a term break, loans already due in it are not moved but are not fined for it
+// @Accept json
+// @Produce json
+// @Param closure body dto.AddClosure true "Holiday or term break"
+// @Param Idempotency-Key header string false "Key replaying the first response for retries"
+// @Tags Admin
+// @Success 201 {object} dto.Response "Successfully add closure"
+// @Failure 400 {object} dto.Response "Incorrect client input"
+// @Failure 500 {object} dto.Response "Internal server error"
+// @Router /api/v1/calendar/closures [post]
+func (ch *CalendarHandler) AddClosure(c *gin.Context) {
+	var (
+		data   dto.AddClosure
+		ctx    = c.Request.Context()
+		resMsg = "failed add closure"
+	)
+	if errMsg := utils.GetData(func() error { return c.ShouldBindJSON(&data) }, resMsg); errMsg != nil {
+		c.JSON(http.StatusBadRequest, errMsg)
+		return
+	}
+	result, err := ch.calendarService.AddClosure(ctx, data)
+	if err != nil {
+		status, errMsg := utils.ValidateErr(err, resMsg, "")
+		if status == 500 {
+			log.LogHSR(ctx, resMsg, "add closure", c.Request.URL.Path, c.Request.Method, err.Error())
+		}
+		c.JSON(status, errMsg)
+		return
+	}
+	c.JSON(http.StatusCreated, utils.Success("success add closure", result, nil))
+}
+
+// DeleteClosure godoc
+// @Summary Delete closure
+// @Description Open the library again on the days of a holiday or a term break
+// @Produce json
+// @Param id path int true "Closure ID"
+// @Tags Admin
+// @Success 200 {object} dto.Response "Successfully delete closure"
+// @Failure 400 {object} dto.Response "Incorrect client input"
+// @Failure 404 {object} dto.Response "Closure not found"
+// @Failure 500 {object} dto.Response "Internal server error"
+// @Router /api/v1/calendar/closures/{id} [delete]
+func (ch *CalendarHandler) DeleteClosure(c *gin.Context) {
+	var (
+		ctx    = c.Request.Context()
+		resMsg = "failed delete closure"
+	)
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, utils.Fail(resMsg, apperr.CodeValidation, "closure id must be a number"))
+		return
+	}
+	if err := ch.calendarService.DeleteClosure(ctx, id); err != nil {
+		status, errMsg := utils.ValidateErr(err, resMsg, "")
+		if status == 500 {
+			log.LogHSR(ctx, resMsg, "delete closure", c.Request.URL.Path, c.Request.Method, err.Error())
+		}
+		c.JSON(status, errMsg)
+		return
+	}
+	c.JSON(http.StatusOK, utils.Success("success delete closure", nil, nil))
+}
+
+// ImportICal godoc
+// @Summary Import iCal
+// @Description Import the all-day events of an iCal file, like the Indonesian public holidays calendar, as closures. Importing the same file again updates the closures instead of adding them twice
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "iCal file (.ics), at most 1 MiB"
+// @Param kind formData string false "Closure kind (default holiday)" Enums(holiday, term_break)
+// @Param Idempotency-Key header string false "Key replaying the first response for retries"
+// @Tags Admin
+// @Success 200 {object} dto.Response "Successfully import iCal"
+// @Failure 400 {object} dto.Response "Incorrect client input"
+// @Failure 500 {object} dto.Response "Internal server error"
+// @Router /api/v1/calendar/import [post]
+func (ch *CalendarHandler) ImportICal(c *gin.Context) {
+	var (
+		ctx    = c.Request.Context()
+		resMsg = "failed import ical"
+	)
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxICalSize+1<<10)
+	kind := c.DefaultPostForm("kind", entity.ClosureHoliday)
+	if kind != entity.ClosureHoliday && kind != entity.ClosureTermBreak {
+		c.JSON(http.StatusBadRequest, utils.Fail(resMsg, apperr.CodeValidation, "kind must be holiday or term_break"))
+		return
+	}
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.Fail(resMsg, apperr.CodeValidation, "file is required and must be at most 1 MiB"))
+		return
+	}
+	if header.Size > maxICalSize {
+		c.JSON(http.StatusBadRequest, utils.Fail(resMsg, apperr.CodeValidation, "file must be at most 1 MiB"))
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.Fail(resMsg, apperr.CodeValidation, "failed read file"))
+		return
+	}
+	defer file.Close()
+	result, err := ch.calendarService.ImportICal(ctx, file, kind)
+	if err != nil {
+		status, errMsg := utils.ValidateErr(err, resMsg, "")
+		if status == 500 {
+			log.LogHSR(ctx, resMsg, "import ical", c.Request.URL.Path, c.Request.Method, err.Error())
+		}
+		c.JSON(status, errMsg)
+		return
+	}
+	c.JSON(http.StatusOK, utils.Success("success import ical", result, nil))
+}
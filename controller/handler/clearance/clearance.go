@@ -0,0 +1,225 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"stmnplibrary/apperr"
+	"stmnplibrary/controller/handler/utils"
+	"stmnplibrary/domain/interface/service"
+	"stmnplibrary/dto"
+	"stmnplibrary/log"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ClearanceHandler struct {
+	clearanceService service.ClearanceService
+}
+
+func FnClearanceHandler(service service.ClearanceService) *ClearanceHandler {
+	return &ClearanceHandler{clearanceService: service}
+}
+
+func getNIS(c *gin.Context) (int, error) {
+	nis, err := strconv.Atoi(c.Param("nis"))
+	if err != nil || nis <= 0 {
+		return 0, fmt.Errorf("nis must be a number")
+	}
+	return nis, nil
+}
+
+// Issue godoc
+// @Summary Issue clearance
+// @Description Issue the library clearance (bebas pustaka) of a student with no active loans and no outstanding sanctions, a student cleared before for the same purpose gets that clearance back
+// @Accept json
+// @Produce json
+// @Param nis path int true "NIS"
+// @Param clearance body dto.IssueClearance true "Clearance purpose"
+// @Param Idempotency-Key header string false "Key replaying the first response for retries"
+// @Tags Admin
+// @Success 201 {object} dto.Response "Successfully issue clearance"
+// @Failure 400 {object} dto.Response "Incorrect client input"
+// @Failure 404 {object} dto.Response "Student not found"
+// @Failure 409 {object} dto.Response "Student still has active loans or outstanding sanctions"
+// @Failure 500 {object} dto.Response "Internal server error"
+// @Router /api/v1/students/{nis}/clearance [post]
+func (ch *ClearanceHandler) Issue(c *gin.Context) {
+	var (
+		data   dto.IssueClearance
+		ctx    = c.Request.Context()
+		resMsg = "failed issue clearance"
+	)
+	nis, err := getNIS(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.Fail(resMsg, apperr.CodeValidation, err.Error()))
+		return
+	}
+	if errMsg := utils.GetData(func() error { return c.ShouldBindJSON(&data) }, resMsg); errMsg != nil {
+		c.JSON(http.StatusBadRequest, errMsg)
+		return
+	}
+	result, err := ch.clearanceService.Issue(ctx, nis, data)
+	if err != nil {
+		status, errMsg := utils.ValidateErr(err, resMsg, "student not found")
+		if status == 500 {
+			log.LogHSR(ctx, resMsg, "issue clearance", c.Request.URL.Path, c.Request.Method, err.Error())
+		}
+		c.JSON(status, errMsg)
+		return
+	}
+	c.JSON(http.StatusCreated, utils.Success("success issue clearance", result, nil))
+}
+
+// IssueBatch godoc
+// @Summary Issue batch clearance
+// @Description Issue the clearance of every student of a batch, a blocked student doesn't stop the others and is listed with what keeps them from being cleared
+// @Accept json
+// @Produce json
+// @Param clearance body dto.IssueBatchClearance true "Batch and clearance purpose"
+// @Param Idempotency-Key header string false "Key replaying the first response for retries"
+// @Tags Admin
+// @Success 200 {object} dto.Response "Successfully issue batch clearance"
+// @Failure 400 {object} dto.Response "Incorrect client input"
+// @Failure 404 {object} dto.Response "No student in batch"
+// @Failure 500 {object} dto.Response "Internal server error"
+// @Router /api/v1/clearances/batch [post]
+func (ch *ClearanceHandler) IssueBatch(c *gin.Context) {
+	var (
+		data   dto.IssueBatchClearance
+		ctx    = c.Request.Context()
+		resMsg = "failed issue batch clearance"
+	)
+	if errMsg := utils.GetData(func() error { return c.ShouldBindJSON(&data) }, resMsg); errMsg != nil {
+		c.JSON(http.StatusBadRequest, errMsg)
+		return
+	}
+	result, err := ch.clearanceService.IssueBatch(ctx, data)
+	if err != nil {
+		status, errMsg := utils.ValidateErr(err, resMsg, "")
+		if status == 500 {
+			log.LogHSR(ctx, resMsg, "issue batch clearance", c.Request.URL.Path, c.Request.Method, err.Error())
+		}
+		c.JSON(status, errMsg)
+		return
+	}
+	c.JSON(http.StatusOK, utils.Success("success issue batch clearance", result, nil))
+}
+
+// GetClearance godoc
+// @Summary Get clearance
+// @Description Get an issued clearance by its number
+// @Produce json
+// @Param number path string true "Clearance number"
+// @Tags Admin
+// @Success 200 {object} dto.Response "Successfully get clearance"
+// @Failure 404 {object} dto.Response "Clearance not found"
+// @Failure 500 {object} dto.Response "Internal server error"
+// @Router /api/v1/clearances/{number} [get]
+func (ch *ClearanceHandler) GetClearance(c *gin.Context) {
+	var (
+		ctx    = c.Request.Context()
+		resMsg = "failed get clearance"
+	)
+	result, err := ch.clearanceService.GetClearance(ctx, c.Param("number"))
+	if err != nil {
+		status, errMsg := utils.ValidateErr(err, resMsg, "clearance not found")
+		if status == 500 {
+			log.LogHSR(ctx, resMsg, "get clearance", c.Request.URL.Path, c.Request.Method, err.Error())
+		}
+		c.JSON(status, errMsg)
+		return
+	}
+	c.JSON(http.StatusOK, utils.Success("success get clearance", result, nil))
+}
+
+// GetClearancePDF godoc
+// @Summary Get clearance certificate
+// @Description Download the clearance certificate as a PDF with a QR code pointing to its verification page
+// @Produce application/pdf
+// @Param number path string true "Clearance number"
+// @Tags Admin
+// @Success 200 {file} file "Clearance certificate"
+// @Failure 404 {object} dto.Response "Clearance not found"
+// @Failure 500 {object} dto.Response "Internal server error"
+// @Router /api/v1/clearances/{number}/pdf [get]
+func (ch *ClearanceHandler) GetClearancePDF(c *gin.Context) {
+	var (
+		ctx    = c.Request.Context()
+		number = c.Param("number")
+		resMsg = "failed get clearance certificate"
+	)
+	pdf, err := ch.clearanceService.Render(ctx, number)
+	if err != nil {
+		status, errMsg := utils.ValidateErr(err, resMsg, "clearance not found")
+		if status == 500 {
+			log.LogHSR(ctx, resMsg, "get clearance certificate", c.Request.URL.Path, c.Request.Method, err.Error())
+		}
+		c.JSON(status, errMsg)
+		return
+	}
+	c.Header("Content-Disposition", `inline; filename="`+number+`.pdf"`)
+	c.Data(http.StatusOK, "application/pdf", pdf)
+}
+
+// Verify godoc
+// @Summary Verify clearance
+// @Description Public page behind the QR code of a certificate, the clearance is only shown when the signature is valid
+// @Produce json
+// @Param number path string true "Clearance number"
+// @Param sig query string true "Signature from the QR code"
+// @Tags Clearance
+// @Success 200 {object} dto.Response "Successfully verify clearance"
+// @Failure 404 {object} dto.Response "Clearance not found"
+// @Failure 500 {object} dto.Response "Internal server error"
+// @Router /api/v1/clearances/{number}/verify [get]
+func (ch *ClearanceHandler) Verify(c *gin.Context) {
+	var (
+		ctx    = c.Request.Context()
+		resMsg = "failed verify clearance"
+	)
+	result, err := ch.clearanceService.Verify(ctx, c.Param("number"), c.Query("sig"))
+	if err != nil {
+		status, errMsg := utils.ValidateErr(err, resMsg, "clearance not found")
+		if status == 500 {
+			log.LogHSR(ctx, resMsg, "verify clearance", c.Request.URL.Path, c.Request.Method, err.Error())
+		}
+		c.JSON(status, errMsg)
+		return
+	}
+	c.JSON(http.StatusOK, utils.Success("success verify clearance", result, nil))
+}
+
+// SettleSanctions godoc
+// @Summary Settle sanctions
+// @Description Mark every outstanding sanction of a student as paid
+// @Produce json
+// @Param nis path int true "NIS"
+// @Param Idempotency-Key header string false "Key replaying the first response for retries"
+// @Tags Admin
+// @Success 200 {object} dto.Response "Successfully settle sanctions"
+// @Failure 400 {object} dto.Response "Incorrect client input"
+// @Failure 404 {object} dto.Response "Student not found"
+// @Failure 500 {object} dto.Response "Internal server error"
+// @Router /api/v1/students/{nis}/sanctions/settle [post]
+func (ch *ClearanceHandler) SettleSanctions(c *gin.Context) {
+	var (
+		ctx    = c.Request.Context()
+		resMsg = "failed settle sanctions"
+	)
+	nis, err := getNIS(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.Fail(resMsg, apperr.CodeValidation, err.Error()))
+		return
+	}
+	result, err := ch.clearanceService.SettleSanctions(ctx, nis)
+	if err != nil {
+		status, errMsg := utils.ValidateErr(err, resMsg, "student not found")
+		if status == 500 {
+			log.LogHSR(ctx, resMsg, "settle sanctions", c.Request.URL.Path, c.Request.Method, err.Error())
+		}
+		c.JSON(status, errMsg)
+		return
+	}
+	c.JSON(http.StatusOK, utils.Success("success settle sanctions", result, nil))
+}
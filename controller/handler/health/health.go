@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"stmnplibrary/apperr"
+	"stmnplibrary/config"
+	"stmnplibrary/controller/handler/utils"
+	"stmnplibrary/dto"
+	"stmnplibrary/health"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+const (
+	statusUp   = "up"
+	statusDown = "down"
+)
+
+type HealthHandler struct {
+	gorm    *gorm.DB
+	rds     *redis.Client
+	timeout time.Duration
+}
+
+func FnHealthHandler(gorm *gorm.DB, rds *redis.Client, server config.Server) *HealthHandler {
+	return &HealthHandler{
+		gorm:    gorm,
+		rds:     rds,
+		timeout: server.ReadinessTimeout,
+	}
+}
+
+func (hh *HealthHandler) check(ctx context.Context, ping func(ctx context.Context) error) dto.HealthCheck {
+	ctx, cancel := context.WithTimeout(ctx, hh.timeout)
+	defer cancel()
+	var (
+		start = time.Now()
+		err   = ping(ctx)
+		check = dto.HealthCheck{
+			Status:  statusUp,
+			Latency: time.Since(start).String(),
+		}
+	)
+	if err != nil {
+		check.Status = statusDown
+		check.Error = err.Error()
+	}
+	return check
+}
+
+func (hh *HealthHandler) checkPostgres(ctx context.Context) dto.HealthCheck {
+	sqlDB, err := hh.gorm.DB()
+	if err != nil {
+		return dto.HealthCheck{Status: statusDown, Error: err.Error()}
+	}
+	check := hh.check(ctx, sqlDB.PingContext)
+	stats := sqlDB.Stats()
+	check.Pool = dto.PostgresPool{
+		MaxOpen:      stats.MaxOpenConnections,
+		Open:         stats.OpenConnections,
+		InUse:        stats.InUse,
+		Idle:         stats.Idle,
+		WaitCount:    stats.WaitCount,
+		WaitDuration: stats.WaitDuration.String(),
+	}
+	return check
+}
+
+func (hh *HealthHandler) checkRedis(ctx context.Context) dto.HealthCheck {
+	check := hh.check(ctx, func(ctx context.Context) error {
+		return hh.rds.Ping(ctx).Err()
+	})
+	stats := hh.rds.PoolStats()
+	check.Pool = dto.RedisPool{
+		Total:    stats.TotalConns,
+		Idle:     stats.IdleConns,
+		Stale:    stats.StaleConns,
+		Hits:     stats.Hits,
+		Misses:   stats.Misses,
+		Timeouts: stats.Timeouts,
+	}
+	return check
+}
+
+// Liveness godoc
+// @Summary Liveness probe
+// @Description The process is up and serving HTTP, dependencies are not checked
+// @Produce json
+// @Tags Health
+// @Success 200 {object} dto.Response "Alive"
+// @Router /healthz [get]
+func (hh *HealthHandler) Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, utils.Success("ok", nil, nil))
+}
+
+// Readiness godoc
+// @Summary Readiness probe
+// @Description Ping postgres and redis with a timeout and report their pool stats, not ready while shutting down
+// @Produce json
+// @Tags Health
+// @Success 200 {object} dto.Response{data=dto.Readiness} "Ready"
+// @Failure 503 {object} dto.Response{data=dto.Readiness} "Not ready"
+// @Router /readyz [get]
+func (hh *HealthHandler) Readiness(c *gin.Context) {
+	if health.IsDraining() {
+		c.JSON(http.StatusServiceUnavailable, utils.Fail("not ready", apperr.CodeUnavailable, "shutting down"))
+		return
+	}
+	var (
+		ctx       = c.Request.Context()
+		readiness = dto.Readiness{
+			Postgres: hh.checkPostgres(ctx),
+			Redis:    hh.checkRedis(ctx),
+		}
+	)
+	if readiness.Postgres.Status != statusUp || readiness.Redis.Status != statusUp {
+		c.JSON(http.StatusServiceUnavailable, &dto.Response{
+			Message: "not ready",
+			Error:   dto.Errors{Code: apperr.CodeUnavailable},
+			Data:    readiness,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, utils.Success("ready", readiness, nil))
+}
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"stmnplibrary/config"
+	"stmnplibrary/dto"
+	"stmnplibrary/health"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// setupHealth points at closed ports so every dependency check fails fast.
+func setupHealth(t *testing.T) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	db, err := gorm.Open(postgres.Open("host=127.0.0.1 port=1 user=x dbname=x sslmode=disable connect_timeout=1"), &gorm.Config{DisableAutomaticPing: true})
+	require.NoError(t, err)
+	rds := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1})
+	t.Cleanup(func() { rds.Close() })
+
+	hh := FnHealthHandler(db, rds, config.Server{ReadinessTimeout: time.Second})
+	router := gin.New()
+	router.GET("/healthz", hh.Liveness)
+	router.GET("/readyz", hh.Readiness)
+	return router
+}
+
+func TestLiveness(t *testing.T) {
+	router := setupHealth(t)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestReadiness_DependencyDown(t *testing.T) {
+	router := setupHealth(t)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	var res struct {
+		Data dto.Readiness `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	assert.Equal(t, statusDown, res.Data.Postgres.Status)
+	assert.NotEmpty(t, res.Data.Postgres.Error)
+	assert.NotNil(t, res.Data.Postgres.Pool)
+	assert.Equal(t, statusDown, res.Data.Redis.Status)
+	assert.NotEmpty(t, res.Data.Redis.Error)
+}
+
+// runs last, draining can't be undone.
+func TestReadiness_Draining(t *testing.T) {
+	router := setupHealth(t)
+	health.Drain()
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Contains(t, w.Body.String(), "shutting down")
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"net/http"
+	"stmnplibrary/apperr"
+	"stmnplibrary/controller/handler/utils"
+	"stmnplibrary/dto"
+	"stmnplibrary/log"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap/zapcore"
+)
+
+type LogHandler struct{}
+
+func FnLogHandler() *LogHandler {
+	return &LogHandler{}
+}
+
+// GetLevel godoc
+// @Summary Get log level
+// @Description Get the current log level
+// @Produce json
+// @Tags Admin
+// @Success 200 {object} dto.Response{data=dto.LogLevel} "Successfully get log level"
+// @Router /api/v1/log/level [get]
+func (lh *LogHandler) GetLevel(c *gin.Context) {
+	c.JSON(http.StatusOK, utils.Success("success get log level", dto.LogLevel{
+		Level: log.Level.String(),
+	}, nil))
+}
+
+// SetLevel godoc
+// @Summary Set log level
+// @Description Change the log level at runtime, it resets to LOG_LEVEL on restart
+// @Accept json
+// @Produce json
+// @Param request body dto.LogLevel true "Log level"
+// @Tags Admin
+// @Success 200 {object} dto.Response{data=dto.LogLevel} "Successfully set log level"
+// @Failure 400 {object} dto.Response "Incorrect client input"
+// @Router /api/v1/log/level [put]
+func (lh *LogHandler) SetLevel(c *gin.Context) {
+	var (
+		data   dto.LogLevel
+		resMsg = "failed set log level"
+	)
+	if err := utils.GetData(func() error { return c.ShouldBindJSON(&data) }, resMsg); err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+	level, err := zapcore.ParseLevel(data.Level)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.Fail(resMsg, apperr.CodeValidation, err.Error()))
+		return
+	}
+	log.Level.SetLevel(level)
+	c.JSON(http.StatusOK, utils.Success("success set log level", data, nil))
+}
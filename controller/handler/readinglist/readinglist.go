@@ -0,0 +1,251 @@
+package handler
+
+import (
+	"net/http"
+	"stmnplibrary/controller/handler/utils"
+	"stmnplibrary/domain/interface/service"
+	"stmnplibrary/dto"
+	"stmnplibrary/log"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReadingListHandler struct {
+	readingListService service.ReadingListService
+}
+
+func FnReadingListHandler(service service.ReadingListService) *ReadingListHandler {
+	return &ReadingListHandler{readingListService: service}
+}
+
+// AddReadingList godoc
+// @Summary Add reading list
+// @Description Assign books, in reading order, to a class until due_at. Leaving sub_class or major empty targets every sub class or major of the class, starts_at defaults to today
+// @Accept json
+// @Produce json
+// @Param reading_list body dto.SaveReadingList true "Reading list"
+// @Param Idempotency-Key header string false "Key replaying the first response for retries"
+// @Tags Staff
+// @Success 201 {object} dto.Response "Successfully add reading list"
+// @Failure 400 {object} dto.Response "Incorrect client input or unknown book"
+// @Failure 500 {object} dto.Response "Internal server error"
+// @Router /api/v1/reading-lists [post]
+func (rh *ReadingListHandler) AddReadingList(c *gin.Context) {
+	var (
+		data   dto.SaveReadingList
+		ctx    = c.Request.Context()
+		resMsg = "failed add reading list"
+	)
+	if errMsg := utils.GetData(func() error { return c.ShouldBindJSON(&data) }, resMsg); errMsg != nil {
+		c.JSON(http.StatusBadRequest, errMsg)
+		return
+	}
+	result, err := rh.readingListService.AddReadingList(ctx, data)
+	if err != nil {
+		status, errMsg := utils.ValidateErr(err, resMsg, "")
+		if status == 500 {
+			log.LogHSR(ctx, resMsg, "add reading list", c.Request.URL.Path, c.Request.Method, err.Error())
+		}
+		c.JSON(status, errMsg)
+		return
+	}
+	c.JSON(http.StatusCreated, utils.Success("success add reading list", result, nil))
+}
+
+// UpdateReadingList godoc
+// @Summary Update reading list
+// @Description Replace a reading list and its books, starts_at is kept when left empty. A teacher can only update the lists they created
+// @Accept json
+// @Produce json
+// @Param id path int true "Reading list id"
+// @Param reading_list body dto.SaveReadingList true "Reading list"
+// @Tags Staff
+// @Success 200 {object} dto.Response "Successfully update reading list"
+// @Failure 400 {object} dto.Response "Incorrect client input or unknown book"
+// @Failure 403 {object} dto.Response "Reading list of another teacher"
+// @Failure 404 {object} dto.Response "Reading list not found"
+// @Failure 500 {object} dto.Response "Internal server error"
+// @Router /api/v1/reading-lists/{id} [put]
+func (rh *ReadingListHandler) UpdateReadingList(c *gin.Context) {
+	var (
+		data   dto.SaveReadingList
+		ctx    = c.Request.Context()
+		resMsg = "failed update reading list"
+	)
+	id, ok := utils.PathID(c, resMsg, "reading list")
+	if !ok {
+		return
+	}
+	if errMsg := utils.GetData(func() error { return c.ShouldBindJSON(&data) }, resMsg); errMsg != nil {
+		c.JSON(http.StatusBadRequest, errMsg)
+		return
+	}
+	result, err := rh.readingListService.UpdateReadingList(ctx, id, data)
+	if err != nil {
+		status, errMsg := utils.ValidateErr(err, resMsg, "")
+		if status == 500 {
+			log.LogHSR(ctx, resMsg, "update reading list", c.Request.URL.Path, c.Request.Method, err.Error())
+		}
+		c.JSON(status, errMsg)
+		return
+	}
+	c.JSON(http.StatusOK, utils.Success("success update reading list", result, nil))
+}
+
+// DeleteReadingList godoc
+// @Summary Delete reading list
+// @Description Delete a reading list, a teacher can only delete the lists they created
+// @Produce json
+// @Param id path int true "Reading list id"
+// @Tags Staff
+// @Success 200 {object} dto.Response "Successfully delete reading list"
+// @Failure 400 {object} dto.Response "Incorrect client input"
+// @Failure 403 {object} dto.Response "Reading list of another teacher"
+// @Failure 404 {object} dto.Response "Reading list not found"
+// @Failure 500 {object} dto.Response "Internal server error"
+// @Router /api/v1/reading-lists/{id} [delete]
+func (rh *ReadingListHandler) DeleteReadingList(c *gin.Context) {
+	var (
+		ctx    = c.Request.Context()
+		resMsg = "failed delete reading list"
+	)
+	id, ok := utils.PathID(c, resMsg, "reading list")
+	if !ok {
+		return
+	}
+	if err := rh.readingListService.DeleteReadingList(ctx, id); err != nil {
+		status, errMsg := utils.ValidateErr(err, resMsg, "")
+		if status == 500 {
+			log.LogHSR(ctx, resMsg, "delete reading list", c.Request.URL.Path, c.Request.Method, err.Error())
+		}
+		c.JSON(status, errMsg)
+		return
+	}
+	c.JSON(http.StatusOK, utils.Success("success delete reading list", nil, nil))
+}
+
+// GetReadingList godoc
+// @Summary Get reading list
+// @Description Get a reading list with its books in reading order
+// @Produce json
+// @Param id path int true "Reading list id"
+// @Tags Staff
+// @Success 200 {object} dto.Response "Successfully get reading list"
+// @Failure 400 {object} dto.Response "Incorrect client input"
+// @Failure 404 {object} dto.Response "Reading list not found"
+// @Failure 500 {object} dto.Response "Internal server error"
+// @Router /api/v1/reading-lists/{id} [get]
+func (rh *ReadingListHandler) GetReadingList(c *gin.Context) {
+	var (
+		ctx    = c.Request.Context()
+		resMsg = "failed get reading list"
+	)
+	id, ok := utils.PathID(c, resMsg, "reading list")
+	if !ok {
+		return
+	}
+	result, err := rh.readingListService.GetReadingList(ctx, id)
+	if err != nil {
+		status, errMsg := utils.ValidateErr(err, resMsg, "")
+		if status == 500 {
+			log.LogHSR(ctx, resMsg, "get reading list", c.Request.URL.Path, c.Request.Method, err.Error())
+		}
+		c.JSON(status, errMsg)
+		return
+	}
+	c.JSON(http.StatusOK, utils.Success("success get reading list", result, nil))
+}
+
+// GetReadingLists godoc
+// @Summary Get reading lists
+// @Description Get the reading lists of every class, newest first, mine only keeps the lists created by the logged in teacher
+// @Produce json
+// @Param class query string false "Class" Enums(X, XI, XII, XIII)
+// @Param major query string false "Major" Enums(RPL, SIJA, PSPT, TPTU, TEI, MEKA, TOI, TEK, IOP)
+// @Param mine query bool false "Only my reading lists"
+// @Param cursor query string false "Cursor, the next_cursor of the previous page"
+// @Param page_size query int false "Page size (default 35, max 100)"
+// @Tags Staff
+// @Success 200 {object} dto.Response "Successfully get reading lists"
+// @Failure 400 {object} dto.Response "Incorrect client input"
+// @Failure 500 {object} dto.Response "Internal server error"
+// @Router /api/v1/reading-lists [get]
+func (rh *ReadingListHandler) GetReadingLists(c *gin.Context) {
+	var (
+		filter dto.ReadingListFilter
+		ctx    = c.Request.Context()
+		resMsg = "failed get reading lists"
+	)
+	if errMsg := utils.GetData(func() error { return c.ShouldBindQuery(&filter) }, resMsg); errMsg != nil {
+		c.JSON(http.StatusBadRequest, errMsg)
+		return
+	}
+	lists, meta, err := rh.readingListService.GetReadingLists(ctx, filter)
+	if err != nil {
+		status, errMsg := utils.ValidateErr(err, resMsg, "")
+		if status == 500 {
+			log.LogHSR(ctx, resMsg, "get reading lists", c.Request.URL.Path, c.Request.Method, err.Error())
+		}
+		c.JSON(status, errMsg)
+		return
+	}
+	c.JSON(http.StatusOK, utils.Success("success get reading lists", lists, meta))
+}
+
+// GetReport godoc
+// @Summary Get reading list report
+// @Description Get the completion of a reading list by every active student it is assigned to, grouped per class. A book is read once a loan of it made since starts_at is returned
+// @Produce json
+// @Param id path int true "Reading list id"
+// @Tags Staff
+// @Success 200 {object} dto.Response "Successfully get reading list report"
+// @Failure 400 {object} dto.Response "Incorrect client input"
+// @Failure 404 {object} dto.Response "Reading list not found"
+// @Failure 500 {object} dto.Response "Internal server error"
+// @Router /api/v1/reading-lists/{id}/report [get]
+func (rh *ReadingListHandler) GetReport(c *gin.Context) {
+	var (
+		ctx    = c.Request.Context()
+		resMsg = "failed get reading list report"
+	)
+	id, ok := utils.PathID(c, resMsg, "reading list")
+	if !ok {
+		return
+	}
+	result, err := rh.readingListService.GetReport(ctx, id)
+	if err != nil {
+		status, errMsg := utils.ValidateErr(err, resMsg, "")
+		if status == 500 {
+			log.LogHSR(ctx, resMsg, "get reading list report", c.Request.URL.Path, c.Request.Method, err.Error())
+		}
+		c.JSON(status, errMsg)
+		return
+	}
+	c.JSON(http.StatusOK, utils.Success("success get reading list report", result, nil))
+}
+
+// GetStudentReadingLists godoc
+// @Summary Get my reading lists
+// @Description Get the reading lists assigned to the class, sub class and major of the logged in student with the progress on every book, the closest due first
+// @Produce json
+// @Tags student
+// @Success 200 {object} dto.Response "Successfully get reading lists"
+// @Failure 404 {object} dto.Response "Student not found"
+// @Failure 500 {object} dto.Response "Internal server error"
+// @Router /api/v1/student/reading-lists [get]
+func (rh *ReadingListHandler) GetStudentReadingLists(c *gin.Context) {
+	var (
+		ctx    = c.Request.Context()
+		resMsg = "failed get reading lists"
+	)
+	lists, err := rh.readingListService.GetStudentReadingLists(ctx)
+	if err != nil {
+		status, errMsg := utils.ValidateErr(err, resMsg, "")
+		if status == 500 {
+			log.LogHSR(ctx, resMsg, "get student reading lists", c.Request.URL.Path, c.Request.Method, err.Error())
+		}
+		c.JSON(status, errMsg)
+		return
+	}
+	c.JSON(http.StatusOK, utils.Success("success get reading lists", lists, nil))
+}
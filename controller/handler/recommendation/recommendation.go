@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"net/http"
+	"stmnplibrary/controller/handler/utils"
+	"stmnplibrary/domain/interface/service"
+	"stmnplibrary/log"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RecommendationHandler struct {
+	recommendationService service.RecommendationService
+}
+
+func FnRecommendationHandler(service service.RecommendationService) *RecommendationHandler {
+	return &RecommendationHandler{recommendationService: service}
+}
+
+// GetRecommendations godoc
+// @Summary Get recommendations
+// @Description Get books the student hasn't borrowed yet, borrowed by the students who borrowed the same books, sharing their categories or authors, or popular in their class and major. They are computed in the background and may be up to an hour old
+// @Produce json
+// @Tags student
+// @Success 200 {object} dto.Response "Successfully get recommendations"
+// @Failure 401 {object} dto.Response "Not logged in"
+// @Failure 500 {object} dto.Response "Internal server error"
+// @Router /api/v1/student/recommendations [get]
+func (rh *RecommendationHandler) GetRecommendations(c *gin.Context) {
+	var (
+		ctx    = c.Request.Context()
+		resMsg = "failed get recommendations"
+	)
+	result, err := rh.recommendationService.GetRecommendations(ctx)
+	if err != nil {
+		status, errMsg := utils.ValidateErr(err, resMsg, "")
+		if status == 500 {
+			log.LogHSR(ctx, resMsg, "get recommendations", c.Request.URL.Path, c.Request.Method, err.Error())
+		}
+		c.JSON(status, errMsg)
+		return
+	}
+	c.JSON(http.StatusOK, utils.Success("success get recommendations", result, nil))
+}
@@ -0,0 +1,204 @@
+package handler
+
+import (
+	"net/http"
+	"stmnplibrary/controller/handler/utils"
+	"stmnplibrary/domain/interface/service"
+	"stmnplibrary/dto"
+	"stmnplibrary/log"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReviewHandler struct {
+	reviewService service.ReviewService
+}
+
+func FnReviewHandler(service service.ReviewService) *ReviewHandler {
+	return &ReviewHandler{reviewService: service}
+}
+
+// PostReview godoc
+// @Summary Review book
+// @Description Rate a book from 1 to 5 stars with an optional text, only a book the student has borrowed and returned can be reviewed. Posting again replaces the review, a hidden review stays hidden
+// @Accept json
+// @Produce json
+// @Param id path int true "Book id"
+// @Param review body dto.PostReview true "Rating and text"
+// @Param Idempotency-Key header string false "Key replaying the first response for retries"
+// @Tags student
+// @Success 201 {object} dto.Response "Successfully review book"
+// @Failure 400 {object} dto.Response "Incorrect client input"
+// @Failure 403 {object} dto.Response "Book not returned by the student"
+// @Failure 404 {object} dto.Response "Book not found"
+// @Failure 500 {object} dto.Response "Internal server error"
+// @Router /api/v1/books/{id}/reviews [post]
+func (rh *ReviewHandler) PostReview(c *gin.Context) {
+	var (
+		data   dto.PostReview
+		ctx    = c.Request.Context()
+		resMsg = "failed review book"
+	)
+	id, ok := utils.PathID(c, resMsg, "book")
+	if !ok {
+		return
+	}
+	if errMsg := utils.GetData(func() error { return c.ShouldBindJSON(&data) }, resMsg); errMsg != nil {
+		c.JSON(http.StatusBadRequest, errMsg)
+		return
+	}
+	result, err := rh.reviewService.PostReview(ctx, id, data)
+	if err != nil {
+		status, errMsg := utils.ValidateErr(err, resMsg, "")
+		if status == 500 {
+			log.LogHSR(ctx, resMsg, "review book", c.Request.URL.Path, c.Request.Method, err.Error())
+		}
+		c.JSON(status, errMsg)
+		return
+	}
+	c.JSON(http.StatusCreated, utils.Success("success review book", result, nil))
+}
+
+// GetBookReviews godoc
+// @Summary Get book reviews
+// @Description Get the visible reviews of a book, newest first
+// @Produce json
+// @Param id path int true "Book id"
+// @Param cursor query string false "Cursor, the next_cursor of the previous page"
+// @Param page_size query int false "Page size (default 35, max 100)"
+// @Tags student
+// @Success 200 {object} dto.Response "Successfully get book reviews"
+// @Failure 400 {object} dto.Response "Incorrect client input"
+// @Failure 500 {object} dto.Response "Internal server error"
+// @Router /api/v1/books/{id}/reviews [get]
+func (rh *ReviewHandler) GetBookReviews(c *gin.Context) {
+	var (
+		query  dto.PageQuery
+		ctx    = c.Request.Context()
+		resMsg = "failed get book reviews"
+	)
+	id, ok := utils.PathID(c, resMsg, "book")
+	if !ok {
+		return
+	}
+	if errMsg := utils.GetData(func() error { return c.ShouldBindQuery(&query) }, resMsg); errMsg != nil {
+		c.JSON(http.StatusBadRequest, errMsg)
+		return
+	}
+	reviews, meta, err := rh.reviewService.GetBookReviews(ctx, id, query)
+	if err != nil {
+		status, errMsg := utils.ValidateErr(err, resMsg, "")
+		if status == 500 {
+			log.LogHSR(ctx, resMsg, "get book reviews", c.Request.URL.Path, c.Request.Method, err.Error())
+		}
+		c.JSON(status, errMsg)
+		return
+	}
+	c.JSON(http.StatusOK, utils.Success("success get book reviews", reviews, meta))
+}
+
+// GetReviews godoc
+// @Summary Get reviews
+// @Description Get the reviews of every book for moderation, newest first, filtered by book and status
+// @Produce json
+// @Param book_id query int false "Book id"
+// @Param status query string false "Status" Enums(visible, hidden)
+// @Param cursor query string false "Cursor, the next_cursor of the previous page"
+// @Param page_size query int false "Page size (default 35, max 100)"
+// @Tags Admin
+// @Success 200 {object} dto.Response "Successfully get reviews"
+// @Failure 400 {object} dto.Response "Incorrect client input"
+// @Failure 500 {object} dto.Response "Internal server error"
+// @Router /api/v1/reviews [get]
+func (rh *ReviewHandler) GetReviews(c *gin.Context) {
+	var (
+		filter dto.ReviewFilter
+		ctx    = c.Request.Context()
+		resMsg = "failed get reviews"
+	)
+	if errMsg := utils.GetData(func() error { return c.ShouldBindQuery(&filter) }, resMsg); errMsg != nil {
+		c.JSON(http.StatusBadRequest, errMsg)
+		return
+	}
+	reviews, meta, err := rh.reviewService.GetReviews(ctx, filter)
+	if err != nil {
+		status, errMsg := utils.ValidateErr(err, resMsg, "")
+		if status == 500 {
+			log.LogHSR(ctx, resMsg, "get reviews", c.Request.URL.Path, c.Request.Method, err.Error())
+		}
+		c.JSON(status, errMsg)
+		return
+	}
+	c.JSON(http.StatusOK, utils.Success("success get reviews", reviews, meta))
+}
+
+// HideReview godoc
+// @Summary Hide review
+// @Description Hide a review from the students and leave it out of the rating of the book
+// @Accept json
+// @Produce json
+// @Param id path int true "Review id"
+// @Param reason body dto.HideReview true "Why the review is hidden"
+// @Tags Admin
+// @Success 200 {object} dto.Response "Successfully hide review"
+// @Failure 400 {object} dto.Response "Incorrect client input"
+// @Failure 404 {object} dto.Response "Review not found"
+// @Failure 500 {object} dto.Response "Internal server error"
+// @Router /api/v1/reviews/{id}/hide [post]
+func (rh *ReviewHandler) HideReview(c *gin.Context) {
+	var (
+		data   dto.HideReview
+		ctx    = c.Request.Context()
+		resMsg = "failed hide review"
+	)
+	id, ok := utils.PathID(c, resMsg, "review")
+	if !ok {
+		return
+	}
+	if errMsg := utils.GetData(func() error { return c.ShouldBindJSON(&data) }, resMsg); errMsg != nil {
+		c.JSON(http.StatusBadRequest, errMsg)
+		return
+	}
+	result, err := rh.reviewService.HideReview(ctx, id, data)
+	if err != nil {
+		status, errMsg := utils.ValidateErr(err, resMsg, "")
+		if status == 500 {
+			log.LogHSR(ctx, resMsg, "hide review", c.Request.URL.Path, c.Request.Method, err.Error())
+		}
+		c.JSON(status, errMsg)
+		return
+	}
+	c.JSON(http.StatusOK, utils.Success("success hide review", result, nil))
+}
+
+// UnhideReview godoc
+// @Summary Unhide review
+// @Description Show a hidden review to the students again and count it in the rating of the book
+// @Produce json
+// @Param id path int true "Review id"
+// @Tags Admin
+// @Success 200 {object} dto.Response "Successfully unhide review"
+// @Failure 400 {object} dto.Response "Incorrect client input"
+// @Failure 404 {object} dto.Response "Review not found"
+// @Failure 500 {object} dto.Response "Internal server error"
+// @Router /api/v1/reviews/{id}/unhide [post]
+func (rh *ReviewHandler) UnhideReview(c *gin.Context) {
+	var (
+		ctx    = c.Request.Context()
+		resMsg = "failed unhide review"
+	)
+	id, ok := utils.PathID(c, resMsg, "review")
+	if !ok {
+		return
+	}
+	result, err := rh.reviewService.UnhideReview(ctx, id)
+	if err != nil {
+		status, errMsg := utils.ValidateErr(err, resMsg, "")
+		if status == 500 {
+			log.LogHSR(ctx, resMsg, "unhide review", c.Request.URL.Path, c.Request.Method, err.Error())
+		}
+		c.JSON(status, errMsg)
+		return
+	}
+	c.JSON(http.StatusOK, utils.Success("success unhide review", result, nil))
+}
@@ -0,0 +1,204 @@
+package handler
+
+import (
+	"net/http"
+	"stmnplibrary/controller/handler/utils"
+	"stmnplibrary/domain/interface/service"
+	"stmnplibrary/dto"
+	"stmnplibrary/log"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SuggestionHandler struct {
+	suggestionService service.SuggestionService
+}
+
+func FnSuggestionHandler(service service.SuggestionService) *SuggestionHandler {
+	return &SuggestionHandler{suggestionService: service}
+}
+
+// AddSuggestion godoc
+// @Summary Suggest book
+// @Description Ask the library to buy a book missing from the catalog, the student votes for it too. A book in the catalog or already suggested can't be suggested again
+// @Accept json
+// @Produce json
+// @Param suggestion body dto.AddSuggestion true "Suggested book"
+// @Param Idempotency-Key header string false "Key replaying the first response for retries"
+// @Tags student
+// @Success 201 {object} dto.Response "Successfully suggest book"
+// @Failure 400 {object} dto.Response "Incorrect client input"
+// @Failure 409 {object} dto.Response "Book in the catalog or already suggested"
+// @Failure 500 {object} dto.Response "Internal server error"
+// @Router /api/v1/suggestions [post]
+func (sh *SuggestionHandler) AddSuggestion(c *gin.Context) {
+	var (
+		data   dto.AddSuggestion
+		ctx    = c.Request.Context()
+		resMsg = "failed suggest book"
+	)
+	if errMsg := utils.GetData(func() error { return c.ShouldBindJSON(&data) }, resMsg); errMsg != nil {
+		c.JSON(http.StatusBadRequest, errMsg)
+		return
+	}
+	result, err := sh.suggestionService.AddSuggestion(ctx, data)
+	if err != nil {
+		status, errMsg := utils.ValidateErr(err, resMsg, "")
+		if status == 500 {
+			log.LogHSR(ctx, resMsg, "suggest book", c.Request.URL.Path, c.Request.Method, err.Error())
+		}
+		c.JSON(status, errMsg)
+		return
+	}
+	c.JSON(http.StatusCreated, utils.Success("success suggest book", result, nil))
+}
+
+// GetSuggestions godoc
+// @Summary Get suggestions
+// @Description Get the suggested books, the most voted first unless sorted by newest
+// @Produce json
+// @Param status query string false "Status" Enums(submitted, under_review, ordered, received, rejected)
+// @Param sort query string false "Sort" Enums(votes, newest)
+// @Param cursor query string false "Cursor, the next_cursor of the previous page"
+// @Param page_size query int false "Page size (default 35, max 100)"
+// @Tags student
+// @Success 200 {object} dto.Response "Successfully get suggestions"
+// @Failure 400 {object} dto.Response "Incorrect client input"
+// @Failure 500 {object} dto.Response "Internal server error"
+// @Router /api/v1/suggestions [get]
+func (sh *SuggestionHandler) GetSuggestions(c *gin.Context) {
+	var (
+		filter dto.SuggestionFilter
+		ctx    = c.Request.Context()
+		resMsg = "failed get suggestions"
+	)
+	if errMsg := utils.GetData(func() error { return c.ShouldBindQuery(&filter) }, resMsg); errMsg != nil {
+		c.JSON(http.StatusBadRequest, errMsg)
+		return
+	}
+	suggestions, meta, err := sh.suggestionService.GetSuggestions(ctx, filter)
+	if err != nil {
+		status, errMsg := utils.ValidateErr(err, resMsg, "")
+		if status == 500 {
+			log.LogHSR(ctx, resMsg, "get suggestions", c.Request.URL.Path, c.Request.Method, err.Error())
+		}
+		c.JSON(status, errMsg)
+		return
+	}
+	c.JSON(http.StatusOK, utils.Success("success get suggestions", suggestions, meta))
+}
+
+// VoteSuggestion godoc
+// @Summary Vote suggestion
+// @Description Ask for a suggested book too, voting again changes nothing. Every voter is notified when the book is received and the first ones get a hold on it
+// @Produce json
+// @Param id path int true "Suggestion id"
+// @Param Idempotency-Key header string false "Key replaying the first response for retries"
+// @Tags student
+// @Success 200 {object} dto.Response "Successfully vote suggestion"
+// @Failure 400 {object} dto.Response "Incorrect client input"
+// @Failure 404 {object} dto.Response "Suggestion not found"
+// @Failure 409 {object} dto.Response "Suggestion closed"
+// @Failure 500 {object} dto.Response "Internal server error"
+// @Router /api/v1/suggestions/{id}/votes [post]
+func (sh *SuggestionHandler) VoteSuggestion(c *gin.Context) {
+	var (
+		ctx    = c.Request.Context()
+		resMsg = "failed vote suggestion"
+	)
+	id, ok := utils.PathID(c, resMsg, "suggestion")
+	if !ok {
+		return
+	}
+	result, err := sh.suggestionService.VoteSuggestion(ctx, id)
+	if err != nil {
+		status, errMsg := utils.ValidateErr(err, resMsg, "")
+		if status == 500 {
+			log.LogHSR(ctx, resMsg, "vote suggestion", c.Request.URL.Path, c.Request.Method, err.Error())
+		}
+		c.JSON(status, errMsg)
+		return
+	}
+	c.JSON(http.StatusOK, utils.Success("success vote suggestion", result, nil))
+}
+
+// MoveSuggestion godoc
+// @Summary Move suggestion
+// @Description Move a suggestion from submitted to under_review then ordered, or reject it with a note until it is received
+// @Accept json
+// @Produce json
+// @Param id path int true "Suggestion id"
+// @Param status body dto.MoveSuggestion true "Next status"
+// @Tags Admin
+// @Success 200 {object} dto.Response "Successfully move suggestion"
+// @Failure 400 {object} dto.Response "Incorrect client input"
+// @Failure 404 {object} dto.Response "Suggestion not found"
+// @Failure 409 {object} dto.Response "Status not reachable from the current one"
+// @Failure 500 {object} dto.Response "Internal server error"
+// @Router /api/v1/suggestions/{id}/status [post]
+func (sh *SuggestionHandler) MoveSuggestion(c *gin.Context) {
+	var (
+		data   dto.MoveSuggestion
+		ctx    = c.Request.Context()
+		resMsg = "failed move suggestion"
+	)
+	id, ok := utils.PathID(c, resMsg, "suggestion")
+	if !ok {
+		return
+	}
+	if errMsg := utils.GetData(func() error { return c.ShouldBindJSON(&data) }, resMsg); errMsg != nil {
+		c.JSON(http.StatusBadRequest, errMsg)
+		return
+	}
+	result, err := sh.suggestionService.MoveSuggestion(ctx, id, data)
+	if err != nil {
+		status, errMsg := utils.ValidateErr(err, resMsg, "")
+		if status == 500 {
+			log.LogHSR(ctx, resMsg, "move suggestion", c.Request.URL.Path, c.Request.Method, err.Error())
+		}
+		c.JSON(status, errMsg)
+		return
+	}
+	c.JSON(http.StatusOK, utils.Success("success move suggestion", result, nil))
+}
+
+// ReceiveSuggestion godoc
+// @Summary Receive suggestion
+// @Description Add an ordered suggestion to the catalog as a book, or link the book already added under its isbn. Every requester is notified with a SuggestionReceived event and the first ones, as many as the available copies, get a hold on the book for LIBRARY_HOLD_TTL
+// @Accept json
+// @Produce json
+// @Param id path int true "Suggestion id"
+// @Param book body dto.ReceiveSuggestion true "Book details"
+// @Param Idempotency-Key header string false "Key replaying the first response for retries"
+// @Tags Admin
+// @Success 200 {object} dto.Response "Successfully receive suggestion"
+// @Failure 400 {object} dto.Response "Incorrect client input"
+// @Failure 404 {object} dto.Response "Suggestion not found"
+// @Failure 409 {object} dto.Response "Suggestion not ordered"
+// @Failure 500 {object} dto.Response "Internal server error"
+// @Router /api/v1/suggestions/{id}/receive [post]
+func (sh *SuggestionHandler) ReceiveSuggestion(c *gin.Context) {
+	var (
+		data   dto.ReceiveSuggestion
+		ctx    = c.Request.Context()
+		resMsg = "failed receive suggestion"
+	)
+	id, ok := utils.PathID(c, resMsg, "suggestion")
+	if !ok {
+		return
+	}
+	if errMsg := utils.GetData(func() error { return c.ShouldBindJSON(&data) }, resMsg); errMsg != nil {
+		c.JSON(http.StatusBadRequest, errMsg)
+		return
+	}
+	result, err := sh.suggestionService.ReceiveSuggestion(ctx, id, data)
+	if err != nil {
+		status, errMsg := utils.ValidateErr(err, resMsg, "")
+		if status == 500 {
+			log.LogHSR(ctx, resMsg, "receive suggestion", c.Request.URL.Path, c.Request.Method, err.Error())
+		}
+		c.JSON(status, errMsg)
+		return
+	}
+	c.JSON(http.StatusOK, utils.Success("success receive suggestion", result, nil))
+}
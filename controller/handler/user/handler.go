@@ -1,10 +1,10 @@
 package handler
 
 import (
-	"fmt"
 	"net/http"
-	"strconv"
 
+	"stmnplibrary/apperr"
+	"stmnplibrary/config"
 	"stmnplibrary/domain/interface/service"
 	"stmnplibrary/dto"
 	"stmnplibrary/constanta"
@@ -16,36 +16,14 @@ import (
 
 type UserHandler struct {
 	userService service.UserService
+	cookie      config.Cookie
 }
 
-func FnUserHandler(service service.UserService) *UserHandler {
-	return &UserHandler{userService: service}
-}
-
-func delCookieToken(c *gin.Context, key string) {
-	const maxAge = -1
-	c.SetSameSite(http.SameSiteStrictMode)
-	http.SetCookie(c.Writer, &http.Cookie{
-		Name:     key,
-		Value:    "token",
-		Path:     "/",
-		Domain:   "localhost",
-		MaxAge:   maxAge,
-		Secure:   false,
-		HttpOnly: true,
-		SameSite: http.SameSiteStrictMode,
-	})
-}
-
-func getPage(c *gin.Context) (int, error) {
-	page, err := strconv.Atoi(c.Query("page"))
-	if err != nil {
-		return 1, nil
-	}
-	if page == 0 {
-		return 1, nil
+func FnUserHandler(service service.UserService, cookie config.Cookie) *UserHandler {
+	return &UserHandler{
+		userService: service,
+		cookie:      cookie,
 	}
-	return page, err
 }
 
 // Logout godoc
@@ -56,7 +34,7 @@ func getPage(c *gin.Context) (int, error) {
 // @Success 200 {object} dto.Response "Successfully confirm logout"
 // @Failure 400 {object} dto.Response "Incorrect client input"
 // @Failure 500 {object} dto.Response "Internal server error"
-// @Router /student/logout [get]
+// @Router /api/v1/auth/logout [post]
 func (uh *UserHandler) Logout(c *gin.Context) {
 	var ctx = c.Request.Context()
 	if err := uh.userService.Logout(ctx); err != nil {
@@ -68,12 +46,9 @@ func (uh *UserHandler) Logout(c *gin.Context) {
 		c.JSON(status, errMsg)
 		return
 	}
-	delCookieToken(c, string(constanta.TokenA))
-	delCookieToken(c, string(constanta.TokenR))
-	c.JSON(http.StatusCreated, &dto.Response{
-		Status:  "true / success",
-		Message: "success logout",
-	})
+	utils.DelCookieToken(c, uh.cookie, string(constanta.TokenA))
+	utils.DelCookieToken(c, uh.cookie, string(constanta.TokenR))
+	c.JSON(http.StatusCreated, utils.Success("success logout", nil, nil))
 }
 
 // Register godoc
@@ -86,9 +61,8 @@ func (uh *UserHandler) Logout(c *gin.Context) {
 // @Success 200 {object} dto.Response "Successfully register"
 // @Failure 400 {object} dto.Response "Incorrect client input"
 // @Failure 500 {object} dto.Response "Internal server error"
-// @Router /register [post]
+// @Router /api/v1/auth/register [post]
 func (uh *UserHandler) Register(c *gin.Context) {
-	const status = "failed / error"
 	const resMsg = "failed register"
 	var data dto.Students
 	ctx := c.Request.Context()
@@ -103,98 +77,106 @@ func (uh *UserHandler) Register(c *gin.Context) {
 		return
 	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, &dto.Response{
-			Status:  status,
-			Message: resMsg,
-			Error:   dto.Errors{Error: "an error occurred"},
-		})
-		log.LogHSR(ctx, resMsg, "register", c.Request.URL.Path, c.Request.Method, err.Error())
+		status, errMsg := utils.ValidateErr(err, resMsg, "")
+		if status == 500 {
+			log.LogHSR(ctx, resMsg, "register", c.Request.URL.Path, c.Request.Method, err.Error())
+		}
+		c.JSON(status, errMsg)
 		return
 	}
-	c.JSON(http.StatusCreated, &dto.Response{
-		Status:  "true / success",
-		Message: "success register",
-	})
+	c.JSON(http.StatusCreated, utils.Success("success register", nil, nil))
+}
+
+// ListBooks godoc
+// @Summary Get books
+// @Description Get books, filtered by author or by categories
+// @Produce json
+// @Param author query string false "Author"
+// @Param category query []string false "List category" collectionFormat(multi)
+// @Param cursor query string false "Cursor, the next_cursor of the previous page"
+// @Param page_size query int false "Page size (default 35, max 100)"
+// @Param sort query string false "Sort order (default title)" Enums(title, author, newest, availability, rating)
+// @Tags student
+// @Success 200 {object} dto.Response "Successfully get books"
+// @Failure 400 {object} dto.Response "Incorrect client input"
+// @Failure 500 {object} dto.Response "Internal server error"
+// @Router /api/v1/books [get]
+func (uh *UserHandler) ListBooks(c *gin.Context) {
+	switch {
+	case c.Query("author") != "":
+		uh.GetBooksByAuthor(c)
+	case len(c.QueryArray("category")) > 0:
+		uh.GetBooksByCategory(c)
+	default:
+		uh.GetBooks(c)
+	}
 }
 
 // GetBooks godoc
 // @Summary Get books 
 // @Description Get all books from db
 // @Produce json
-// @Param page query int true "Page"
+// @Param cursor query string false "Cursor, the next_cursor of the previous page"
+// @Param page_size query int false "Page size (default 35, max 100)"
+// @Param sort query string false "Sort order (default title)" Enums(title, author, newest, availability, rating)
 // @Tags student
 // @Success 200 {object} dto.Response "Successfully get books"
 // @Failure 400 {object} dto.Response "Incorrect client input"
 // @Failure 500 {object} dto.Response "Internal server error"
+// @Deprecated
 // @Router /student/books [get]
 func (uh *UserHandler) GetBooks(c *gin.Context) {
 	const resMsg = "failed get books"
 	var ctx = c.Request.Context()
-	page, err := getPage(c)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.Response{
-			Status:  resMsg,
-			Message: err.Error(),
-		})
-		return
-	}
-	books, err := uh.userService.GetBooks(ctx, page)
-	if (len(books) == 0 || books == nil) && err == nil {
-		c.JSON(http.StatusOK, dto.Response{
-			Status: "success",
-			Message: "no books found",
-			Data: []interface{}{},
-		})
+	var query dto.BookQuery
+	if errMsg := utils.GetData(func() error { return c.ShouldBindQuery(&query) }, resMsg); errMsg != nil {
+		c.JSON(http.StatusBadRequest, errMsg)
 		return
 	}
+	books, meta, err := uh.userService.GetBooks(ctx, query)
 	if err != nil {
 		status, errMsg := utils.ValidateErr(err, resMsg, "books not found")
 		if status == 500 {
 			log.LogHSR(ctx, resMsg, "get_books", c.Request.URL.Path, c.Request.Method, err.Error())
 		}
 		c.JSON(status, errMsg)
-		fmt.Printf("\nini status: %d\n", status)
 		return
 	}
-	c.JSON(http.StatusOK, books)
+	if len(books) == 0 {
+		c.JSON(http.StatusOK, utils.Success("no books found", []dto.Books{}, meta))
+		return
+	}
+	c.JSON(http.StatusOK, utils.Success("success get books", books, meta))
 }
 
 // GetBooksByAuthor godoc
 // @Summary Get books 
 // @Description Get books with author as filter
 // @Produce json
-// @Param page query int true "Page"
+// @Param cursor query string false "Cursor, the next_cursor of the previous page"
+// @Param page_size query int false "Page size (default 35, max 100)"
+// @Param sort query string false "Sort order (default title)" Enums(title, author, newest, availability, rating)
 // @Param author query string true "Author"
 // @Tags student
 // @Success 200 {object} dto.Response "Successfully get books by author"
 // @Failure 400 {object} dto.Response "Incorrect client input"
 // @Failure 500 {object} dto.Response "Internal server error"
+// @Deprecated
 // @Router /student/books/author [get]
 func (uh *UserHandler) GetBooksByAuthor(c *gin.Context) {
 	const resMsg = "failed get books"
 	var ctx = c.Request.Context()
 	author := c.Query("author")
 	if author == "" {
-		c.JSON(400, "author must be filled")
+		c.JSON(http.StatusBadRequest, utils.Fail(resMsg, apperr.CodeValidation, "author must be filled"))
 		return
 	}
-	page, err := getPage(c)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.Response{
-			Status:  resMsg,
-			Message: err.Error(),
-		})
-		return
-	}
-	books, err := uh.userService.GetBooksByAuthor(ctx, author, page)
-	if (len(books) == 0 || books == nil) && err == nil {
-		c.JSON(http.StatusOK, dto.Response{
-			Status: "success",
-			Message: "no books found",
-			Data: []interface{}{},
-		})
+	var query dto.BookQuery
+	if errMsg := utils.GetData(func() error { return c.ShouldBindQuery(&query) }, resMsg); errMsg != nil {
+		c.JSON(http.StatusBadRequest, errMsg)
 		return
 	}
+	books, meta, err := uh.userService.GetBooksByAuthor(ctx, author, query)
 	if err != nil {
 		status, errMsg := utils.ValidateErr(err, resMsg, "author not found")
 		if status == 500 {
@@ -203,45 +185,41 @@ func (uh *UserHandler) GetBooksByAuthor(c *gin.Context) {
 		c.JSON(status, errMsg)
 		return
 	}
-	c.JSON(200, books)
+	if len(books) == 0 {
+		c.JSON(http.StatusOK, utils.Success("no books found", []dto.Books{}, meta))
+		return
+	}
+	c.JSON(http.StatusOK, utils.Success("success get books", books, meta))
 }
 
 // GetBooksByCategory godoc
 // @Summary Get books 
 // @Description Get books with category as filter
 // @Produce json
-// @Param page query int true "Page"
+// @Param cursor query string false "Cursor, the next_cursor of the previous page"
+// @Param page_size query int false "Page size (default 35, max 100)"
+// @Param sort query string false "Sort order (default title)" Enums(title, author, newest, availability, rating)
 // @Param category query []string true "List category" collectionFormat(multi) minItems(1)
 // @Tags student
 // @Success 200 {object} dto.Response "Successfully get books by category"
 // @Failure 400 {object} dto.Response "Incorrect client input"
 // @Failure 500 {object} dto.Response "Internal server error"
+// @Deprecated
 // @Router /student/books/category [get]
 func (uh *UserHandler) GetBooksByCategory(c *gin.Context) {
 	const resMsg = "failed get books"
 	var ctx = c.Request.Context()
 	category := c.QueryArray("category")
 	if len(category) == 0 {
-		c.JSON(400, "category must be filled")
+		c.JSON(http.StatusBadRequest, utils.Fail(resMsg, apperr.CodeValidation, "category must be filled"))
 		return
 	}
-	page, err := getPage(c)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.Response{
-			Status:  resMsg,
-			Message: err.Error(),
-		})
-		return
-	}
-	books, err := uh.userService.GetBooksByCategory(ctx, category, page)
-	if (len(books) == 0 || books == nil) && err == nil {
-		c.JSON(http.StatusOK, dto.Response{
-			Status: "success",
-			Message: "no books found",
-			Data: []interface{}{},
-		})
+	var query dto.BookQuery
+	if errMsg := utils.GetData(func() error { return c.ShouldBindQuery(&query) }, resMsg); errMsg != nil {
+		c.JSON(http.StatusBadRequest, errMsg)
 		return
 	}
+	books, meta, err := uh.userService.GetBooksByCategory(ctx, category, query)
 	if err != nil {
 		status, errMsg := utils.ValidateErr(err, resMsg, "category not found")
 		if status == 500 {
@@ -250,7 +228,11 @@ func (uh *UserHandler) GetBooksByCategory(c *gin.Context) {
 		c.JSON(status, errMsg)
 		return
 	}
-	c.JSON(200, books)
+	if len(books) == 0 {
+		c.JSON(http.StatusOK, utils.Success("no books found", []dto.Books{}, meta))
+		return
+	}
+	c.JSON(http.StatusOK, utils.Success("success get books", books, meta))
 }
 
 // Loan godoc
@@ -259,23 +241,54 @@ func (uh *UserHandler) GetBooksByCategory(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param loan body dto.Loan true "Loan data, with book id and returned time as a value"
+// @Param Idempotency-Key header string false "Key replaying the first response for retries"
 // @Tags student
 // @Success 200 {object} dto.Response "Successfully loan a book"
 // @Failure 400 {object} dto.Response "Incorrect client input"
 // @Failure 500 {object} dto.Response "Internal server error"
+// @Deprecated
 // @Router /student/book/loan [post]
 func (uh *UserHandler) Loan(c *gin.Context) {
-	const status = "failed / error"
-	const resMsg = "failed borrow"
 	var data dto.Loan
-	ctx := c.Request.Context()
 	errMsg := utils.GetData(func() error { return c.ShouldBindJSON(&data) }, "failed loan")
 	if errMsg != nil {
 		c.JSON(http.StatusBadRequest, errMsg)
 		return
 	}
-	err := uh.userService.Loan(ctx, data)
-	if err != nil {
+	uh.loan(c, data)
+}
+
+// LoanBook godoc
+// @Summary Loan book
+// @Description Borrow a book until the end of the due date in the library timezone, from today to 7 days ahead. A due date on a closed day is pushed to the next open day
+// @Accept json
+// @Produce json
+// @Param id path int true "Book id"
+// @Param loan body dto.LoanBook true "Due date, dd-mm-yyyy or yyyy-mm-dd"
+// @Param Idempotency-Key header string false "Key replaying the first response for retries"
+// @Tags student
+// @Success 201 {object} dto.Response "Successfully loan a book"
+// @Failure 400 {object} dto.Response "Incorrect client input"
+// @Failure 500 {object} dto.Response "Internal server error"
+// @Router /api/v1/books/{id}/loans [post]
+func (uh *UserHandler) LoanBook(c *gin.Context) {
+	const resMsg = "failed loan"
+	id, ok := utils.PathID(c, resMsg, "book")
+	if !ok {
+		return
+	}
+	var data dto.LoanBook
+	if errMsg := utils.GetData(func() error { return c.ShouldBindJSON(&data) }, resMsg); errMsg != nil {
+		c.JSON(http.StatusBadRequest, errMsg)
+		return
+	}
+	uh.loan(c, dto.Loan{ID: id, ReturnedAt: data.ReturnedAt})
+}
+
+func (uh *UserHandler) loan(c *gin.Context, data dto.Loan) {
+	const resMsg = "failed borrow"
+	var ctx = c.Request.Context()
+	if err := uh.userService.Loan(ctx, data); err != nil {
 		status, errMsg := utils.ValidateErr(err, resMsg, "")
 		if status == 500 {
 			log.LogHSR(ctx, resMsg, "loan", c.Request.URL.Path, c.Request.Method, err.Error())
@@ -283,8 +296,5 @@ func (uh *UserHandler) Loan(c *gin.Context) {
 		c.JSON(status, errMsg)
 		return
 	}
-	c.JSON(http.StatusCreated, &dto.Response{
-		Status:  "true / success",
-		Message: "success borrow",
-	})
+	c.JSON(http.StatusCreated, utils.Success("success borrow", nil, nil))
 }
@@ -1,24 +1,62 @@
 package utils
 
 import (
+	"net/http"
+	"stmnplibrary/apperr"
+	"stmnplibrary/config"
 	"stmnplibrary/dto"
-	"strings"
+	"strconv"
+	"time"
 
+	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 )
 
+func SetCookieToken(c *gin.Context, cfg config.Cookie, key string, token string, ttl time.Duration) {
+	c.SetSameSite(http.SameSiteStrictMode)
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     key,
+		Value:    token,
+		Path:     "/",
+		Domain:   cfg.Domain,
+		MaxAge:   int(ttl.Seconds()),
+		Secure:   cfg.Secure,
+		HttpOnly: true,
+		SameSite: http.SameSiteStrictMode,
+	})
+}
+
+func DelCookieToken(c *gin.Context, cfg config.Cookie, key string) {
+	const maxAge = -1
+	c.SetSameSite(http.SameSiteStrictMode)
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     key,
+		Value:    "token",
+		Path:     "/",
+		Domain:   cfg.Domain,
+		MaxAge:   maxAge,
+		Secure:   cfg.Secure,
+		HttpOnly: true,
+		SameSite: http.SameSiteStrictMode,
+	})
+}
+
 func getMsgType(err validator.FieldError) string {
 	switch err.Tag() {
 	case "required":
 		return "fields must be filled in"
 	case "email":
 		return "incorrect email format"
+	case "http_url":
+		return "must be a valid http(s) url"
 	case "number":
 		return "must be a number"
 	case "min":
 		return "exceeds the minimum limit: " + err.Param()
 	case "max":
 		return "exceeds the maximum limit: " + err.Param()
+	case "oneof":
+		return "must be one of: " + err.Param()
 	case "gt":
 		return "len category id must > 0"
 	case "unique":
@@ -29,12 +67,11 @@ func getMsgType(err validator.FieldError) string {
 }
 
 func GetData(fn func() error, message string) *dto.Response {
-	const status = "false / error"
 	const jserr = "json format is wrong"
 	if err := fn(); err != nil {
 		errMsg := &dto.Response{
-			Status:  status,
 			Message: message,
+			Error:   dto.Errors{Code: apperr.CodeValidation},
 		}
 		if ve, ok := err.(validator.ValidationErrors); ok {
 			for _, i := range ve {
@@ -52,10 +89,9 @@ func GetData(fn func() error, message string) *dto.Response {
 }
 
 func ErrorMsg(resMsg string, msg []string) *dto.Response {
-	const status = "false / failed"
 	errBS := &dto.Response{
-		Status:  status,
 		Message: resMsg,
+		Error:   dto.Errors{Code: apperr.CodeValidation},
 	}
 	for _, v := range msg {
 		errBS.Error.Service = append(errBS.Error.Service, dto.Service{
@@ -65,39 +101,54 @@ func ErrorMsg(resMsg string, msg []string) *dto.Response {
 	return errBS
 }
 
+// ValidateErr maps an error to its http status and response body, errMsg
+// replaces the message of a no_data_affected error when it is not empty.
 func ValidateErr(err error, resMsg string, errMsg string) (int, *dto.Response) {
-	const status = "false / failed"
-	if strings.Contains(err.Error(), "internal server error: ") || strings.Contains(err.Error(), "operation" ) {
-		return 500, &dto.Response{
-			Status:  status,
-			Message: resMsg,
-			Error:   dto.Errors{Error: "an error occurred"},
-		}
+	var (
+		e   = apperr.From(err)
+		msg = e.Public()
+	)
+	if e.Code == apperr.CodeNoDataAffected && errMsg != "" {
+		msg = errMsg
 	}
-	if strings.Contains(err.Error(), "no data found") {
-		return 404, &dto.Response{
-			Status:  status,
-			Message: resMsg,
-			Error:   dto.Errors{Error: err.Error()},
-		}
+	return e.Kind.Status(), &dto.Response{
+		Message: resMsg,
+		Error: dto.Errors{
+			Code:  e.Code,
+			Error: msg,
+		},
 	}
-	if strings.Contains(err.Error(), "no data affected") {
-		return 400, &dto.Response{
-			Status:  status,
-			Message: resMsg,
-			Error:   dto.Errors{Error: errMsg},
-		}
+}
+
+// Success builds the envelope of a successful request, meta is only set by
+// list endpoints.
+func Success(message string, data any, meta *dto.Meta) *dto.Response {
+	return &dto.Response{
+		Success: true,
+		Message: message,
+		Data:    data,
+		Meta:    meta,
 	}
-	if strings.Contains(err.Error(), "duplicate request") {
-		return 409, &dto.Response{
-			Status: status,
-			Message: resMsg,
-			Error: dto.Errors{Error: err.Error()},
-		}
+}
+
+// Fail builds the envelope of a request rejected before reaching a service.
+func Fail(message string, code string, reason string) *dto.Response {
+	return &dto.Response{
+		Message: message,
+		Error: dto.Errors{
+			Code:  code,
+			Error: reason,
+		},
 	}
-	return 400, &dto.Response{
-		Status:  status,
-		Message: resMsg,
-		Error:   dto.Errors{Error: err.Error()},
+}
+
+// PathID reads the id path parameter, writing the bad request response when
+// it isn't a positive number, name says which id it is in the message.
+func PathID(c *gin.Context, resMsg string, name string) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, Fail(resMsg, apperr.CodeValidation, name+" id must be a number"))
+		return 0, false
 	}
+	return id, true
 }
@@ -0,0 +1,194 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"stmnplibrary/apperr"
+	"stmnplibrary/controller/handler/utils"
+	"stmnplibrary/domain/interface/service"
+	"stmnplibrary/dto"
+	"stmnplibrary/log"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookHandler struct {
+	webhookService service.WebhookService
+}
+
+func FnWebhookHandler(service service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{webhookService: service}
+}
+
+func getID(c *gin.Context, name string) (int, error) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("%s id must be a number", name)
+	}
+	return id, nil
+}
+
+// AddWebhook godoc
+// @Summary Add webhook
+// @Description Subscribe a school system to loan, return and sanction events, every delivery is signed with HMAC-SHA256 of the secret in X-Webhook-Signature. The secret is generated when left empty and only returned here
+// @Accept json
+// @Produce json
+// @Param webhook body dto.AddWebhook true "Webhook subscription"
+// @Param Idempotency-Key header string false "Key replaying the first response for retries"
+// @Tags Admin
+// @Success 201 {object} dto.Response "Successfully add webhook"
+// @Failure 400 {object} dto.Response "Incorrect client input"
+// @Failure 500 {object} dto.Response "Internal server error"
+// @Router /api/v1/webhooks [post]
+func (wh *WebhookHandler) AddWebhook(c *gin.Context) {
+	var (
+		data   dto.AddWebhook
+		ctx    = c.Request.Context()
+		resMsg = "failed add webhook"
+	)
+	if errMsg := utils.GetData(func() error { return c.ShouldBindJSON(&data) }, resMsg); errMsg != nil {
+		c.JSON(http.StatusBadRequest, errMsg)
+		return
+	}
+	result, err := wh.webhookService.AddWebhook(ctx, data)
+	if err != nil {
+		status, errMsg := utils.ValidateErr(err, resMsg, "")
+		if status == 500 {
+			log.LogHSR(ctx, resMsg, "add webhook", c.Request.URL.Path, c.Request.Method, err.Error())
+		}
+		c.JSON(status, errMsg)
+		return
+	}
+	c.JSON(http.StatusCreated, utils.Success("success add webhook", result, nil))
+}
+
+// GetWebhooks godoc
+// @Summary Get webhooks
+// @Description Get every webhook subscription, deleted ones are inactive
+// @Produce json
+// @Tags Admin
+// @Success 200 {object} dto.Response "Successfully get webhooks"
+// @Failure 500 {object} dto.Response "Internal server error"
+// @Router /api/v1/webhooks [get]
+func (wh *WebhookHandler) GetWebhooks(c *gin.Context) {
+	var (
+		ctx    = c.Request.Context()
+		resMsg = "failed get webhooks"
+	)
+	webhooks, err := wh.webhookService.GetWebhooks(ctx)
+	if err != nil {
+		status, errMsg := utils.ValidateErr(err, resMsg, "")
+		if status == 500 {
+			log.LogHSR(ctx, resMsg, "get webhooks", c.Request.URL.Path, c.Request.Method, err.Error())
+		}
+		c.JSON(status, errMsg)
+		return
+	}
+	c.JSON(http.StatusOK, utils.Success("success get webhooks", webhooks, nil))
+}
+
+// DeleteWebhook godoc
+// @Summary Delete webhook
+// @Description Deactivate a webhook subscription, its pending deliveries are no longer sent and its delivery log is kept
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Tags Admin
+// @Success 200 {object} dto.Response "Successfully delete webhook"
+// @Failure 400 {object} dto.Response "Incorrect client input"
+// @Failure 404 {object} dto.Response "Webhook not found or already deleted"
+// @Failure 500 {object} dto.Response "Internal server error"
+// @Router /api/v1/webhooks/{id} [delete]
+func (wh *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	var (
+		ctx    = c.Request.Context()
+		resMsg = "failed delete webhook"
+	)
+	id, err := getID(c, "webhook")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.Fail(resMsg, apperr.CodeValidation, err.Error()))
+		return
+	}
+	if err := wh.webhookService.DeleteWebhook(ctx, id); err != nil {
+		status, errMsg := utils.ValidateErr(err, resMsg, "webhook not found or already deleted")
+		if status == 500 {
+			log.LogHSR(ctx, resMsg, "delete webhook", c.Request.URL.Path, c.Request.Method, err.Error())
+		}
+		c.JSON(status, errMsg)
+		return
+	}
+	c.JSON(http.StatusOK, utils.Success("success delete webhook", nil, nil))
+}
+
+// GetDeliveries godoc
+// @Summary Get webhook deliveries
+// @Description Get the delivery log of a webhook, newest first, with the last response and error of every delivery
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Param status query string false "Status" Enums(pending, succeeded, failed, dead)
+// @Param cursor query string false "Cursor, the next_cursor of the previous page"
+// @Param page_size query int false "Page size (default 35, max 100)"
+// @Tags Admin
+// @Success 200 {object} dto.Response "Successfully get webhook deliveries"
+// @Failure 400 {object} dto.Response "Incorrect client input"
+// @Failure 500 {object} dto.Response "Internal server error"
+// @Router /api/v1/webhooks/{id}/deliveries [get]
+func (wh *WebhookHandler) GetDeliveries(c *gin.Context) {
+	var (
+		filter dto.DeliveryFilter
+		ctx    = c.Request.Context()
+		resMsg = "failed get webhook deliveries"
+	)
+	id, err := getID(c, "webhook")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.Fail(resMsg, apperr.CodeValidation, err.Error()))
+		return
+	}
+	if err := utils.GetData(func() error { return c.ShouldBindQuery(&filter) }, resMsg); err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+	deliveries, meta, err := wh.webhookService.GetDeliveries(ctx, id, filter)
+	if err != nil {
+		status, errMsg := utils.ValidateErr(err, resMsg, "")
+		if status == 500 {
+			log.LogHSR(ctx, resMsg, "get webhook deliveries", c.Request.URL.Path, c.Request.Method, err.Error())
+		}
+		c.JSON(status, errMsg)
+		return
+	}
+	c.JSON(http.StatusOK, utils.Success("success get webhook deliveries", deliveries, meta))
+}
+
+// ReplayDelivery godoc
+// @Summary Replay webhook delivery
+// @Description Send a delivery again from its first attempt with its original body, whatever its status
+// @Produce json
+// @Param id path int true "Delivery ID"
+// @Param Idempotency-Key header string false "Key replaying the first response for retries"
+// @Tags Admin
+// @Success 202 {object} dto.Response "Successfully queue webhook delivery"
+// @Failure 400 {object} dto.Response "Incorrect client input"
+// @Failure 404 {object} dto.Response "Delivery not found"
+// @Failure 500 {object} dto.Response "Internal server error"
+// @Router /api/v1/webhook-deliveries/{id}/replay [post]
+func (wh *WebhookHandler) ReplayDelivery(c *gin.Context) {
+	var (
+		ctx    = c.Request.Context()
+		resMsg = "failed replay webhook delivery"
+	)
+	id, err := getID(c, "delivery")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.Fail(resMsg, apperr.CodeValidation, err.Error()))
+		return
+	}
+	if err := wh.webhookService.ReplayDelivery(ctx, id); err != nil {
+		status, errMsg := utils.ValidateErr(err, resMsg, "delivery not found")
+		if status == 500 {
+			log.LogHSR(ctx, resMsg, "replay webhook delivery", c.Request.URL.Path, c.Request.Method, err.Error())
+		}
+		c.JSON(status, errMsg)
+		return
+	}
+	c.JSON(http.StatusAccepted, utils.Success("success queue webhook delivery", nil, nil))
+}
@@ -2,31 +2,45 @@ package config
 
 import (
 	"fmt"
+	"stmnplibrary/config"
 	"stmnplibrary/log"
+	"stmnplibrary/metrics"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
-
-	"os"
+	"gorm.io/plugin/opentelemetry/tracing"
 )
 
-func ProviderConnStr() string {
-	return fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable TimeZone=Asia/Jakarta", os.Getenv("POSTGRES_HOST"), os.Getenv("POSTGRES_USER"), os.Getenv("POSTGRES_PASSWORD"), os.Getenv("POSTGRES_NAME"), os.Getenv("POSTGRES_PORT"))
+func ProviderConnStr(cfg config.Postgres) string {
+	return fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s TimeZone=%s", cfg.Host, cfg.User, cfg.Password, cfg.Name, cfg.Port, cfg.SSLMode, cfg.TimeZone)
 }
 
-func Init(connStr string) (*gorm.DB, func()) {
+func Init(connStr string, cfg config.Postgres) (*gorm.DB, func()) {
 	db, err := gorm.Open(postgres.Open(connStr), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Silent),
+		Logger:               logger.Default.LogMode(logger.Silent),
+		DisableAutomaticPing: true,
 	})
 	if err != nil {
 		log.LogConfig("failed connect db", "connect_db_gorm", err)
 		panic(err)
 	}
+	if err := db.Use(metrics.GormPlugin{}); err != nil {
+		log.LogConfig("failed register gorm metrics", "connect_db_gorm", err)
+		panic(err)
+	}
+	if err := db.Use(tracing.NewPlugin(tracing.WithoutMetrics(), tracing.WithoutQueryVariables())); err != nil {
+		log.LogConfig("failed register gorm tracing", "connect_db_gorm", err)
+		panic(err)
+	}
 
 	sqlDB, _ := db.DB()
-	sqlDB.SetMaxIdleConns(5)
-	sqlDB.SetMaxOpenConns(20)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	// an unreachable db is reported by /readyz instead of crashing at startup
+	if err := sqlDB.Ping(); err != nil {
+		log.LogConfig("failed ping db", "connect_db_gorm", err)
+	}
 	return db, func(){
 		if err := sqlDB.Close(); err != nil {
 			panic(err)
@@ -0,0 +1,245 @@
+package migration
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+//go:embed sql/*.sql
+var files embed.FS
+
+const createTable = `CREATE TABLE IF NOT EXISTS schema_migrations (
+    version     INTEGER     PRIMARY KEY,
+    name        VARCHAR(255) NOT NULL,
+    applied_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+)`
+
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+type Status struct {
+	Version   int
+	Name      string
+	AppliedAt *time.Time
+}
+
+type applied struct {
+	Version   int       `gorm:"column:version"`
+	Name      string    `gorm:"column:name"`
+	AppliedAt time.Time `gorm:"column:applied_at"`
+}
+
+func (applied) TableName() string {
+	return "schema_migrations"
+}
+
+type Migrator struct {
+	gorm       *gorm.DB
+	migrations []Migration
+}
+
+func FnMigrator(gorm *gorm.DB) (*Migrator, error) {
+	migrations, err := Load(files)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{
+		gorm:       gorm,
+		migrations: migrations,
+	}, nil
+}
+
+// Load reads every <version>_<name>.(up|down).sql file in fsys and returns
+// them ordered by version. Every version must have both an up and a down file.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.Glob(fsys, "sql/*.sql")
+	if err != nil {
+		return nil, fmt.Errorf("failed read migrations: %w", err)
+	}
+	var byVersion = map[int]*Migration{}
+	for _, path := range entries {
+		file := strings.TrimPrefix(path, "sql/")
+		var direction string
+		switch {
+		case strings.HasSuffix(file, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(file, ".down.sql"):
+			direction = "down"
+		default:
+			return nil, fmt.Errorf("migration %s must end with .up.sql or .down.sql", file)
+		}
+		base := strings.TrimSuffix(file, "."+direction+".sql")
+		v, name, ok := strings.Cut(base, "_")
+		if !ok {
+			return nil, fmt.Errorf("migration %s must be named <version>_<name>", file)
+		}
+		version, err := strconv.Atoi(v)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("migration %s has invalid version", file)
+		}
+		content, err := fs.ReadFile(fsys, path)
+		if err != nil {
+			return nil, fmt.Errorf("failed read migration %s: %w", file, err)
+		}
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+		}
+		if m.Name != name {
+			return nil, fmt.Errorf("migration version %d has two names: %s and %s", version, m.Name, name)
+		}
+		if direction == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+	var migrations = make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %04d_%s must have both up and down file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+func (m *Migrator) ensureTable(ctx context.Context) error {
+	if err := m.gorm.WithContext(ctx).Exec(createTable).Error; err != nil {
+		return fmt.Errorf("failed create schema_migrations: %w", err)
+	}
+	return nil
+}
+
+func (m *Migrator) applied(ctx context.Context, tx *gorm.DB) (map[int]applied, error) {
+	var rows []applied
+	if err := tx.WithContext(ctx).Order("version").Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed read schema_migrations: %w", err)
+	}
+	var result = make(map[int]applied, len(rows))
+	for _, r := range rows {
+		result[r.Version] = r
+	}
+	return result, nil
+}
+
+// lock serializes concurrent migrate runs for the rest of the transaction.
+func (m *Migrator) lock(ctx context.Context, tx *gorm.DB) error {
+	if err := tx.WithContext(ctx).Exec("LOCK TABLE schema_migrations IN EXCLUSIVE MODE").Error; err != nil {
+		return fmt.Errorf("failed lock schema_migrations: %w", err)
+	}
+	return nil
+}
+
+// Up applies every pending migration in order, each one in its own transaction.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	if err := m.ensureTable(ctx); err != nil {
+		return nil, err
+	}
+	var done []Migration
+	for _, mg := range m.migrations {
+		var skip bool
+		err := m.gorm.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := m.lock(ctx, tx); err != nil {
+				return err
+			}
+			list, err := m.applied(ctx, tx)
+			if err != nil {
+				return err
+			}
+			if _, ok := list[mg.Version]; ok {
+				skip = true
+				return nil
+			}
+			if err := tx.WithContext(ctx).Exec(mg.Up).Error; err != nil {
+				return fmt.Errorf("failed apply migration %04d_%s: %w", mg.Version, mg.Name, err)
+			}
+			return tx.WithContext(ctx).Create(&applied{Version: mg.Version, Name: mg.Name, AppliedAt: time.Now()}).Error
+		})
+		if err != nil {
+			return done, err
+		}
+		if !skip {
+			done = append(done, mg)
+		}
+	}
+	return done, nil
+}
+
+// Down rolls back the last steps applied migrations, newest first.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	if steps <= 0 {
+		return nil, errors.New("steps must be greater than 0")
+	}
+	if err := m.ensureTable(ctx); err != nil {
+		return nil, err
+	}
+	var done []Migration
+	for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+		var (
+			mg   = m.migrations[i]
+			skip bool
+		)
+		err := m.gorm.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := m.lock(ctx, tx); err != nil {
+				return err
+			}
+			list, err := m.applied(ctx, tx)
+			if err != nil {
+				return err
+			}
+			if _, ok := list[mg.Version]; !ok {
+				skip = true
+				return nil
+			}
+			if err := tx.WithContext(ctx).Exec(mg.Down).Error; err != nil {
+				return fmt.Errorf("failed rollback migration %04d_%s: %w", mg.Version, mg.Name, err)
+			}
+			return tx.WithContext(ctx).Where("version = ?", mg.Version).Delete(&applied{}).Error
+		})
+		if err != nil {
+			return done, err
+		}
+		if !skip {
+			done = append(done, mg)
+		}
+	}
+	return done, nil
+}
+
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	if err := m.ensureTable(ctx); err != nil {
+		return nil, err
+	}
+	list, err := m.applied(ctx, m.gorm)
+	if err != nil {
+		return nil, err
+	}
+	var status = make([]Status, 0, len(m.migrations))
+	for _, mg := range m.migrations {
+		s := Status{Version: mg.Version, Name: mg.Name}
+		if a, ok := list[mg.Version]; ok {
+			appliedAt := a.AppliedAt
+			s.AppliedAt = &appliedAt
+		}
+		status = append(status, s)
+	}
+	return status, nil
+}
@@ -0,0 +1,63 @@
+package migration
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad_Embedded(t *testing.T) {
+	migrations, err := Load(files)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, migrations)
+	for i, m := range migrations {
+		assert.NotEmpty(t, m.Up)
+		assert.NotEmpty(t, m.Down)
+		if i > 0 {
+			assert.Greater(t, m.Version, migrations[i-1].Version)
+		}
+	}
+}
+
+func TestLoad_Cases(t *testing.T) {
+	tests := []struct {
+		name      string
+		fsys      fstest.MapFS
+		expectErr bool
+		versions  []int
+	}{
+		{"Ordered_By_Version", fstest.MapFS{
+			"sql/0010_b.up.sql":   {Data: []byte("b")},
+			"sql/0010_b.down.sql": {Data: []byte("b")},
+			"sql/0002_a.up.sql":   {Data: []byte("a")},
+			"sql/0002_a.down.sql": {Data: []byte("a")},
+		}, false, []int{2, 10}},
+		{"Missing_Down", fstest.MapFS{
+			"sql/0001_a.up.sql": {Data: []byte("a")},
+		}, true, nil},
+		{"Invalid_Version", fstest.MapFS{
+			"sql/x_a.up.sql":   {Data: []byte("a")},
+			"sql/x_a.down.sql": {Data: []byte("a")},
+		}, true, nil},
+		{"Invalid_Suffix", fstest.MapFS{
+			"sql/0001_a.sql": {Data: []byte("a")},
+		}, true, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migrations, err := Load(tt.fsys)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			var versions []int
+			for _, m := range migrations {
+				versions = append(versions, m.Version)
+			}
+			assert.Equal(t, tt.versions, versions)
+		})
+	}
+}
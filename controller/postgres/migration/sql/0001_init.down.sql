@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS loan;
+DROP TABLE IF EXISTS connections;
+DROP TABLE IF EXISTS books;
+DROP TABLE IF EXISTS categories;
+DROP TABLE IF EXISTS students;
@@ -0,0 +1,65 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE TABLE students (
+    id            SERIAL PRIMARY KEY,
+    nis           INTEGER      NOT NULL UNIQUE,
+    name          VARCHAR(30)  NOT NULL,
+    phone_number  VARCHAR(16)  NOT NULL,
+    email         VARCHAR(50)  NOT NULL UNIQUE,
+    password      TEXT         NOT NULL,
+    class         VARCHAR(4)   NOT NULL,
+    sub_class     VARCHAR(1)   NOT NULL,
+    major         VARCHAR(4)   NOT NULL,
+    batch         INTEGER      NOT NULL,
+    max_book      INTEGER      NOT NULL DEFAULT 0 CHECK (max_book BETWEEN 0 AND 3),
+    role          VARCHAR(10)  NOT NULL DEFAULT 'students' CHECK (role IN ('students', 'admin')),
+    is_active     BOOLEAN      NOT NULL DEFAULT TRUE,
+    created_at    TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_students_name_trgm ON students USING GIN (name gin_trgm_ops);
+CREATE INDEX idx_students_class_major ON students (class, major, batch);
+
+CREATE TABLE categories (
+    id    SERIAL PRIMARY KEY,
+    name  VARCHAR(50) NOT NULL UNIQUE
+);
+
+CREATE TABLE books (
+    id               SERIAL PRIMARY KEY,
+    isbn             VARCHAR(20)  NOT NULL UNIQUE,
+    name             VARCHAR(255) NOT NULL,
+    author           VARCHAR(255) NOT NULL,
+    publisher        VARCHAR(255) NOT NULL,
+    description      VARCHAR(300) NOT NULL,
+    stock            INTEGER      NOT NULL CHECK (stock >= 0),
+    available_stock  INTEGER      NOT NULL CHECK (available_stock >= 0),
+    created_at       TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    CHECK (available_stock <= stock)
+);
+
+CREATE INDEX idx_books_name_trgm ON books USING GIN (name gin_trgm_ops);
+CREATE INDEX idx_books_author_trgm ON books USING GIN (author gin_trgm_ops);
+
+CREATE TABLE connections (
+    id_book      INTEGER NOT NULL REFERENCES books (id) ON DELETE CASCADE,
+    id_category  INTEGER NOT NULL REFERENCES categories (id) ON DELETE CASCADE,
+    PRIMARY KEY (id_book, id_category)
+);
+
+CREATE INDEX idx_connections_category ON connections (id_category);
+
+CREATE TABLE loan (
+    id                SERIAL PRIMARY KEY,
+    id_user           INTEGER     NOT NULL REFERENCES students (id),
+    id_book           INTEGER     NOT NULL REFERENCES books (id),
+    borrow_at         TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    must_returned_at  TIMESTAMPTZ NOT NULL,
+    returned_at       TIMESTAMPTZ,
+    sanctions         BIGINT,
+    is_returned       BOOLEAN     NOT NULL DEFAULT FALSE
+);
+
+CREATE UNIQUE INDEX idx_loan_active ON loan (id_user, id_book) WHERE is_returned = FALSE;
+CREATE INDEX idx_loan_is_returned ON loan (is_returned);
+CREATE INDEX idx_loan_user ON loan (id_user);
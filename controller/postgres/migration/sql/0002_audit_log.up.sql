@@ -0,0 +1,28 @@
+CREATE TABLE audit_log (
+    id          BIGSERIAL PRIMARY KEY,
+    actor_id    INTEGER      NOT NULL DEFAULT 0,
+    actor_role  VARCHAR(10)  NOT NULL DEFAULT '',
+    action      VARCHAR(20)  NOT NULL,
+    entity      VARCHAR(20)  NOT NULL,
+    entity_id   VARCHAR(50)  NOT NULL,
+    before      JSONB,
+    after       JSONB,
+    diff        JSONB,
+    trace_id    VARCHAR(64)  NOT NULL DEFAULT '',
+    ip          VARCHAR(45)  NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_audit_log_actor ON audit_log (actor_id, created_at);
+CREATE INDEX idx_audit_log_entity ON audit_log (entity, created_at);
+CREATE INDEX idx_audit_log_created_at ON audit_log (created_at);
+
+CREATE FUNCTION audit_log_append_only() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_no_update
+    BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_audit_log_created_at_id;
+CREATE INDEX idx_audit_log_created_at ON audit_log (created_at);
+
+DROP INDEX IF EXISTS idx_loan_borrow_at_id;
+
+DROP INDEX IF EXISTS idx_books_available_stock_id;
+DROP INDEX IF EXISTS idx_books_created_at_id;
+DROP INDEX IF EXISTS idx_books_author_id;
+DROP INDEX IF EXISTS idx_books_name_id;
@@ -0,0 +1,9 @@
+CREATE INDEX idx_books_name_id ON books (name, id);
+CREATE INDEX idx_books_author_id ON books (author, id);
+CREATE INDEX idx_books_created_at_id ON books (created_at, id);
+CREATE INDEX idx_books_available_stock_id ON books (available_stock, id);
+
+CREATE INDEX idx_loan_borrow_at_id ON loan (borrow_at, id);
+
+DROP INDEX IF EXISTS idx_audit_log_created_at;
+CREATE INDEX idx_audit_log_created_at_id ON audit_log (created_at, id);
@@ -0,0 +1,16 @@
+CREATE TABLE outbox (
+    id            BIGSERIAL PRIMARY KEY,
+    event_type    VARCHAR(50)  NOT NULL,
+    aggregate     VARCHAR(20)  NOT NULL,
+    aggregate_id  VARCHAR(50)  NOT NULL,
+    payload       JSONB        NOT NULL,
+    trace_id      VARCHAR(64)  NOT NULL DEFAULT '',
+    attempts      INTEGER      NOT NULL DEFAULT 0,
+    last_error    TEXT         NOT NULL DEFAULT '',
+    available_at  TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    published_at  TIMESTAMPTZ,
+    dead_at       TIMESTAMPTZ,
+    created_at    TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_outbox_pending ON outbox (available_at, id) WHERE published_at IS NULL AND dead_at IS NULL;
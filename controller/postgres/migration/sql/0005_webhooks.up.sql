@@ -0,0 +1,29 @@
+CREATE TABLE webhook_subscriptions (
+    id          SERIAL PRIMARY KEY,
+    url         VARCHAR(2048) NOT NULL,
+    secret      VARCHAR(128)  NOT NULL,
+    event_types JSONB         NOT NULL,
+    active      BOOLEAN       NOT NULL DEFAULT TRUE,
+    created_by  INTEGER       NOT NULL DEFAULT 0,
+    created_at  TIMESTAMPTZ   NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE webhook_deliveries (
+    id               BIGSERIAL PRIMARY KEY,
+    subscription_id  INTEGER      NOT NULL REFERENCES webhook_subscriptions (id),
+    event_id         BIGINT       NOT NULL,
+    event_type       VARCHAR(50)  NOT NULL,
+    payload          JSONB        NOT NULL,
+    status           VARCHAR(10)  NOT NULL DEFAULT 'pending',
+    attempts         INTEGER      NOT NULL DEFAULT 0,
+    response_status  INTEGER      NOT NULL DEFAULT 0,
+    response_body    TEXT         NOT NULL DEFAULT '',
+    last_error       TEXT         NOT NULL DEFAULT '',
+    next_attempt_at  TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    delivered_at     TIMESTAMPTZ,
+    created_at       TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    UNIQUE (subscription_id, event_id)
+);
+
+CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries (next_attempt_at, id) WHERE status IN ('pending', 'failed');
+CREATE INDEX idx_webhook_deliveries_log ON webhook_deliveries (subscription_id, created_at, id);
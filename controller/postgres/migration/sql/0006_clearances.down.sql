@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS clearances;
+ALTER TABLE loan DROP COLUMN IF EXISTS sanctions_paid_at;
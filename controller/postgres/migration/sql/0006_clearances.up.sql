@@ -0,0 +1,19 @@
+ALTER TABLE loan ADD COLUMN sanctions_paid_at TIMESTAMPTZ;
+
+CREATE TABLE clearances (
+    id          SERIAL PRIMARY KEY,
+    number      VARCHAR(20)  NOT NULL UNIQUE,
+    id_user     INTEGER      NOT NULL UNIQUE REFERENCES students (id),
+    nis         INTEGER      NOT NULL,
+    name        VARCHAR(30)  NOT NULL,
+    class       VARCHAR(4)   NOT NULL,
+    sub_class   VARCHAR(1)   NOT NULL,
+    major       VARCHAR(4)   NOT NULL,
+    batch       INTEGER      NOT NULL,
+    purpose     VARCHAR(10)  NOT NULL CHECK (purpose IN ('graduation', 'transfer')),
+    signature   VARCHAR(64)  NOT NULL,
+    issued_by   INTEGER      NOT NULL DEFAULT 0,
+    issued_at   TIMESTAMPTZ  NOT NULL
+);
+
+CREATE INDEX idx_clearances_batch ON clearances (batch);
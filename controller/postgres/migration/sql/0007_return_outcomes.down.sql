@@ -0,0 +1,6 @@
+ALTER TABLE loan
+    DROP COLUMN IF EXISTS fee,
+    DROP COLUMN IF EXISTS condition_note,
+    DROP COLUMN IF EXISTS outcome;
+
+ALTER TABLE books DROP COLUMN IF EXISTS price;
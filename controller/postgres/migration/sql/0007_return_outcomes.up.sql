@@ -0,0 +1,8 @@
+ALTER TABLE books ADD COLUMN price BIGINT NOT NULL DEFAULT 0 CHECK (price >= 0);
+
+ALTER TABLE loan
+    ADD COLUMN outcome         VARCHAR(10)  CHECK (outcome IN ('returned', 'damaged', 'lost')),
+    ADD COLUMN condition_note  VARCHAR(300) NOT NULL DEFAULT '',
+    ADD COLUMN fee             BIGINT       NOT NULL DEFAULT 0 CHECK (fee >= 0);
+
+UPDATE loan SET outcome = 'returned' WHERE is_returned = TRUE;
@@ -0,0 +1,21 @@
+CREATE TABLE opening_hours (
+    weekday     SMALLINT    PRIMARY KEY CHECK (weekday BETWEEN 0 AND 6),
+    is_open     BOOLEAN     NOT NULL DEFAULT TRUE,
+    opens_at    VARCHAR(5)  NOT NULL DEFAULT '07:00',
+    closes_at   VARCHAR(5)  NOT NULL DEFAULT '15:00'
+);
+
+INSERT INTO opening_hours (weekday, is_open) VALUES
+    (0, FALSE), (1, TRUE), (2, TRUE), (3, TRUE), (4, TRUE), (5, TRUE), (6, FALSE);
+
+CREATE TABLE closures (
+    id          SERIAL       PRIMARY KEY,
+    name        VARCHAR(100) NOT NULL,
+    kind        VARCHAR(10)  NOT NULL CHECK (kind IN ('holiday', 'term_break')),
+    start_date  DATE         NOT NULL,
+    end_date    DATE         NOT NULL CHECK (end_date >= start_date),
+    uid         VARCHAR(255) UNIQUE,
+    created_at  TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_closures_dates ON closures (start_date, end_date);
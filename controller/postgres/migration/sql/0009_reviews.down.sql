@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS reviews;
+
+DROP INDEX IF EXISTS idx_books_rating_avg_id;
+
+ALTER TABLE books
+    DROP COLUMN IF EXISTS rating_count,
+    DROP COLUMN IF EXISTS rating_avg;
@@ -0,0 +1,21 @@
+ALTER TABLE books
+    ADD COLUMN rating_avg    NUMERIC(3, 2) NOT NULL DEFAULT 0,
+    ADD COLUMN rating_count  INTEGER       NOT NULL DEFAULT 0;
+
+CREATE INDEX idx_books_rating_avg_id ON books (rating_avg, id);
+
+CREATE TABLE reviews (
+    id             SERIAL        PRIMARY KEY,
+    id_user        INTEGER       NOT NULL REFERENCES students (id),
+    id_book        INTEGER       NOT NULL REFERENCES books (id) ON DELETE CASCADE,
+    rating         SMALLINT      NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    body           VARCHAR(1000) NOT NULL DEFAULT '',
+    status         VARCHAR(10)   NOT NULL DEFAULT 'visible' CHECK (status IN ('visible', 'hidden')),
+    hidden_reason  VARCHAR(300)  NOT NULL DEFAULT '',
+    created_at     TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    updated_at     TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    UNIQUE (id_user, id_book)
+);
+
+CREATE INDEX idx_reviews_book_created_at_id ON reviews (id_book, created_at, id);
+CREATE INDEX idx_reviews_status_created_at_id ON reviews (status, created_at, id);
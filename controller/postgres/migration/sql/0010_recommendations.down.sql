@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_loan_id_book_id_user;
+DROP INDEX IF EXISTS idx_loan_id_user_id_book;
@@ -0,0 +1,2 @@
+CREATE INDEX idx_loan_id_user_id_book ON loan (id_user, id_book);
+CREATE INDEX idx_loan_id_book_id_user ON loan (id_book, id_user);
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS reading_list_books;
+DROP TABLE IF EXISTS reading_lists;
+
+-- teachers can't be told apart from students any more, they are kept deactivated
+UPDATE students SET role = 'students', is_active = FALSE WHERE role = 'teacher';
+
+ALTER TABLE students
+    DROP CONSTRAINT students_role_check,
+    ADD CONSTRAINT students_role_check CHECK (role IN ('students', 'admin'));
@@ -0,0 +1,28 @@
+ALTER TABLE students
+    DROP CONSTRAINT students_role_check,
+    ADD CONSTRAINT students_role_check CHECK (role IN ('students', 'admin', 'teacher'));
+
+CREATE TABLE reading_lists (
+    id           SERIAL       PRIMARY KEY,
+    title        VARCHAR(100) NOT NULL,
+    description  VARCHAR(500) NOT NULL DEFAULT '',
+    class        VARCHAR(4)   NOT NULL,
+    sub_class    VARCHAR(1)   NOT NULL DEFAULT '',
+    major        VARCHAR(4)   NOT NULL DEFAULT '',
+    starts_at    DATE         NOT NULL,
+    due_at       DATE         NOT NULL CHECK (due_at >= starts_at),
+    created_by   INTEGER      NOT NULL REFERENCES students (id),
+    created_at   TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_reading_lists_target ON reading_lists (class, major, sub_class);
+CREATE INDEX idx_reading_lists_created_at_id ON reading_lists (created_at, id);
+
+CREATE TABLE reading_list_books (
+    id_list   INTEGER  NOT NULL REFERENCES reading_lists (id) ON DELETE CASCADE,
+    id_book   INTEGER  NOT NULL REFERENCES books (id),
+    position  SMALLINT NOT NULL CHECK (position > 0),
+    PRIMARY KEY (id_list, id_book),
+    UNIQUE (id_list, position)
+);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS holds;
+DROP TABLE IF EXISTS suggestion_votes;
+DROP TABLE IF EXISTS suggestions;
@@ -0,0 +1,44 @@
+CREATE TABLE suggestions (
+    id          SERIAL       PRIMARY KEY,
+    id_user     INTEGER      NOT NULL REFERENCES students (id),
+    title       VARCHAR(200) NOT NULL,
+    author      VARCHAR(100) NOT NULL,
+    isbn        VARCHAR(20)  NOT NULL DEFAULT '',
+    status      VARCHAR(20)  NOT NULL DEFAULT 'submitted'
+                CHECK (status IN ('submitted', 'under_review', 'ordered', 'received', 'rejected')),
+    votes       INTEGER      NOT NULL DEFAULT 0,
+    note        VARCHAR(300) NOT NULL DEFAULT '',
+    id_book     INTEGER      REFERENCES books (id),
+    created_at  TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+-- one open suggestion per isbn, students upvote it instead of suggesting it again
+CREATE UNIQUE INDEX uq_suggestions_open_isbn ON suggestions (isbn)
+    WHERE isbn <> '' AND status NOT IN ('received', 'rejected');
+CREATE INDEX idx_suggestions_votes_id ON suggestions (votes, id);
+CREATE INDEX idx_suggestions_created_at_id ON suggestions (created_at, id);
+
+-- the student suggesting a book votes for it, every voter is a requester
+CREATE TABLE suggestion_votes (
+    id_suggestion  INTEGER     NOT NULL REFERENCES suggestions (id) ON DELETE CASCADE,
+    id_user        INTEGER     NOT NULL REFERENCES students (id),
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (id_suggestion, id_user)
+);
+
+CREATE INDEX idx_suggestion_votes_created_at ON suggestion_votes (id_suggestion, created_at);
+
+-- a hold keeps a copy of a book for one student until it expires or they borrow it
+CREATE TABLE holds (
+    id             SERIAL      PRIMARY KEY,
+    id_user        INTEGER     NOT NULL REFERENCES students (id),
+    id_book        INTEGER     NOT NULL REFERENCES books (id),
+    id_suggestion  INTEGER     REFERENCES suggestions (id) ON DELETE SET NULL,
+    expires_at     TIMESTAMPTZ NOT NULL,
+    fulfilled_at   TIMESTAMPTZ,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX uq_holds_open ON holds (id_user, id_book) WHERE fulfilled_at IS NULL;
+CREATE INDEX idx_holds_book_expires_at ON holds (id_book, expires_at) WHERE fulfilled_at IS NULL;
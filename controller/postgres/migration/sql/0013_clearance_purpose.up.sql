@@ -0,0 +1,2 @@
+ALTER TABLE clearances DROP CONSTRAINT IF EXISTS clearances_id_user_key;
+ALTER TABLE clearances ADD CONSTRAINT clearances_id_user_purpose_key UNIQUE (id_user, purpose);
@@ -0,0 +1,345 @@
+package seed
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"stmnplibrary/domain/entity"
+	"stmnplibrary/security"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const sanctionPerDay = 2000
+
+type Options struct {
+	Seed       int64
+	Categories int
+	Books      int
+	Students   int
+	Loans      int
+	Password   string
+	Now        time.Time
+}
+
+type Student struct {
+	ID          int `gorm:"primaryKey"`
+	NIS         int
+	Name        string
+	PhoneNumber string
+	Email       string
+	Password    string
+	Class       string
+	SubClass    string
+	Major       string
+	Batch       int
+	MaxBook     int
+	Role        string
+	IsActive    bool
+}
+
+func (Student) TableName() string {
+	return "students"
+}
+
+type Loan struct {
+	ID             int `gorm:"primaryKey"`
+	IdUser         int
+	IdBook         int
+	BorrowAt       time.Time
+	MustReturnedAt time.Time
+	ReturnedAt     *time.Time
+	Sanctions      *int64
+	IsReturned     bool
+	student        int
+	book           int
+}
+
+func (Loan) TableName() string {
+	return "loan"
+}
+
+type Data struct {
+	Categories  []entity.Categories
+	Books       []entity.BookData
+	Connections [][]int
+	Students    []Student
+	Loans       []Loan
+}
+
+var (
+	categoryNames = []string{"Fiksi", "Sains", "Sejarah", "Teknologi", "Pemrograman", "Jaringan", "Matematika", "Biografi", "Agama", "Bahasa", "Ekonomi", "Seni", "Otomotif", "Elektronika", "Kimia", "Fisika", "Novel", "Komik", "Kesehatan", "Pariwisata"}
+	firstNames    = []string{"Budi", "Siti", "Agus", "Dewi", "Rizky", "Putri", "Andi", "Nur", "Fajar", "Ayu", "Bagus", "Intan", "Yoga", "Rani", "Dimas", "Lestari", "Wahyu", "Sari", "Eko", "Indah"}
+	lastNames     = []string{"Santoso", "Wijaya", "Saputra", "Pratama", "Hidayat", "Kusuma", "Setiawan", "Nugroho", "Lestari", "Permata", "Susanto", "Rahman", "Siregar", "Simanjuntak", "Purnomo"}
+	authors       = []string{"Tere Liye", "Andrea Hirata", "Pramoedya Ananta Toer", "Dee Lestari", "Ahmad Fuadi", "Eka Kurniawan", "Leila S. Chudori", "Abdul Kadir", "Rinaldi Munir", "Jubilee Enterprise", "Budi Raharjo", "Onno W. Purbo"}
+	publishers    = []string{"Gramedia Pustaka Utama", "Bentang Pustaka", "Erlangga", "Informatika", "Andi Offset", "Elex Media Komputindo", "Mizan", "Republika"}
+	titleWords    = []string{"Laskar", "Pelangi", "Bumi", "Manusia", "Negeri", "Lima", "Menara", "Jaringan", "Komputer", "Dasar", "Algoritma", "Pemrograman", "Cantik", "Luka", "Hujan", "Senja", "Rumah", "Kaca", "Jejak", "Langkah"}
+	majors        = []string{"RPL", "SIJA", "PSPT", "TPTU", "TEI", "MEKA", "TOI", "TEK", "IOP"}
+	subClasses    = []string{"A", "B", "C"}
+)
+
+func isbn13(r *rand.Rand) string {
+	var digits = "978"
+	for i := 0; i < 9; i++ {
+		digits += strconv.Itoa(r.Intn(10))
+	}
+	var sum int
+	for i, c := range digits {
+		d := int(c - '0')
+		if i%2 == 1 {
+			d *= 3
+		}
+		sum += d
+	}
+	return digits + strconv.Itoa((10-sum%10)%10)
+}
+
+func phoneNumber(r *rand.Rand) string {
+	var number = "08" + strconv.Itoa(1+r.Intn(9))
+	for i := 0; i < 8; i++ {
+		number += strconv.Itoa(r.Intn(10))
+	}
+	return number
+}
+
+func classFor(r *rand.Rand, major string) string {
+	classes := []string{"X", "XI", "XII"}
+	if major == "IOP" || major == "SIJA" {
+		classes = append(classes, "XIII")
+	}
+	return classes[r.Intn(len(classes))]
+}
+
+func batchFor(class string, now time.Time) int {
+	switch class {
+	case "XI":
+		return now.Year() - 1
+	case "XII":
+		return now.Year() - 2
+	case "XIII":
+		return now.Year() - 3
+	default:
+		return now.Year()
+	}
+}
+
+func sanctions(mustReturnedAt time.Time, returnedAt time.Time) int64 {
+	if !returnedAt.After(mustReturnedAt) {
+		return 0
+	}
+	return int64(returnedAt.Sub(mustReturnedAt).Hours()/24) * sanctionPerDay
+}
+
+// Generate builds a deterministic data set for opt.Seed. Password must
+// already be hashed; every student shares it.
+func Generate(opt Options) (*Data, error) {
+	var (
+		r    = rand.New(rand.NewSource(opt.Seed))
+		data = &Data{}
+	)
+	for i := 0; i < opt.Categories; i++ {
+		name := categoryNames[i%len(categoryNames)]
+		if round := i / len(categoryNames); round > 0 {
+			name += " " + strconv.Itoa(round+1)
+		}
+		data.Categories = append(data.Categories, entity.Categories{Name: name})
+	}
+
+	var (
+		available = make([]int, opt.Books)
+		usedISBN  = map[string]bool{}
+	)
+	for i := 0; i < opt.Books; i++ {
+		var (
+			title  = titleWords[r.Intn(len(titleWords))] + " " + titleWords[r.Intn(len(titleWords))] + " " + strconv.Itoa(i+1)
+			author = authors[r.Intn(len(authors))]
+			stock  = 1 + r.Intn(10)
+			isbn   = isbn13(r)
+		)
+		for usedISBN[isbn] {
+			isbn = isbn13(r)
+		}
+		usedISBN[isbn] = true
+		data.Books = append(data.Books, entity.BookData{
+			ISBN:           isbn,
+			Name:           title,
+			Author:         author,
+			Publisher:      publishers[r.Intn(len(publishers))],
+			Description:    "Buku " + title + " karya " + author + " untuk koleksi perpustakaan sekolah, cocok untuk siswa SMK.",
+			Stock:          stock,
+			AvailableStock: stock,
+		})
+		available[i] = stock
+		var connect []int
+		if opt.Categories > 0 {
+			connect = r.Perm(opt.Categories)[:1+r.Intn(min(3, opt.Categories))]
+		}
+		data.Connections = append(data.Connections, connect)
+	}
+
+	data.Students = append(data.Students, Student{
+		NIS:         1,
+		Name:        "Admin Perpustakaan",
+		PhoneNumber: "+6281100000000",
+		Email:       "admin@stmnp.sch.id",
+		Password:    opt.Password,
+		Class:       "X",
+		SubClass:    "A",
+		Major:       "RPL",
+		Batch:       opt.Now.Year(),
+		Role:        "admin",
+		IsActive:    true,
+	})
+	for i := 0; i < opt.Students; i++ {
+		var (
+			nis      = 20000 + i
+			major    = majors[r.Intn(len(majors))]
+			class    = classFor(r, major)
+			personal = entity.PersonalInfo{
+				Name:        firstNames[r.Intn(len(firstNames))] + " " + lastNames[r.Intn(len(lastNames))],
+				PhoneNumber: phoneNumber(r),
+				Email:       strconv.Itoa(nis) + "@stmnp.sch.id",
+			}
+			academic = entity.AcademicInfo{Class: class, Major: major}
+			errMsg   []string
+		)
+		personal.ValidatePN(&errMsg)
+		academic.ValidateClass(&errMsg)
+		if len(errMsg) > 0 {
+			return nil, fmt.Errorf("generated invalid student %d: %s", nis, strings.Join(errMsg, ", "))
+		}
+		data.Students = append(data.Students, Student{
+			NIS:         nis,
+			Name:        personal.Name,
+			PhoneNumber: personal.PhoneNumber,
+			Email:       personal.Email,
+			Password:    opt.Password,
+			Class:       class,
+			SubClass:    subClasses[r.Intn(len(subClasses))],
+			Major:       major,
+			Batch:       batchFor(class, opt.Now),
+			Role:        "students",
+			IsActive:    true,
+		})
+	}
+	// the teacher comes after the students so loans keep indexing them from 1
+	data.Students = append(data.Students, Student{
+		NIS:         2,
+		Name:        "Guru Bahasa Indonesia",
+		PhoneNumber: "+6281100000001",
+		Email:       "guru@stmnp.sch.id",
+		Password:    opt.Password,
+		Class:       "X",
+		SubClass:    "A",
+		Major:       "RPL",
+		Batch:       opt.Now.Year(),
+		Role:        "teacher",
+		IsActive:    true,
+	})
+
+	if opt.Students == 0 || opt.Books == 0 {
+		return data, nil
+	}
+	var active = map[[2]int]bool{}
+	for i := 0; i < opt.Loans; i++ {
+		var (
+			student = 1 + r.Intn(opt.Students)
+			book    = r.Intn(opt.Books)
+			kind    = r.Intn(10)
+			loan    = Loan{student: student, book: book}
+		)
+		canBorrow := data.Students[student].MaxBook < entity.MaxBookLimit && available[book] > 0 && !active[[2]int{student, book}]
+		switch {
+		case kind < 4 && canBorrow:
+			loan.BorrowAt = opt.Now.AddDate(0, 0, -r.Intn(4))
+			loan.MustReturnedAt = loan.BorrowAt.AddDate(0, 0, 4+r.Intn(4))
+		case kind < 6 && canBorrow:
+			loan.BorrowAt = opt.Now.AddDate(0, 0, -(8 + r.Intn(30)))
+			loan.MustReturnedAt = loan.BorrowAt.AddDate(0, 0, 1+r.Intn(7))
+		default:
+			loan.BorrowAt = opt.Now.AddDate(0, 0, -(10 + r.Intn(180)))
+			loan.MustReturnedAt = loan.BorrowAt.AddDate(0, 0, 1+r.Intn(7))
+			returnedAt := loan.MustReturnedAt.AddDate(0, 0, r.Intn(8)-4)
+			if returnedAt.Before(loan.BorrowAt) {
+				returnedAt = loan.BorrowAt
+			}
+			s := sanctions(loan.MustReturnedAt, returnedAt)
+			loan.ReturnedAt = &returnedAt
+			loan.Sanctions = &s
+			loan.IsReturned = true
+		}
+		if !loan.IsReturned {
+			data.Students[student].MaxBook++
+			data.Books[book].AvailableStock--
+			available[book]--
+			active[[2]int{student, book}] = true
+		}
+		data.Loans = append(data.Loans, loan)
+	}
+	return data, nil
+}
+
+// Insert writes data in a single transaction, filling in the generated ids
+// for connections and loans.
+func Insert(ctx context.Context, db *gorm.DB, data *Data, batchSize int) error {
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(data.Categories) > 0 {
+			if err := tx.CreateInBatches(&data.Categories, batchSize).Error; err != nil {
+				return fmt.Errorf("failed seed categories: %w", err)
+			}
+		}
+		if len(data.Books) > 0 {
+			if err := tx.CreateInBatches(&data.Books, batchSize).Error; err != nil {
+				return fmt.Errorf("failed seed books: %w", err)
+			}
+		}
+		var connections []entity.Connections
+		for b, list := range data.Connections {
+			for _, c := range list {
+				connections = append(connections, entity.Connections{
+					BookID:     data.Books[b].BookID,
+					IdCategory: data.Categories[c].ID,
+				})
+			}
+		}
+		if len(connections) > 0 {
+			if err := tx.CreateInBatches(&connections, batchSize).Error; err != nil {
+				return fmt.Errorf("failed seed connections: %w", err)
+			}
+		}
+		if len(data.Students) > 0 {
+			if err := tx.CreateInBatches(&data.Students, batchSize).Error; err != nil {
+				return fmt.Errorf("failed seed students: %w", err)
+			}
+		}
+		for i := range data.Loans {
+			data.Loans[i].IdUser = data.Students[data.Loans[i].student].ID
+			data.Loans[i].IdBook = data.Books[data.Loans[i].book].BookID
+		}
+		if len(data.Loans) > 0 {
+			if err := tx.CreateInBatches(&data.Loans, batchSize).Error; err != nil {
+				return fmt.Errorf("failed seed loans: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+func Run(ctx context.Context, db *gorm.DB, opt Options) (*Data, error) {
+	hash, err := security.HashPassword(opt.Password)
+	if err != nil {
+		return nil, err
+	}
+	opt.Password = hash
+	data, err := Generate(opt)
+	if err != nil {
+		return nil, err
+	}
+	if err := Insert(ctx, db, data, 500); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
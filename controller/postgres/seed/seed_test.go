@@ -0,0 +1,103 @@
+package seed
+
+import (
+	"testing"
+	"time"
+
+	"stmnplibrary/domain/entity"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func opt(seed int64) Options {
+	return Options{
+		Seed:       seed,
+		Categories: 25,
+		Books:      40,
+		Students:   60,
+		Loans:      300,
+		Password:   "hash",
+		Now:        time.Date(2025, 8, 17, 10, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestGenerate_Deterministic(t *testing.T) {
+	a, err := Generate(opt(7))
+	assert.NoError(t, err)
+	b, err := Generate(opt(7))
+	assert.NoError(t, err)
+	assert.Equal(t, a, b)
+
+	c, err := Generate(opt(8))
+	assert.NoError(t, err)
+	assert.NotEqual(t, a.Books, c.Books)
+}
+
+func TestGenerate_Valid(t *testing.T) {
+	data, err := Generate(opt(1))
+	assert.NoError(t, err)
+	assert.Len(t, data.Categories, 25)
+	assert.Len(t, data.Books, 40)
+	assert.Len(t, data.Students, 62)
+	assert.Len(t, data.Loans, 300)
+
+	for _, s := range data.Students {
+		var errMsg []string
+		p := entity.PersonalInfo{PhoneNumber: s.PhoneNumber}
+		p.ValidatePN(&errMsg)
+		assert.Empty(t, errMsg)
+		assert.Equal(t, s.PhoneNumber, p.PhoneNumber)
+		assert.LessOrEqual(t, s.MaxBook, entity.MaxBookLimit)
+	}
+
+	var (
+		activeLoans = map[[2]int]int{}
+		outByBook   = map[int]int{}
+		kinds       = map[string]int{}
+	)
+	for _, l := range data.Loans {
+		switch {
+		case l.IsReturned:
+			kinds["returned"]++
+			assert.NotNil(t, l.ReturnedAt)
+			assert.NotNil(t, l.Sanctions)
+		case l.MustReturnedAt.Before(opt(1).Now):
+			kinds["overdue"]++
+		default:
+			kinds["active"]++
+		}
+		if !l.IsReturned {
+			activeLoans[[2]int{l.student, l.book}]++
+			outByBook[l.book]++
+		}
+	}
+	for _, n := range activeLoans {
+		assert.Equal(t, 1, n)
+	}
+	for i, b := range data.Books {
+		assert.Equal(t, b.Stock-outByBook[i], b.AvailableStock)
+		assert.GreaterOrEqual(t, b.AvailableStock, 0)
+		assert.NotEmpty(t, data.Connections[i])
+		assert.GreaterOrEqual(t, len(b.Description), 50)
+	}
+	assert.Positive(t, kinds["returned"])
+	assert.Positive(t, kinds["overdue"])
+	assert.Positive(t, kinds["active"])
+}
+
+func TestIsbn13_Checksum(t *testing.T) {
+	data, err := Generate(opt(3))
+	assert.NoError(t, err)
+	for _, b := range data.Books {
+		assert.Len(t, b.ISBN, 13)
+		var sum int
+		for i, c := range b.ISBN {
+			d := int(c - '0')
+			if i%2 == 1 {
+				d *= 3
+			}
+			sum += d
+		}
+		assert.Equal(t, 0, sum%10)
+	}
+}
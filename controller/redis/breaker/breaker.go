@@ -0,0 +1,158 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"stmnplibrary/log"
+	"stmnplibrary/metrics"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+var ErrOpen = errors.New("redis circuit breaker is open")
+
+type state int
+
+const (
+	closed state = iota
+	open
+	halfOpen
+)
+
+func (s state) String() string {
+	switch s {
+	case open:
+		return "open"
+	case halfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// Breaker is a go-redis hook failing calls fast with ErrOpen after threshold
+// consecutive failures, once cooldown passes a single probe is let through
+// and its result closes or reopens the circuit.
+type Breaker struct {
+	mu        sync.Mutex
+	state     state
+	failures  int
+	openedAt  time.Time
+	probing   bool
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+}
+
+func New(threshold int, cooldown time.Duration) *Breaker {
+	return &Breaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+	}
+}
+
+func (b *Breaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case open:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return ErrOpen
+		}
+		b.transition(halfOpen)
+		b.probing = true
+		return nil
+	case halfOpen:
+		if b.probing {
+			return ErrOpen
+		}
+		b.probing = true
+	}
+	return nil
+}
+
+func (b *Breaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+	if !isFailure(err) {
+		b.failures = 0
+		if b.state != closed {
+			b.transition(closed)
+		}
+		return
+	}
+	b.failures++
+	if b.state == halfOpen || b.failures >= b.threshold {
+		b.openedAt = b.now()
+		if b.state != open {
+			b.transition(open)
+		}
+	}
+}
+
+// transition must be called with mu held.
+func (b *Breaker) transition(to state) {
+	from := b.state
+	b.state = to
+	if to == closed {
+		metrics.RedisCircuitOpen.Set(0)
+	} else {
+		metrics.RedisCircuitOpen.Set(1)
+	}
+	log.ZapLog.Warn("redis circuit breaker", zap.Stringer("from", from), zap.Stringer("to", to), zap.Int("failures", b.failures))
+}
+
+func (b *Breaker) Open() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state == open && b.now().Sub(b.openedAt) < b.cooldown
+}
+
+// isFailure reports whether err means redis is unreachable, replies from the
+// server (redis.Nil, WRONGTYPE...) and canceled requests don't count.
+func isFailure(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var rerr redis.Error
+	return !errors.As(err, &rerr)
+}
+
+func (b *Breaker) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+func (b *Breaker) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if err := b.allow(); err != nil {
+			cmd.SetErr(err)
+			return err
+		}
+		err := next(ctx, cmd)
+		b.record(err)
+		return err
+	}
+}
+
+func (b *Breaker) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		if err := b.allow(); err != nil {
+			for _, cmd := range cmds {
+				cmd.SetErr(err)
+			}
+			return err
+		}
+		err := next(ctx, cmds)
+		b.record(err)
+		return err
+	}
+}
@@ -0,0 +1,71 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"stmnplibrary/log"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+var errDial = errors.New("dial tcp 127.0.0.1:6379: connect: connection refused")
+
+func setupBreaker(t *testing.T) (*Breaker, *time.Time, func(err error) error) {
+	log.LogInit(zap.NewNop())
+	now := time.Now()
+	b := New(3, 10*time.Second)
+	b.now = func() time.Time { return now }
+
+	hook := b.ProcessHook(func(ctx context.Context, cmd redis.Cmder) error { return cmd.Err() })
+	call := func(err error) error {
+		cmd := redis.NewStatusCmd(context.Background(), "ping")
+		cmd.SetErr(err)
+		return hook(context.Background(), cmd)
+	}
+	return b, &now, call
+}
+
+func TestBreaker_OpensAfterThreshold(t *testing.T) {
+	b, _, call := setupBreaker(t)
+
+	for i := 0; i < 3; i++ {
+		assert.ErrorIs(t, call(errDial), errDial)
+	}
+	assert.True(t, b.Open())
+	assert.ErrorIs(t, call(nil), ErrOpen)
+}
+
+func TestBreaker_ServerRepliesAreNotFailures(t *testing.T) {
+	b, _, call := setupBreaker(t)
+
+	for i := 0; i < 5; i++ {
+		call(redis.Nil)
+	}
+	call(errDial)
+	call(errDial)
+	call(nil)
+	call(errDial)
+	assert.False(t, b.Open())
+}
+
+func TestBreaker_HalfOpenProbe(t *testing.T) {
+	b, now, call := setupBreaker(t)
+	for i := 0; i < 3; i++ {
+		call(errDial)
+	}
+
+	*now = now.Add(11 * time.Second)
+	assert.ErrorIs(t, call(errDial), errDial, "probe goes through after cooldown")
+	assert.True(t, b.Open(), "failed probe reopens")
+	assert.ErrorIs(t, call(nil), ErrOpen)
+
+	*now = now.Add(11 * time.Second)
+	assert.NoError(t, call(nil))
+	assert.False(t, b.Open())
+	assert.NoError(t, call(nil))
+}
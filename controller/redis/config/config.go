@@ -1,11 +1,13 @@
 package config
 
 import (
+	"stmnplibrary/config"
+	"stmnplibrary/controller/redis/breaker"
 	"stmnplibrary/log"
 
 	"context"
-	"os"
 
+	"github.com/redis/go-redis/extra/redisotel/v9"
 	"github.com/redis/go-redis/v9"
 )
 
@@ -13,15 +15,20 @@ func ProviderCTX() context.Context {
 	return context.TODO()
 }
 
-func ConnectRedis(ctx context.Context) (*redis.Client, func()) {
+func ConnectRedis(ctx context.Context, cfg config.Redis) (*redis.Client, func()) {
 	rds := redis.NewClient(&redis.Options{
-		Addr:     os.Getenv("REDIS_ADDR"),
-		Password: os.Getenv("REDIS_PASSWORD"),
-		DB:       0,
+		Addr:     cfg.Addr,
+		Password: cfg.Password,
+		DB:       cfg.DB,
 	})
+	rds.AddHook(breaker.New(cfg.BreakerThreshold, cfg.BreakerCooldown))
+	if err := redisotel.InstrumentTracing(rds); err != nil {
+		log.LogConfig("failed instrument redis tracing", "connect_redis", err)
+		panic(err)
+	}
+	// an unreachable redis is reported by /readyz instead of crashing at startup
 	if err := rds.Ping(ctx).Err(); err != nil {
 		log.LogConfig("failed connect to redis", "connect_redis", err)
-		panic(err)
 	}
 	return rds, func () {
 		if err := rds.Close(); err != nil {
@@ -0,0 +1,52 @@
+package fallback
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimiter(t *testing.T) {
+	now := time.Now()
+	l := NewLimiter(2, time.Minute)
+	l.now = func() time.Time { return now }
+
+	ok, _ := l.Allow("ip:1")
+	assert.True(t, ok)
+	ok, _ = l.Allow("ip:1")
+	assert.True(t, ok)
+	ok, retryAfter := l.Allow("ip:1")
+	assert.False(t, ok)
+	assert.Equal(t, time.Minute, retryAfter)
+
+	ok, _ = l.Allow("ip:2")
+	assert.True(t, ok, "keys are limited separately")
+
+	now = now.Add(time.Minute)
+	ok, _ = l.Allow("ip:1")
+	assert.True(t, ok, "window resets")
+	assert.Len(t, l.windows, 1, "expired windows are pruned")
+}
+
+func TestLRU(t *testing.T) {
+	now := time.Now()
+	c := NewLRU[int](2, time.Minute)
+	c.now = func() time.Time { return now }
+
+	c.Add("a", 1)
+	c.Add("b", 2)
+	_, _ = c.Get("a")
+	c.Add("c", 3)
+
+	_, ok := c.Get("b")
+	assert.False(t, ok, "least recently used is evicted")
+	v, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+
+	now = now.Add(2 * time.Minute)
+	_, ok = c.Get("c")
+	assert.False(t, ok, "expired")
+	assert.Equal(t, 1, c.Len())
+}
@@ -0,0 +1,64 @@
+package fallback
+
+import (
+	"sync"
+	"time"
+)
+
+type window struct {
+	start time.Time
+	count int
+}
+
+// Limiter is an in-memory fixed window limiter used while redis is down, it
+// only sees the traffic of this instance.
+type Limiter struct {
+	mu        sync.Mutex
+	limit     int
+	period    time.Duration
+	windows   map[string]*window
+	lastPrune time.Time
+	now       func() time.Time
+}
+
+func NewLimiter(limit int, period time.Duration) *Limiter {
+	return &Limiter{
+		limit:   limit,
+		period:  period,
+		windows: make(map[string]*window),
+		now:     time.Now,
+	}
+}
+
+// Allow counts a hit for key, when the limit is reached it returns false and
+// how long until the window resets.
+func (l *Limiter) Allow(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	l.prune(now)
+
+	w, ok := l.windows[key]
+	if !ok || now.Sub(w.start) >= l.period {
+		w = &window{start: now}
+		l.windows[key] = w
+	}
+	if w.count >= l.limit {
+		return false, w.start.Add(l.period).Sub(now)
+	}
+	w.count++
+	return true, 0
+}
+
+// prune drops expired windows once per period so idle keys don't pile up.
+func (l *Limiter) prune(now time.Time) {
+	if now.Sub(l.lastPrune) < l.period {
+		return
+	}
+	for key, w := range l.windows {
+		if now.Sub(w.start) >= l.period {
+			delete(l.windows, key)
+		}
+	}
+	l.lastPrune = now
+}
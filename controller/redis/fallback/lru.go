@@ -0,0 +1,76 @@
+package fallback
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+type entry[V any] struct {
+	key       string
+	value     V
+	expiresAt time.Time
+}
+
+// LRU is a size bounded in-memory cache with a ttl per entry, it backs the
+// redis book cache while redis is down.
+type LRU[V any] struct {
+	mu       sync.Mutex
+	capacity int
+	ttl      time.Duration
+	ll       *list.List
+	items    map[string]*list.Element
+	now      func() time.Time
+}
+
+func NewLRU[V any](capacity int, ttl time.Duration) *LRU[V] {
+	return &LRU[V]{
+		capacity: capacity,
+		ttl:      ttl,
+		ll:       list.New(),
+		items:    make(map[string]*list.Element),
+		now:      time.Now,
+	}
+}
+
+func (c *LRU[V]) Get(key string) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var zero V
+	el, ok := c.items[key]
+	if !ok {
+		return zero, false
+	}
+	e := el.Value.(*entry[V])
+	if c.now().After(e.expiresAt) {
+		c.ll.Remove(el)
+		delete(c.items, key)
+		return zero, false
+	}
+	c.ll.MoveToFront(el)
+	return e.value, true
+}
+
+func (c *LRU[V]) Add(key string, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	expiresAt := c.now().Add(c.ttl)
+	if el, ok := c.items[key]; ok {
+		e := el.Value.(*entry[V])
+		e.value, e.expiresAt = value, expiresAt
+		c.ll.MoveToFront(el)
+		return
+	}
+	c.items[key] = c.ll.PushFront(&entry[V]{key: key, value: value, expiresAt: expiresAt})
+	if c.ll.Len() > c.capacity {
+		oldest := c.ll.Back()
+		c.ll.Remove(oldest)
+		delete(c.items, oldest.Value.(*entry[V]).key)
+	}
+}
+
+func (c *LRU[V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
@@ -2,12 +2,13 @@ package repository
 
 import (
 	"context"
+	"stmnplibrary/apperr"
 	"stmnplibrary/domain/entity"
 	"stmnplibrary/domain/interface/repository"
 	"stmnplibrary/constanta"
 	"stmnplibrary/controller/repository/utils"
+	"stmnplibrary/pagination"
 	"time"
-	"fmt"
 	"errors"
 	"strings"
 
@@ -39,9 +40,9 @@ func (ar *adminRepository) getGorm(ctx context.Context) *gorm.DB {
 func (ar *adminRepository) validateQuery(result *gorm.DB) error {
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return errors.New("no data found")
+			return apperr.ErrNotFound
 		}
-		return fmt.Errorf("internal server error: %w", result.Error)
+		return apperr.Internal(result.Error)
 	}
 	return nil
 }
@@ -49,28 +50,16 @@ func (ar *adminRepository) validateQuery(result *gorm.DB) error {
 func (ar *adminRepository) validateExec(result *gorm.DB) error {
 	if result.Error != nil {
 		if strings.Contains(result.Error.Error(), "violates foreign key constraint") {
-			return errors.New("id doesn't exist yet")
+			return apperr.ErrReferenceNotFound
 		}
-		return fmt.Errorf("internal server error: %w", result.Error)
+		return apperr.Internal(result.Error)
 	}
 	if result.RowsAffected == 0 {
-		return errors.New("no data affected")
+		return apperr.ErrNoDataAffected
 	}
 	return nil
 }
 
-func (ar *adminRepository) RedisSETNX(ctx context.Context, key string, value string, ttl time.Duration) (bool, error) {
-	isNew, err := ar.rds.SetNX(ctx, key, value, ttl).Result()
-	if err != nil {
-		return false, utils.ValidateErrRds(err)
-	}
-	if isNew { 
-		return true, nil
-	} else {
-		return false, nil
-	}
-}
-
 func (ar *adminRepository) RedisDel(ctx context.Context, key string) error {
 	if err := ar.rds.Del(ctx, key).Err(); err != nil {
 		return utils.ValidateErrRds(err)
@@ -85,80 +74,81 @@ func (ar *adminRepository) RedisSet(ctx context.Context, key string, data any, t
 	return nil
 }
 
-func (ar *adminRepository) RedisGet(ctx context.Context, key string) (any, error) {
-	result := ar.rds.Get(ctx, key)
-	if result.Err() != nil {
-		return nil, utils.ValidateErrRds(result.Err())
+func (ar *adminRepository) RedisGet(ctx context.Context, key string) ([]byte, error) {
+	result, err := ar.rds.Get(ctx, key).Bytes()
+	if err != nil {
+		return nil, utils.ValidateErrRds(err)
 	}
 	return result, nil
 }
 
+// WithTx runs fn in a transaction, inside the transaction of a caller it
+// runs in a savepoint of it so a book can be added as part of a bigger change.
 func (ar *adminRepository) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
-	return ar.gorm.Transaction(func(tx *gorm.DB) error {
+	return ar.getGorm(ctx).Transaction(func(tx *gorm.DB) error {
 		ctx = context.WithValue(ctx, constanta.TX, tx)
 		return fn(ctx)
 	})
 }
 
-func (ar *adminRepository) GetLoanData(ctx context.Context, offset int) ([]entity.LoanData, error) {
+// loanOrder lists loans newest first.
+var loanOrder = utils.Order{Column: "loan.borrow_at", ID: "loan.id", Desc: true, Key: utils.KeyTime}
+
+// studentOrder lists students by nis.
+var studentOrder = utils.Order{Column: "nis", ID: "id", Key: utils.KeyInt}
+
+// getLoanData lists one page of loans together with the total matching rows,
+// isReturned nil lists every loan.
+func (ar *adminRepository) getLoanData(ctx context.Context, page pagination.Page, isReturned *bool) ([]entity.LoanData, int64, error) {
 	var (
-		limit    = 35
-		loanData = make([]entity.LoanData, 0, limit)
+		total    int64
+		loanData = make([]entity.LoanData, 0, page.Size+1)
+		query    = ar.gorm.WithContext(ctx).Model(&entity.LoanData{})
 	)
-	result := ar.gorm.WithContext(ctx).Model(&entity.LoanData{}).Select(
+	if isReturned != nil {
+		query = query.Where("loan.is_returned = ?", *isReturned)
+	}
+	query = query.Session(&gorm.Session{})
+	if msgErr := ar.validateQuery(query.Count(&total)); msgErr != nil {
+		return nil, 0, msgErr
+	}
+	query, err := utils.Keyset(query, loanOrder, page)
+	if err != nil {
+		return nil, 0, err
+	}
+	result := query.Select(
+		"loan.id",
 		"students.name AS student_name",
 		"books.name AS book_name",
 		"loan.borrow_at",
 		"loan.returned_at",
 		"loan.must_returned_at",
 		"loan.sanctions",
-	).Joins("LEFT JOIN students ON students.id = loan.id_user").Joins("LEFT JOIN books on books.id = loan.id_book").Limit(limit).Offset(offset).Scan(&loanData)
+		"COALESCE(loan.outcome, '') AS outcome",
+		"loan.fee",
+	).Joins("LEFT JOIN students ON students.id = loan.id_user").Joins("LEFT JOIN books on books.id = loan.id_book").Scan(&loanData)
 	if msgErr := ar.validateQuery(result); msgErr != nil {
-		return nil, msgErr
+		return nil, 0, msgErr
 	}
-	return loanData, nil
+	return loanData, total, nil
 }
 
-func (ar *adminRepository) GetLDDone(ctx context.Context, offset int) ([]entity.LoanData, error) {
-	var (
-		limit    = 35
-		loanData = make([]entity.LoanData, 0, limit)
-	)
-	result := ar.gorm.WithContext(ctx).Model(&entity.LoanData{}).Select(
-		"students.name AS student_name",
-		"books.name AS book_name",
-		"loan.borrow_at",
-		"loan.returned_at",
-		"loan.must_returned_at",
-		"loan.sanctions",
-	).Joins("LEFT JOIN students ON students.id = loan.id_user").Joins("LEFT JOIN books on books.id = loan.id_book").Where("loan.is_returned = ?", true).Limit(limit).Offset(offset).Scan(&loanData)
-	if msgErr := ar.validateQuery(result); msgErr != nil {
-		return nil, msgErr
-	}
-	return loanData, nil
+func (ar *adminRepository) GetLoanData(ctx context.Context, page pagination.Page) ([]entity.LoanData, int64, error) {
+	return ar.getLoanData(ctx, page, nil)
 }
 
-func (ar *adminRepository) GetLDDont(ctx context.Context, offset int) ([]entity.LoanData, error) {
-	var (
-		limit    = 35
-		loanData = make([]entity.LoanData, 0, limit)
-	)
-	result := ar.gorm.WithContext(ctx).Model(&entity.LoanData{}).Select(
-		"students.name AS student_name",
-		"books.name AS book_name",
-		"loan.borrow_at",
-		"loan.returned_at",
-		"loan.must_returned_at",
-		"loan.sanctions",
-	).Joins("LEFT JOIN students ON students.id = loan.id_user").Joins("LEFT JOIN books on books.id = loan.id_book").Where("loan.is_returned = ?", false).Limit(limit).Offset(offset).Scan(&loanData)
-	if msgErr := ar.validateQuery(result); msgErr != nil {
-		return nil, msgErr
-	}
-	return loanData, nil
+func (ar *adminRepository) GetLDDone(ctx context.Context, page pagination.Page) ([]entity.LoanData, int64, error) {
+	var isReturned = true
+	return ar.getLoanData(ctx, page, &isReturned)
+}
+
+func (ar *adminRepository) GetLDDont(ctx context.Context, page pagination.Page) ([]entity.LoanData, int64, error) {
+	var isReturned = false
+	return ar.getLoanData(ctx, page, &isReturned)
 }
 
 func (ar *adminRepository) AddCategory(ctx context.Context, data entity.Category) error {
-	result := ar.gorm.WithContext(ctx).Model(&entity.Category{}).Create(data)
+	result := ar.getGorm(ctx).WithContext(ctx).Model(&entity.Category{}).Create(data)
 	if msgErr := ar.validateExec(result); msgErr != nil {
 		return msgErr
 	}
@@ -183,9 +173,9 @@ func (ar *adminRepository) AddConnections(ctx context.Context, data []entity.Con
 	return nil
 }
 
-func (ar *adminRepository) GetStudentId(ctx context.Context, studentName string) (int, error) {
+func (ar *adminRepository) GetStudentId(ctx context.Context, nis int) (int, error) {
 	var id int
-	result := ar.gorm.WithContext(ctx).Model(&entity.Students{}).Select("id").Where("name = ?", studentName).First(&id)
+	result := ar.gorm.WithContext(ctx).Model(&entity.Students{}).Select("id").Where("nis = ?", nis).First(&id)
 	if msgErr := ar.validateQuery(result); msgErr != nil {
 		return 0, msgErr
 	}
@@ -201,9 +191,18 @@ func (ar *adminRepository) GetBookId(ctx context.Context, isbn string) (int, err
 	return id, nil
 }
 
+func (ar *adminRepository) GetActiveLoan(ctx context.Context, id int) (entity.Loan, error) {
+	var loan entity.Loan
+	result := ar.gorm.WithContext(ctx).Select("id_user", "id_book").Where("id = ?", id).Where("is_returned = ?", false).First(&loan)
+	if msgErr := ar.validateQuery(result); msgErr != nil {
+		return entity.Loan{}, msgErr
+	}
+	return loan, nil
+}
+
 func (ar *adminRepository) GetStudentLoan(ctx context.Context, idUser int, idBook int) (entity.LdUpdate, error) {
 	var loanData entity.LdUpdate
-	result := ar.gorm.Debug().WithContext(ctx).Table("loan").Model(&entity.LdUpdate{}).Select(
+	result := ar.getGorm(ctx).Debug().WithContext(ctx).Table("loan").Model(&entity.LdUpdate{}).Select(
 		"must_returned_at", 
 		"sanctions", 
 		"returned_at",
@@ -214,11 +213,14 @@ func (ar *adminRepository) GetStudentLoan(ctx context.Context, idUser int, idBoo
 	return loanData, nil
 }
 
-func (ar *adminRepository) UpdateTabLoan(ctx context.Context, idUser int, idBook int, sanctions int64, returnedAt time.Time) error {        
-	result := ar.gorm.WithContext(ctx).Model(&entity.LoanData{}).Clauses(clause.Locking{Strength: "UPDATE"}).Where("id_user = ?", idUser).Where("id_book = ?", idBook).Where("is_returned = ?", false).UpdateColumns(map[string]interface{}{
+func (ar *adminRepository) UpdateTabLoan(ctx context.Context, idUser int, idBook int, data entity.LoanReturn) error {        
+	result := ar.getGorm(ctx).WithContext(ctx).Model(&entity.LoanData{}).Clauses(clause.Locking{Strength: "UPDATE"}).Where("id_user = ?", idUser).Where("id_book = ?", idBook).Where("is_returned = ?", false).UpdateColumns(map[string]interface{}{
 		"is_returned": true,
-		"returned_at": returnedAt,
-		"sanctions": gorm.Expr("COALESCE(sanctions, 0) + ?", sanctions),
+		"returned_at": data.ReturnedAt,
+		"sanctions": gorm.Expr("COALESCE(sanctions, 0) + ?", data.Sanctions),
+		"outcome": data.Outcome,
+		"condition_note": data.ConditionNote,
+		"fee": data.Fee,
 	})
 	if msgErr := ar.validateExec(result); msgErr != nil {
 		return msgErr
@@ -227,17 +229,142 @@ func (ar *adminRepository) UpdateTabLoan(ctx context.Context, idUser int, idBook
 }
 
 func (ar *adminRepository) UpdateStock(ctx context.Context, idBook int) error {
-	result := ar.gorm.WithContext(ctx).Model(&entity.Book{}).Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", idBook).UpdateColumn("available_stock", gorm.Expr("available_stock + ?", 1))
+	result := ar.getGorm(ctx).WithContext(ctx).Model(&entity.Book{}).Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", idBook).UpdateColumn("available_stock", gorm.Expr("available_stock + ?", 1))
+	if msgErr := ar.validateExec(result); msgErr != nil {
+		return msgErr
+	}
+	return nil
+}
+
+// WriteOffStock takes a lost book out of the stock for good, its available
+// stock already went down when it was borrowed.
+func (ar *adminRepository) WriteOffStock(ctx context.Context, idBook int) error {
+	result := ar.getGorm(ctx).WithContext(ctx).Model(&entity.Book{}).Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", idBook).Where("stock > available_stock").UpdateColumn("stock", gorm.Expr("stock - ?", 1))
 	if msgErr := ar.validateExec(result); msgErr != nil {
 		return msgErr
 	}
 	return nil
 }
 
+func (ar *adminRepository) GetBookPrice(ctx context.Context, idBook int) (int64, error) {
+	var price int64
+	result := ar.getGorm(ctx).WithContext(ctx).Model(&entity.BookData{}).Select("price").Where("id = ?", idBook).Scan(&price)
+	if msgErr := ar.validateQuery(result); msgErr != nil {
+		return 0, msgErr
+	}
+	return price, nil
+}
+
 func (ar *adminRepository) UpdateMaxBook(ctx context.Context, id int) error {
-	result := ar.gorm.WithContext(ctx).Model(&entity.Students{}).Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).Where("max_book > 0").UpdateColumn("max_book", gorm.Expr("max_book - ?", 1))
+	result := ar.getGorm(ctx).WithContext(ctx).Model(&entity.Students{}).Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).Where("max_book > 0").UpdateColumn("max_book", gorm.Expr("max_book - ?", 1))
 	if msgErr := ar.validateExec(result); msgErr != nil {
 		return msgErr
 	}
 	return nil
-}
\ No newline at end of file
+}
+func (ar *adminRepository) GetStudents(ctx context.Context, filter entity.StudentFilter, page pagination.Page) ([]entity.StudentData, int64, error) {
+	var (
+		total    int64
+		students = make([]entity.StudentData, 0, page.Size+1)
+		query    = ar.gorm.WithContext(ctx).Model(&entity.StudentData{}).Where("role = ?", "students")
+	)
+	if filter.NIS != 0 {
+		query = query.Where("nis = ?", filter.NIS)
+	}
+	if filter.Name != "" {
+		query = query.Where("name ILIKE ?", "%"+filter.Name+"%")
+	}
+	if filter.Class != "" {
+		query = query.Where("class = ?", filter.Class)
+	}
+	if filter.Major != "" {
+		query = query.Where("major = ?", filter.Major)
+	}
+	if filter.Batch != 0 {
+		query = query.Where("batch = ?", filter.Batch)
+	}
+	query = query.Session(&gorm.Session{})
+	if msgErr := ar.validateQuery(query.Count(&total)); msgErr != nil {
+		return nil, 0, msgErr
+	}
+	query, err := utils.Keyset(query, studentOrder, page)
+	if err != nil {
+		return nil, 0, err
+	}
+	result := query.Find(&students)
+	if msgErr := ar.validateQuery(result); msgErr != nil {
+		return nil, 0, msgErr
+	}
+	return students, total, nil
+}
+
+func (ar *adminRepository) GetStudent(ctx context.Context, nis int) (entity.StudentData, error) {
+	var student entity.StudentData
+	result := ar.gorm.WithContext(ctx).Model(&entity.StudentData{}).Where("nis = ?", nis).Where("role = ?", "students").First(&student)
+	if msgErr := ar.validateQuery(result); msgErr != nil {
+		return entity.StudentData{}, msgErr
+	}
+	return student, nil
+}
+
+func (ar *adminRepository) GetStudentLoans(ctx context.Context, idUser int) ([]entity.LoanData, error) {
+	var loanData []entity.LoanData
+	result := ar.gorm.WithContext(ctx).Model(&entity.LoanData{}).Select(
+		"loan.id",
+		"books.name AS book_name",
+		"loan.borrow_at",
+		"loan.returned_at",
+		"loan.must_returned_at",
+		"loan.sanctions",
+		"COALESCE(loan.outcome, '') AS outcome",
+		"loan.fee",
+	).Joins("LEFT JOIN books on books.id = loan.id_book").Where("loan.id_user = ?", idUser).Order("loan.borrow_at DESC").Scan(&loanData)
+	if msgErr := ar.validateQuery(result); msgErr != nil {
+		return nil, msgErr
+	}
+	return loanData, nil
+}
+
+func (ar *adminRepository) UpdateStudent(ctx context.Context, nis int, data entity.StudentData) error {
+	result := ar.getGorm(ctx).WithContext(ctx).Model(&entity.StudentData{}).Where("nis = ?", nis).Where("is_active = ?", true).Updates(map[string]interface{}{
+		"name":         data.Name,
+		"phone_number": data.PhoneNumber,
+		"email":        data.Email,
+		"class":        data.Class,
+		"sub_class":    data.SubClass,
+		"major":        data.Major,
+		"batch":        data.Batch,
+	})
+	if msgErr := ar.validateExec(result); msgErr != nil {
+		return msgErr
+	}
+	return nil
+}
+
+func (ar *adminRepository) DeactivateStudent(ctx context.Context, nis int) error {
+	result := ar.getGorm(ctx).WithContext(ctx).Model(&entity.StudentData{}).Where("nis = ?", nis).Where("is_active = ?", true).UpdateColumn("is_active", false)
+	if msgErr := ar.validateExec(result); msgErr != nil {
+		return msgErr
+	}
+	return nil
+}
+
+func (ar *adminRepository) UpdateStudentRole(ctx context.Context, nis int, from string, to string) error {
+	result := ar.getGorm(ctx).WithContext(ctx).Model(&entity.StudentData{}).Where("nis = ?", nis).Where("role = ?", from).Where("is_active = ?", true).UpdateColumn("role", to)
+	if msgErr := ar.validateExec(result); msgErr != nil {
+		return msgErr
+	}
+	return nil
+}
+
+func (ar *adminRepository) GetStats(ctx context.Context) (entity.Stats, error) {
+	var stats entity.Stats
+	result := ar.gorm.WithContext(ctx).Raw(`SELECT
+		(SELECT COUNT(*) FROM loan WHERE is_returned = false) AS active_loans,
+		(SELECT COUNT(*) FROM loan WHERE is_returned = false AND must_returned_at < NOW()) AS overdue_loans,
+		(SELECT COUNT(*) FROM books WHERE available_stock = 0) AS out_of_stock`).Scan(&stats)
+	if msgErr := ar.validateQuery(result); msgErr != nil {
+		return entity.Stats{}, msgErr
+	}
+	return stats, nil
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"stmnplibrary/apperr"
+	"stmnplibrary/constanta"
+	"stmnplibrary/controller/repository/utils"
+	"stmnplibrary/domain/entity"
+	"stmnplibrary/domain/interface/repository"
+	"stmnplibrary/pagination"
+
+	"gorm.io/gorm"
+)
+
+type auditRepository struct {
+	gorm *gorm.DB
+}
+
+func FnAuditRepository(gorm *gorm.DB) repository.AuditRepository {
+	return &auditRepository{
+		gorm: gorm,
+	}
+}
+
+func (ar *auditRepository) getGorm(ctx context.Context) *gorm.DB {
+	tx, ok := ctx.Value(constanta.TX).(*gorm.DB)
+	if !ok {
+		return ar.gorm
+	}
+	return tx
+}
+
+func (ar *auditRepository) validateQuery(result *gorm.DB) error {
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return apperr.ErrNotFound
+		}
+		return apperr.Internal(result.Error)
+	}
+	return nil
+}
+
+func (ar *auditRepository) Record(ctx context.Context, data entity.Audit) error {
+	result := ar.getGorm(ctx).WithContext(ctx).Omit("id", "created_at").Create(&data)
+	if result.Error != nil {
+		return apperr.Internal(fmt.Errorf("failed record audit: %w", result.Error))
+	}
+	if result.RowsAffected == 0 {
+		return apperr.Internal(errors.New("no audit recorded"))
+	}
+	return nil
+}
+
+// auditOrder lists the audit log newest first.
+var auditOrder = utils.Order{Column: "created_at", ID: "id", Desc: true, Key: utils.KeyTime}
+
+func (ar *auditRepository) GetAudits(ctx context.Context, filter entity.AuditFilter, page pagination.Page) ([]entity.Audit, int64, error) {
+	var (
+		total  int64
+		audits = make([]entity.Audit, 0, page.Size+1)
+		query  = ar.gorm.WithContext(ctx).Model(&entity.Audit{})
+	)
+	if filter.ActorID != 0 {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.Entity != "" {
+		query = query.Where("entity = ?", filter.Entity)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("created_at < ?", filter.To)
+	}
+	query = query.Session(&gorm.Session{})
+	if msgErr := ar.validateQuery(query.Count(&total)); msgErr != nil {
+		return nil, 0, msgErr
+	}
+	query, err := utils.Keyset(query, auditOrder, page)
+	if err != nil {
+		return nil, 0, err
+	}
+	result := query.Find(&audits)
+	if msgErr := ar.validateQuery(result); msgErr != nil {
+		return nil, 0, msgErr
+	}
+	return audits, total, nil
+}
@@ -1,13 +1,13 @@
 package repository
 
 import (
+	"stmnplibrary/apperr"
 	"stmnplibrary/domain/entity"
 	"stmnplibrary/domain/interface/repository"
 	"stmnplibrary/controller/repository/utils"
 
 	"context"
 	"errors"
-	"fmt"
 	"strings"
 	"time"
 
@@ -30,9 +30,9 @@ func FnAuthRepository(gorm *gorm.DB, rds *redis.Client) repository.AuthRepositor
 func (ar *authRepository) validateQuery(result *gorm.DB) error {
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return errors.New("no data found")
+			return apperr.ErrNotFound
 		}
-		return fmt.Errorf("internal server error: %w", result.Error)
+		return apperr.Internal(result.Error)
 	}
 	return nil
 }
@@ -40,12 +40,12 @@ func (ar *authRepository) validateQuery(result *gorm.DB) error {
 func (ar *authRepository) validateExec(result *gorm.DB) error {
 	if result.Error != nil {
 		if strings.Contains(result.Error.Error(), "violates foreign key constraint") {
-			return errors.New("id doesn't exist yet")
+			return apperr.ErrReferenceNotFound
 		}
-		return fmt.Errorf("internal server error: %w", result.Error)
+		return apperr.Internal(result.Error)
 	}
 	if result.RowsAffected == 0 {
-		return errors.New("no data affected")
+		return apperr.ErrNoDataAffected
 	}
 	return nil
 }
@@ -80,7 +80,7 @@ func (ar *authRepository) GetPassword(ctx context.Context, nis int) (string, str
 
 func (ar *authRepository) GetId(ctx context.Context, nis int) (int, error) {
 	var id int
-	err := ar.gorm.WithContext(ctx).Model(&entity.Students{}).Select("id").Where("nis = ?", nis).First(&id)
+	err := ar.gorm.WithContext(ctx).Model(&entity.Students{}).Select("id").Where("nis = ?", nis).Where("is_active = ?", true).First(&id)
 	if msgErr := ar.validateQuery(err); msgErr != nil {
 		return 0, msgErr
 	}
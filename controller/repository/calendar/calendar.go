@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"stmnplibrary/apperr"
+	"stmnplibrary/constanta"
+	"stmnplibrary/domain/entity"
+	"stmnplibrary/domain/interface/repository"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type calendarRepository struct {
+	gorm *gorm.DB
+}
+
+func FnCalendarRepository(gorm *gorm.DB) repository.CalendarRepository {
+	return &calendarRepository{gorm: gorm}
+}
+
+func (cr *calendarRepository) getGorm(ctx context.Context) *gorm.DB {
+	tx, ok := ctx.Value(constanta.TX).(*gorm.DB)
+	if !ok {
+		return cr.gorm
+	}
+	return tx
+}
+
+func (cr *calendarRepository) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return cr.gorm.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ctx = context.WithValue(ctx, constanta.TX, tx)
+		return fn(ctx)
+	})
+}
+
+func (cr *calendarRepository) validateQuery(result *gorm.DB) error {
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return apperr.ErrNotFound
+		}
+		return apperr.Internal(result.Error)
+	}
+	return nil
+}
+
+func (cr *calendarRepository) validateExec(result *gorm.DB) error {
+	if result.Error != nil {
+		return apperr.Internal(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return apperr.ErrNotFound
+	}
+	return nil
+}
+
+func (cr *calendarRepository) GetOpeningHours(ctx context.Context) ([]entity.OpeningHours, error) {
+	var hours []entity.OpeningHours
+	result := cr.getGorm(ctx).WithContext(ctx).Order("weekday").Find(&hours)
+	if msgErr := cr.validateQuery(result); msgErr != nil {
+		return nil, msgErr
+	}
+	return hours, nil
+}
+
+func (cr *calendarRepository) SetOpeningHours(ctx context.Context, hours []entity.OpeningHours) error {
+	err := cr.getGorm(ctx).WithContext(ctx).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "weekday"}}, DoUpdates: clause.AssignmentColumns([]string{"is_open", "opens_at", "closes_at"})}).
+		Create(&hours).Error
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	return nil
+}
+
+// GetClosures returns the closures overlapping the days from from to to.
+func (cr *calendarRepository) GetClosures(ctx context.Context, from time.Time, to time.Time) ([]entity.Closure, error) {
+	var closures []entity.Closure
+	result := cr.getGorm(ctx).WithContext(ctx).
+		Where("end_date >= ?::date AND start_date <= ?::date", from.Format(time.DateOnly), to.Format(time.DateOnly)).
+		Order("start_date").Order("id").
+		Find(&closures)
+	if msgErr := cr.validateQuery(result); msgErr != nil {
+		return nil, msgErr
+	}
+	return closures, nil
+}
+
+func (cr *calendarRepository) AddClosure(ctx context.Context, data *entity.Closure) error {
+	if err := cr.getGorm(ctx).WithContext(ctx).Create(data).Error; err != nil {
+		return apperr.Internal(err)
+	}
+	return nil
+}
+
+// UpsertClosures adds imported closures, a closure imported before under the
+// same uid is updated so the same file can be imported again.
+func (cr *calendarRepository) UpsertClosures(ctx context.Context, data []entity.Closure) (int64, error) {
+	result := cr.getGorm(ctx).WithContext(ctx).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "uid"}}, DoUpdates: clause.AssignmentColumns([]string{"name", "kind", "start_date", "end_date"})}).
+		Create(&data)
+	if result.Error != nil {
+		return 0, apperr.Internal(result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+func (cr *calendarRepository) DeleteClosure(ctx context.Context, id int) (entity.Closure, error) {
+	var closure entity.Closure
+	result := cr.getGorm(ctx).WithContext(ctx).
+		Clauses(clause.Returning{}).
+		Where("id = ?", id).
+		Delete(&closure)
+	if msgErr := cr.validateExec(result); msgErr != nil {
+		return entity.Closure{}, msgErr
+	}
+	return closure, nil
+}
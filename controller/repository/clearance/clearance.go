@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"stmnplibrary/apperr"
+	"stmnplibrary/constanta"
+	"stmnplibrary/domain/entity"
+	"stmnplibrary/domain/interface/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type clearanceRepository struct {
+	gorm *gorm.DB
+}
+
+func FnClearanceRepository(gorm *gorm.DB) repository.ClearanceRepository {
+	return &clearanceRepository{gorm: gorm}
+}
+
+func (cr *clearanceRepository) getGorm(ctx context.Context) *gorm.DB {
+	tx, ok := ctx.Value(constanta.TX).(*gorm.DB)
+	if !ok {
+		return cr.gorm
+	}
+	return tx
+}
+
+func (cr *clearanceRepository) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return cr.gorm.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ctx = context.WithValue(ctx, constanta.TX, tx)
+		return fn(ctx)
+	})
+}
+
+func (cr *clearanceRepository) validateQuery(result *gorm.DB) error {
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return apperr.ErrNotFound
+		}
+		return apperr.Internal(result.Error)
+	}
+	return nil
+}
+
+// LockStudent locks the student row like a loan does when it takes a slot of
+// max_book, so no loan starts while the clearance is checked and issued.
+func (cr *clearanceRepository) LockStudent(ctx context.Context, nis int) (entity.StudentData, error) {
+	var student entity.StudentData
+	result := cr.getGorm(ctx).WithContext(ctx).Model(&entity.StudentData{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("nis = ?", nis).Where("role = ?", "students").
+		First(&student)
+	if msgErr := cr.validateQuery(result); msgErr != nil {
+		return entity.StudentData{}, msgErr
+	}
+	return student, nil
+}
+
+func (cr *clearanceRepository) GetStudentsByBatch(ctx context.Context, batch int) ([]entity.StudentData, error) {
+	var students []entity.StudentData
+	result := cr.gorm.WithContext(ctx).Model(&entity.StudentData{}).
+		Where("batch = ?", batch).Where("role = ?", "students").
+		Order("nis").
+		Find(&students)
+	if msgErr := cr.validateQuery(result); msgErr != nil {
+		return nil, msgErr
+	}
+	return students, nil
+}
+
+// CheckClearance counts the loans not returned yet and sums the sanctions not
+// settled yet of a student.
+func (cr *clearanceRepository) CheckClearance(ctx context.Context, idUser int) (entity.ClearanceCheck, error) {
+	var check entity.ClearanceCheck
+	result := cr.getGorm(ctx).WithContext(ctx).Model(&entity.LoanData{}).
+		Select(
+			"COUNT(*) FILTER (WHERE is_returned = FALSE) AS active_loans",
+			"COALESCE(SUM(sanctions) FILTER (WHERE sanctions > 0 AND sanctions_paid_at IS NULL), 0) AS outstanding_sanctions",
+		).
+		Where("id_user = ?", idUser).
+		Scan(&check)
+	if msgErr := cr.validateQuery(result); msgErr != nil {
+		return entity.ClearanceCheck{}, msgErr
+	}
+	return check, nil
+}
+
+func (cr *clearanceRepository) SettleSanctions(ctx context.Context, idUser int) (int64, error) {
+	result := cr.getGorm(ctx).WithContext(ctx).Model(&entity.LoanData{}).
+		Where("id_user = ?", idUser).
+		Where("sanctions > 0 AND sanctions_paid_at IS NULL").
+		UpdateColumn("sanctions_paid_at", gorm.Expr("NOW()"))
+	if result.Error != nil {
+		return 0, apperr.Internal(result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+func (cr *clearanceRepository) AddClearance(ctx context.Context, data *entity.Clearance) error {
+	if err := cr.getGorm(ctx).WithContext(ctx).Create(data).Error; err != nil {
+		return apperr.Internal(err)
+	}
+	return nil
+}
+
+func (cr *clearanceRepository) GetClearance(ctx context.Context, number string) (entity.Clearance, error) {
+	var data entity.Clearance
+	result := cr.gorm.WithContext(ctx).Where("number = ?", number).First(&data)
+	if msgErr := cr.validateQuery(result); msgErr != nil {
+		return entity.Clearance{}, msgErr
+	}
+	return data, nil
+}
+
+func (cr *clearanceRepository) GetClearanceByUser(ctx context.Context, idUser int, purpose string) (entity.Clearance, error) {
+	var data entity.Clearance
+	result := cr.getGorm(ctx).WithContext(ctx).Where("id_user = ?", idUser).Where("purpose = ?", purpose).First(&data)
+	if msgErr := cr.validateQuery(result); msgErr != nil {
+		return entity.Clearance{}, msgErr
+	}
+	return data, nil
+}
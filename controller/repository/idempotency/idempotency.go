@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"stmnplibrary/controller/repository/utils"
+	"stmnplibrary/domain/interface/repository"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type idempotencyRepository struct {
+	rds *redis.Client
+}
+
+func FnIdempotencyRepository(rds *redis.Client) repository.IdempotencyRepository {
+	return &idempotencyRepository{
+		rds: rds,
+	}
+}
+
+func (ir *idempotencyRepository) RedisSETNX(ctx context.Context, key string, data any, ttl time.Duration) (bool, error) {
+	isNew, err := ir.rds.SetNX(ctx, key, data, ttl).Result()
+	if err != nil {
+		return false, utils.ValidateErrRds(err)
+	}
+	return isNew, nil
+}
+
+func (ir *idempotencyRepository) RedisSet(ctx context.Context, key string, data any, ttl time.Duration) error {
+	if err := ir.rds.Set(ctx, key, data, ttl).Err(); err != nil {
+		return utils.ValidateErrRds(err)
+	}
+	return nil
+}
+
+func (ir *idempotencyRepository) RedisGet(ctx context.Context, key string) ([]byte, error) {
+	result, err := ir.rds.Get(ctx, key).Bytes()
+	if err != nil {
+		return nil, utils.ValidateErrRds(err)
+	}
+	return result, nil
+}
+
+func (ir *idempotencyRepository) RedisDel(ctx context.Context, key string) error {
+	if err := ir.rds.Del(ctx, key).Err(); err != nil {
+		return utils.ValidateErrRds(err)
+	}
+	return nil
+}
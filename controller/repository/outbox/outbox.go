@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"stmnplibrary/apperr"
+	"stmnplibrary/constanta"
+	"stmnplibrary/controller/repository/utils"
+	"stmnplibrary/domain/entity"
+	"stmnplibrary/domain/interface/repository"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type outboxRepository struct {
+	gorm *gorm.DB
+	rds  *redis.Client
+}
+
+func FnOutboxRepository(gorm *gorm.DB, rds *redis.Client) repository.OutboxRepository {
+	return &outboxRepository{
+		gorm: gorm,
+		rds:  rds,
+	}
+}
+
+func (ob *outboxRepository) getGorm(ctx context.Context) *gorm.DB {
+	tx, ok := ctx.Value(constanta.TX).(*gorm.DB)
+	if !ok {
+		return ob.gorm
+	}
+	return tx
+}
+
+func (ob *outboxRepository) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return ob.gorm.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ctx = context.WithValue(ctx, constanta.TX, tx)
+		return fn(ctx)
+	})
+}
+
+// Add writes the event with the transaction in ctx, so it only exists when
+// the change it describes was committed.
+func (ob *outboxRepository) Add(ctx context.Context, data entity.Outbox) error {
+	if err := ob.getGorm(ctx).WithContext(ctx).Create(&data).Error; err != nil {
+		return apperr.Internal(err)
+	}
+	return nil
+}
+
+// GetPending locks the next due events, SKIP LOCKED lets several relays run
+// side by side without publishing the same event twice at once.
+func (ob *outboxRepository) GetPending(ctx context.Context, limit int) ([]entity.Outbox, error) {
+	var events []entity.Outbox
+	err := ob.getGorm(ctx).WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("published_at IS NULL AND dead_at IS NULL AND available_at <= NOW()").
+		Order("available_at").Order("id").
+		Limit(limit).
+		Find(&events).Error
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return events, nil
+}
+
+func (ob *outboxRepository) MarkPublished(ctx context.Context, ids []int) error {
+	err := ob.getGorm(ctx).WithContext(ctx).Model(&entity.Outbox{}).
+		Where("id IN ?", ids).
+		Update("published_at", gorm.Expr("NOW()")).Error
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	return nil
+}
+
+func (ob *outboxRepository) MarkRetry(ctx context.Context, id int, lastErr string, availableAt time.Time) error {
+	err := ob.getGorm(ctx).WithContext(ctx).Model(&entity.Outbox{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"attempts":     gorm.Expr("attempts + 1"),
+			"last_error":   lastErr,
+			"available_at": availableAt,
+		}).Error
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	return nil
+}
+
+func (ob *outboxRepository) MarkDead(ctx context.Context, id int, lastErr string) error {
+	err := ob.getGorm(ctx).WithContext(ctx).Model(&entity.Outbox{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"attempts":   gorm.Expr("attempts + 1"),
+			"last_error": lastErr,
+			"dead_at":    gorm.Expr("NOW()"),
+		}).Error
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	return nil
+}
+
+func (ob *outboxRepository) XAdd(ctx context.Context, stream string, maxLen int64, values map[string]any) error {
+	err := ob.rds.XAdd(ctx, &redis.XAddArgs{
+		Stream: stream,
+		MaxLen: maxLen,
+		Approx: true,
+		Values: values,
+	}).Err()
+	if err != nil {
+		return utils.ValidateErrRds(err)
+	}
+	return nil
+}
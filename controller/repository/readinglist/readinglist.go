@@ -0,0 +1,225 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"stmnplibrary/apperr"
+	"stmnplibrary/constanta"
+	"stmnplibrary/controller/repository/utils"
+	"stmnplibrary/domain/entity"
+	"stmnplibrary/domain/interface/repository"
+	"stmnplibrary/pagination"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type readingListRepository struct {
+	gorm *gorm.DB
+}
+
+func FnReadingListRepository(gorm *gorm.DB) repository.ReadingListRepository {
+	return &readingListRepository{gorm: gorm}
+}
+
+func (rr *readingListRepository) getGorm(ctx context.Context) *gorm.DB {
+	tx, ok := ctx.Value(constanta.TX).(*gorm.DB)
+	if !ok {
+		return rr.gorm
+	}
+	return tx
+}
+
+func (rr *readingListRepository) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return rr.gorm.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ctx = context.WithValue(ctx, constanta.TX, tx)
+		return fn(ctx)
+	})
+}
+
+func (rr *readingListRepository) validateQuery(result *gorm.DB) error {
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return apperr.ErrNotFound
+		}
+		return apperr.Internal(result.Error)
+	}
+	return nil
+}
+
+func (rr *readingListRepository) validateExec(result *gorm.DB) error {
+	if result.Error != nil {
+		return apperr.Internal(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return apperr.ErrNotFound
+	}
+	return nil
+}
+
+// withBooks preloads the books of the lists in reading order.
+func withBooks(db *gorm.DB) *gorm.DB {
+	return db.Preload("Books", func(db *gorm.DB) *gorm.DB {
+		return db.Select("reading_list_books.*", "books.name", "books.author").
+			Joins("JOIN books ON books.id = reading_list_books.id_book").
+			Order("reading_list_books.position")
+	})
+}
+
+func (rr *readingListRepository) GetStudent(ctx context.Context, id int) (entity.StudentData, error) {
+	var student entity.StudentData
+	result := rr.gorm.WithContext(ctx).
+		Where("id = ?", id).Where("role = ?", "students").
+		First(&student)
+	if msgErr := rr.validateQuery(result); msgErr != nil {
+		return entity.StudentData{}, msgErr
+	}
+	return student, nil
+}
+
+// GetStudents returns the active students the list is assigned to.
+func (rr *readingListRepository) GetStudents(ctx context.Context, list entity.ReadingList) ([]entity.StudentData, error) {
+	var students []entity.StudentData
+	query := rr.gorm.WithContext(ctx).
+		Where("role = ?", "students").Where("is_active = TRUE").
+		Where("class = ?", list.Class)
+	if list.SubClass != "" {
+		query = query.Where("sub_class = ?", list.SubClass)
+	}
+	if list.Major != "" {
+		query = query.Where("major = ?", list.Major)
+	}
+	result := query.Order("major").Order("sub_class").Order("nis").Find(&students)
+	if msgErr := rr.validateQuery(result); msgErr != nil {
+		return nil, msgErr
+	}
+	return students, nil
+}
+
+func (rr *readingListRepository) CountBooks(ctx context.Context, ids []int) (int64, error) {
+	var count int64
+	result := rr.getGorm(ctx).WithContext(ctx).Model(&entity.Book{}).Where("id IN ?", ids).Count(&count)
+	if msgErr := rr.validateQuery(result); msgErr != nil {
+		return 0, msgErr
+	}
+	return count, nil
+}
+
+func (rr *readingListRepository) AddReadingList(ctx context.Context, data *entity.ReadingList) error {
+	if err := rr.getGorm(ctx).WithContext(ctx).Omit(clause.Associations).Create(data).Error; err != nil {
+		return apperr.Internal(err)
+	}
+	return nil
+}
+
+func (rr *readingListRepository) UpdateReadingList(ctx context.Context, data *entity.ReadingList) error {
+	result := rr.getGorm(ctx).WithContext(ctx).Model(data).
+		Clauses(clause.Returning{}).
+		Omit(clause.Associations).
+		Select("title", "description", "class", "sub_class", "major", "starts_at", "due_at", "updated_at").
+		Updates(data)
+	return rr.validateExec(result)
+}
+
+// SetReadingListBooks replaces the books of a list.
+func (rr *readingListRepository) SetReadingListBooks(ctx context.Context, idList int, books []entity.ReadingListBook) error {
+	var db = rr.getGorm(ctx).WithContext(ctx)
+	if err := db.Where("id_list = ?", idList).Delete(&entity.ReadingListBook{}).Error; err != nil {
+		return apperr.Internal(err)
+	}
+	for i := range books {
+		books[i].IdList = idList
+	}
+	if err := db.Create(&books).Error; err != nil {
+		return apperr.Internal(err)
+	}
+	return nil
+}
+
+func (rr *readingListRepository) DeleteReadingList(ctx context.Context, id int) (entity.ReadingList, error) {
+	var list entity.ReadingList
+	result := rr.getGorm(ctx).WithContext(ctx).
+		Clauses(clause.Returning{}).
+		Where("id = ?", id).
+		Delete(&list)
+	if msgErr := rr.validateExec(result); msgErr != nil {
+		return entity.ReadingList{}, msgErr
+	}
+	return list, nil
+}
+
+func (rr *readingListRepository) GetReadingList(ctx context.Context, id int) (entity.ReadingList, error) {
+	var list entity.ReadingList
+	result := withBooks(rr.getGorm(ctx).WithContext(ctx)).Where("id = ?", id).First(&list)
+	if msgErr := rr.validateQuery(result); msgErr != nil {
+		return entity.ReadingList{}, msgErr
+	}
+	return list, nil
+}
+
+var readingListOrder = utils.Order{Column: "created_at", ID: "id", Desc: true, Key: utils.KeyTime}
+
+func (rr *readingListRepository) GetReadingLists(ctx context.Context, filter entity.ReadingListFilter, page pagination.Page) ([]entity.ReadingList, int64, error) {
+	var (
+		total int64
+		lists = make([]entity.ReadingList, 0, page.Size+1)
+		query = rr.gorm.WithContext(ctx).Model(&entity.ReadingList{})
+	)
+	if filter.Class != "" {
+		query = query.Where("class = ?", filter.Class)
+	}
+	if filter.Major != "" {
+		query = query.Where("major = ?", filter.Major)
+	}
+	if filter.CreatedBy != 0 {
+		query = query.Where("created_by = ?", filter.CreatedBy)
+	}
+	query = query.Session(&gorm.Session{})
+	if msgErr := rr.validateQuery(query.Count(&total)); msgErr != nil {
+		return nil, 0, msgErr
+	}
+	query, err := utils.Keyset(withBooks(query), readingListOrder, page)
+	if err != nil {
+		return nil, 0, err
+	}
+	if msgErr := rr.validateQuery(query.Find(&lists)); msgErr != nil {
+		return nil, 0, msgErr
+	}
+	return lists, total, nil
+}
+
+// GetStudentReadingLists returns the lists assigned to the class, sub class
+// and major of the student, the closest due first.
+func (rr *readingListRepository) GetStudentReadingLists(ctx context.Context, student entity.StudentData) ([]entity.ReadingList, error) {
+	var lists []entity.ReadingList
+	result := withBooks(rr.gorm.WithContext(ctx)).
+		Where("class = ?", student.Class).
+		Where("sub_class IN ('', ?)", student.SubClass).
+		Where("major IN ('', ?)", student.Major).
+		Order("due_at").Order("id").
+		Find(&lists)
+	if msgErr := rr.validateQuery(result); msgErr != nil {
+		return nil, msgErr
+	}
+	return lists, nil
+}
+
+// GetProgress returns the books of the list the students borrowed since it
+// started, with their first return.
+func (rr *readingListRepository) GetProgress(ctx context.Context, list entity.ReadingList, idUsers []int) ([]entity.ReadingProgress, error) {
+	var progress []entity.ReadingProgress
+	if len(idUsers) == 0 {
+		return progress, nil
+	}
+	result := rr.gorm.WithContext(ctx).Model(&entity.LoanData{}).
+		Select("id_user", "id_book", "MIN(returned_at) AS returned_at").
+		Where("id_book IN (?)", rr.gorm.Model(&entity.ReadingListBook{}).Select("id_book").Where("id_list = ?", list.ID)).
+		Where("id_user IN ?", idUsers).
+		Where("borrow_at >= ?", list.StartsAt).
+		Group("id_user").Group("id_book").
+		Scan(&progress)
+	if msgErr := rr.validateQuery(result); msgErr != nil {
+		return nil, msgErr
+	}
+	return progress, nil
+}
@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"stmnplibrary/apperr"
+	"stmnplibrary/controller/repository/utils"
+	"stmnplibrary/domain/entity"
+	"stmnplibrary/domain/interface/repository"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+type recommendationRepository struct {
+	gorm *gorm.DB
+	rds  *redis.Client
+}
+
+func FnRecommendationRepository(gorm *gorm.DB, rds *redis.Client) repository.RecommendationRepository {
+	return &recommendationRepository{gorm: gorm, rds: rds}
+}
+
+func (rr *recommendationRepository) validateQuery(result *gorm.DB) error {
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return apperr.ErrNotFound
+		}
+		return apperr.Internal(result.Error)
+	}
+	return nil
+}
+
+func (rr *recommendationRepository) GetStudent(ctx context.Context, id int) (entity.StudentData, error) {
+	var student entity.StudentData
+	result := rr.gorm.WithContext(ctx).
+		Where("id = ?", id).Where("role = ?", "students").
+		First(&student)
+	if msgErr := rr.validateQuery(result); msgErr != nil {
+		return entity.StudentData{}, msgErr
+	}
+	return student, nil
+}
+
+// GetStudents returns the active students after afterID in id order, the job
+// walks them a batch at a time.
+func (rr *recommendationRepository) GetStudents(ctx context.Context, afterID int, limit int) ([]entity.StudentData, error) {
+	var students []entity.StudentData
+	result := rr.gorm.WithContext(ctx).
+		Where("id > ?", afterID).Where("role = ?", "students").Where("is_active = TRUE").
+		Order("id").
+		Limit(limit).
+		Find(&students)
+	if msgErr := rr.validateQuery(result); msgErr != nil {
+		return nil, msgErr
+	}
+	return students, nil
+}
+
+// candidates scores every book the student hasn't borrowed by the books they
+// borrowed and by the loans of their class and major since @since.
+const candidates = `WITH borrowed AS (
+	SELECT DISTINCT id_book FROM loan WHERE id_user = @user
+), co AS (
+	SELECT theirs.id_book, COUNT(DISTINCT theirs.id_user) AS n
+	FROM loan shared
+	JOIN loan theirs ON theirs.id_user = shared.id_user
+	WHERE shared.id_book IN (SELECT id_book FROM borrowed) AND shared.id_user <> @user
+	GROUP BY theirs.id_book
+), cat AS (
+	SELECT connections.id_book, COUNT(DISTINCT connections.id_category) AS n
+	FROM connections
+	WHERE connections.id_category IN (SELECT id_category FROM connections WHERE id_book IN (SELECT id_book FROM borrowed))
+	GROUP BY connections.id_book
+), au AS (
+	SELECT books.id AS id_book, COUNT(*) AS n
+	FROM books
+	JOIN books same ON same.author = books.author
+	WHERE same.id IN (SELECT id_book FROM borrowed)
+	GROUP BY books.id
+), pop AS (
+	SELECT loan.id_book, COUNT(DISTINCT loan.id_user) AS n
+	FROM loan
+	JOIN students ON students.id = loan.id_user
+	WHERE students.class = @class AND students.major = @major AND students.id <> @user AND loan.borrow_at >= @since
+	GROUP BY loan.id_book
+)
+SELECT books.id, books.name, books.author, books.publisher, books.available_stock, books.rating_avg,
+	COALESCE(co.n, 0) AS co_borrowed, COALESCE(cat.n, 0) AS shared_categories, COALESCE(au.n, 0) AS same_author, COALESCE(pop.n, 0) AS popular
+FROM books
+LEFT JOIN co ON co.id_book = books.id
+LEFT JOIN cat ON cat.id_book = books.id
+LEFT JOIN au ON au.id_book = books.id
+LEFT JOIN pop ON pop.id_book = books.id
+WHERE books.id NOT IN (SELECT id_book FROM borrowed)
+	AND (co.n IS NOT NULL OR cat.n IS NOT NULL OR au.n IS NOT NULL OR pop.n IS NOT NULL)
+ORDER BY @co * COALESCE(co.n, 0) + @cat * COALESCE(cat.n, 0) + @au * COALESCE(au.n, 0) + @pop * COALESCE(pop.n, 0) DESC, books.rating_avg DESC, books.id
+LIMIT @limit`
+
+// GetCandidates returns the limit best scored books for the student, the
+// score is entity.Candidate.Score.
+func (rr *recommendationRepository) GetCandidates(ctx context.Context, student entity.StudentData, since time.Time, limit int) ([]entity.Candidate, error) {
+	var result []entity.Candidate
+	query := rr.gorm.WithContext(ctx).Raw(candidates, map[string]any{
+		"user":  student.ID,
+		"class": student.Class,
+		"major": student.Major,
+		"since": since,
+		"co":    entity.WeightCoBorrowed,
+		"cat":   entity.WeightSharedCategories,
+		"au":    entity.WeightSameAuthor,
+		"pop":   entity.WeightPopular,
+		"limit": limit,
+	}).Scan(&result)
+	if msgErr := rr.validateQuery(query); msgErr != nil {
+		return nil, msgErr
+	}
+	return result, nil
+}
+
+func (rr *recommendationRepository) RedisSet(ctx context.Context, key string, data any, ttl time.Duration) error {
+	if err := rr.rds.Set(ctx, key, data, ttl).Err(); err != nil {
+		return utils.ValidateErrRds(err)
+	}
+	return nil
+}
+
+func (rr *recommendationRepository) RedisGet(ctx context.Context, key string) ([]byte, error) {
+	result, err := rr.rds.Get(ctx, key).Bytes()
+	if err != nil {
+		return nil, utils.ValidateErrRds(err)
+	}
+	return result, nil
+}
@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"stmnplibrary/apperr"
+	"stmnplibrary/constanta"
+	"stmnplibrary/controller/repository/utils"
+	"stmnplibrary/domain/entity"
+	"stmnplibrary/domain/interface/repository"
+	"stmnplibrary/pagination"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type reviewRepository struct {
+	gorm *gorm.DB
+}
+
+func FnReviewRepository(gorm *gorm.DB) repository.ReviewRepository {
+	return &reviewRepository{gorm: gorm}
+}
+
+func (rr *reviewRepository) getGorm(ctx context.Context) *gorm.DB {
+	tx, ok := ctx.Value(constanta.TX).(*gorm.DB)
+	if !ok {
+		return rr.gorm
+	}
+	return tx
+}
+
+func (rr *reviewRepository) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return rr.gorm.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ctx = context.WithValue(ctx, constanta.TX, tx)
+		return fn(ctx)
+	})
+}
+
+func (rr *reviewRepository) validateQuery(result *gorm.DB) error {
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return apperr.ErrNotFound
+		}
+		return apperr.Internal(result.Error)
+	}
+	return nil
+}
+
+func (rr *reviewRepository) validateExec(result *gorm.DB) error {
+	if result.Error != nil {
+		return apperr.Internal(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return apperr.ErrNotFound
+	}
+	return nil
+}
+
+// LockBook locks the book until the end of the transaction so the ratings of
+// concurrent reviews are computed one after the other.
+func (rr *reviewRepository) LockBook(ctx context.Context, idBook int) error {
+	var book entity.Book
+	result := rr.getGorm(ctx).WithContext(ctx).Model(&entity.Book{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").
+		Where("id = ?", idBook).
+		First(&book)
+	return rr.validateQuery(result)
+}
+
+func (rr *reviewRepository) HasReturnedLoan(ctx context.Context, idUser int, idBook int) (bool, error) {
+	var count int64
+	result := rr.getGorm(ctx).WithContext(ctx).Model(&entity.LoanData{}).
+		Where("id_user = ? AND id_book = ? AND is_returned = TRUE", idUser, idBook).
+		Count(&count)
+	if msgErr := rr.validateQuery(result); msgErr != nil {
+		return false, msgErr
+	}
+	return count > 0, nil
+}
+
+// UpdateBookRating computes the rating of the book again from its visible
+// reviews.
+func (rr *reviewRepository) UpdateBookRating(ctx context.Context, idBook int) (entity.Rating, error) {
+	var rating entity.Rating
+	result := rr.getGorm(ctx).WithContext(ctx).Raw(`UPDATE books SET rating_avg = r.rating_avg, rating_count = r.rating_count
+		FROM (SELECT COALESCE(ROUND(AVG(rating), 2), 0) AS rating_avg, COUNT(*) AS rating_count FROM reviews WHERE id_book = ? AND status = ?) r
+		WHERE books.id = ?
+		RETURNING books.rating_avg, books.rating_count`, idBook, entity.ReviewVisible, idBook).
+		Scan(&rating)
+	if msgErr := rr.validateExec(result); msgErr != nil {
+		return entity.Rating{}, msgErr
+	}
+	return rating, nil
+}
+
+func (rr *reviewRepository) GetReview(ctx context.Context, id int) (entity.Review, error) {
+	var review entity.Review
+	result := rr.getGorm(ctx).WithContext(ctx).Where("id = ?", id).First(&review)
+	if msgErr := rr.validateQuery(result); msgErr != nil {
+		return entity.Review{}, msgErr
+	}
+	return review, nil
+}
+
+func (rr *reviewRepository) GetReviewByUser(ctx context.Context, idUser int, idBook int) (entity.Review, error) {
+	var review entity.Review
+	result := rr.getGorm(ctx).WithContext(ctx).Where("id_user = ? AND id_book = ?", idUser, idBook).First(&review)
+	if msgErr := rr.validateQuery(result); msgErr != nil {
+		return entity.Review{}, msgErr
+	}
+	return review, nil
+}
+
+var reviewOrder = utils.Order{Column: "reviews.created_at", ID: "reviews.id", Desc: true, Key: utils.KeyTime}
+
+func (rr *reviewRepository) GetReviews(ctx context.Context, filter entity.ReviewFilter, page pagination.Page) ([]entity.ReviewData, int64, error) {
+	var (
+		total   int64
+		reviews = make([]entity.ReviewData, 0, page.Size+1)
+		query   = rr.gorm.WithContext(ctx).Model(&entity.Review{})
+	)
+	if filter.IdBook != 0 {
+		query = query.Where("reviews.id_book = ?", filter.IdBook)
+	}
+	if filter.Status != "" {
+		query = query.Where("reviews.status = ?", filter.Status)
+	}
+	query = query.Session(&gorm.Session{})
+	if msgErr := rr.validateQuery(query.Count(&total)); msgErr != nil {
+		return nil, 0, msgErr
+	}
+	query, err := utils.Keyset(query.
+		Select("reviews.*", "students.name AS student_name", "books.name AS book_name").
+		Joins("JOIN students ON students.id = reviews.id_user").
+		Joins("JOIN books ON books.id = reviews.id_book"), reviewOrder, page)
+	if err != nil {
+		return nil, 0, err
+	}
+	if msgErr := rr.validateQuery(query.Scan(&reviews)); msgErr != nil {
+		return nil, 0, msgErr
+	}
+	return reviews, total, nil
+}
+
+// UpsertReview adds the review of a student or replaces the rating and text
+// of the one they posted before, the moderation status is kept.
+func (rr *reviewRepository) UpsertReview(ctx context.Context, data *entity.Review) error {
+	err := rr.getGorm(ctx).WithContext(ctx).
+		Clauses(
+			clause.OnConflict{Columns: []clause.Column{{Name: "id_user"}, {Name: "id_book"}}, DoUpdates: clause.AssignmentColumns([]string{"rating", "body", "updated_at"})},
+			clause.Returning{},
+		).
+		Create(data).Error
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	return nil
+}
+
+func (rr *reviewRepository) SetReviewStatus(ctx context.Context, id int, status string, reason string) (entity.Review, error) {
+	var review entity.Review
+	result := rr.getGorm(ctx).WithContext(ctx).Model(&review).
+		Clauses(clause.Returning{}).
+		Where("id = ?", id).
+		Updates(map[string]any{"status": status, "hidden_reason": reason, "updated_at": gorm.Expr("NOW()")})
+	if msgErr := rr.validateExec(result); msgErr != nil {
+		return entity.Review{}, msgErr
+	}
+	return review, nil
+}
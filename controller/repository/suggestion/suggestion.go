@@ -0,0 +1,215 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"stmnplibrary/apperr"
+	"stmnplibrary/constanta"
+	"stmnplibrary/controller/repository/utils"
+	"stmnplibrary/domain/entity"
+	"stmnplibrary/domain/interface/repository"
+	"stmnplibrary/pagination"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type suggestionRepository struct {
+	gorm *gorm.DB
+}
+
+func FnSuggestionRepository(gorm *gorm.DB) repository.SuggestionRepository {
+	return &suggestionRepository{gorm: gorm}
+}
+
+func (sr *suggestionRepository) getGorm(ctx context.Context) *gorm.DB {
+	tx, ok := ctx.Value(constanta.TX).(*gorm.DB)
+	if !ok {
+		return sr.gorm
+	}
+	return tx
+}
+
+func (sr *suggestionRepository) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return sr.gorm.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ctx = context.WithValue(ctx, constanta.TX, tx)
+		return fn(ctx)
+	})
+}
+
+func (sr *suggestionRepository) validateQuery(result *gorm.DB) error {
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return apperr.ErrNotFound
+		}
+		return apperr.Internal(result.Error)
+	}
+	return nil
+}
+
+func (sr *suggestionRepository) validateExec(result *gorm.DB) error {
+	if result.Error != nil {
+		return apperr.Internal(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return apperr.ErrNotFound
+	}
+	return nil
+}
+
+func (sr *suggestionRepository) GetBookID(ctx context.Context, isbn string) (int, error) {
+	var id int
+	result := sr.getGorm(ctx).WithContext(ctx).Model(&entity.Book{}).Select("id").Where("isbn = ?", isbn).First(&id)
+	if msgErr := sr.validateQuery(result); msgErr != nil {
+		return 0, msgErr
+	}
+	return id, nil
+}
+
+// LockBookStock locks the book until the end of the transaction and returns
+// its available stock.
+func (sr *suggestionRepository) LockBookStock(ctx context.Context, idBook int) (int, error) {
+	var book entity.Book
+	result := sr.getGorm(ctx).WithContext(ctx).Model(&entity.Book{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id", "available_stock").
+		Where("id = ?", idBook).
+		First(&book)
+	if msgErr := sr.validateQuery(result); msgErr != nil {
+		return 0, msgErr
+	}
+	return book.AvailableStock, nil
+}
+
+func (sr *suggestionRepository) AddSuggestion(ctx context.Context, data *entity.Suggestion) error {
+	if err := sr.getGorm(ctx).WithContext(ctx).Create(data).Error; err != nil {
+		return apperr.Internal(err)
+	}
+	return nil
+}
+
+// GetOpenSuggestion returns the suggestion of the isbn still in progress.
+func (sr *suggestionRepository) GetOpenSuggestion(ctx context.Context, isbn string) (entity.Suggestion, error) {
+	var suggestion entity.Suggestion
+	result := sr.getGorm(ctx).WithContext(ctx).
+		Where("isbn = ?", isbn).
+		Where("status NOT IN ?", []string{entity.SuggestionReceived, entity.SuggestionRejected}).
+		First(&suggestion)
+	if msgErr := sr.validateQuery(result); msgErr != nil {
+		return entity.Suggestion{}, msgErr
+	}
+	return suggestion, nil
+}
+
+// LockSuggestion returns the suggestion locked until the end of the
+// transaction so votes and status changes happen one after the other.
+func (sr *suggestionRepository) LockSuggestion(ctx context.Context, id int) (entity.Suggestion, error) {
+	var suggestion entity.Suggestion
+	result := sr.getGorm(ctx).WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		First(&suggestion)
+	if msgErr := sr.validateQuery(result); msgErr != nil {
+		return entity.Suggestion{}, msgErr
+	}
+	return suggestion, nil
+}
+
+// suggestionOrders are the sorts offered on the suggestions, an unknown sort
+// falls back to votes.
+var suggestionOrders = map[string]utils.Order{
+	pagination.SortVotes:  {Column: "suggestions.votes", ID: "suggestions.id", Desc: true, Key: utils.KeyInt},
+	pagination.SortNewest: {Column: "suggestions.created_at", ID: "suggestions.id", Desc: true, Key: utils.KeyTime},
+}
+
+func (sr *suggestionRepository) GetSuggestions(ctx context.Context, filter entity.SuggestionFilter, page pagination.Page) ([]entity.SuggestionData, int64, error) {
+	var (
+		total       int64
+		suggestions = make([]entity.SuggestionData, 0, page.Size+1)
+		query       = sr.gorm.WithContext(ctx).Model(&entity.Suggestion{})
+	)
+	if filter.Status != "" {
+		query = query.Where("suggestions.status = ?", filter.Status)
+	}
+	query = query.Session(&gorm.Session{})
+	if msgErr := sr.validateQuery(query.Count(&total)); msgErr != nil {
+		return nil, 0, msgErr
+	}
+	order, ok := suggestionOrders[page.Sort]
+	if !ok {
+		order = suggestionOrders[pagination.SortVotes]
+	}
+	query, err := utils.Keyset(query.
+		Select("suggestions.*", "students.name AS student_name",
+			"EXISTS (SELECT 1 FROM suggestion_votes v WHERE v.id_suggestion = suggestions.id AND v.id_user = ?) AS voted", filter.Voter).
+		Joins("JOIN students ON students.id = suggestions.id_user"), order, page)
+	if err != nil {
+		return nil, 0, err
+	}
+	if msgErr := sr.validateQuery(query.Scan(&suggestions)); msgErr != nil {
+		return nil, 0, msgErr
+	}
+	return suggestions, total, nil
+}
+
+func (sr *suggestionRepository) UpdateSuggestion(ctx context.Context, data *entity.Suggestion) error {
+	result := sr.getGorm(ctx).WithContext(ctx).Model(data).
+		Clauses(clause.Returning{}).
+		Select("isbn", "status", "votes", "note", "id_book", "updated_at").
+		Updates(data)
+	return sr.validateExec(result)
+}
+
+// AddVote records the vote of a student, voting twice is a no-op reported
+// by false.
+func (sr *suggestionRepository) AddVote(ctx context.Context, idSuggestion int, idUser int) (bool, error) {
+	result := sr.getGorm(ctx).WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&entity.SuggestionVote{IdSuggestion: idSuggestion, IdUser: idUser})
+	if result.Error != nil {
+		return false, apperr.Internal(result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (sr *suggestionRepository) CountVotes(ctx context.Context, idSuggestion int) (int, error) {
+	var count int64
+	result := sr.getGorm(ctx).WithContext(ctx).Model(&entity.SuggestionVote{}).Where("id_suggestion = ?", idSuggestion).Count(&count)
+	if msgErr := sr.validateQuery(result); msgErr != nil {
+		return 0, msgErr
+	}
+	return int(count), nil
+}
+
+// GetRequesters returns the students who asked for the suggested book, the
+// first to ask first.
+func (sr *suggestionRepository) GetRequesters(ctx context.Context, idSuggestion int) ([]int, error) {
+	var ids []int
+	result := sr.getGorm(ctx).WithContext(ctx).Model(&entity.SuggestionVote{}).
+		Where("id_suggestion = ?", idSuggestion).
+		Order("created_at").Order("id_user").
+		Pluck("id_user", &ids)
+	if msgErr := sr.validateQuery(result); msgErr != nil {
+		return nil, msgErr
+	}
+	return ids, nil
+}
+
+// AddHolds adds the holds, the open hold a student already has on the book
+// is renewed instead.
+func (sr *suggestionRepository) AddHolds(ctx context.Context, holds []entity.Hold) error {
+	if len(holds) == 0 {
+		return nil
+	}
+	err := sr.getGorm(ctx).WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:     []clause.Column{{Name: "id_user"}, {Name: "id_book"}},
+			TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "fulfilled_at IS NULL"}}},
+			DoUpdates:   clause.AssignmentColumns([]string{"id_suggestion", "expires_at"}),
+		}).
+		Create(&holds).Error
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	return nil
+}
@@ -4,12 +4,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"os"
+	"stmnplibrary/apperr"
+	"stmnplibrary/config"
 	"stmnplibrary/constanta"
+	"stmnplibrary/controller/redis/fallback"
 	"stmnplibrary/controller/repository/utils"
 	"stmnplibrary/domain/entity"
 	"stmnplibrary/domain/interface/repository"
-	"strconv"
+	"stmnplibrary/log"
+	"stmnplibrary/metrics"
+	"stmnplibrary/pagination"
 	"strings"
 	"time"
 
@@ -20,14 +24,20 @@ import (
 )
 
 type userRepository struct {
-	gorm *gorm.DB
-	rds  *redis.Client
+	gorm      *gorm.DB
+	rds       *redis.Client
+	rateLimit config.RateLimit
+	degrade   config.Degrade
+	local     *fallback.Limiter
 }
 
-func FnUserRepository(gorm *gorm.DB, rds *redis.Client) repository.UserRepository {
+func FnUserRepository(gorm *gorm.DB, rds *redis.Client, rateLimit config.RateLimit, degrade config.Degrade) repository.UserRepository {
 	return &userRepository{
-		gorm: gorm,
-		rds:  rds,
+		gorm:      gorm,
+		rds:       rds,
+		rateLimit: rateLimit,
+		degrade:   degrade,
+		local:     fallback.NewLimiter(rateLimit.PerMinute, time.Minute),
 	}
 }
 
@@ -57,9 +67,9 @@ func (ur *userRepository) getDb(ctx context.Context) *gorm.DB {
 func (ur *userRepository) validateQuery(result *gorm.DB) error {
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return errors.New("no data found")
+			return apperr.ErrNotFound
 		}
-		return fmt.Errorf("internal server error: %w", result.Error)
+		return apperr.Internal(result.Error)
 	}
 	return nil
 }
@@ -67,61 +77,44 @@ func (ur *userRepository) validateQuery(result *gorm.DB) error {
 func (ur *userRepository) validateExec(result *gorm.DB) error {
 	if result.Error != nil {
 		if strings.Contains(result.Error.Error(), "violates foreign key constraint") {
-			return errors.New("id doesn't exist yet")
+			return apperr.ErrReferenceNotFound
 		}
-		return fmt.Errorf("internal server error: %w", result.Error)
+		return apperr.Internal(result.Error)
 	}
 	if result.RowsAffected == 0 {
-		return errors.New("no data affected")
+		return apperr.ErrNoDataAffected
 	}
 	return nil
 }
 
 func (ur *userRepository) RateLimiter(ctx context.Context, key string) error {
-	limit, _ := strconv.Atoi(os.Getenv("LIMIT"))
 	limiter := redis_rate.NewLimiter(ur.rds)
-	res, err := limiter.Allow(ctx, key, redis_rate.PerMinute(limit))
+	res, err := limiter.Allow(ctx, key, redis_rate.PerMinute(ur.rateLimit.PerMinute))
 	if err != nil {
-		return fmt.Errorf("internal server error: %w", err)
+		return ur.degradedRateLimiter(ctx, key, err)
 	}
 	if res.Allowed == 0 {
-		return fmt.Errorf("too many request. Try again in: %v", res.RetryAfter)
+		return apperr.New(apperr.KindTooManyRequests, apperr.CodeRateLimited, fmt.Sprintf("too many request. Try again in: %v", res.RetryAfter))
 	}
 	return nil
 }
 
-func (ur *userRepository) RedisZS(ctx context.Context, key string, score float64, member interface{}) error {
-	if err := ur.rds.ZAdd(ctx, key, redis.Z{
-		Score:  score,
-		Member: member,
-	}); err != nil {
-		return utils.ValidateErrRds(err.Err())
-	}
-	if err := ur.rds.Expire(ctx, key, 5*time.Minute); err != nil {
-		return utils.ValidateErrRds(err.Err())
-	}
-	return nil
-}
-
-func (ur *userRepository) RedisZR(ctx context.Context, key string, start int, stop int) ([]string, error) {
-	result := ur.rds.ZRange(ctx, key, int64(start), int64(stop))
-	if msgErr := utils.ValidateErrRds(result.Err()); msgErr != nil {
-		return nil, msgErr
-	}
-	return result.Val(), nil
-}
-
-func (ur *userRepository) RedisWp(ctx context.Context, fn func(ctx context.Context) (interface{}, error)) (interface{}, error) {
-	var pipe = ur.rds.Pipeline()
-	ctx = context.WithValue(ctx, constanta.WP, pipe)
-	result, err := fn(ctx)
-	if err != nil {
-		return nil, utils.ValidateErrRds(err)
-	}
-	if _, err := pipe.Exec(ctx); err != nil {
-		return nil, fmt.Errorf("internal server error: %w", err)
+// degradedRateLimiter applies the rate limit policy while redis fails: fallback
+// counts on the local limiter, open lets the request through, closed rejects it.
+func (ur *userRepository) degradedRateLimiter(ctx context.Context, key string, err error) error {
+	var policy = ur.degrade.RateLimitPolicy
+	metrics.Degraded.WithLabelValues("rate_limit", policy).Inc()
+	log.LogDegraded(ctx, "rate_limit", policy, err)
+	switch policy {
+	case "open":
+		return nil
+	case "fallback":
+		if allowed, retryAfter := ur.local.Allow(key); !allowed {
+			return apperr.New(apperr.KindTooManyRequests, apperr.CodeRateLimited, fmt.Sprintf("too many request. Try again in: %v", retryAfter))
+		}
+		return nil
 	}
-	return result, nil
+	return utils.ValidateErrRds(err)
 }
 
 func (ur *userRepository) RedisWtx(ctx context.Context, fn func(ctx context.Context) error) error {
@@ -137,23 +130,7 @@ func (ur *userRepository) RedisWtx(ctx context.Context, fn func(ctx context.Cont
 		return utils.ValidateErrRds(err)
 	}
 	if _, err := txPipe.Exec(ctx); err != nil {
-		return fmt.Errorf("internal server error: %w", err)
-	}
-	return nil
-}
-
-func (ur *userRepository) RedisHGetAll(ctx context.Context, key string, dest any) error {
-	var rds = ur.getRDS(ctx, "batch")
-	if err := rds.HGetAll(ctx, key).Scan(&dest); err != nil {
-		return utils.ValidateErrRds(err)
-	}
-	return nil
-}
-
-func (ur *userRepository) RedisHSET(ctx context.Context, key string, value any) error {
-	var rds = ur.getRDS(ctx, "batch")
-	if err := rds.HSet(ctx, key, value, 5*time.Minute).Err(); err != nil {
-		return utils.ValidateErrRds(err)
+		return apperr.Internal(err)
 	}
 	return nil
 }
@@ -174,10 +151,10 @@ func (ur *userRepository) RedisSet(ctx context.Context, key string, data any, tt
 	return nil
 }
 
-func (ur *userRepository) RedisGet(ctx context.Context, key string) (any, error) {
-	result := ur.rds.Get(ctx, key)
-	if result.Err() != nil {
-		return nil, utils.ValidateErrRds(result.Err())
+func (ur *userRepository) RedisGet(ctx context.Context, key string) ([]byte, error) {
+	result, err := ur.rds.Get(ctx, key).Bytes()
+	if err != nil {
+		return nil, utils.ValidateErrRds(err)
 	}
 	return result, nil
 }
@@ -190,12 +167,12 @@ func (ur *userRepository) WithContext(ctx context.Context, fn func(context.Conte
 }
 
 func (ur *userRepository) Register(ctx context.Context, data *entity.Students) error {
-	result := ur.gorm.WithContext(ctx).Create(&data)
+	result := ur.getDb(ctx).WithContext(ctx).Create(&data)
 	if result.Error != nil {
-		return fmt.Errorf("failed save data: %v", result.Error)
+		return apperr.Internal(fmt.Errorf("failed save data: %w", result.Error))
 	}
 	if result.RowsAffected == 0 {
-		return fmt.Errorf("no data saved")
+		return apperr.Internal(errors.New("no data saved"))
 	}
 	return nil
 }
@@ -203,7 +180,7 @@ func (ur *userRepository) Register(ctx context.Context, data *entity.Students) e
 func (ur *userRepository) GetNIS(ctx context.Context, nis int) error {
 	var result entity.Students
 	err := ur.gorm.WithContext(ctx).Select("nis").Where("nis = ?", nis).First(&result).Error
-	if msgErr := utils.ValidateErrIDK(err, "nis already registered"); msgErr != nil {
+	if msgErr := utils.ValidateErrIDK(err, apperr.CodeNISRegistered, "nis already registered"); msgErr != nil {
 		return msgErr
 	}
 	return nil
@@ -212,77 +189,139 @@ func (ur *userRepository) GetNIS(ctx context.Context, nis int) error {
 func (ur *userRepository) GetEmail(ctx context.Context, email string) error {
 	var result entity.Students
 	err := ur.gorm.WithContext(ctx).Select("email").Where("email = ?", email).First(&result).Error
-	if msgErr := utils.ValidateErrIDK(err, "email already used"); msgErr != nil {
+	if msgErr := utils.ValidateErrIDK(err, apperr.CodeEmailUsed, "email already used"); msgErr != nil {
 		return msgErr
 	}
 	return nil
 }
 
-func (ur *userRepository) GetBooks(ctx context.Context, offset int) ([]entity.Book, error) {
+// bookOrders are the sorts offered on the catalog.
+var bookOrders = map[string]utils.Order{
+	pagination.SortTitle:        {Column: "books.name", ID: "books.id", Key: utils.KeyString},
+	pagination.SortAuthor:       {Column: "books.author", ID: "books.id", Key: utils.KeyString},
+	pagination.SortNewest:       {Column: "books.created_at", ID: "books.id", Desc: true, Key: utils.KeyTime},
+	pagination.SortAvailability: {Column: "books.available_stock", ID: "books.id", Desc: true, Key: utils.KeyInt},
+	pagination.SortRating:       {Column: "books.rating_avg", ID: "books.id", Desc: true, Key: utils.KeyFloat},
+}
+
+var bookColumns = []string{"books.id", "books.name", "books.author", "books.publisher", "books.description", "books.available_stock", "books.rating_avg", "books.rating_count", "books.created_at"}
+
+// bookPage applies the keyset page of the requested sort, an unknown sort
+// falls back to title.
+func (ur *userRepository) bookPage(query *gorm.DB, page pagination.Page) (*gorm.DB, error) {
+	order, ok := bookOrders[page.Sort]
+	if !ok {
+		order = bookOrders[pagination.SortTitle]
+	}
+	return utils.Keyset(query, order, page)
+}
+
+func (ur *userRepository) GetBooks(ctx context.Context, page pagination.Page) ([]entity.Book, int64, error) {
 	var (
-		limit = 35
+		total int64
 		books []entity.Book
 	)
-	result := ur.gorm.WithContext(ctx).Select("id", "name", "author", "publisher", "description", "available_stock").Preload("Categories", func(db *gorm.DB) *gorm.DB {
+	if msgErr := ur.validateQuery(ur.gorm.WithContext(ctx).Model(&entity.Book{}).Count(&total)); msgErr != nil {
+		return nil, 0, msgErr
+	}
+	query, err := ur.bookPage(ur.gorm.WithContext(ctx).Select(bookColumns).Preload("Categories", func(db *gorm.DB) *gorm.DB {
 		return db.Select("id", "name")
-	}).Limit(limit).Offset(offset).Find(&books)
-	if msgErr := ur.validateQuery(result); msgErr != nil {
-		return nil, msgErr
+	}), page)
+	if err != nil {
+		return nil, 0, err
+	}
+	if msgErr := ur.validateQuery(query.Find(&books)); msgErr != nil {
+		return nil, 0, msgErr
 	}
-	return books, nil
+	return books, total, nil
+}
+
+// booksJoin is the books to categories join shared by the filtered listings
+// and their count queries.
+func (ur *userRepository) booksJoin(ctx context.Context) *gorm.DB {
+	return ur.gorm.WithContext(ctx).Model(&entity.Book{}).Joins("JOIN connections ON connections.id_book = books.id").Joins("JOIN categories ON categories.id = connections.id_category")
 }
 
-func (ur *userRepository) GetBooksByAuthor(ctx context.Context, author string, offset int) ([]entity.Book, error) {
+func (ur *userRepository) GetBooksByAuthor(ctx context.Context, author string, page pagination.Page) ([]entity.Book, int64, error) {
 	var (
-		limit = 35
+		total int64
 		books []entity.Book
 		where = "%" + author + "%"
 	)
-	result := ur.gorm.WithContext(ctx).Select("books.id", "books.name", "books.author", "books.publisher", "books.description", "books.available_stock").Preload("Categories").Joins("JOIN connections ON connections.id_book = books.id").Joins("JOIN categories ON categories.id = connections.id_category").Where("books.author ILIKE ?", where).Limit(limit).Offset(offset).Distinct().Find(&books)
-	if msgErr := ur.validateQuery(result); msgErr != nil {
-		return nil, msgErr
+	if msgErr := ur.validateQuery(ur.booksJoin(ctx).Where("books.author ILIKE ?", where).Distinct("books.id").Count(&total)); msgErr != nil {
+		return nil, 0, msgErr
+	}
+	query, err := ur.bookPage(ur.booksJoin(ctx).Select(bookColumns).Preload("Categories").Where("books.author ILIKE ?", where).Distinct(), page)
+	if err != nil {
+		return nil, 0, err
 	}
-	return books, nil
+	if msgErr := ur.validateQuery(query.Find(&books)); msgErr != nil {
+		return nil, 0, msgErr
+	}
+	return books, total, nil
 }
 
-func (ur *userRepository) GetBooksByCategory(ctx context.Context, category []string, offset int) ([]entity.Book, error) {
+func (ur *userRepository) GetBooksByCategory(ctx context.Context, category []string, page pagination.Page) ([]entity.Book, int64, error) {
 	var (
 		books []entity.Book
-		limit = 35
+		total int64
 	)
-	result := ur.gorm.WithContext(ctx).Select("books.id", "books.name", "books.author", "books.publisher", "books.description", "books.available_stock").Preload("Categories").Joins("JOIN connections ON connections.id_book = books.id").Joins("JOIN categories ON categories.id = connections.id_category").Where("categories.name IN ?", category).Limit(limit).Offset(offset).Distinct().Find(&books)
-	if msgErr := ur.validateQuery(result); msgErr != nil {
-		return nil, msgErr
+	if msgErr := ur.validateQuery(ur.booksJoin(ctx).Where("categories.name IN ?", category).Distinct("books.id").Count(&total)); msgErr != nil {
+		return nil, 0, msgErr
+	}
+	query, err := ur.bookPage(ur.booksJoin(ctx).Select(bookColumns).Preload("Categories").Where("categories.name IN ?", category).Distinct(), page)
+	if err != nil {
+		return nil, 0, err
 	}
-	return books, nil
+	if msgErr := ur.validateQuery(query.Find(&books)); msgErr != nil {
+		return nil, 0, msgErr
+	}
+	return books, total, nil
 }
 
 func (ur *userRepository) CheckLoan(ctx context.Context, idBook int, idUser int) error {
 	var test entity.Loan
 	const isReturn = false
 	result := ur.gorm.WithContext(ctx).Select("id_user", "id_book").Where("id_user = ?", idUser).Where("id_book = ?", idBook).Where("is_returned = ?", false).First(&test)
-	if msgErr := utils.ValidateErrIDK(result.Error, "you have already borrowed that book"); msgErr != nil {
+	if msgErr := utils.ValidateErrIDK(result.Error, apperr.CodeAlreadyBorrowed, "you have already borrowed that book"); msgErr != nil {
 		return msgErr
 	}
 	return nil
 }
 
 func (ur *userRepository) UpdateLimitLoan(ctx context.Context, id int) error {
-	result := ur.getDb(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).Model(&entity.Students{}).WithContext(ctx).Where("id = ?", id).Where("max_book < ?", 3).UpdateColumn("max_book", gorm.Expr("max_book + ?", 1))
+	result := ur.getDb(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).Model(&entity.Students{}).WithContext(ctx).Where("id = ?", id).Where("max_book < ?", entity.MaxBookLimit).UpdateColumn("max_book", gorm.Expr("max_book + ?", 1))
 	if msgErr := ur.validateExec(result); msgErr != nil {
 		return msgErr
 	}
 	return nil
 }
 
-func (ur *userRepository) UpdateBookStock(ctx context.Context, idBook int) error {
-	result := ur.gorm.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).Model(&entity.Book{}).Where("id = ?", idBook).Where("available_stock > ?", 0).UpdateColumn("available_stock", gorm.Expr("available_stock - ?", 1))
+// UpdateBookStock takes a copy of the book out, the copies held for other
+// students can't be taken.
+func (ur *userRepository) UpdateBookStock(ctx context.Context, idBook int, idUser int, now time.Time) error {
+	held := ur.getDb(ctx).Model(&entity.Hold{}).Select("COUNT(*)").
+		Where("id_book = ?", idBook).Where("id_user <> ?", idUser).
+		Where("fulfilled_at IS NULL").Where("expires_at > ?", now)
+	result := ur.getDb(ctx).WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).Model(&entity.Book{}).Where("id = ?", idBook).Where("available_stock > (?)", held).UpdateColumn("available_stock", gorm.Expr("available_stock - ?", 1))
 	if msgErr := ur.validateExec(result); msgErr != nil {
 		return msgErr
 	}
 	return nil
 }
 
+// FulfillHold closes the open hold of the student on the book, if any.
+func (ur *userRepository) FulfillHold(ctx context.Context, idBook int, idUser int, now time.Time) error {
+	result := ur.getDb(ctx).WithContext(ctx).Model(&entity.Hold{}).
+		Where("id_book = ?", idBook).Where("id_user = ?", idUser).
+		Where("fulfilled_at IS NULL").Where("expires_at > ?", now).
+		UpdateColumn("fulfilled_at", now)
+	if result.Error != nil {
+		return apperr.Internal(result.Error)
+	}
+	return nil
+}
+
 func (ur *userRepository) CreateLoan(ctx context.Context, loanData entity.Loan) error {
 	result := ur.getDb(ctx).WithContext(ctx).Create(&loanData)
 	if msgErr := ur.validateExec(result); msgErr != nil {
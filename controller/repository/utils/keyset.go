@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"stmnplibrary/apperr"
+	"stmnplibrary/pagination"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Order is the keyset order of a listing: the sort column, the id column
+// breaking ties and how a cursor key is turned back into a column value.
+type Order struct {
+	Column string
+	ID     string
+	Desc   bool
+	Key    func(key string) (any, error)
+}
+
+func KeyString(key string) (any, error) {
+	return key, nil
+}
+
+func KeyInt(key string) (any, error) {
+	return strconv.Atoi(key)
+}
+
+func KeyFloat(key string) (any, error) {
+	return strconv.ParseFloat(key, 64)
+}
+
+func KeyTime(key string) (any, error) {
+	return time.Parse(time.RFC3339Nano, key)
+}
+
+// Keyset continues query right after the cursor of page, the row comparison
+// is served by the (column, id) index however deep the page is. One row more
+// than the page size is fetched so pagination.Trim can tell if there is a
+// next page.
+func Keyset(query *gorm.DB, order Order, page pagination.Page) (*gorm.DB, error) {
+	var dir, cmp = " ASC", " > "
+	if order.Desc {
+		dir, cmp = " DESC", " < "
+	}
+	if page.After != nil {
+		key, err := order.Key(page.After.Key)
+		if err != nil {
+			return nil, apperr.Wrap(err, apperr.KindInvalid, apperr.CodeInvalidCursor, "invalid cursor")
+		}
+		query = query.Where("("+order.Column+", "+order.ID+")"+cmp+"(?, ?)", key, page.After.ID)
+	}
+	return query.Order(order.Column + dir).Order(order.ID + dir).Limit(page.Size + 1), nil
+}
@@ -3,34 +3,39 @@ package utils
 import (
 	"context"
 	"errors"
-	"fmt"
+	"stmnplibrary/apperr"
+	"stmnplibrary/controller/redis/breaker"
 
 	"github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
 )
 
-func ValidateErrIDK(err error, msg string) error {
+func ValidateErrIDK(err error, code string, msg string) error {
 	if err == nil {
-		return fmt.Errorf("%s", msg)
+		return apperr.New(apperr.KindInvalid, code, msg)
 	}
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil
 	}
-	return fmt.Errorf("internal server error: %w", err)
+	return apperr.Internal(err)
 }
 
 func ValidateErrRds(err error) error {
-	if err == nil {
-		return nil
+	var typed *apperr.Error
+	if err == nil || errors.As(err, &typed) {
+		return err
 	}
 	if errors.Is(err, redis.Nil) {
-		return errors.New("no data found")
+		return apperr.ErrNotFound
+	}
+	if errors.Is(err, breaker.ErrOpen) {
+		return apperr.Unavailable(err)
 	}
 	if errors.Is(err, context.DeadlineExceeded) {
-		return fmt.Errorf("operation timed out")
+		return apperr.Wrap(err, apperr.KindInternal, apperr.CodeTimeout, "operation timed out")
 	}
 	if errors.Is(err, context.Canceled) {
-		return fmt.Errorf("operation was canceled by the client")
+		return apperr.Wrap(err, apperr.KindInternal, apperr.CodeCanceled, "operation was canceled by the client")
 	}
-	return fmt.Errorf("internal server error: %w", err)
+	return apperr.Internal(err)
 }
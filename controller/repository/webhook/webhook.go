@@ -0,0 +1,266 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"stmnplibrary/apperr"
+	"stmnplibrary/constanta"
+	"stmnplibrary/controller/repository/utils"
+	"stmnplibrary/domain/entity"
+	"stmnplibrary/domain/interface/repository"
+	"stmnplibrary/pagination"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type webhookRepository struct {
+	gorm *gorm.DB
+	rds  *redis.Client
+}
+
+func FnWebhookRepository(gorm *gorm.DB, rds *redis.Client) repository.WebhookRepository {
+	return &webhookRepository{
+		gorm: gorm,
+		rds:  rds,
+	}
+}
+
+func (wr *webhookRepository) getGorm(ctx context.Context) *gorm.DB {
+	tx, ok := ctx.Value(constanta.TX).(*gorm.DB)
+	if !ok {
+		return wr.gorm
+	}
+	return tx
+}
+
+func (wr *webhookRepository) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return wr.gorm.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ctx = context.WithValue(ctx, constanta.TX, tx)
+		return fn(ctx)
+	})
+}
+
+func (wr *webhookRepository) validateQuery(result *gorm.DB) error {
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return apperr.ErrNotFound
+		}
+		return apperr.Internal(result.Error)
+	}
+	return nil
+}
+
+func (wr *webhookRepository) validateExec(result *gorm.DB) error {
+	if result.Error != nil {
+		return apperr.Internal(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return apperr.ErrNotFound
+	}
+	return nil
+}
+
+// deliveryOrder lists the delivery log newest first.
+var deliveryOrder = utils.Order{Column: "created_at", ID: "id", Desc: true, Key: utils.KeyTime}
+
+func (wr *webhookRepository) AddSubscription(ctx context.Context, data *entity.WebhookSubscription) error {
+	if err := wr.getGorm(ctx).WithContext(ctx).Create(data).Error; err != nil {
+		return apperr.Internal(err)
+	}
+	return nil
+}
+
+func (wr *webhookRepository) GetSubscriptions(ctx context.Context, activeOnly bool) ([]entity.WebhookSubscription, error) {
+	var (
+		subscriptions []entity.WebhookSubscription
+		query         = wr.gorm.WithContext(ctx)
+	)
+	if activeOnly {
+		query = query.Where("active")
+	}
+	if msgErr := wr.validateQuery(query.Order("id").Find(&subscriptions)); msgErr != nil {
+		return nil, msgErr
+	}
+	return subscriptions, nil
+}
+
+func (wr *webhookRepository) DeactivateSubscription(ctx context.Context, id int) error {
+	result := wr.getGorm(ctx).WithContext(ctx).Model(&entity.WebhookSubscription{}).
+		Where("id = ? AND active", id).
+		Update("active", false)
+	return wr.validateExec(result)
+}
+
+// AddDeliveries skips deliveries that already exist, the outbox stream is at
+// least once so the same event may be read twice.
+func (wr *webhookRepository) AddDeliveries(ctx context.Context, data []entity.WebhookDelivery) error {
+	err := wr.getGorm(ctx).WithContext(ctx).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "subscription_id"}, {Name: "event_id"}}, DoNothing: true}).
+		Create(&data).Error
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	return nil
+}
+
+// ClaimDeliveries takes the due deliveries of active subscriptions and pushes
+// their next attempt lease into the future, so the http calls run outside the
+// transaction while another dispatcher skips them.
+func (wr *webhookRepository) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]entity.DueDelivery, error) {
+	var deliveries []entity.DueDelivery
+	err := wr.gorm.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Table("webhook_deliveries AS d").
+			Select("d.*, s.url, s.secret").
+			Joins("JOIN webhook_subscriptions AS s ON s.id = d.subscription_id").
+			Where("d.status IN ? AND d.next_attempt_at <= NOW() AND s.active", []string{"pending", "failed"}).
+			Order("d.next_attempt_at").Order("d.id").
+			Limit(limit).
+			Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "d"}, Options: "SKIP LOCKED"}).
+			Find(&deliveries).Error
+		if err != nil || len(deliveries) == 0 {
+			return err
+		}
+		var ids = make([]int, 0, len(deliveries))
+		for _, d := range deliveries {
+			ids = append(ids, d.ID)
+		}
+		return tx.Model(&entity.WebhookDelivery{}).
+			Where("id IN ?", ids).
+			Update("next_attempt_at", time.Now().Add(lease)).Error
+	})
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return deliveries, nil
+}
+
+func (wr *webhookRepository) SaveAttempt(ctx context.Context, data entity.WebhookDelivery) error {
+	err := wr.gorm.WithContext(ctx).Model(&entity.WebhookDelivery{}).
+		Where("id = ?", data.ID).
+		Updates(map[string]any{
+			"status":          data.Status,
+			"attempts":        data.Attempts,
+			"response_status": data.ResponseStatus,
+			"response_body":   data.ResponseBody,
+			"last_error":      data.LastError,
+			"next_attempt_at": data.NextAttemptAt,
+			"delivered_at":    data.DeliveredAt,
+		}).Error
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	return nil
+}
+
+func (wr *webhookRepository) GetDeliveries(ctx context.Context, subscriptionID int, status string, page pagination.Page) ([]entity.WebhookDelivery, int64, error) {
+	var (
+		total      int64
+		deliveries = make([]entity.WebhookDelivery, 0, page.Size+1)
+		query      = wr.gorm.WithContext(ctx).Model(&entity.WebhookDelivery{}).Where("subscription_id = ?", subscriptionID)
+	)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	query = query.Session(&gorm.Session{})
+	if msgErr := wr.validateQuery(query.Count(&total)); msgErr != nil {
+		return nil, 0, msgErr
+	}
+	query, err := utils.Keyset(query, deliveryOrder, page)
+	if err != nil {
+		return nil, 0, err
+	}
+	if msgErr := wr.validateQuery(query.Find(&deliveries)); msgErr != nil {
+		return nil, 0, msgErr
+	}
+	return deliveries, total, nil
+}
+
+// ResetDelivery queues a delivery again from its first attempt.
+func (wr *webhookRepository) ResetDelivery(ctx context.Context, id int) error {
+	result := wr.getGorm(ctx).WithContext(ctx).Model(&entity.WebhookDelivery{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"status":          "pending",
+			"attempts":        0,
+			"last_error":      "",
+			"next_attempt_at": gorm.Expr("NOW()"),
+		})
+	return wr.validateExec(result)
+}
+
+// CreateGroup creates the consumer group reading the stream from its start,
+// an existing group is left as it is.
+func (wr *webhookRepository) CreateGroup(ctx context.Context, stream string, group string) error {
+	err := wr.rds.XGroupCreateMkStream(ctx, stream, group, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return utils.ValidateErrRds(err)
+	}
+	return nil
+}
+
+// ReadEvents first takes over the events another consumer left unacked for
+// minIdle, then reads new ones, without blocking.
+func (wr *webhookRepository) ReadEvents(ctx context.Context, stream string, group string, consumer string, count int, minIdle time.Duration) ([]entity.StreamEvent, error) {
+	claimed, _, err := wr.rds.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+		Stream:   stream,
+		Group:    group,
+		Consumer: consumer,
+		MinIdle:  minIdle,
+		Start:    "0-0",
+		Count:    int64(count),
+	}).Result()
+	if err != nil {
+		return nil, utils.ValidateErrRds(err)
+	}
+	var messages = claimed
+	if len(messages) < count {
+		streams, err := wr.rds.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    group,
+			Consumer: consumer,
+			Streams:  []string{stream, ">"},
+			Count:    int64(count - len(messages)),
+			Block:    -1,
+		}).Result()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return nil, utils.ValidateErrRds(err)
+		}
+		for _, s := range streams {
+			messages = append(messages, s.Messages...)
+		}
+	}
+	var events = make([]entity.StreamEvent, 0, len(messages))
+	for _, m := range messages {
+		events = append(events, streamEvent(m))
+	}
+	return events, nil
+}
+
+func streamEvent(m redis.XMessage) entity.StreamEvent {
+	field := func(name string) string {
+		v, _ := m.Values[name].(string)
+		return v
+	}
+	id, _ := strconv.Atoi(field("id"))
+	return entity.StreamEvent{
+		StreamID:    m.ID,
+		ID:          id,
+		Type:        field("type"),
+		Aggregate:   field("aggregate"),
+		AggregateID: field("aggregate_id"),
+		Payload:     field("payload"),
+		TraceID:     field("trace_id"),
+		CreatedAt:   field("created_at"),
+	}
+}
+
+func (wr *webhookRepository) AckEvents(ctx context.Context, stream string, group string, ids []string) error {
+	if err := wr.rds.XAck(ctx, stream, group, ids...).Err(); err != nil {
+		return utils.ValidateErrRds(err)
+	}
+	return nil
+}
@@ -2,142 +2,142 @@ package service
 
 import (
 	"context"
-	"errors"
+	"stmnplibrary/apperr"
 	"fmt"
+	"stmnplibrary/audit"
+	"stmnplibrary/calendar"
+	"stmnplibrary/clock"
 	"stmnplibrary/domain/entity"
 	"stmnplibrary/domain/interface/repository"
 	"stmnplibrary/domain/interface/service"
 	"stmnplibrary/dto"
-	"stmnplibrary/constanta"
 	"stmnplibrary/controller/service/utils"
+	"stmnplibrary/outbox"
+	"stmnplibrary/pagination"
+	"strconv"
 	"time"
 )
 
+const keyReTk = "stmnplibrary:accesstoken:id:%d"
+
 type adminService struct {
 	adminRepository repository.AdminRepository
+	auditRepository  repository.AuditRepository
+	outboxRepository repository.OutboxRepository
+	calendarRepository repository.CalendarRepository
+	clock              clock.Clock
 }
 
-func FnAdminService(repository repository.AdminRepository) service.AdminService {
-	return &adminService{adminRepository: repository}
+func FnAdminService(repository repository.AdminRepository, auditRepository repository.AuditRepository, outboxRepository repository.OutboxRepository, calendarRepository repository.CalendarRepository, clock clock.Clock) service.AdminService {
+	return &tracedAdminService{
+		next: newAdminService(repository, auditRepository, outboxRepository, calendarRepository, clock),
+	}
 }
 
-func (as *adminService) getLimitOffset(page int) (int, int) {
-	var limit = 35
-	return limit, ((page - 1) * limit)
+func newAdminService(repository repository.AdminRepository, auditRepository repository.AuditRepository, outboxRepository repository.OutboxRepository, calendarRepository repository.CalendarRepository, clock clock.Clock) *adminService {
+	return &adminService{
+		adminRepository:    repository,
+		auditRepository:    auditRepository,
+		outboxRepository:   outboxRepository,
+		calendarRepository: calendarRepository,
+		clock:              clock,
+	}
 }
 
-func (as *adminService) GetLoanData(ctx context.Context, page int) ([]dto.LoanData, error) {
-	var (
-		keyLoanData = "stmnplibary:loandata:page:%d"
-		_, offset = as.getLimitOffset(page)
-	)
-	result, _ := as.adminRepository.RedisGet(ctx, keyLoanData)
-	rsl, _ := result.([]dto.LoanData)
-	if rsl != nil && len(rsl) > 0{
-		return rsl, nil
-	}
-	data, err := as.adminRepository.GetLoanData(ctx, offset)
+func (as *adminService) record(ctx context.Context, action string, entityName string, entityID string, before any, after any) error {
+	data, err := utils.NewAudit(ctx, action, entityName, entityID, before, after)
 	if err != nil {
-		return nil, utils.ValidateErrTw(err, "service - get_loan_data: %w")
-	}
-	if data == nil || len(data) == 0 {
-		return nil, fmt.Errorf("data doesn't exists")
+		return err
 	}
-	loanData := utils.LoanDataMapper(data)
-	as.adminRepository.RedisSet(ctx, fmt.Sprintf(keyLoanData, page), loanData, 3*time.Minute)
-	return loanData, nil
+	return as.auditRepository.Record(ctx, data)
 }
 
-func (as *adminService) GetLDDone(ctx context.Context, page int) ([]dto.LoanData, error) {
-	var (
-		keyLoanData = "stmnplibary:loandata:done:page:%d"
-		_, offset = as.getLimitOffset(page)
-	)
-	result, _ := as.adminRepository.RedisGet(ctx, keyLoanData)
-	rsl, _ := result.([]dto.LoanData)
-	if rsl != nil && len(rsl) > 0{
-		return rsl, nil
-	}
-	data, err := as.adminRepository.GetLDDone(ctx, offset)
+func (as *adminService) publish(ctx context.Context, eventType string, aggregate string, aggregateID string, payload any) error {
+	data, err := utils.NewEvent(ctx, eventType, aggregate, aggregateID, payload)
 	if err != nil {
-		return nil, utils.ValidateErrTw(err, "service - get_loan_data_done: %w")
-	}
-	if data == nil || len(data) == 0 {
-		return nil, fmt.Errorf("data doesn't exists")
+		return err
 	}
-	loanData := utils.LoanDataMapper(data)
-	as.adminRepository.RedisSet(ctx, fmt.Sprintf(keyLoanData, page), loanData, 3*time.Minute)
-	return loanData, nil
+	return as.outboxRepository.Add(ctx, data)
 }
 
-func (as *adminService) GetLDDont(ctx context.Context, page int) ([]dto.LoanData, error) {
+// loanPage is the cached form of one page of loan data.
+type loanPage struct {
+	Data []dto.LoanData `json:"data"`
+	Meta dto.Meta       `json:"meta"`
+}
+
+// getLoanData serves one keyset page of loans, the cache is keyed by the
+// cursor so a page never shifts when loans are added in between.
+func (as *adminService) getLoanData(ctx context.Context, keyLoanData string, query dto.PageQuery, errMsg string, fetch func(context.Context, pagination.Page) ([]entity.LoanData, int64, error)) ([]dto.LoanData, *dto.Meta, error) {
+	page, err := pagination.New(query.Cursor, query.PageSize, pagination.SortNewest)
+	if err != nil {
+		return nil, nil, err
+	}
 	var (
-		keyLoanData = "stmnplibary:loandata:dont:page:%d"
-		_, offset = as.getLimitOffset(page)
+		key    = fmt.Sprintf(keyLoanData, page.Key())
+		cached loanPage
 	)
-	result, _ := as.adminRepository.RedisGet(ctx, keyLoanData)
-	rsl, _ := result.([]dto.LoanData)
-	if rsl != nil && len(rsl) > 0{
-		return rsl, nil
+	if result, err := as.adminRepository.RedisGet(ctx, key); err == nil && utils.UnMarshal(result, &cached) == nil && len(cached.Data) > 0 {
+		return cached.Data, &cached.Meta, nil
 	}
-	data, err := as.adminRepository.GetLDDont(ctx, offset)
+	data, total, err := fetch(ctx, page)
 	if err != nil {
-		return nil, utils.ValidateErrTw(err, "service - get_loan_data_dont: %w")
+		return nil, nil, utils.ValidateErrTw(err, errMsg)
 	}
-	if data == nil || len(data) == 0 {
-		return nil, fmt.Errorf("data doesn't exists")
+	if len(data) == 0 {
+		return nil, nil, apperr.New(apperr.KindNotFound, apperr.CodeNotFound, "data doesn't exists")
 	}
-	loanData := utils.LoanDataMapper(data)
-	as.adminRepository.RedisSet(ctx, fmt.Sprintf(keyLoanData, page), loanData, 3*time.Minute)
-	return loanData, nil
+	data, next := pagination.Trim(data, page, utils.LoanKey)
+	cached = loanPage{
+		Data: utils.LoanDataMapper(data),
+		Meta: *utils.NewMeta(page, next, total),
+	}
+	if val, err := utils.Marshal(cached); err == nil {
+		as.adminRepository.RedisSet(ctx, key, val, 3*time.Minute)
+	}
+	return cached.Data, &cached.Meta, nil
+}
+
+func (as *adminService) GetLoanData(ctx context.Context, query dto.PageQuery) ([]dto.LoanData, *dto.Meta, error) {
+	return as.getLoanData(ctx, "stmnplibary:loandata:all:%s", query, "service - get_loan_data: %w", as.adminRepository.GetLoanData)
+}
+
+func (as *adminService) GetLDDone(ctx context.Context, query dto.PageQuery) ([]dto.LoanData, *dto.Meta, error) {
+	return as.getLoanData(ctx, "stmnplibary:loandata:done:%s", query, "service - get_loan_data_done: %w", as.adminRepository.GetLDDone)
+}
+
+func (as *adminService) GetLDDont(ctx context.Context, query dto.PageQuery) ([]dto.LoanData, *dto.Meta, error) {
+	return as.getLoanData(ctx, "stmnplibary:loandata:dont:%s", query, "service - get_loan_data_dont: %w", as.adminRepository.GetLDDont)
 }
 
 func (as *adminService) AddCategory(ctx context.Context, data dto.Category) error {
-	var (
-		ik, ok = ctx.Value(string(constanta.IK)).(string)
-		entityData = entity.Category{
-			Name: data.Name,
-		}
-		keyIk = "idempotency:key:"+ik
-	)
-	if !ok {
-		return errors.New("missing idempotency key")		
-	}
-	isNew, _ := as.adminRepository.RedisSETNX(ctx, keyIk, ik, 25*time.Minute)
-	if !isNew {
-		return errors.New("duplicate request")
+	var entityData = entity.Category{
+		Name: data.Name,
 	}
-	if err := as.adminRepository.AddCategory(ctx, entityData); err != nil {
-		if err := as.adminRepository.RedisDel(ctx, keyIk); err != nil {
-			return errors.New("failed delete key")
+	return as.adminRepository.WithTx(ctx, func(ctx context.Context) error {
+		const errMsg = "service - add_category: %w"
+		if err := as.adminRepository.AddCategory(ctx, entityData); err != nil {
+			return utils.ValidateErrTw(err, errMsg)
 		}
-		return utils.ValidateErrTw(err, "service - add_category: %w")
-	}
-	return nil
+		if err := as.record(ctx, audit.ActionCreate, "category", entityData.Name, nil, entityData); err != nil {
+			return utils.ValidateErrTw(err, errMsg)
+		}
+		return nil
+	})
 }
 
 func (as *adminService) AddBook(ctx context.Context, data dto.BookData) error {
-	var (
-		ik, ok = ctx.Value(string(constanta.IK)).(string)
-		entityData = entity.BookData{
-			ISBN:           data.ISBN,
-			Name:           data.Name,
-			Author:         data.Author,
-			Publisher:      data.Publisher,
-			Description:    data.Description,
-			Stock:          data.Stock,
-			AvailableStock: data.AvailableStock,
-		}
-		keyIk = "idempotency:key:"+ik
-	)
-	if !ok {
-		return errors.New("missing idempotency key")
+	var entityData = entity.BookData{
+		ISBN:           data.ISBN,
+		Name:           data.Name,
+		Author:         data.Author,
+		Publisher:      data.Publisher,
+		Description:    data.Description,
+		Stock:          data.Stock,
+		AvailableStock: data.AvailableStock,
+		Price:          data.Price,
 	}
-	isNew, _ := as.adminRepository.RedisSETNX(ctx, keyIk, ik, 24*time.Hour)
-	if !isNew {
-		return errors.New("duplicate request")
-	}
-	if err := as.adminRepository.WithTx(ctx, func(ctx context.Context) error {
+	return as.adminRepository.WithTx(ctx, func(ctx context.Context) error {
 		var (
 			errMsg        = "service - add_book: %w"
 			entityConnect = make([]entity.Connections, 0, 10)
@@ -154,26 +154,34 @@ func (as *adminService) AddBook(ctx context.Context, data dto.BookData) error {
 		if err := as.adminRepository.AddConnections(ctx, entityConnect); err != nil {
 			return utils.ValidateErrTw(err, errMsg)
 		}
-		return nil
-	}); err != nil {
-		if err := as.adminRepository.RedisDel(ctx, keyIk); err != nil {
-			return errors.New("failed delete key")
+		if err := as.record(ctx, audit.ActionCreate, "book", strconv.Itoa(entityData.BookID), nil, map[string]any{
+			"book":        entityData,
+			"id_category": data.IDCategory,
+		}); err != nil {
+			return utils.ValidateErrTw(err, errMsg)
+		}
+		if err := as.publish(ctx, outbox.EventBookAdded, "book", strconv.Itoa(entityData.BookID), map[string]any{
+			"id_book":     entityData.BookID,
+			"isbn":        entityData.ISBN,
+			"name":        entityData.Name,
+			"author":      entityData.Author,
+			"id_category": data.IDCategory,
+		}); err != nil {
+			return utils.ValidateErrTw(err, errMsg)
 		}
-		return err
-	} else {
 		return nil
-	}
+	})
 }
 
 func (as *adminService) Confirm(ctx context.Context, data dto.Confirm) error {
 	var (
 		entityCf = entity.Confirm {
-			Student: data.Student,
+			NIS: data.NIS,
 			ISBN: data.ISBN,
 		}
 		errMsg = "service - confirm loan: %w"
 	)
-	idStudent, err := as.adminRepository.GetStudentId(ctx, entityCf.Student)
+	idStudent, err := as.adminRepository.GetStudentId(ctx, entityCf.NIS)
 	if err != nil {
 		return utils.ValidateErrTw(err, errMsg)
 	}
@@ -181,22 +189,291 @@ func (as *adminService) Confirm(ctx context.Context, data dto.Confirm) error {
 	if err != nil {
 		return utils.ValidateErrTw(err, errMsg)
 	}
-	slData, err := as.adminRepository.GetStudentLoan(ctx, idStudent, idBook)
+	return as.returnLoan(ctx, idStudent, idBook, data.ReturnOutcome, errMsg)
+}
+
+func (as *adminService) ReturnLoan(ctx context.Context, id int, data dto.ReturnOutcome) error {
+	const errMsg = "service - return_loan: %w"
+	loan, err := as.adminRepository.GetActiveLoan(ctx, id)
 	if err != nil {
 		return utils.ValidateErrTw(err, errMsg)
 	}
-	lds := utils.InitLD(&slData)
-	lds.GiveSanctions()
-	return as.adminRepository.WithTx(ctx, func(context.Context) error {
-		if err := as.adminRepository.UpdateTabLoan(ctx, idStudent, idBook, *lds.Sanctions, *lds.ReturnedAt); err != nil {
+	return as.returnLoan(ctx, loan.IdUser, loan.IdBook, data, errMsg)
+}
+
+// returnLoan closes the active loan of a student on a book and gives the
+// sanctions when it is late, all in one transaction. A returned or damaged
+// book goes back to the available stock, a lost one is written off the stock
+// for good, a repair or replacement fee is added to the sanctions. The loan
+// slot is given back whatever the outcome.
+func (as *adminService) returnLoan(ctx context.Context, idStudent int, idBook int, data dto.ReturnOutcome, errMsg string) error {
+	var outcome = data.Outcome
+	if outcome == "" {
+		outcome = entity.OutcomeReturned
+	}
+	return as.adminRepository.WithTx(ctx, func(ctx context.Context) error {
+		slData, err := as.adminRepository.GetStudentLoan(ctx, idStudent, idBook)
+		if err != nil {
+			return utils.ValidateErrTw(err, errMsg)
+		}
+		lds := utils.InitLD(&slData, as.clock.Now())
+		cal, err := calendar.Load(ctx, as.calendarRepository, slData.MustReturnedAt, *lds.ReturnedAt)
+		if err != nil {
+			return utils.ValidateErrTw(err, errMsg)
+		}
+		lds.GiveSanctions(cal)
+		var (
+			fee         = data.Fee
+			stockChange = map[string]any{"stock_change": 1}
+		)
+		switch outcome {
+		case entity.OutcomeReturned:
+			fee = 0
+		case entity.OutcomeLost:
+			if fee == 0 {
+				if fee, err = as.adminRepository.GetBookPrice(ctx, idBook); err != nil {
+					return utils.ValidateErrTw(err, errMsg)
+				}
+			}
+			if fee == 0 {
+				return apperr.New(apperr.KindInvalid, apperr.CodeValidation, "book has no replacement price, send the fee")
+			}
+			stockChange = map[string]any{"stock_change": 0, "total_stock_change": -1}
+		}
+		*lds.Sanctions += fee
+		if err := as.adminRepository.UpdateTabLoan(ctx, idStudent, idBook, entity.LoanReturn{
+			Outcome:       outcome,
+			ConditionNote: data.ConditionNote,
+			Fee:           fee,
+			Sanctions:     *lds.Sanctions,
+			ReturnedAt:    *lds.ReturnedAt,
+		}); err != nil {
 			return utils.ValidateErrTw(err, errMsg)
 		}
-		if err := as.adminRepository.UpdateStock(ctx, idBook); err != nil {
+		if outcome == entity.OutcomeLost {
+			err = as.adminRepository.WriteOffStock(ctx, idBook)
+		} else {
+			err = as.adminRepository.UpdateStock(ctx, idBook)
+		}
+		if err != nil {
 			return utils.ValidateErrTw(err, errMsg)
 		}
 		if err := as.adminRepository.UpdateMaxBook(ctx, idStudent); err != nil {
 			return utils.ValidateErrTw(err, errMsg)
 		}
+		var after = map[string]any{
+			"is_returned":      true,
+			"must_returned_at": lds.MustReturnedAt,
+			"returned_at":      lds.ReturnedAt,
+			"sanctions":        lds.Sanctions,
+			"outcome":          outcome,
+			"condition_note":   data.ConditionNote,
+			"fee":              fee,
+			"max_book_change":  -1,
+		}
+		for k, v := range stockChange {
+			after[k] = v
+		}
+		if err := as.record(ctx, audit.ActionReturn, "loan", fmt.Sprintf("%d:%d", idStudent, idBook), map[string]any{
+			"is_returned":      false,
+			"must_returned_at": slData.MustReturnedAt,
+			"returned_at":      slData.ReturnedAt,
+			"sanctions":        slData.Sanctions,
+		}, after); err != nil {
+			return utils.ValidateErrTw(err, errMsg)
+		}
+		var loanID = fmt.Sprintf("%d:%d", idStudent, idBook)
+		if err := as.publish(ctx, outbox.EventLoanReturned, "loan", loanID, map[string]any{
+			"id_user":     idStudent,
+			"id_book":     idBook,
+			"returned_at": lds.ReturnedAt,
+			"sanctions":   lds.Sanctions,
+			"outcome":     outcome,
+			"fee":         fee,
+		}); err != nil {
+			return utils.ValidateErrTw(err, errMsg)
+		}
+		if *lds.Sanctions > 0 {
+			if err := as.publish(ctx, outbox.EventSanctionIssued, "loan", loanID, map[string]any{
+				"id_user":   idStudent,
+				"id_book":   idBook,
+				"sanctions": lds.Sanctions,
+				"fee":       fee,
+			}); err != nil {
+				return utils.ValidateErrTw(err, errMsg)
+			}
+		}
+		return nil
+	})
+}
+
+func (as *adminService) GetStudents(ctx context.Context, filter dto.StudentFilter) ([]dto.StudentData, *dto.Meta, error) {
+	page, err := pagination.New(filter.Cursor, filter.PageSize, pagination.SortNIS)
+	if err != nil {
+		return nil, nil, err
+	}
+	var entityFilter = entity.StudentFilter{
+		NIS:   filter.NIS,
+		Name:  filter.Name,
+		Class: filter.Class,
+		Major: filter.Major,
+		Batch: filter.Batch,
+	}
+	data, total, err := as.adminRepository.GetStudents(ctx, entityFilter, page)
+	if err != nil {
+		return nil, nil, utils.ValidateErrTw(err, "service - get_students: %w")
+	}
+	data, next := pagination.Trim(data, page, utils.StudentKey)
+	return utils.StudentDataMapper(data), utils.NewMeta(page, next, total), nil
+}
+
+func (as *adminService) GetStudent(ctx context.Context, nis int) (*dto.StudentProfile, error) {
+	const errMsg = "service - get_student: %w"
+	student, err := as.adminRepository.GetStudent(ctx, nis)
+	if err != nil {
+		return nil, utils.ValidateErrTw(err, errMsg)
+	}
+	loans, err := as.adminRepository.GetStudentLoans(ctx, student.ID)
+	if err != nil {
+		return nil, utils.ValidateErrTw(err, errMsg)
+	}
+	profile := &dto.StudentProfile{
+		Student:     utils.StudentDataMapper([]entity.StudentData{student})[0],
+		ActiveLoans: make([]dto.LoanData, 0),
+		History:     make([]dto.LoanData, 0),
+		MaxBook: dto.MaxBook{
+			Used:      student.MaxBook,
+			Limit:     entity.MaxBookLimit,
+			Remaining: entity.MaxBookLimit - student.MaxBook,
+		},
+	}
+	for _, l := range utils.LoanDataMapper(loans) {
+		if l.Sanctions != nil {
+			profile.Sanctions += *l.Sanctions
+		}
+		if l.ReturnedAt == nil {
+			profile.ActiveLoans = append(profile.ActiveLoans, l)
+			continue
+		}
+		profile.History = append(profile.History, l)
+	}
+	return profile, nil
+}
+
+func (as *adminService) UpdateStudent(ctx context.Context, nis int, data dto.UpdateStudent) ([]string, error) {
+	const errIntrnl = "service - update_student: %w"
+	var errMsg []string
+	student, err := as.adminRepository.GetStudent(ctx, nis)
+	if err != nil {
+		return nil, utils.ValidateErrTw(err, errIntrnl)
+	}
+	if !student.IsActive {
+		return nil, apperr.New(apperr.KindInvalid, apperr.CodeStudentInactive, "student has been deactivated")
+	}
+	var (
+		personal = entity.PersonalInfo{
+			Name:        student.Name,
+			PhoneNumber: student.PhoneNumber,
+			Email:       student.Email,
+		}
+		academic = entity.AcademicInfo{
+			Class:    student.Class,
+			SubClass: student.SubClass,
+			Major:    student.Major,
+			Batch:    student.Batch,
+		}
+	)
+	if data.Name != nil {
+		personal.Name = *data.Name
+	}
+	if data.Email != nil {
+		personal.Email = *data.Email
+	}
+	if data.PhoneNumber != nil {
+		personal.PhoneNumber = *data.PhoneNumber
+		personal.ValidatePN(&errMsg)
+	}
+	if data.Class != nil {
+		academic.Class = *data.Class
+	}
+	if data.SubClass != nil {
+		academic.SubClass = *data.SubClass
+	}
+	if data.Major != nil {
+		academic.Major = *data.Major
+	}
+	if data.Batch != nil {
+		academic.Batch = *data.Batch
+	}
+	if data.Class != nil || data.Major != nil {
+		academic.ValidateClass(&errMsg)
+	}
+	if len(errMsg) > 0 {
+		return errMsg, nil
+	}
+	var updated = entity.StudentData{
+		ID:          student.ID,
+		NIS:         student.NIS,
+		Name:        personal.Name,
+		PhoneNumber: personal.PhoneNumber,
+		Email:       personal.Email,
+		Class:       academic.Class,
+		SubClass:    academic.SubClass,
+		Major:       academic.Major,
+		Batch:       academic.Batch,
+		MaxBook:     student.MaxBook,
+		IsActive:    student.IsActive,
+	}
+	if err := as.adminRepository.WithTx(ctx, func(ctx context.Context) error {
+		if err := as.adminRepository.UpdateStudent(ctx, nis, updated); err != nil {
+			return utils.ValidateErrTw(err, errIntrnl)
+		}
+		if err := as.record(ctx, audit.ActionUpdate, "student", strconv.Itoa(nis), utils.StudentDataMapper([]entity.StudentData{student})[0], utils.StudentDataMapper([]entity.StudentData{updated})[0]); err != nil {
+			return utils.ValidateErrTw(err, errIntrnl)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func (as *adminService) DeactivateStudent(ctx context.Context, nis int) error {
+	const errMsg = "service - deactivate_student: %w"
+	return as.adminRepository.WithTx(ctx, func(ctx context.Context) error {
+		if err := as.adminRepository.DeactivateStudent(ctx, nis); err != nil {
+			return utils.ValidateErrTw(err, errMsg)
+		}
+		if err := as.record(ctx, audit.ActionDeactivate, "student", strconv.Itoa(nis), map[string]any{"is_active": true}, map[string]any{"is_active": false}); err != nil {
+			return utils.ValidateErrTw(err, errMsg)
+		}
+		return nil
+	})
+}
+
+// UpdateStudentRole moves an active account between the students and teacher
+// roles. The refresh session is revoked so the new role applies from the next
+// login, admins can't be changed here.
+func (as *adminService) UpdateStudentRole(ctx context.Context, nis int, data dto.UpdateRole) error {
+	const errMsg = "service - update_student_role: %w"
+	from := "students"
+	if data.Role == "students" {
+		from = "teacher"
+	}
+	return as.adminRepository.WithTx(ctx, func(ctx context.Context) error {
+		id, err := as.adminRepository.GetStudentId(ctx, nis)
+		if err != nil {
+			return utils.ValidateErrTw(err, errMsg)
+		}
+		if err := as.adminRepository.UpdateStudentRole(ctx, nis, from, data.Role); err != nil {
+			return utils.ValidateErrTw(err, errMsg)
+		}
+		if err := as.record(ctx, audit.ActionUpdate, "student", strconv.Itoa(nis), map[string]any{"role": from}, map[string]any{"role": data.Role}); err != nil {
+			return utils.ValidateErrTw(err, errMsg)
+		}
+		if err := as.adminRepository.RedisDel(ctx, fmt.Sprintf(keyReTk, id)); err != nil {
+			return utils.ValidateErrTw(err, errMsg)
+		}
 		return nil
 	})
 }
@@ -4,105 +4,148 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
+	"stmnplibrary/apperr"
+	"stmnplibrary/clock"
 	"stmnplibrary/domain/entity"
 	"stmnplibrary/domain/interface/service"
 	"stmnplibrary/dto"
 	"stmnplibrary/mocks"
+	"stmnplibrary/outbox"
+	"stmnplibrary/pagination"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
-func setup(t *testing.T) (*mocks.AdminRepository, service.AdminService) {
+// testNow is the time of the fake clock, 09:00 in Jakarta.
+var testNow = time.Date(2026, 5, 4, 9, 0, 0, 0, time.FixedZone("WIB", 7*60*60))
+
+func setup(t *testing.T) (*mocks.AdminRepository, *mocks.AuditRepository, *mocks.OutboxRepository, service.AdminService) {
 	repo := mocks.NewAdminRepository(t)
-	svc := FnAdminService(repo)
-	return repo, svc
+	auditRepo := mocks.NewAuditRepository(t)
+	outboxRepo := mocks.NewOutboxRepository(t)
+	svc := newAdminService(repo, auditRepo, outboxRepo, openCalendar(t, nil), clock.NewFake(testNow))
+	return repo, auditRepo, outboxRepo, svc
+}
+
+// openCalendar is a library open every day but on closures.
+func openCalendar(t *testing.T, closures []entity.Closure) *mocks.CalendarRepository {
+	calRepo := mocks.NewCalendarRepository(t)
+	calRepo.On("GetOpeningHours", mock.Anything).Return([]entity.OpeningHours(nil), nil).Maybe()
+	calRepo.On("GetClosures", mock.Anything, mock.Anything, mock.Anything).Return(closures, nil).Maybe()
+	return calRepo
 }
 
 func TestGetLoanData_All_Methods(t *testing.T) {
-	repo, svc := setup(t)
+	repo, _, _, svc := setup(t)
 	ctx := context.Background()
+	first := pagination.Page{Sort: pagination.SortNewest, Size: pagination.DefaultSize}
+	fullPage := make([]entity.LoanData, pagination.DefaultSize+1)
+	for i := range fullPage {
+		fullPage[i] = entity.LoanData{ID: 100 - i, BorrowAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC).Add(-time.Duration(i) * time.Hour)}
+	}
 
 	tests := []struct {
 		name       string
 		methodName string
-		page       int
-		mockRedis  interface{}
+		mockRedis  []byte
 		mockDB     []entity.LoanData
 		errDB      error
 		expectErr  bool
+		hasNext    bool
 	}{
-		{"Success_From_Redis", "GetLoanData", 1, []dto.LoanData{{StudentName: "Budi"}}, nil, nil, false},
-		{"Success_From_DB", "GetLDDone", 2, nil, []entity.LoanData{{StudentName: "Agus"}}, nil, false},
-		{"Error_DB_Failure", "GetLDDont", 1, nil, nil, errors.New("db down"), true},
-		{"Error_Data_Empty", "GetLoanData", 1, nil, nil, nil, true},
+		{"Success_From_Redis", "GetLoanData", []byte(`{"data":[{"student_name":"Budi"}],"meta":{"page_size":35,"sort":"newest","total":1}}`), nil, nil, false, false},
+		{"Success_From_DB", "GetLDDone", nil, []entity.LoanData{{ID: 1, StudentName: "Agus"}}, nil, false, false},
+		{"Success_Has_Next", "GetLDDont", nil, fullPage, nil, false, true},
+		{"Error_DB_Failure", "GetLDDont", nil, nil, errors.New("db down"), true, false},
+		{"Error_Data_Empty", "GetLoanData", nil, nil, nil, true, false},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			offset := (tt.page - 1) * 35
 			var key string
-
 			switch tt.methodName {
 			case "GetLoanData":
-				key = "stmnplibary:loandata:page:%d"
-				repo.On("RedisGet", ctx, mock.Anything).Return(tt.mockRedis, nil).Once()
-				if tt.mockRedis == nil {
-					repo.On("GetLoanData", ctx, offset).Return(tt.mockDB, tt.errDB).Once()
-				}
+				key = "stmnplibary:loandata:all:%s"
 			case "GetLDDone":
-				key = "stmnplibary:loandata:done:page:%d"
-				repo.On("RedisGet", ctx, mock.Anything).Return(tt.mockRedis, nil).Once()
-				if tt.mockRedis == nil {
-					repo.On("GetLDDone", ctx, offset).Return(tt.mockDB, tt.errDB).Once()
-				}
+				key = "stmnplibary:loandata:done:%s"
 			case "GetLDDont":
-				key = "stmnplibary:loandata:dont:page:%d"
-				repo.On("RedisGet", ctx, mock.Anything).Return(tt.mockRedis, nil).Once()
-				if tt.mockRedis == nil {
-					repo.On("GetLDDont", ctx, offset).Return(tt.mockDB, tt.errDB).Once()
-				}
+				key = "stmnplibary:loandata:dont:%s"
+			}
+			fullKey := fmt.Sprintf(key, first.Key())
+			repo.On("RedisGet", ctx, fullKey).Return(tt.mockRedis, nil).Once()
+			if tt.mockRedis == nil {
+				repo.On(tt.methodName, ctx, first).Return(tt.mockDB, int64(len(tt.mockDB)), tt.errDB).Once()
 			}
-
 			if tt.mockRedis == nil && tt.errDB == nil && len(tt.mockDB) > 0 {
-				fullKey := fmt.Sprintf(key, tt.page)
 				repo.On("RedisSet", ctx, fullKey, mock.Anything, 3*time.Minute).Return(nil).Once()
 			}
 
-			var err error
-			if tt.methodName == "GetLoanData" {
-				_, err = svc.GetLoanData(ctx, tt.page)
-			}
-			if tt.methodName == "GetLDDone" {
-				_, err = svc.GetLDDone(ctx, tt.page)
-			}
-			if tt.methodName == "GetLDDont" {
-				_, err = svc.GetLDDont(ctx, tt.page)
+			var (
+				data []dto.LoanData
+				meta *dto.Meta
+				err  error
+			)
+			switch tt.methodName {
+			case "GetLoanData":
+				data, meta, err = svc.GetLoanData(ctx, dto.PageQuery{})
+			case "GetLDDone":
+				data, meta, err = svc.GetLDDone(ctx, dto.PageQuery{})
+			case "GetLDDont":
+				data, meta, err = svc.GetLDDont(ctx, dto.PageQuery{})
 			}
 
 			if tt.expectErr {
 				assert.Error(t, err)
-			} else {
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, pagination.SortNewest, meta.Sort)
+			assert.Equal(t, tt.hasNext, meta.HasNext)
+			if len(tt.mockDB) > 0 {
+				// the id is what POST /api/v1/loans/:id/return takes
+				assert.Equal(t, tt.mockDB[0].ID, data[0].ID)
+			}
+			if tt.hasNext {
+				assert.Len(t, data, pagination.DefaultSize)
+				next, err := pagination.Decode(meta.NextCursor)
 				assert.NoError(t, err)
+				last := fullPage[pagination.DefaultSize-1]
+				assert.Equal(t, &pagination.Cursor{Sort: pagination.SortNewest, Key: last.BorrowAt.Format(time.RFC3339Nano), ID: last.ID}, next)
 			}
 		})
 	}
 }
 
+func TestGetLoanData_Invalid_Cursor(t *testing.T) {
+	_, _, _, svc := setup(t)
+	cursor := pagination.Cursor{Sort: pagination.SortTitle, Key: "A", ID: 1}.Encode()
+
+	_, _, err := svc.GetLoanData(context.Background(), dto.PageQuery{Cursor: cursor})
+	assert.ErrorIs(t, err, pagination.ErrInvalidCursor)
+}
+
 func TestAddCategory_Cases(t *testing.T) {
-	repo, svc := setup(t)
+	repo, auditRepo, _, svc := setup(t)
 	ctx := context.Background()
+	withTx := func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	}
 
 	t.Run("Success", func(t *testing.T) {
+		repo.On("WithTx", ctx, mock.AnythingOfType("func(context.Context) error")).Return(withTx).Once()
 		repo.On("AddCategory", ctx, mock.Anything).Return(nil).Once()
+		auditRepo.On("Record", ctx, mock.Anything).Return(nil).Once()
 		err := svc.AddCategory(ctx, dto.Category{Name: "Horror"})
 		assert.NoError(t, err)
 	})
 
 	t.Run("Fail_DB_Error", func(t *testing.T) {
+		repo.On("WithTx", ctx, mock.AnythingOfType("func(context.Context) error")).Return(withTx).Once()
 		repo.On("AddCategory", ctx, mock.Anything).Return(errors.New("duplicate")).Once()
 		err := svc.AddCategory(ctx, dto.Category{Name: "Horror"})
 		assert.Error(t, err)
@@ -110,7 +153,7 @@ func TestAddCategory_Cases(t *testing.T) {
 }
 
 func TestAddBook_Cases(t *testing.T) {
-	repo, svc := setup(t)
+	repo, auditRepo, outboxRepo, svc := setup(t)
 	ctx := context.Background()
 	input := dto.BookData{ISBN: "123", Name: "Test", IDCategory: []int{1}}
 
@@ -120,6 +163,10 @@ func TestAddBook_Cases(t *testing.T) {
 		}).Once()
 		repo.On("AddBook", ctx, mock.Anything).Return(nil).Once()
 		repo.On("AddConnections", ctx, mock.Anything).Return(nil).Once()
+		auditRepo.On("Record", ctx, mock.Anything).Return(nil).Once()
+		outboxRepo.On("Add", ctx, mock.MatchedBy(func(e entity.Outbox) bool {
+			return e.EventType == outbox.EventBookAdded && e.Aggregate == "book"
+		})).Return(nil).Once()
 
 		err := svc.AddBook(ctx, input)
 		assert.NoError(t, err)
@@ -137,14 +184,14 @@ func TestAddBook_Cases(t *testing.T) {
 }
 
 func TestConfirm_Cases(t *testing.T) {
-	repo, svc := setup(t)
+	repo, auditRepo, outboxRepo, svc := setup(t)
 	ctx := context.Background()
-	input := dto.Confirm{Student: "A", ISBN: "B"}
+	input := dto.Confirm{NIS: 1001, ISBN: "B"}
 
 	t.Run("Success_Return_On_Time", func(t *testing.T) {
-		now := time.Now()
+		now := testNow
 		sanc := int64(0)
-		repo.On("GetStudentId", ctx, "A").Return(1, nil).Once()
+		repo.On("GetStudentId", ctx, 1001).Return(1, nil).Once()
 		repo.On("GetBookId", ctx, "B").Return(2, nil).Once()
 		repo.On("GetStudentLoan", ctx, 1, 2).Return(entity.LdUpdate{
 			MustReturnedAt: now.Add(24 * time.Hour),
@@ -154,21 +201,28 @@ func TestConfirm_Cases(t *testing.T) {
 		repo.On("WithTx", ctx, mock.AnythingOfType("func(context.Context) error")).Return(func(ctx context.Context, fn func(context.Context) error) error {
 			return fn(ctx)
 		}).Once()
-		repo.On("UpdateTabLoan", ctx, 1, 2, mock.Anything, mock.Anything).Return(nil).Once()
+		repo.On("UpdateTabLoan", ctx, 1, 2, mock.Anything).Return(nil).Once()
 		repo.On("UpdateStock", ctx, 2).Return(nil).Once()
+		repo.On("UpdateMaxBook", ctx, 1).Return(nil).Once()
+		auditRepo.On("Record", ctx, mock.MatchedBy(func(a entity.Audit) bool {
+			return a.Action == "return" && a.Entity == "loan" && a.EntityID == "1:2"
+		})).Return(nil).Once()
+		outboxRepo.On("Add", ctx, mock.MatchedBy(func(e entity.Outbox) bool {
+			return e.EventType == outbox.EventLoanReturned && e.AggregateID == "1:2"
+		})).Return(nil).Once()
 
 		err := svc.Confirm(ctx, input)
 		assert.NoError(t, err)
 	})
 
 	t.Run("Fail_Student_Not_Found", func(t *testing.T) {
-		repo.On("GetStudentId", ctx, "A").Return(0, errors.New("not found")).Once()
+		repo.On("GetStudentId", ctx, 1001).Return(0, errors.New("not found")).Once()
 		err := svc.Confirm(ctx, input)
 		assert.Error(t, err)
 	})
 
 	t.Run("Fail_Update_Stock_Tx", func(t *testing.T) {
-		now := time.Now()
+		now := testNow
 		sanc := int64(0)
 		repo.On("GetStudentId", ctx, mock.Anything).Return(1, nil).Once()
 		repo.On("GetBookId", ctx, mock.Anything).Return(2, nil).Once()
@@ -176,10 +230,285 @@ func TestConfirm_Cases(t *testing.T) {
 		repo.On("WithTx", ctx, mock.AnythingOfType("func(context.Context) error")).Return(func(ctx context.Context, fn func(context.Context) error) error {
 			return fn(ctx)
 		}).Once()
-		repo.On("UpdateTabLoan", ctx, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+		repo.On("UpdateTabLoan", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
 		repo.On("UpdateStock", ctx, 2).Return(errors.New("deadlock")).Once()
 
 		err := svc.Confirm(ctx, input)
 		assert.Error(t, err)
 	})
-}
\ No newline at end of file
+}
+func TestReturnLoan_Cases(t *testing.T) {
+	repo, auditRepo, outboxRepo, svc := setup(t)
+	ctx := context.Background()
+
+	t.Run("Success_Late_Return", func(t *testing.T) {
+		late := testNow.Add(-48 * time.Hour)
+		repo.On("GetActiveLoan", ctx, 7).Return(entity.Loan{IdUser: 1, IdBook: 2}, nil).Once()
+		repo.On("GetStudentLoan", ctx, 1, 2).Return(entity.LdUpdate{MustReturnedAt: late}, nil).Once()
+		repo.On("WithTx", ctx, mock.AnythingOfType("func(context.Context) error")).Return(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		}).Once()
+		repo.On("UpdateTabLoan", ctx, 1, 2, mock.MatchedBy(func(r entity.LoanReturn) bool {
+			return r.Sanctions > 0 && r.Outcome == entity.OutcomeReturned && r.Fee == 0
+		})).Return(nil).Once()
+		repo.On("UpdateStock", ctx, 2).Return(nil).Once()
+		repo.On("UpdateMaxBook", ctx, 1).Return(nil).Once()
+		auditRepo.On("Record", ctx, mock.MatchedBy(func(a entity.Audit) bool {
+			return a.Action == "return" && a.EntityID == "1:2"
+		})).Return(nil).Once()
+		outboxRepo.On("Add", ctx, mock.MatchedBy(func(e entity.Outbox) bool {
+			return e.EventType == outbox.EventLoanReturned
+		})).Return(nil).Once()
+		outboxRepo.On("Add", ctx, mock.MatchedBy(func(e entity.Outbox) bool {
+			return e.EventType == outbox.EventSanctionIssued && e.AggregateID == "1:2"
+		})).Return(nil).Once()
+
+		err := svc.ReturnLoan(ctx, 7, dto.ReturnOutcome{})
+		assert.NoError(t, err)
+	})
+
+	t.Run("Success_Damaged", func(t *testing.T) {
+		due := testNow.Add(24 * time.Hour)
+		repo.On("GetActiveLoan", ctx, 9).Return(entity.Loan{IdUser: 1, IdBook: 3}, nil).Once()
+		repo.On("GetStudentLoan", ctx, 1, 3).Return(entity.LdUpdate{MustReturnedAt: due}, nil).Once()
+		repo.On("WithTx", ctx, mock.AnythingOfType("func(context.Context) error")).Return(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		}).Once()
+		repo.On("UpdateTabLoan", ctx, 1, 3, mock.MatchedBy(func(r entity.LoanReturn) bool {
+			return r.Outcome == entity.OutcomeDamaged && r.ConditionNote == "torn cover" && r.Fee == 15000 && r.Sanctions == 15000
+		})).Return(nil).Once()
+		repo.On("UpdateStock", ctx, 3).Return(nil).Once()
+		repo.On("UpdateMaxBook", ctx, 1).Return(nil).Once()
+		auditRepo.On("Record", ctx, mock.MatchedBy(func(a entity.Audit) bool {
+			return a.Action == "return" && a.EntityID == "1:3"
+		})).Return(nil).Once()
+		outboxRepo.On("Add", ctx, mock.MatchedBy(func(e entity.Outbox) bool {
+			return e.EventType == outbox.EventLoanReturned && e.AggregateID == "1:3"
+		})).Return(nil).Once()
+		outboxRepo.On("Add", ctx, mock.MatchedBy(func(e entity.Outbox) bool {
+			return e.EventType == outbox.EventSanctionIssued && e.AggregateID == "1:3"
+		})).Return(nil).Once()
+
+		err := svc.ReturnLoan(ctx, 9, dto.ReturnOutcome{Outcome: entity.OutcomeDamaged, ConditionNote: "torn cover", Fee: 15000})
+		assert.NoError(t, err)
+	})
+
+	t.Run("Success_Lost_Charged_Book_Price", func(t *testing.T) {
+		due := testNow.Add(24 * time.Hour)
+		repo.On("GetActiveLoan", ctx, 10).Return(entity.Loan{IdUser: 1, IdBook: 4}, nil).Once()
+		repo.On("GetStudentLoan", ctx, 1, 4).Return(entity.LdUpdate{MustReturnedAt: due}, nil).Once()
+		repo.On("WithTx", ctx, mock.AnythingOfType("func(context.Context) error")).Return(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		}).Once()
+		repo.On("GetBookPrice", ctx, 4).Return(int64(85000), nil).Once()
+		repo.On("UpdateTabLoan", ctx, 1, 4, mock.MatchedBy(func(r entity.LoanReturn) bool {
+			return r.Outcome == entity.OutcomeLost && r.Fee == 85000 && r.Sanctions == 85000
+		})).Return(nil).Once()
+		repo.On("WriteOffStock", ctx, 4).Return(nil).Once()
+		repo.On("UpdateMaxBook", ctx, 1).Return(nil).Once()
+		auditRepo.On("Record", ctx, mock.MatchedBy(func(a entity.Audit) bool {
+			return a.Action == "return" && a.EntityID == "1:4"
+		})).Return(nil).Once()
+		outboxRepo.On("Add", ctx, mock.Anything).Return(nil).Twice()
+
+		err := svc.ReturnLoan(ctx, 10, dto.ReturnOutcome{Outcome: entity.OutcomeLost})
+		assert.NoError(t, err)
+	})
+
+	t.Run("Fail_Lost_Without_Price", func(t *testing.T) {
+		due := testNow.Add(24 * time.Hour)
+		repo.On("GetActiveLoan", ctx, 11).Return(entity.Loan{IdUser: 1, IdBook: 5}, nil).Once()
+		repo.On("GetStudentLoan", ctx, 1, 5).Return(entity.LdUpdate{MustReturnedAt: due}, nil).Once()
+		repo.On("WithTx", ctx, mock.AnythingOfType("func(context.Context) error")).Return(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		}).Once()
+		repo.On("GetBookPrice", ctx, 5).Return(int64(0), nil).Once()
+
+		err := svc.ReturnLoan(ctx, 11, dto.ReturnOutcome{Outcome: entity.OutcomeLost})
+		assert.Equal(t, apperr.KindInvalid, apperr.KindOf(err))
+	})
+
+	t.Run("Closed_Days_Not_Fined", func(t *testing.T) {
+		repo, auditRepo, outboxRepo := mocks.NewAdminRepository(t), mocks.NewAuditRepository(t), mocks.NewOutboxRepository(t)
+		// the library was closed on every day of the last 5, only the
+		// days before are fined
+		today := testNow
+		svc := newAdminService(repo, auditRepo, outboxRepo, openCalendar(t, []entity.Closure{
+			{Name: "Libur Semester", Kind: entity.ClosureTermBreak, StartDate: today.AddDate(0, 0, -4), EndDate: today},
+		}), clock.NewFake(testNow))
+		repo.On("GetActiveLoan", ctx, 12).Return(entity.Loan{IdUser: 1, IdBook: 6}, nil).Once()
+		repo.On("GetStudentLoan", ctx, 1, 6).Return(entity.LdUpdate{MustReturnedAt: today.AddDate(0, 0, -7).Add(-time.Hour)}, nil).Once()
+		repo.On("WithTx", ctx, mock.AnythingOfType("func(context.Context) error")).Return(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		}).Once()
+		repo.On("UpdateTabLoan", ctx, 1, 6, mock.MatchedBy(func(r entity.LoanReturn) bool {
+			return r.Sanctions == 2*2000
+		})).Return(nil).Once()
+		repo.On("UpdateStock", ctx, 6).Return(nil).Once()
+		repo.On("UpdateMaxBook", ctx, 1).Return(nil).Once()
+		auditRepo.On("Record", ctx, mock.Anything).Return(nil).Once()
+		outboxRepo.On("Add", ctx, mock.Anything).Return(nil).Twice()
+
+		err := svc.ReturnLoan(ctx, 12, dto.ReturnOutcome{})
+		assert.NoError(t, err)
+	})
+
+	t.Run("Late_By_Library_Day", func(t *testing.T) {
+		repo, auditRepo, outboxRepo := mocks.NewAdminRepository(t), mocks.NewAuditRepository(t), mocks.NewOutboxRepository(t)
+		// due at the end of the 4th in Jakarta and read back in UTC, returned
+		// at 00:30 of the 5th in Jakarta while it is still the 4th in UTC
+		due := clock.EndOfDay(testNow).UTC()
+		svc := newAdminService(repo, auditRepo, outboxRepo, openCalendar(t, nil), clock.NewFake(clock.StartOfDay(testNow).Add(24*time.Hour+30*time.Minute)))
+		repo.On("GetActiveLoan", ctx, 13).Return(entity.Loan{IdUser: 1, IdBook: 7}, nil).Once()
+		repo.On("GetStudentLoan", ctx, 1, 7).Return(entity.LdUpdate{MustReturnedAt: due}, nil).Once()
+		repo.On("WithTx", ctx, mock.AnythingOfType("func(context.Context) error")).Return(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		}).Once()
+		repo.On("UpdateTabLoan", ctx, 1, 7, mock.MatchedBy(func(r entity.LoanReturn) bool {
+			return r.Sanctions == 2000
+		})).Return(nil).Once()
+		repo.On("UpdateStock", ctx, 7).Return(nil).Once()
+		repo.On("UpdateMaxBook", ctx, 1).Return(nil).Once()
+		auditRepo.On("Record", ctx, mock.Anything).Return(nil).Once()
+		outboxRepo.On("Add", ctx, mock.Anything).Return(nil).Twice()
+
+		err := svc.ReturnLoan(ctx, 13, dto.ReturnOutcome{})
+		assert.NoError(t, err)
+	})
+
+	t.Run("Fail_Not_Active", func(t *testing.T) {
+		repo.On("GetActiveLoan", ctx, 8).Return(entity.Loan{}, apperr.ErrNotFound).Once()
+		err := svc.ReturnLoan(ctx, 8, dto.ReturnOutcome{})
+		assert.ErrorIs(t, err, apperr.ErrNotFound)
+	})
+}
+
+func TestGetStudent_Profile(t *testing.T) {
+	repo, _, _, svc := setup(t)
+	ctx := context.Background()
+
+	t.Run("Split_Active_And_History", func(t *testing.T) {
+		now := testNow
+		sanc := int64(4000)
+		repo.On("GetStudent", ctx, 1001).Return(entity.StudentData{ID: 1, NIS: 1001, MaxBook: 1, IsActive: true}, nil).Once()
+		repo.On("GetStudentLoans", ctx, 1).Return([]entity.LoanData{
+			{ID: 7, BookName: "A"},
+			{ID: 6, BookName: "B", ReturnedAt: &now, Sanctions: &sanc},
+		}, nil).Once()
+
+		profile, err := svc.GetStudent(ctx, 1001)
+		assert.NoError(t, err)
+		assert.Len(t, profile.ActiveLoans, 1)
+		assert.Len(t, profile.History, 1)
+		assert.Equal(t, 7, profile.ActiveLoans[0].ID)
+		assert.Equal(t, 6, profile.History[0].ID)
+		assert.Equal(t, int64(4000), profile.Sanctions)
+		assert.Equal(t, entity.MaxBookLimit-1, profile.MaxBook.Remaining)
+	})
+
+	t.Run("Fail_Student_Not_Found", func(t *testing.T) {
+		repo.On("GetStudent", ctx, 1002).Return(entity.StudentData{}, apperr.ErrNotFound).Once()
+		_, err := svc.GetStudent(ctx, 1002)
+		assert.Error(t, err)
+	})
+}
+
+func TestUpdateStudent_Cases(t *testing.T) {
+	repo, auditRepo, _, svc := setup(t)
+	ctx := context.Background()
+	active := entity.StudentData{ID: 1, NIS: 1001, Name: "Student", Class: "XI", Major: "RPL", IsActive: true}
+
+	t.Run("Success", func(t *testing.T) {
+		phone := "08123456789"
+		repo.On("GetStudent", ctx, 1001).Return(active, nil).Once()
+		repo.On("WithTx", ctx, mock.AnythingOfType("func(context.Context) error")).Return(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		}).Once()
+		repo.On("UpdateStudent", ctx, 1001, mock.MatchedBy(func(d entity.StudentData) bool {
+			return d.PhoneNumber == "+628123456789" && d.Class == "XI"
+		})).Return(nil).Once()
+		auditRepo.On("Record", ctx, mock.MatchedBy(func(a entity.Audit) bool {
+			return a.Entity == "student" && strings.Contains(a.Diff, "phone_number") && !strings.Contains(a.Diff, "class")
+		})).Return(nil).Once()
+
+		msg, err := svc.UpdateStudent(ctx, 1001, dto.UpdateStudent{PhoneNumber: &phone})
+		assert.NoError(t, err)
+		assert.Nil(t, msg)
+	})
+
+	t.Run("Fail_Validation", func(t *testing.T) {
+		class := "XIII"
+		repo.On("GetStudent", ctx, 1001).Return(active, nil).Once()
+
+		msg, err := svc.UpdateStudent(ctx, 1001, dto.UpdateStudent{Class: &class})
+		assert.NoError(t, err)
+		assert.NotEmpty(t, msg)
+	})
+
+	t.Run("Fail_Deactivated", func(t *testing.T) {
+		repo.On("GetStudent", ctx, 1003).Return(entity.StudentData{NIS: 1003}, nil).Once()
+
+		_, err := svc.UpdateStudent(ctx, 1003, dto.UpdateStudent{})
+		assert.Error(t, err)
+	})
+}
+
+func TestUpdateStudentRole_Cases(t *testing.T) {
+	repo, auditRepo, _, svc := setup(t)
+	ctx := context.Background()
+	withTx := func() {
+		repo.On("WithTx", ctx, mock.AnythingOfType("func(context.Context) error")).Return(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		}).Once()
+	}
+
+	t.Run("Success_Promote", func(t *testing.T) {
+		withTx()
+		repo.On("GetStudentId", ctx, 1001).Return(7, nil).Once()
+		repo.On("UpdateStudentRole", ctx, 1001, "students", "teacher").Return(nil).Once()
+		auditRepo.On("Record", ctx, mock.MatchedBy(func(a entity.Audit) bool {
+			return a.Action == "update" && a.Entity == "student" && a.EntityID == "1001" &&
+				strings.Contains(a.Diff, `"students"`) && strings.Contains(a.Diff, `"teacher"`)
+		})).Return(nil).Once()
+		repo.On("RedisDel", ctx, "stmnplibrary:accesstoken:id:7").Return(nil).Once()
+
+		assert.NoError(t, svc.UpdateStudentRole(ctx, 1001, dto.UpdateRole{Role: "teacher"}))
+	})
+
+	t.Run("Success_Demote", func(t *testing.T) {
+		withTx()
+		repo.On("GetStudentId", ctx, 2).Return(3, nil).Once()
+		repo.On("UpdateStudentRole", ctx, 2, "teacher", "students").Return(nil).Once()
+		auditRepo.On("Record", ctx, mock.Anything).Return(nil).Once()
+		repo.On("RedisDel", ctx, "stmnplibrary:accesstoken:id:3").Return(nil).Once()
+
+		assert.NoError(t, svc.UpdateStudentRole(ctx, 2, dto.UpdateRole{Role: "students"}))
+	})
+
+	t.Run("Fail_Not_Found", func(t *testing.T) {
+		withTx()
+		repo.On("GetStudentId", ctx, 1002).Return(0, apperr.ErrNotFound).Once()
+
+		err := svc.UpdateStudentRole(ctx, 1002, dto.UpdateRole{Role: "teacher"})
+		assert.ErrorIs(t, err, apperr.ErrNotFound)
+	})
+
+	t.Run("Fail_Admin_Or_Same_Role", func(t *testing.T) {
+		withTx()
+		repo.On("GetStudentId", ctx, 1).Return(1, nil).Once()
+		repo.On("UpdateStudentRole", ctx, 1, "students", "teacher").Return(apperr.ErrNoDataAffected).Once()
+
+		err := svc.UpdateStudentRole(ctx, 1, dto.UpdateRole{Role: "teacher"})
+		assert.ErrorIs(t, err, apperr.ErrNoDataAffected)
+	})
+
+	t.Run("Fail_Revoke_Session", func(t *testing.T) {
+		withTx()
+		repo.On("GetStudentId", ctx, 1001).Return(7, nil).Once()
+		repo.On("UpdateStudentRole", ctx, 1001, "students", "teacher").Return(nil).Once()
+		auditRepo.On("Record", ctx, mock.Anything).Return(nil).Once()
+		repo.On("RedisDel", ctx, "stmnplibrary:accesstoken:id:7").Return(errors.New("redis down")).Once()
+
+		assert.Error(t, svc.UpdateStudentRole(ctx, 1001, dto.UpdateRole{Role: "teacher"}))
+	})
+}
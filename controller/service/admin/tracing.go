@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+
+	"stmnplibrary/domain/interface/service"
+	"stmnplibrary/dto"
+	"stmnplibrary/tracing"
+)
+
+// tracedAdminService wraps every AdminService method in a span, failed calls are recorded on it.
+type tracedAdminService struct {
+	next service.AdminService
+}
+
+func (t *tracedAdminService) GetLoanData(ctx context.Context, query dto.PageQuery) (result []dto.LoanData, meta *dto.Meta, err error) {
+	ctx, span := tracing.Start(ctx, "AdminService.GetLoanData")
+	defer func() { tracing.End(span, err) }()
+	return t.next.GetLoanData(ctx, query)
+}
+
+func (t *tracedAdminService) GetLDDone(ctx context.Context, query dto.PageQuery) (result []dto.LoanData, meta *dto.Meta, err error) {
+	ctx, span := tracing.Start(ctx, "AdminService.GetLDDone")
+	defer func() { tracing.End(span, err) }()
+	return t.next.GetLDDone(ctx, query)
+}
+
+func (t *tracedAdminService) GetLDDont(ctx context.Context, query dto.PageQuery) (result []dto.LoanData, meta *dto.Meta, err error) {
+	ctx, span := tracing.Start(ctx, "AdminService.GetLDDont")
+	defer func() { tracing.End(span, err) }()
+	return t.next.GetLDDont(ctx, query)
+}
+
+func (t *tracedAdminService) Confirm(ctx context.Context, data dto.Confirm) (err error) {
+	ctx, span := tracing.Start(ctx, "AdminService.Confirm")
+	defer func() { tracing.End(span, err) }()
+	return t.next.Confirm(ctx, data)
+}
+
+func (t *tracedAdminService) ReturnLoan(ctx context.Context, id int, data dto.ReturnOutcome) (err error) {
+	ctx, span := tracing.Start(ctx, "AdminService.ReturnLoan")
+	defer func() { tracing.End(span, err) }()
+	return t.next.ReturnLoan(ctx, id, data)
+}
+
+func (t *tracedAdminService) GetStudents(ctx context.Context, filter dto.StudentFilter) (result []dto.StudentData, meta *dto.Meta, err error) {
+	ctx, span := tracing.Start(ctx, "AdminService.GetStudents")
+	defer func() { tracing.End(span, err) }()
+	return t.next.GetStudents(ctx, filter)
+}
+
+func (t *tracedAdminService) GetStudent(ctx context.Context, nis int) (result *dto.StudentProfile, err error) {
+	ctx, span := tracing.Start(ctx, "AdminService.GetStudent")
+	defer func() { tracing.End(span, err) }()
+	return t.next.GetStudent(ctx, nis)
+}
+
+func (t *tracedAdminService) UpdateStudent(ctx context.Context, nis int, data dto.UpdateStudent) (result []string, err error) {
+	ctx, span := tracing.Start(ctx, "AdminService.UpdateStudent")
+	defer func() { tracing.End(span, err) }()
+	return t.next.UpdateStudent(ctx, nis, data)
+}
+
+func (t *tracedAdminService) DeactivateStudent(ctx context.Context, nis int) (err error) {
+	ctx, span := tracing.Start(ctx, "AdminService.DeactivateStudent")
+	defer func() { tracing.End(span, err) }()
+	return t.next.DeactivateStudent(ctx, nis)
+}
+
+func (t *tracedAdminService) UpdateStudentRole(ctx context.Context, nis int, data dto.UpdateRole) (err error) {
+	ctx, span := tracing.Start(ctx, "AdminService.UpdateStudentRole")
+	defer func() { tracing.End(span, err) }()
+	return t.next.UpdateStudentRole(ctx, nis, data)
+}
+
+func (t *tracedAdminService) AddCategory(ctx context.Context, data dto.Category) (err error) {
+	ctx, span := tracing.Start(ctx, "AdminService.AddCategory")
+	defer func() { tracing.End(span, err) }()
+	return t.next.AddCategory(ctx, data)
+}
+
+func (t *tracedAdminService) AddBook(ctx context.Context, data dto.BookData) (err error) {
+	ctx, span := tracing.Start(ctx, "AdminService.AddBook")
+	defer func() { tracing.End(span, err) }()
+	return t.next.AddBook(ctx, data)
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"stmnplibrary/apperr"
+	"stmnplibrary/controller/service/utils"
+	"stmnplibrary/domain/entity"
+	"stmnplibrary/domain/interface/repository"
+	"stmnplibrary/domain/interface/service"
+	"stmnplibrary/dto"
+	"stmnplibrary/pagination"
+	"time"
+)
+
+type auditService struct {
+	auditRepository repository.AuditRepository
+}
+
+func FnAuditService(repository repository.AuditRepository) service.AuditService {
+	return &tracedAuditService{
+		next: newAuditService(repository),
+	}
+}
+
+func newAuditService(repository repository.AuditRepository) *auditService {
+	return &auditService{auditRepository: repository}
+}
+
+func parseDate(date string) (time.Time, error) {
+	const layout = "02-01-2006"
+	if date == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.ParseInLocation(layout, date, time.Local)
+	if err != nil {
+		return time.Time{}, apperr.Wrap(err, apperr.KindInvalid, apperr.CodeInvalidDate, "wrong date format make sure it is like this: dd-mm-yyyy")
+	}
+	return t, nil
+}
+
+func (as *auditService) GetAudits(ctx context.Context, filter dto.AuditFilter) ([]dto.Audit, *dto.Meta, error) {
+	page, err := pagination.New(filter.Cursor, filter.PageSize, pagination.SortNewest)
+	if err != nil {
+		return nil, nil, err
+	}
+	from, err := parseDate(filter.From)
+	if err != nil {
+		return nil, nil, err
+	}
+	to, err := parseDate(filter.To)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !to.IsZero() {
+		to = to.AddDate(0, 0, 1)
+	}
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		return nil, nil, apperr.New(apperr.KindInvalid, apperr.CodeInvalidDate, "from must be before to")
+	}
+	data, total, err := as.auditRepository.GetAudits(ctx, entity.AuditFilter{
+		ActorID: filter.Actor,
+		Entity:  filter.Entity,
+		From:    from,
+		To:      to,
+	}, page)
+	if err != nil {
+		return nil, nil, utils.ValidateErrTw(err, "service - get_audits: %w")
+	}
+	data, next := pagination.Trim(data, page, utils.AuditKey)
+	return utils.AuditMapper(data), utils.NewMeta(page, next, total), nil
+}
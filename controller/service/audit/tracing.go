@@ -0,0 +1,20 @@
+package service
+
+import (
+	"context"
+
+	"stmnplibrary/domain/interface/service"
+	"stmnplibrary/dto"
+	"stmnplibrary/tracing"
+)
+
+// tracedAuditService wraps every AuditService method in a span, failed calls are recorded on it.
+type tracedAuditService struct {
+	next service.AuditService
+}
+
+func (t *tracedAuditService) GetAudits(ctx context.Context, filter dto.AuditFilter) (result []dto.Audit, meta *dto.Meta, err error) {
+	ctx, span := tracing.Start(ctx, "AuditService.GetAudits")
+	defer func() { tracing.End(span, err) }()
+	return t.next.GetAudits(ctx, filter)
+}
@@ -10,7 +10,6 @@ import (
 	"stmnplibrary/security"
 
 	"context"
-	"time"
 	"fmt"
 )
 
@@ -19,11 +18,19 @@ const keyReTk = "stmnplibrary:accesstoken:id:%d"
 
 type authService struct {
 	authRepository repository.AuthRepository
+	jwt            *token.JWT
 }
 
-func FnAuthService(repository repository.AuthRepository) service.AuthService {
+func FnAuthService(repository repository.AuthRepository, jwt *token.JWT) service.AuthService {
+	return &tracedAuthService{
+		next: newAuthService(repository, jwt),
+	}
+}
+
+func newAuthService(repository repository.AuthRepository, jwt *token.JWT) *authService {
 	return &authService {
 		authRepository: repository,
+		jwt:            jwt,
 	}
 }
 
@@ -43,12 +50,12 @@ func (as * authService) Login(ctx context.Context, data dto.Login) (*claims.Toke
 	if err := security.UnHashPassword(data.Password, pH); err != nil {
 		return nil, err
 	}
-	token, err := token.GenerateToken(id, role)
+	token, err := as.jwt.GenerateToken(id, role)
 	if err != nil {
-		return nil, fmt.Errorf(errIntrnl, err)
+		return nil, utils.ValidateErrTw(err, errIntrnl)
 	}
 
-	if err := as.authRepository.RedisSet(ctx, key, []byte(token.RefreshToken), 24*7*time.Hour); err != nil {
+	if err := as.authRepository.RedisSet(ctx, key, []byte(token.RefreshToken), as.jwt.RefreshTTL()); err != nil {
 		return nil, utils.ValidateErrTw(err, errIntrnl)
 	}
 
@@ -57,7 +64,7 @@ func (as * authService) Login(ctx context.Context, data dto.Login) (*claims.Toke
 
 func (as *authService) Refresh(ctx context.Context, refreshTkn string) (*claims.Token, error) {
 	const errIntrnl = "service - refresh: %w"
-	cls, err := token.ValidateToken(refreshTkn)
+	cls, err := as.jwt.ValidateToken(refreshTkn)
 	if err != nil {
 		return nil, err
 	}
@@ -65,11 +72,11 @@ func (as *authService) Refresh(ctx context.Context, refreshTkn string) (*claims.
 	if _, err := as.authRepository.RedisGet(ctx, key); err != nil {
 		return nil, utils.ValidateErrTw(err, errIntrnl)
 	}
-	token, err := token.GenerateToken(cls.UserId, cls.Role)
+	token, err := as.jwt.GenerateToken(cls.UserId, cls.Role)
 	if err != nil {
 		return nil, err
 	}
-	if err := as.authRepository.RedisSet(ctx, key, []byte(token.RefreshToken), 24*7*time.Hour); err != nil {
+	if err := as.authRepository.RedisSet(ctx, key, []byte(token.RefreshToken), as.jwt.RefreshTTL()); err != nil {
 		return nil, utils.ValidateErrTw(err, errIntrnl)
 	}
 	return token, nil
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"stmnplibrary/config"
+	"stmnplibrary/domain/interface/service"
+	"stmnplibrary/dto"
+	"stmnplibrary/mocks"
+	"stmnplibrary/security"
+	token "stmnplibrary/security/jwt"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var testJWT = token.FnJWT(config.JWT{SecretKey: "secret", AccessTTL: 3 * time.Minute, RefreshTTL: 120 * time.Hour})
+
+func setupAuth(t *testing.T) (*mocks.AuthRepository, service.AuthService) {
+	repo := mocks.NewAuthRepository(t)
+	svc := newAuthService(repo, testJWT)
+	return repo, svc
+}
+
+func TestLogin(t *testing.T) {
+	repo, svc := setupAuth(t)
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		password := "password123"
+		hashedPassword, _ := security.HashPassword(password)
+
+		repo.On("GetId", ctx, 123).Return(1, nil).Once()
+		repo.On("GetPassword", ctx, 123).Return(hashedPassword, "students", nil).Once()
+		repo.On("RedisSet", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+
+		res, err := svc.Login(ctx, dto.Login{NIS: 123, Password: password})
+
+		assert.NoError(t, err)
+		assert.NotNil(t, res)
+	})
+
+	t.Run("Fail_Wrong_Password", func(t *testing.T) {
+		hashedPassword, _ := security.HashPassword("password123")
+
+		repo.On("GetId", ctx, 123).Return(1, nil).Once()
+		repo.On("GetPassword", ctx, 123).Return(hashedPassword, "students", nil).Once()
+
+		res, err := svc.Login(ctx, dto.Login{NIS: 123, Password: "wrong"})
+
+		assert.Error(t, err)
+		assert.Nil(t, res)
+	})
+}
+
+func TestRefresh(t *testing.T) {
+	repo, svc := setupAuth(t)
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		repo.On("RedisGet", ctx, mock.Anything).Return([]byte("valid-refresh-token"), nil).Once()
+		repo.On("RedisSet", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+
+		validToken, _ := testJWT.GenerateToken(1, "students")
+		res, err := svc.Refresh(ctx, validToken.RefreshToken)
+
+		assert.NoError(t, err)
+		assert.NotNil(t, res)
+	})
+
+	t.Run("Fail_Invalid_Token", func(t *testing.T) {
+		res, err := svc.Refresh(ctx, "invalid")
+		assert.Error(t, err)
+		assert.Nil(t, res)
+	})
+}
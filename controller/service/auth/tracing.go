@@ -0,0 +1,27 @@
+package service
+
+import (
+	"context"
+
+	"stmnplibrary/domain/interface/service"
+	"stmnplibrary/dto"
+	"stmnplibrary/security/jwt/claims"
+	"stmnplibrary/tracing"
+)
+
+// tracedAuthService wraps every AuthService method in a span, failed calls are recorded on it.
+type tracedAuthService struct {
+	next service.AuthService
+}
+
+func (t *tracedAuthService) Login(ctx context.Context, data dto.Login) (result *claims.Token, err error) {
+	ctx, span := tracing.Start(ctx, "AuthService.Login")
+	defer func() { tracing.End(span, err) }()
+	return t.next.Login(ctx, data)
+}
+
+func (t *tracedAuthService) Refresh(ctx context.Context, refreshTkn string) (result *claims.Token, err error) {
+	ctx, span := tracing.Start(ctx, "AuthService.Refresh")
+	defer func() { tracing.End(span, err) }()
+	return t.next.Refresh(ctx, refreshTkn)
+}
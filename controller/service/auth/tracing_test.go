@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"stmnplibrary/apperr"
+	"stmnplibrary/dto"
+	"stmnplibrary/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracedAuthService(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	repo := mocks.NewAuthRepository(t)
+	svc := FnAuthService(repo, testJWT)
+	// the repository runs under the span of the service call
+	traced := mock.MatchedBy(func(ctx context.Context) bool {
+		return trace.SpanFromContext(ctx).SpanContext().IsValid()
+	})
+
+	repo.On("GetId", traced, 123).Return(0, apperr.ErrNotFound).Once()
+	_, err := svc.Login(context.Background(), dto.Login{NIS: 123, Password: "password123"})
+	assert.Error(t, err)
+
+	validToken, _ := testJWT.GenerateToken(1, "students")
+	repo.On("RedisGet", traced, mock.Anything).Return([]byte("valid-refresh-token"), nil).Once()
+	repo.On("RedisSet", traced, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+	_, err = svc.Refresh(context.Background(), validToken.RefreshToken)
+	assert.NoError(t, err)
+
+	ended := recorder.Ended()
+	require.Len(t, ended, 2)
+	assert.Equal(t, "AuthService.Login", ended[0].Name())
+	assert.Equal(t, codes.Error, ended[0].Status().Code)
+	assert.Equal(t, "AuthService.Refresh", ended[1].Name())
+	assert.Equal(t, codes.Unset, ended[1].Status().Code)
+}
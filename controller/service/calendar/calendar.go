@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"io"
+	"stmnplibrary/apperr"
+	"stmnplibrary/audit"
+	"stmnplibrary/calendar"
+	"stmnplibrary/clock"
+	"stmnplibrary/controller/service/utils"
+	"stmnplibrary/domain/entity"
+	"stmnplibrary/domain/interface/repository"
+	"stmnplibrary/domain/interface/service"
+	"stmnplibrary/dto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type calendarService struct {
+	calendarRepository repository.CalendarRepository
+	auditRepository    repository.AuditRepository
+	clock              clock.Clock
+}
+
+func FnCalendarService(repository repository.CalendarRepository, auditRepository repository.AuditRepository, clock clock.Clock) service.CalendarService {
+	return &tracedCalendarService{
+		next: newCalendarService(repository, auditRepository, clock),
+	}
+}
+
+func newCalendarService(repository repository.CalendarRepository, auditRepository repository.AuditRepository, clock clock.Clock) *calendarService {
+	return &calendarService{
+		calendarRepository: repository,
+		auditRepository:    auditRepository,
+		clock:              clock,
+	}
+}
+
+func (cs *calendarService) record(ctx context.Context, action string, entityName string, entityID string, before any, after any) error {
+	data, err := utils.NewAudit(ctx, action, entityName, entityID, before, after)
+	if err != nil {
+		return err
+	}
+	return cs.auditRepository.Record(ctx, data)
+}
+
+// weekly is how the opening hours are kept in the audit log, one entry per
+// day like "monday": "07:00-15:00".
+func weekly(hours []entity.OpeningHours) map[string]any {
+	var week = make(map[string]any, len(hours))
+	for _, h := range hours {
+		day := strings.ToLower(time.Weekday(h.Weekday).String())
+		if !h.IsOpen {
+			week[day] = "closed"
+			continue
+		}
+		week[day] = h.OpensAt + "-" + h.ClosesAt
+	}
+	return week
+}
+
+func errValidation(msg string) error {
+	return apperr.New(apperr.KindInvalid, apperr.CodeValidation, msg)
+}
+
+// GetCalendar returns the opening hours and the closures from from to to, the
+// coming year when they are empty.
+func (cs *calendarService) GetCalendar(ctx context.Context, query dto.CalendarQuery) (*dto.Calendar, error) {
+	const errMsg = "service - get_calendar: %w"
+	var (
+		from = cs.clock.Now()
+		to   time.Time
+	)
+	if query.From != "" {
+		from, _ = time.ParseInLocation(time.DateOnly, query.From, cs.clock.Location())
+	}
+	to = from.AddDate(1, 0, 0)
+	if query.To != "" {
+		to, _ = time.ParseInLocation(time.DateOnly, query.To, cs.clock.Location())
+	}
+	if to.Before(from) {
+		return nil, errValidation("to must not be before from")
+	}
+	hours, err := cs.calendarRepository.GetOpeningHours(ctx)
+	if err != nil {
+		return nil, utils.ValidateErrTw(err, errMsg)
+	}
+	closures, err := cs.calendarRepository.GetClosures(ctx, from, to)
+	if err != nil {
+		return nil, utils.ValidateErrTw(err, errMsg)
+	}
+	var result = &dto.Calendar{
+		OpeningHours: utils.OpeningHoursMapper(hours),
+		Closures:     make([]dto.Closure, 0, len(closures)),
+	}
+	for _, c := range closures {
+		result.Closures = append(result.Closures, utils.ClosureMapper(c))
+	}
+	return result, nil
+}
+
+// SetOpeningHours replaces the opening hours of the days sent, the other days
+// are kept.
+func (cs *calendarService) SetOpeningHours(ctx context.Context, data dto.SetOpeningHours) ([]dto.OpeningHours, error) {
+	const errMsg = "service - set_opening_hours: %w"
+	var (
+		seen  [7]bool
+		hours = make([]entity.OpeningHours, 0, len(data.Days))
+	)
+	for _, d := range data.Days {
+		if seen[d.Weekday] {
+			return nil, errValidation("weekday " + strconv.Itoa(d.Weekday) + " is sent twice")
+		}
+		seen[d.Weekday] = true
+		// HH:MM compares in time order
+		if d.IsOpen && d.OpensAt >= d.ClosesAt {
+			return nil, errValidation("opens_at must be before closes_at")
+		}
+		hours = append(hours, entity.OpeningHours{
+			Weekday:  d.Weekday,
+			IsOpen:   d.IsOpen,
+			OpensAt:  d.OpensAt,
+			ClosesAt: d.ClosesAt,
+		})
+	}
+	var result []entity.OpeningHours
+	err := cs.calendarRepository.WithTx(ctx, func(ctx context.Context) error {
+		before, err := cs.calendarRepository.GetOpeningHours(ctx)
+		if err != nil {
+			return err
+		}
+		if err := cs.calendarRepository.SetOpeningHours(ctx, hours); err != nil {
+			return err
+		}
+		if result, err = cs.calendarRepository.GetOpeningHours(ctx); err != nil {
+			return err
+		}
+		return cs.record(ctx, audit.ActionUpdate, "opening_hours", "weekly", weekly(before), weekly(result))
+	})
+	if err != nil {
+		return nil, utils.ValidateErrTw(err, errMsg)
+	}
+	return utils.OpeningHoursMapper(result), nil
+}
+
+func (cs *calendarService) AddClosure(ctx context.Context, data dto.AddClosure) (*dto.Closure, error) {
+	const errMsg = "service - add_closure: %w"
+	start, _ := time.Parse(time.DateOnly, data.StartDate)
+	end := start
+	if data.EndDate != "" {
+		end, _ = time.Parse(time.DateOnly, data.EndDate)
+	}
+	if end.Before(start) {
+		return nil, errValidation("end_date must not be before start_date")
+	}
+	var closure = entity.Closure{
+		Name:      data.Name,
+		Kind:      data.Kind,
+		StartDate: start,
+		EndDate:   end,
+	}
+	err := cs.calendarRepository.WithTx(ctx, func(ctx context.Context) error {
+		if err := cs.calendarRepository.AddClosure(ctx, &closure); err != nil {
+			return err
+		}
+		return cs.record(ctx, audit.ActionCreate, "closure", strconv.Itoa(closure.ID), nil, map[string]any{
+			"name":       closure.Name,
+			"kind":       closure.Kind,
+			"start_date": data.StartDate,
+			"end_date":   end.Format(time.DateOnly),
+		})
+	})
+	if err != nil {
+		return nil, utils.ValidateErrTw(err, errMsg)
+	}
+	result := utils.ClosureMapper(closure)
+	return &result, nil
+}
+
+func (cs *calendarService) DeleteClosure(ctx context.Context, id int) error {
+	const errMsg = "service - delete_closure: %w"
+	err := cs.calendarRepository.WithTx(ctx, func(ctx context.Context) error {
+		closure, err := cs.calendarRepository.DeleteClosure(ctx, id)
+		if err != nil {
+			return err
+		}
+		return cs.record(ctx, audit.ActionDelete, "closure", strconv.Itoa(id), map[string]any{
+			"name":       closure.Name,
+			"kind":       closure.Kind,
+			"start_date": closure.StartDate.Format(time.DateOnly),
+			"end_date":   closure.EndDate.Format(time.DateOnly),
+		}, nil)
+	})
+	if err != nil {
+		return utils.ValidateErrTw(err, errMsg)
+	}
+	return nil
+}
+
+// ImportICal adds every all-day event of an iCalendar file as a closure of
+// kind, importing the same file again updates the closures instead of
+// duplicating them.
+func (cs *calendarService) ImportICal(ctx context.Context, file io.Reader, kind string) (*dto.CalendarImport, error) {
+	const errMsg = "service - import_ical: %w"
+	closures, err := calendar.ParseICS(file, kind)
+	if err != nil {
+		return nil, apperr.Wrap(err, apperr.KindInvalid, apperr.CodeValidation, "invalid calendar file: "+err.Error())
+	}
+	if len(closures) == 0 {
+		return nil, errValidation("no event in calendar file")
+	}
+	var result = &dto.CalendarImport{Events: len(closures)}
+	err = cs.calendarRepository.WithTx(ctx, func(ctx context.Context) error {
+		if result.Imported, err = cs.calendarRepository.UpsertClosures(ctx, closures); err != nil {
+			return err
+		}
+		return cs.record(ctx, audit.ActionImport, "closure", "ical", nil, map[string]any{
+			"kind":     kind,
+			"events":   result.Events,
+			"imported": result.Imported,
+			"from":     closures[0].StartDate.Format(time.DateOnly),
+			"to":       closures[len(closures)-1].StartDate.Format(time.DateOnly),
+		})
+	})
+	if err != nil {
+		return nil, utils.ValidateErrTw(err, errMsg)
+	}
+	return result, nil
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"stmnplibrary/apperr"
+	"stmnplibrary/clock"
+	"stmnplibrary/domain/entity"
+	"stmnplibrary/dto"
+	"stmnplibrary/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setup(t *testing.T) (*mocks.CalendarRepository, *mocks.AuditRepository, *calendarService) {
+	repo := mocks.NewCalendarRepository(t)
+	auditRepo := mocks.NewAuditRepository(t)
+	svc := newCalendarService(repo, auditRepo, clock.NewFake(time.Date(2026, 5, 4, 9, 0, 0, 0, time.UTC)))
+	repo.On("WithTx", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	}).Maybe()
+	return repo, auditRepo, svc
+}
+
+func TestGetCalendar(t *testing.T) {
+	ctx := context.Background()
+	repo, _, svc := setup(t)
+	from := time.Date(2026, 5, 4, 9, 0, 0, 0, time.UTC)
+	repo.On("GetOpeningHours", ctx).Return([]entity.OpeningHours{{Weekday: 0}, {Weekday: 1, IsOpen: true, OpensAt: "07:00", ClosesAt: "15:00"}}, nil).Once()
+	repo.On("GetClosures", ctx, from, from.AddDate(1, 0, 0)).Return([]entity.Closure{
+		{ID: 1, Name: "Hari Kemerdekaan", Kind: entity.ClosureHoliday, StartDate: time.Date(2026, 8, 17, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2026, 8, 17, 0, 0, 0, 0, time.UTC)},
+	}, nil).Once()
+
+	result, err := svc.GetCalendar(ctx, dto.CalendarQuery{})
+	require.NoError(t, err)
+	assert.Len(t, result.OpeningHours, 2)
+	assert.Equal(t, dto.Closure{ID: 1, Name: "Hari Kemerdekaan", Kind: entity.ClosureHoliday, StartDate: "2026-08-17", EndDate: "2026-08-17"}, result.Closures[0])
+
+	_, err = svc.GetCalendar(ctx, dto.CalendarQuery{From: "2026-05-04", To: "2026-05-01"})
+	assert.Equal(t, apperr.KindInvalid, apperr.KindOf(err))
+}
+
+func TestSetOpeningHours_Cases(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		repo, auditRepo, svc := setup(t)
+		after := []entity.OpeningHours{{Weekday: 6, IsOpen: true, OpensAt: "08:00", ClosesAt: "12:00"}}
+		repo.On("GetOpeningHours", ctx).Return([]entity.OpeningHours{{Weekday: 6}}, nil).Once()
+		repo.On("SetOpeningHours", ctx, after).Return(nil).Once()
+		repo.On("GetOpeningHours", ctx).Return(after, nil).Once()
+		auditRepo.On("Record", ctx, mock.MatchedBy(func(a entity.Audit) bool {
+			return a.Entity == "opening_hours" && a.Action == "update"
+		})).Return(nil).Once()
+
+		result, err := svc.SetOpeningHours(ctx, dto.SetOpeningHours{Days: []dto.OpeningHours{{Weekday: 6, IsOpen: true, OpensAt: "08:00", ClosesAt: "12:00"}}})
+		require.NoError(t, err)
+		assert.Equal(t, []dto.OpeningHours{{Weekday: 6, IsOpen: true, OpensAt: "08:00", ClosesAt: "12:00"}}, result)
+	})
+
+	t.Run("Closes_Before_Opening", func(t *testing.T) {
+		_, _, svc := setup(t)
+		_, err := svc.SetOpeningHours(ctx, dto.SetOpeningHours{Days: []dto.OpeningHours{{Weekday: 1, IsOpen: true, OpensAt: "15:00", ClosesAt: "07:00"}}})
+		assert.Equal(t, apperr.KindInvalid, apperr.KindOf(err))
+	})
+
+	t.Run("Weekday_Twice", func(t *testing.T) {
+		_, _, svc := setup(t)
+		_, err := svc.SetOpeningHours(ctx, dto.SetOpeningHours{Days: []dto.OpeningHours{{Weekday: 0}, {Weekday: 0}}})
+		assert.Equal(t, apperr.KindInvalid, apperr.KindOf(err))
+	})
+}
+
+func TestAddClosure_Cases(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Single_Day", func(t *testing.T) {
+		repo, auditRepo, svc := setup(t)
+		repo.On("AddClosure", ctx, mock.MatchedBy(func(c *entity.Closure) bool {
+			return c.StartDate.Equal(c.EndDate) && c.UID == nil
+		})).Run(func(args mock.Arguments) { args.Get(1).(*entity.Closure).ID = 3 }).Return(nil).Once()
+		auditRepo.On("Record", ctx, mock.MatchedBy(func(a entity.Audit) bool {
+			return a.Entity == "closure" && a.EntityID == "3"
+		})).Return(nil).Once()
+
+		result, err := svc.AddClosure(ctx, dto.AddClosure{Name: "Rapat Guru", Kind: entity.ClosureHoliday, StartDate: "2026-06-01"})
+		require.NoError(t, err)
+		assert.Equal(t, "2026-06-01", result.EndDate)
+		assert.False(t, result.Imported)
+	})
+
+	t.Run("Ends_Before_Start", func(t *testing.T) {
+		_, _, svc := setup(t)
+		_, err := svc.AddClosure(ctx, dto.AddClosure{Name: "Libur", Kind: entity.ClosureTermBreak, StartDate: "2026-12-21", EndDate: "2026-12-01"})
+		assert.Equal(t, apperr.KindInvalid, apperr.KindOf(err))
+	})
+}
+
+func TestDeleteClosure_Not_Found(t *testing.T) {
+	ctx := context.Background()
+	repo, _, svc := setup(t)
+	repo.On("DeleteClosure", ctx, 9).Return(entity.Closure{}, apperr.ErrNotFound).Once()
+
+	err := svc.DeleteClosure(ctx, 9)
+	assert.Equal(t, apperr.KindNotFound, apperr.KindOf(err))
+}
+
+func TestImportICal_Cases(t *testing.T) {
+	ctx := context.Background()
+	const ics = "BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART;VALUE=DATE:20260817\nDTEND;VALUE=DATE:20260818\nUID:kemerdekaan\nSUMMARY:Hari Kemerdekaan\nEND:VEVENT\nEND:VCALENDAR\n"
+
+	t.Run("Success", func(t *testing.T) {
+		repo, auditRepo, svc := setup(t)
+		repo.On("UpsertClosures", ctx, mock.MatchedBy(func(c []entity.Closure) bool {
+			return len(c) == 1 && *c[0].UID == "kemerdekaan" && c[0].Kind == entity.ClosureHoliday
+		})).Return(int64(1), nil).Once()
+		auditRepo.On("Record", ctx, mock.MatchedBy(func(a entity.Audit) bool {
+			return a.Action == "import" && a.Entity == "closure"
+		})).Return(nil).Once()
+
+		result, err := svc.ImportICal(ctx, strings.NewReader(ics), entity.ClosureHoliday)
+		require.NoError(t, err)
+		assert.Equal(t, &dto.CalendarImport{Events: 1, Imported: 1}, result)
+	})
+
+	t.Run("No_Event", func(t *testing.T) {
+		_, _, svc := setup(t)
+		_, err := svc.ImportICal(ctx, strings.NewReader("BEGIN:VCALENDAR\nEND:VCALENDAR\n"), entity.ClosureHoliday)
+		assert.Equal(t, apperr.KindInvalid, apperr.KindOf(err))
+	})
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"io"
+
+	"stmnplibrary/domain/interface/service"
+	"stmnplibrary/dto"
+	"stmnplibrary/tracing"
+)
+
+// tracedCalendarService wraps every CalendarService method in a span, failed calls are recorded on it.
+type tracedCalendarService struct {
+	next service.CalendarService
+}
+
+func (t *tracedCalendarService) GetCalendar(ctx context.Context, query dto.CalendarQuery) (result *dto.Calendar, err error) {
+	ctx, span := tracing.Start(ctx, "CalendarService.GetCalendar")
+	defer func() { tracing.End(span, err) }()
+	return t.next.GetCalendar(ctx, query)
+}
+
+func (t *tracedCalendarService) SetOpeningHours(ctx context.Context, data dto.SetOpeningHours) (result []dto.OpeningHours, err error) {
+	ctx, span := tracing.Start(ctx, "CalendarService.SetOpeningHours")
+	defer func() { tracing.End(span, err) }()
+	return t.next.SetOpeningHours(ctx, data)
+}
+
+func (t *tracedCalendarService) AddClosure(ctx context.Context, data dto.AddClosure) (result *dto.Closure, err error) {
+	ctx, span := tracing.Start(ctx, "CalendarService.AddClosure")
+	defer func() { tracing.End(span, err) }()
+	return t.next.AddClosure(ctx, data)
+}
+
+func (t *tracedCalendarService) DeleteClosure(ctx context.Context, id int) (err error) {
+	ctx, span := tracing.Start(ctx, "CalendarService.DeleteClosure")
+	defer func() { tracing.End(span, err) }()
+	return t.next.DeleteClosure(ctx, id)
+}
+
+func (t *tracedCalendarService) ImportICal(ctx context.Context, file io.Reader, kind string) (result *dto.CalendarImport, err error) {
+	ctx, span := tracing.Start(ctx, "CalendarService.ImportICal")
+	defer func() { tracing.End(span, err) }()
+	return t.next.ImportICal(ctx, file, kind)
+}
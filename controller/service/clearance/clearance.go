@@ -0,0 +1,246 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"stmnplibrary/apperr"
+	"stmnplibrary/audit"
+	"stmnplibrary/clearance"
+	"stmnplibrary/clock"
+	"stmnplibrary/config"
+	"stmnplibrary/constanta"
+	"stmnplibrary/controller/service/utils"
+	"stmnplibrary/domain/entity"
+	"stmnplibrary/domain/interface/repository"
+	"stmnplibrary/domain/interface/service"
+	"stmnplibrary/dto"
+	"strconv"
+	"time"
+)
+
+type clearanceService struct {
+	clearanceRepository repository.ClearanceRepository
+	auditRepository     repository.AuditRepository
+	cfg                 config.Clearance
+	clock               clock.Clock
+}
+
+func FnClearanceService(repository repository.ClearanceRepository, auditRepository repository.AuditRepository, cfg config.Clearance, clock clock.Clock) service.ClearanceService {
+	return &tracedClearanceService{
+		next: newClearanceService(repository, auditRepository, cfg, clock),
+	}
+}
+
+func newClearanceService(repository repository.ClearanceRepository, auditRepository repository.AuditRepository, cfg config.Clearance, clock clock.Clock) *clearanceService {
+	return &clearanceService{
+		clearanceRepository: repository,
+		auditRepository:     auditRepository,
+		cfg:                 cfg,
+		clock:               clock,
+	}
+}
+
+func (cs *clearanceService) record(ctx context.Context, action string, entityName string, entityID string, before any, after any) error {
+	data, err := utils.NewAudit(ctx, action, entityName, entityID, before, after)
+	if err != nil {
+		return err
+	}
+	return cs.auditRepository.Record(ctx, data)
+}
+
+func (cs *clearanceService) mapper(c entity.Clearance) *dto.Clearance {
+	return &dto.Clearance{
+		Number:    c.Number,
+		NIS:       c.NIS,
+		Name:      c.Name,
+		Class:     c.Class,
+		SubClass:  c.SubClass,
+		Major:     c.Major,
+		Batch:     c.Batch,
+		Purpose:   c.Purpose,
+		IssuedAt:  c.IssuedAt,
+		VerifyURL: clearance.VerifyURL(cs.cfg.VerifyURL, c),
+	}
+}
+
+func errNotCleared(check entity.ClearanceCheck) error {
+	return apperr.New(apperr.KindConflict, apperr.CodeNotCleared, fmt.Sprintf("student still has %d active loans and %d outstanding sanctions", check.ActiveLoans, check.OutstandingSanctions))
+}
+
+// issue clears one student in its own transaction. A student cleared before
+// gets the existing clearance back, a student with active loans or
+// outstanding sanctions is blocked and gets the reason in the check.
+func (cs *clearanceService) issue(ctx context.Context, nis int, purpose string) (entity.Clearance, string, entity.ClearanceCheck, error) {
+	var (
+		data   entity.Clearance
+		status string
+		check  entity.ClearanceCheck
+	)
+	err := cs.clearanceRepository.WithTx(ctx, func(ctx context.Context) error {
+		student, err := cs.clearanceRepository.LockStudent(ctx, nis)
+		if err != nil {
+			return err
+		}
+		// a student keeps one certificate per purpose, one cleared for transfer
+		// still needs its own for graduation
+		data, err = cs.clearanceRepository.GetClearanceByUser(ctx, student.ID, purpose)
+		if err == nil {
+			status = clearance.StatusExisting
+			return nil
+		}
+		if apperr.KindOf(err) != apperr.KindNotFound {
+			return err
+		}
+		if check, err = cs.clearanceRepository.CheckClearance(ctx, student.ID); err != nil {
+			return err
+		}
+		if !check.Clear() {
+			status = clearance.StatusBlocked
+			return nil
+		}
+		issuedAt := cs.clock.Now().Truncate(time.Second)
+		number, err := clearance.NewNumber(issuedAt)
+		if err != nil {
+			return apperr.Internal(err)
+		}
+		issuedBy, _ := ctx.Value(constanta.UI).(int)
+		data = entity.Clearance{
+			Number:   number,
+			IdUser:   student.ID,
+			NIS:      student.NIS,
+			Name:     student.Name,
+			Class:    student.Class,
+			SubClass: student.SubClass,
+			Major:    student.Major,
+			Batch:    student.Batch,
+			Purpose:  purpose,
+			IssuedBy: issuedBy,
+			IssuedAt: issuedAt,
+		}
+		data.Signature = clearance.Sign(cs.cfg.SigningKey, data)
+		if err := cs.clearanceRepository.AddClearance(ctx, &data); err != nil {
+			return err
+		}
+		status = clearance.StatusIssued
+		return cs.record(ctx, audit.ActionCreate, "clearance", number, nil, map[string]any{
+			"nis":     data.NIS,
+			"batch":   data.Batch,
+			"purpose": data.Purpose,
+		})
+	})
+	return data, status, check, err
+}
+
+func (cs *clearanceService) Issue(ctx context.Context, nis int, data dto.IssueClearance) (*dto.Clearance, error) {
+	const errMsg = "service - issue_clearance: %w"
+	result, status, check, err := cs.issue(ctx, nis, data.Purpose)
+	if err != nil {
+		return nil, utils.ValidateErrTw(err, errMsg)
+	}
+	if status == clearance.StatusBlocked {
+		return nil, errNotCleared(check)
+	}
+	return cs.mapper(result), nil
+}
+
+// IssueBatch clears every student of a batch one by one, a blocked student
+// doesn't stop the others.
+func (cs *clearanceService) IssueBatch(ctx context.Context, data dto.IssueBatchClearance) (*dto.ClearanceBatch, error) {
+	const errMsg = "service - issue_batch_clearance: %w"
+	students, err := cs.clearanceRepository.GetStudentsByBatch(ctx, data.Batch)
+	if err != nil {
+		return nil, utils.ValidateErrTw(err, errMsg)
+	}
+	if len(students) == 0 {
+		return nil, apperr.New(apperr.KindNotFound, apperr.CodeNotFound, "no student in batch "+strconv.Itoa(data.Batch))
+	}
+	var batch = &dto.ClearanceBatch{
+		Batch:   data.Batch,
+		Results: make([]dto.ClearanceResult, 0, len(students)),
+	}
+	for _, s := range students {
+		result, status, check, err := cs.issue(ctx, s.NIS, data.Purpose)
+		if err != nil {
+			return nil, utils.ValidateErrTw(err, errMsg)
+		}
+		switch status {
+		case clearance.StatusIssued:
+			batch.Issued++
+		case clearance.StatusExisting:
+			batch.Existing++
+		case clearance.StatusBlocked:
+			batch.Blocked++
+		}
+		batch.Results = append(batch.Results, dto.ClearanceResult{
+			NIS:                  s.NIS,
+			Name:                 s.Name,
+			Status:               status,
+			Number:               result.Number,
+			ActiveLoans:          check.ActiveLoans,
+			OutstandingSanctions: check.OutstandingSanctions,
+		})
+	}
+	return batch, nil
+}
+
+func (cs *clearanceService) GetClearance(ctx context.Context, number string) (*dto.Clearance, error) {
+	data, err := cs.clearanceRepository.GetClearance(ctx, number)
+	if err != nil {
+		return nil, utils.ValidateErrTw(err, "service - get_clearance: %w")
+	}
+	return cs.mapper(data), nil
+}
+
+// Verify checks a scanned certificate, the clearance is only returned when the
+// signature matches both the stored row and the key.
+func (cs *clearanceService) Verify(ctx context.Context, number string, signature string) (*dto.ClearanceVerification, error) {
+	data, err := cs.clearanceRepository.GetClearance(ctx, number)
+	if err != nil {
+		return nil, utils.ValidateErrTw(err, "service - verify_clearance: %w")
+	}
+	if !clearance.Verify(cs.cfg.SigningKey, data, signature) {
+		return &dto.ClearanceVerification{Valid: false}, nil
+	}
+	return &dto.ClearanceVerification{Valid: true, Clearance: cs.mapper(data)}, nil
+}
+
+func (cs *clearanceService) Render(ctx context.Context, number string) ([]byte, error) {
+	const errMsg = "service - render_clearance: %w"
+	data, err := cs.clearanceRepository.GetClearance(ctx, number)
+	if err != nil {
+		return nil, utils.ValidateErrTw(err, errMsg)
+	}
+	pdf, err := clearance.Render(data, clearance.VerifyURL(cs.cfg.VerifyURL, data), cs.cfg.Institution)
+	if err != nil {
+		return nil, utils.ValidateErrTw(apperr.Internal(err), errMsg)
+	}
+	return pdf, nil
+}
+
+// SettleSanctions marks every outstanding sanction of a student as paid.
+func (cs *clearanceService) SettleSanctions(ctx context.Context, nis int) (*dto.SettledSanctions, error) {
+	const errMsg = "service - settle_sanctions: %w"
+	var settled dto.SettledSanctions
+	err := cs.clearanceRepository.WithTx(ctx, func(ctx context.Context) error {
+		student, err := cs.clearanceRepository.LockStudent(ctx, nis)
+		if err != nil {
+			return err
+		}
+		check, err := cs.clearanceRepository.CheckClearance(ctx, student.ID)
+		if err != nil {
+			return err
+		}
+		if check.OutstandingSanctions == 0 {
+			return nil
+		}
+		if settled.Loans, err = cs.clearanceRepository.SettleSanctions(ctx, student.ID); err != nil {
+			return err
+		}
+		settled.Amount = check.OutstandingSanctions
+		return cs.record(ctx, audit.ActionSettle, "student", strconv.Itoa(nis), map[string]any{"outstanding_sanctions": check.OutstandingSanctions}, map[string]any{"outstanding_sanctions": 0})
+	})
+	if err != nil {
+		return nil, utils.ValidateErrTw(err, errMsg)
+	}
+	return &settled, nil
+}
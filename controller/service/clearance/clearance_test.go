@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"stmnplibrary/apperr"
+	"stmnplibrary/clearance"
+	"stmnplibrary/clock"
+	"stmnplibrary/config"
+	"stmnplibrary/domain/entity"
+	"stmnplibrary/dto"
+	"stmnplibrary/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var testCfg = config.Clearance{
+	SigningKey:  "clearance-signing-key",
+	VerifyURL:   "https://library.example/api/v1/clearances",
+	Institution: "STMNP Library",
+}
+
+func setup(t *testing.T) (*mocks.ClearanceRepository, *mocks.AuditRepository, *clearanceService, time.Time) {
+	repo := mocks.NewClearanceRepository(t)
+	auditRepo := mocks.NewAuditRepository(t)
+	now := time.Date(2026, 5, 4, 9, 0, 0, 0, time.UTC)
+	svc := newClearanceService(repo, auditRepo, testCfg, clock.NewFake(now))
+	repo.On("WithTx", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	}).Maybe()
+	return repo, auditRepo, svc, now
+}
+
+func student(id int, nis int) entity.StudentData {
+	return entity.StudentData{ID: id, NIS: nis, Name: "Student " + strings.Repeat("I", id), Class: "XII", SubClass: "A", Major: "RPL", Batch: 2024}
+}
+
+func TestIssue_Cases(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Issued", func(t *testing.T) {
+		repo, auditRepo, svc, now := setup(t)
+		repo.On("LockStudent", ctx, 100).Return(student(1, 100), nil).Once()
+		repo.On("GetClearanceByUser", ctx, 1, clearance.PurposeGraduation).Return(entity.Clearance{}, apperr.ErrNotFound).Once()
+		repo.On("CheckClearance", ctx, 1).Return(entity.ClearanceCheck{}, nil).Once()
+		repo.On("AddClearance", ctx, mock.MatchedBy(func(c *entity.Clearance) bool {
+			return c.IdUser == 1 && c.NIS == 100 && c.Purpose == clearance.PurposeGraduation && c.IssuedAt.Equal(now) &&
+				strings.HasPrefix(c.Number, "BP-2026-") && clearance.Verify(testCfg.SigningKey, *c, c.Signature)
+		})).Return(nil).Once()
+		auditRepo.On("Record", ctx, mock.MatchedBy(func(a entity.Audit) bool {
+			return a.Entity == "clearance" && a.Action == "create"
+		})).Return(nil).Once()
+
+		result, err := svc.Issue(ctx, 100, dto.IssueClearance{Purpose: clearance.PurposeGraduation})
+		require.NoError(t, err)
+		assert.Equal(t, 100, result.NIS)
+		assert.True(t, strings.HasPrefix(result.VerifyURL, testCfg.VerifyURL+"/"+result.Number+"/verify?sig="))
+	})
+
+	t.Run("Existing", func(t *testing.T) {
+		repo, _, svc, _ := setup(t)
+		repo.On("LockStudent", ctx, 100).Return(student(1, 100), nil).Once()
+		repo.On("GetClearanceByUser", ctx, 1, clearance.PurposeGraduation).Return(entity.Clearance{Number: "BP-2025-AAAAAAAA", NIS: 100}, nil).Once()
+
+		result, err := svc.Issue(ctx, 100, dto.IssueClearance{Purpose: clearance.PurposeGraduation})
+		require.NoError(t, err)
+		assert.Equal(t, "BP-2025-AAAAAAAA", result.Number)
+	})
+
+	t.Run("Other_Purpose", func(t *testing.T) {
+		// cleared for transfer, the graduation certificate is a new one
+		repo, auditRepo, svc, _ := setup(t)
+		repo.On("LockStudent", ctx, 100).Return(student(1, 100), nil).Once()
+		repo.On("GetClearanceByUser", ctx, 1, clearance.PurposeGraduation).Return(entity.Clearance{}, apperr.ErrNotFound).Once()
+		repo.On("CheckClearance", ctx, 1).Return(entity.ClearanceCheck{}, nil).Once()
+		repo.On("AddClearance", ctx, mock.MatchedBy(func(c *entity.Clearance) bool {
+			return c.Purpose == clearance.PurposeGraduation
+		})).Return(nil).Once()
+		auditRepo.On("Record", ctx, mock.Anything).Return(nil).Once()
+
+		result, err := svc.Issue(ctx, 100, dto.IssueClearance{Purpose: clearance.PurposeGraduation})
+		require.NoError(t, err)
+		assert.Equal(t, clearance.PurposeGraduation, result.Purpose)
+		assert.NotEqual(t, "BP-2025-AAAAAAAA", result.Number)
+		repo.AssertNotCalled(t, "GetClearanceByUser", ctx, 1, clearance.PurposeTransfer)
+	})
+
+	t.Run("Blocked", func(t *testing.T) {
+		repo, _, svc, _ := setup(t)
+		repo.On("LockStudent", ctx, 100).Return(student(1, 100), nil).Once()
+		repo.On("GetClearanceByUser", ctx, 1, clearance.PurposeTransfer).Return(entity.Clearance{}, apperr.ErrNotFound).Once()
+		repo.On("CheckClearance", ctx, 1).Return(entity.ClearanceCheck{ActiveLoans: 1, OutstandingSanctions: 4000}, nil).Once()
+
+		_, err := svc.Issue(ctx, 100, dto.IssueClearance{Purpose: clearance.PurposeTransfer})
+		assert.Equal(t, apperr.CodeNotCleared, apperr.CodeOf(err))
+		assert.Equal(t, apperr.KindConflict, apperr.KindOf(err))
+	})
+
+	t.Run("Student_Not_Found", func(t *testing.T) {
+		repo, _, svc, _ := setup(t)
+		repo.On("LockStudent", ctx, 100).Return(entity.StudentData{}, apperr.ErrNotFound).Once()
+
+		_, err := svc.Issue(ctx, 100, dto.IssueClearance{Purpose: clearance.PurposeTransfer})
+		assert.Equal(t, apperr.KindNotFound, apperr.KindOf(err))
+	})
+}
+
+func TestIssueBatch(t *testing.T) {
+	ctx := context.Background()
+	repo, auditRepo, svc, _ := setup(t)
+	repo.On("GetStudentsByBatch", ctx, 2024).Return([]entity.StudentData{student(1, 100), student(2, 101), student(3, 102)}, nil).Once()
+	repo.On("LockStudent", ctx, 100).Return(student(1, 100), nil).Once()
+	repo.On("LockStudent", ctx, 101).Return(student(2, 101), nil).Once()
+	repo.On("LockStudent", ctx, 102).Return(student(3, 102), nil).Once()
+	repo.On("GetClearanceByUser", ctx, 1, clearance.PurposeGraduation).Return(entity.Clearance{}, apperr.ErrNotFound).Once()
+	repo.On("GetClearanceByUser", ctx, 2, clearance.PurposeGraduation).Return(entity.Clearance{Number: "BP-2025-AAAAAAAA"}, nil).Once()
+	repo.On("GetClearanceByUser", ctx, 3, clearance.PurposeGraduation).Return(entity.Clearance{}, apperr.ErrNotFound).Once()
+	repo.On("CheckClearance", ctx, 1).Return(entity.ClearanceCheck{}, nil).Once()
+	repo.On("CheckClearance", ctx, 3).Return(entity.ClearanceCheck{ActiveLoans: 2}, nil).Once()
+	repo.On("AddClearance", ctx, mock.Anything).Return(nil).Once()
+	auditRepo.On("Record", ctx, mock.Anything).Return(nil).Once()
+
+	result, err := svc.IssueBatch(ctx, dto.IssueBatchClearance{Batch: 2024, Purpose: clearance.PurposeGraduation})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Issued)
+	assert.Equal(t, 1, result.Existing)
+	assert.Equal(t, 1, result.Blocked)
+	assert.Equal(t, clearance.StatusBlocked, result.Results[2].Status)
+	assert.Equal(t, int64(2), result.Results[2].ActiveLoans)
+	assert.Empty(t, result.Results[2].Number)
+}
+
+func TestVerify(t *testing.T) {
+	ctx := context.Background()
+	repo, _, svc, now := setup(t)
+	data := entity.Clearance{Number: "BP-2026-7K3M9Q2X", NIS: 100, Name: "Siti", Batch: 2024, Purpose: clearance.PurposeGraduation, IssuedAt: now}
+	data.Signature = clearance.Sign(testCfg.SigningKey, data)
+	repo.On("GetClearance", ctx, data.Number).Return(data, nil).Twice()
+
+	result, err := svc.Verify(ctx, data.Number, data.Signature)
+	require.NoError(t, err)
+	assert.True(t, result.Valid)
+	assert.Equal(t, "Siti", result.Clearance.Name)
+
+	result, err = svc.Verify(ctx, data.Number, strings.Repeat("0", 64))
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Nil(t, result.Clearance)
+}
+
+func TestSettleSanctions(t *testing.T) {
+	ctx := context.Background()
+	repo, auditRepo, svc, _ := setup(t)
+	repo.On("LockStudent", ctx, 100).Return(student(1, 100), nil).Once()
+	repo.On("CheckClearance", ctx, 1).Return(entity.ClearanceCheck{OutstandingSanctions: 6000}, nil).Once()
+	repo.On("SettleSanctions", ctx, 1).Return(int64(2), nil).Once()
+	auditRepo.On("Record", ctx, mock.MatchedBy(func(a entity.Audit) bool {
+		return a.Action == "settle" && a.Entity == "student" && a.EntityID == "100"
+	})).Return(nil).Once()
+
+	result, err := svc.SettleSanctions(ctx, 100)
+	require.NoError(t, err)
+	assert.Equal(t, &dto.SettledSanctions{Loans: 2, Amount: 6000}, result)
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+
+	"stmnplibrary/domain/interface/service"
+	"stmnplibrary/dto"
+	"stmnplibrary/tracing"
+)
+
+// tracedClearanceService wraps every ClearanceService method in a span, failed calls are recorded on it.
+type tracedClearanceService struct {
+	next service.ClearanceService
+}
+
+func (t *tracedClearanceService) Issue(ctx context.Context, nis int, data dto.IssueClearance) (result *dto.Clearance, err error) {
+	ctx, span := tracing.Start(ctx, "ClearanceService.Issue")
+	defer func() { tracing.End(span, err) }()
+	return t.next.Issue(ctx, nis, data)
+}
+
+func (t *tracedClearanceService) IssueBatch(ctx context.Context, data dto.IssueBatchClearance) (result *dto.ClearanceBatch, err error) {
+	ctx, span := tracing.Start(ctx, "ClearanceService.IssueBatch")
+	defer func() { tracing.End(span, err) }()
+	return t.next.IssueBatch(ctx, data)
+}
+
+func (t *tracedClearanceService) GetClearance(ctx context.Context, number string) (result *dto.Clearance, err error) {
+	ctx, span := tracing.Start(ctx, "ClearanceService.GetClearance")
+	defer func() { tracing.End(span, err) }()
+	return t.next.GetClearance(ctx, number)
+}
+
+func (t *tracedClearanceService) Verify(ctx context.Context, number string, signature string) (result *dto.ClearanceVerification, err error) {
+	ctx, span := tracing.Start(ctx, "ClearanceService.Verify")
+	defer func() { tracing.End(span, err) }()
+	return t.next.Verify(ctx, number, signature)
+}
+
+func (t *tracedClearanceService) Render(ctx context.Context, number string) (result []byte, err error) {
+	ctx, span := tracing.Start(ctx, "ClearanceService.Render")
+	defer func() { tracing.End(span, err) }()
+	return t.next.Render(ctx, number)
+}
+
+func (t *tracedClearanceService) SettleSanctions(ctx context.Context, nis int) (result *dto.SettledSanctions, err error) {
+	ctx, span := tracing.Start(ctx, "ClearanceService.SettleSanctions")
+	defer func() { tracing.End(span, err) }()
+	return t.next.SettleSanctions(ctx, nis)
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"stmnplibrary/apperr"
+	"stmnplibrary/config"
+	"stmnplibrary/controller/service/utils"
+	"stmnplibrary/domain/entity"
+	"stmnplibrary/domain/interface/repository"
+	"stmnplibrary/domain/interface/service"
+	"stmnplibrary/dto"
+	"stmnplibrary/log"
+	"stmnplibrary/metrics"
+)
+
+const keyIdempotency = "stmnplibrary:idempotency:%s"
+
+var (
+	errInProgress = apperr.New(apperr.KindConflict, apperr.CodeDuplicateRequest, "a request with this idempotency key is still in progress")
+	errKeyReused  = apperr.New(apperr.KindUnprocessable, apperr.CodeIdempotencyKeyReused, "idempotency key was already used with a different request")
+)
+
+type idempotencyService struct {
+	idempotencyRepository repository.IdempotencyRepository
+	cfg                   config.Idempotency
+	degrade               config.Degrade
+}
+
+func FnIdempotencyService(repository repository.IdempotencyRepository, cfg config.Idempotency, degrade config.Degrade) service.IdempotencyService {
+	return &tracedIdempotencyService{
+		next: newIdempotencyService(repository, cfg, degrade),
+	}
+}
+
+func newIdempotencyService(repository repository.IdempotencyRepository, cfg config.Idempotency, degrade config.Degrade) *idempotencyService {
+	return &idempotencyService{
+		idempotencyRepository: repository,
+		cfg:                   cfg,
+		degrade:               degrade,
+	}
+}
+
+// degraded applies the idempotency policy while redis fails: open runs the
+// request without replay protection, closed rejects it.
+func (is *idempotencyService) degraded(ctx context.Context, err error) error {
+	var policy = is.degrade.IdempotencyPolicy
+	metrics.Degraded.WithLabelValues("idempotency", policy).Inc()
+	log.LogDegraded(ctx, "idempotency", policy, err)
+	if policy == "open" {
+		return nil
+	}
+	return apperr.Unavailable(err)
+}
+
+// Begin claims key for the request identified by fingerprint. A nil response
+// means the caller owns the key and must Complete or Release it, otherwise the
+// stored response of the first request is returned for replay.
+func (is *idempotencyService) Begin(ctx context.Context, key string, fingerprint string) (*dto.StoredResponse, error) {
+	var (
+		keyIk  = fmt.Sprintf(keyIdempotency, key)
+		record = entity.Idempotency{Fingerprint: fingerprint}
+	)
+	val, err := utils.Marshal(record)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	claimed, err := is.idempotencyRepository.RedisSETNX(ctx, keyIk, val, is.cfg.LockTTL)
+	if err != nil {
+		return nil, is.degraded(ctx, err)
+	}
+	if claimed {
+		return nil, nil
+	}
+	result, err := is.idempotencyRepository.RedisGet(ctx, keyIk)
+	if err != nil {
+		// the first request released the key in between, the retry may go again
+		if errors.Is(err, apperr.ErrNotFound) {
+			return nil, errInProgress
+		}
+		return nil, is.degraded(ctx, err)
+	}
+	if err := utils.UnMarshal(result, &record); err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if record.Fingerprint != fingerprint {
+		return nil, errKeyReused
+	}
+	if !record.Done {
+		return nil, errInProgress
+	}
+	return &dto.StoredResponse{
+		Status:      record.Status,
+		ContentType: record.ContentType,
+		Body:        record.Body,
+	}, nil
+}
+
+// Complete stores the response of the request holding key so retries replay it.
+func (is *idempotencyService) Complete(ctx context.Context, key string, fingerprint string, res dto.StoredResponse) error {
+	val, err := utils.Marshal(entity.Idempotency{
+		Fingerprint: fingerprint,
+		Done:        true,
+		Status:      res.Status,
+		ContentType: res.ContentType,
+		Body:        res.Body,
+	})
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	return is.idempotencyRepository.RedisSet(ctx, fmt.Sprintf(keyIdempotency, key), val, is.cfg.TTL)
+}
+
+// Release frees key after a failed request so a retry runs it again.
+func (is *idempotencyService) Release(ctx context.Context, key string) error {
+	return is.idempotencyRepository.RedisDel(ctx, fmt.Sprintf(keyIdempotency, key))
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"stmnplibrary/apperr"
+	"stmnplibrary/config"
+	"stmnplibrary/dto"
+	"stmnplibrary/log"
+	"stmnplibrary/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+const testKey = "stmnplibrary:idempotency:7:POST:/api/v1/books:abc"
+
+func setup(t *testing.T, policy string) (*mocks.IdempotencyRepository, *idempotencyService) {
+	repo := mocks.NewIdempotencyRepository(t)
+	svc := newIdempotencyService(repo, config.Idempotency{TTL: 24 * time.Hour, LockTTL: 30 * time.Second}, config.Degrade{IdempotencyPolicy: policy})
+	return repo, svc
+}
+
+func TestBegin_Cases(t *testing.T) {
+	log.LogInit(zap.NewNop())
+	ctx := context.Background()
+	tests := []struct {
+		name     string
+		policy   string
+		claimed  bool
+		errSETNX error
+		stored   []byte
+		errGet   error
+		want     *dto.StoredResponse
+		wantCode string
+	}{
+		{"Claimed", "open", true, nil, nil, nil, nil, ""},
+		{"Replay_Done", "open", false, nil, []byte(`{"fingerprint":"fp","done":true,"status":201,"content_type":"application/json","body":"eyJzdWNjZXNzIjp0cnVlfQ=="}`), nil,
+			&dto.StoredResponse{Status: 201, ContentType: "application/json", Body: []byte(`{"success":true}`)}, ""},
+		{"Key_Reused", "open", false, nil, []byte(`{"fingerprint":"other","done":true,"status":201}`), nil, nil, apperr.CodeIdempotencyKeyReused},
+		{"In_Progress", "open", false, nil, []byte(`{"fingerprint":"fp"}`), nil, nil, apperr.CodeDuplicateRequest},
+		{"Released_In_Between", "open", false, nil, nil, apperr.ErrNotFound, nil, apperr.CodeDuplicateRequest},
+		{"Redis_Down_Open", "open", false, apperr.Unavailable(errors.New("breaker open")), nil, nil, nil, ""},
+		{"Redis_Down_Closed", "closed", false, apperr.Unavailable(errors.New("breaker open")), nil, nil, nil, apperr.CodeUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, svc := setup(t, tt.policy)
+			repo.On("RedisSETNX", ctx, testKey, mock.Anything, 30*time.Second).Return(tt.claimed, tt.errSETNX).Once()
+			if !tt.claimed && tt.errSETNX == nil {
+				repo.On("RedisGet", ctx, testKey).Return(tt.stored, tt.errGet).Once()
+			}
+
+			got, err := svc.Begin(ctx, "7:POST:/api/v1/books:abc", "fp")
+			if tt.wantCode != "" {
+				assert.Equal(t, tt.wantCode, apperr.CodeOf(err))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestComplete_And_Release(t *testing.T) {
+	ctx := context.Background()
+	repo, svc := setup(t, "open")
+	repo.On("RedisSet", ctx, testKey, mock.MatchedBy(func(val []byte) bool {
+		return string(val) == `{"fingerprint":"fp","done":true,"status":201,"content_type":"application/json","body":"e30="}`
+	}), 24*time.Hour).Return(nil).Once()
+	repo.On("RedisDel", ctx, testKey).Return(nil).Once()
+
+	assert.NoError(t, svc.Complete(ctx, "7:POST:/api/v1/books:abc", "fp", dto.StoredResponse{Status: 201, ContentType: "application/json", Body: []byte("{}")}))
+	assert.NoError(t, svc.Release(ctx, "7:POST:/api/v1/books:abc"))
+}
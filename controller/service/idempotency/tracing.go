@@ -0,0 +1,32 @@
+package service
+
+import (
+	"context"
+
+	"stmnplibrary/domain/interface/service"
+	"stmnplibrary/dto"
+	"stmnplibrary/tracing"
+)
+
+// tracedIdempotencyService wraps every IdempotencyService method in a span, failed calls are recorded on it.
+type tracedIdempotencyService struct {
+	next service.IdempotencyService
+}
+
+func (t *tracedIdempotencyService) Begin(ctx context.Context, key string, fingerprint string) (result *dto.StoredResponse, err error) {
+	ctx, span := tracing.Start(ctx, "IdempotencyService.Begin")
+	defer func() { tracing.End(span, err) }()
+	return t.next.Begin(ctx, key, fingerprint)
+}
+
+func (t *tracedIdempotencyService) Complete(ctx context.Context, key string, fingerprint string, res dto.StoredResponse) (err error) {
+	ctx, span := tracing.Start(ctx, "IdempotencyService.Complete")
+	defer func() { tracing.End(span, err) }()
+	return t.next.Complete(ctx, key, fingerprint, res)
+}
+
+func (t *tracedIdempotencyService) Release(ctx context.Context, key string) (err error) {
+	ctx, span := tracing.Start(ctx, "IdempotencyService.Release")
+	defer func() { tracing.End(span, err) }()
+	return t.next.Release(ctx, key)
+}
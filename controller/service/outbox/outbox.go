@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"stmnplibrary/apperr"
+	"stmnplibrary/config"
+	"stmnplibrary/domain/entity"
+	"stmnplibrary/domain/interface/repository"
+	"stmnplibrary/domain/interface/service"
+	"stmnplibrary/metrics"
+	"stmnplibrary/worker"
+	"strconv"
+	"time"
+)
+
+type outboxService struct {
+	outboxRepository repository.OutboxRepository
+	cfg              config.Outbox
+	now              func() time.Time
+}
+
+func FnOutboxService(repository repository.OutboxRepository, cfg config.Outbox) service.OutboxService {
+	return &tracedOutboxService{
+		next: newOutboxService(repository, cfg),
+	}
+}
+
+func newOutboxService(repository repository.OutboxRepository, cfg config.Outbox) *outboxService {
+	return &outboxService{
+		outboxRepository: repository,
+		cfg:              cfg,
+		now:              time.Now,
+	}
+}
+
+func values(event entity.Outbox) map[string]any {
+	return map[string]any{
+		"id":           strconv.Itoa(event.ID),
+		"type":         event.EventType,
+		"aggregate":    event.Aggregate,
+		"aggregate_id": event.AggregateID,
+		"payload":      event.Payload,
+		"trace_id":     event.TraceID,
+		"created_at":   event.CreatedAt.Format(time.RFC3339Nano),
+	}
+}
+
+// Relay publishes one batch of due events to the stream and returns how many
+// events it took. Rows stay locked until the batch commits, so an event is
+// published at least once: a crash after XADD publishes it again. A failed
+// event is retried with backoff and moved to the dead letter stream after
+// MaxAttempts, while redis is unavailable the batch stops without counting
+// an attempt.
+func (obs *outboxService) Relay(ctx context.Context) (int, error) {
+	var (
+		taken    int
+		relayErr error
+	)
+	err := obs.outboxRepository.WithTx(ctx, func(ctx context.Context) error {
+		events, err := obs.outboxRepository.GetPending(ctx, obs.cfg.BatchSize)
+		if err != nil {
+			return err
+		}
+		var published = make([]int, 0, len(events))
+		for _, event := range events {
+			err := obs.outboxRepository.XAdd(ctx, obs.cfg.Stream, obs.cfg.StreamMaxLen, values(event))
+			if err == nil {
+				published = append(published, event.ID)
+				metrics.OutboxEvents.WithLabelValues(event.EventType, "published").Inc()
+				continue
+			}
+			// redis being down is not the fault of the event, keep its attempts
+			if apperr.KindOf(err) == apperr.KindUnavailable {
+				relayErr = err
+				break
+			}
+			if err := obs.fail(ctx, event, err); err != nil {
+				return err
+			}
+		}
+		taken = len(events)
+		if len(published) > 0 {
+			return obs.outboxRepository.MarkPublished(ctx, published)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return taken, relayErr
+}
+
+// fail schedules the next attempt of event or, when it was the last one,
+// moves it to the dead letter stream.
+func (obs *outboxService) fail(ctx context.Context, event entity.Outbox, cause error) error {
+	var attempts = event.Attempts + 1
+	if attempts >= obs.cfg.MaxAttempts {
+		dead := values(event)
+		dead["attempts"] = strconv.Itoa(attempts)
+		dead["error"] = cause.Error()
+		if err := obs.outboxRepository.XAdd(ctx, obs.cfg.DeadLetterStream, obs.cfg.StreamMaxLen, dead); err == nil {
+			metrics.OutboxEvents.WithLabelValues(event.EventType, "dead").Inc()
+			return obs.outboxRepository.MarkDead(ctx, event.ID, cause.Error())
+		}
+	}
+	metrics.OutboxEvents.WithLabelValues(event.EventType, "retried").Inc()
+	return obs.outboxRepository.MarkRetry(ctx, event.ID, cause.Error(), obs.now().Add(worker.Backoff(obs.cfg.RetryBackoff, obs.cfg.RetryBackoffMax, attempts)))
+}
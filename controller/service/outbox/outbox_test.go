@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"stmnplibrary/apperr"
+	"stmnplibrary/config"
+	"stmnplibrary/domain/entity"
+	"stmnplibrary/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var testCfg = config.Outbox{
+	Stream:           "events",
+	DeadLetterStream: "events:dead",
+	StreamMaxLen:     1000,
+	BatchSize:        10,
+	MaxAttempts:      3,
+	RetryBackoff:     time.Second,
+	RetryBackoffMax:  time.Minute,
+}
+
+func setup(t *testing.T) (*mocks.OutboxRepository, *outboxService, time.Time) {
+	repo := mocks.NewOutboxRepository(t)
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	svc := newOutboxService(repo, testCfg)
+	svc.now = func() time.Time { return now }
+	repo.On("WithTx", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	}).Maybe()
+	return repo, svc, now
+}
+
+func TestRelay_Cases(t *testing.T) {
+	ctx := context.Background()
+	events := []entity.Outbox{
+		{ID: 1, EventType: "LoanCreated", Payload: `{"id_book":10}`},
+		{ID: 2, EventType: "LoanReturned", Attempts: 0},
+		{ID: 3, EventType: "BookAdded", Attempts: 2},
+	}
+	isEvent := func(id string) any {
+		return mock.MatchedBy(func(v map[string]any) bool { return v["id"] == id })
+	}
+
+	t.Run("Publish_Retry_And_Dead_Letter", func(t *testing.T) {
+		repo, svc, now := setup(t)
+		failed := errors.New("stream full")
+		repo.On("GetPending", ctx, 10).Return(events, nil).Once()
+		repo.On("XAdd", ctx, "events", int64(1000), isEvent("1")).Return(nil).Once()
+		repo.On("XAdd", ctx, "events", int64(1000), isEvent("2")).Return(apperr.Internal(failed)).Once()
+		repo.On("MarkRetry", ctx, 2, mock.Anything, now.Add(time.Second)).Return(nil).Once()
+		repo.On("XAdd", ctx, "events", int64(1000), isEvent("3")).Return(apperr.Internal(failed)).Once()
+		repo.On("XAdd", ctx, "events:dead", int64(1000), mock.MatchedBy(func(v map[string]any) bool {
+			return v["id"] == "3" && v["attempts"] == "3" && v["error"] != ""
+		})).Return(nil).Once()
+		repo.On("MarkDead", ctx, 3, mock.Anything).Return(nil).Once()
+		repo.On("MarkPublished", ctx, []int{1}).Return(nil).Once()
+
+		n, err := svc.Relay(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, n)
+	})
+
+	t.Run("Redis_Unavailable_Keeps_Attempts", func(t *testing.T) {
+		repo, svc, _ := setup(t)
+		repo.On("GetPending", ctx, 10).Return(events, nil).Once()
+		repo.On("XAdd", ctx, "events", int64(1000), isEvent("1")).Return(nil).Once()
+		repo.On("XAdd", ctx, "events", int64(1000), isEvent("2")).Return(apperr.Unavailable(errors.New("breaker open"))).Once()
+		repo.On("MarkPublished", ctx, []int{1}).Return(nil).Once()
+
+		_, err := svc.Relay(ctx)
+		assert.Equal(t, apperr.KindUnavailable, apperr.KindOf(err))
+	})
+
+	t.Run("Nothing_Pending", func(t *testing.T) {
+		repo, svc, _ := setup(t)
+		repo.On("GetPending", ctx, 10).Return(nil, nil).Once()
+
+		n, err := svc.Relay(ctx)
+		assert.NoError(t, err)
+		assert.Zero(t, n)
+	})
+}
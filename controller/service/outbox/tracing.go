@@ -0,0 +1,19 @@
+package service
+
+import (
+	"context"
+
+	"stmnplibrary/domain/interface/service"
+	"stmnplibrary/tracing"
+)
+
+// tracedOutboxService wraps every OutboxService method in a span, failed calls are recorded on it.
+type tracedOutboxService struct {
+	next service.OutboxService
+}
+
+func (t *tracedOutboxService) Relay(ctx context.Context) (result int, err error) {
+	ctx, span := tracing.Start(ctx, "OutboxService.Relay")
+	defer func() { tracing.End(span, err) }()
+	return t.next.Relay(ctx)
+}
@@ -0,0 +1,392 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"stmnplibrary/apperr"
+	"stmnplibrary/audit"
+	"stmnplibrary/clock"
+	"stmnplibrary/constanta"
+	"stmnplibrary/controller/service/utils"
+	"stmnplibrary/domain/entity"
+	"stmnplibrary/domain/interface/repository"
+	"stmnplibrary/domain/interface/service"
+	"stmnplibrary/dto"
+	"stmnplibrary/pagination"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var errNotOwner = apperr.New(apperr.KindForbidden, apperr.CodeForbidden, "only the teacher who created the reading list or an admin can change it")
+
+type readingListService struct {
+	readingListRepository repository.ReadingListRepository
+	auditRepository       repository.AuditRepository
+	clock                 clock.Clock
+}
+
+func FnReadingListService(repository repository.ReadingListRepository, auditRepository repository.AuditRepository, clock clock.Clock) service.ReadingListService {
+	return &tracedReadingListService{
+		next: newReadingListService(repository, auditRepository, clock),
+	}
+}
+
+func newReadingListService(repository repository.ReadingListRepository, auditRepository repository.AuditRepository, clock clock.Clock) *readingListService {
+	return &readingListService{
+		readingListRepository: repository,
+		auditRepository:       auditRepository,
+		clock:                 clock,
+	}
+}
+
+func (rs *readingListService) record(ctx context.Context, action string, entityName string, entityID string, before any, after any) error {
+	data, err := utils.NewAudit(ctx, action, entityName, entityID, before, after)
+	if err != nil {
+		return err
+	}
+	return rs.auditRepository.Record(ctx, data)
+}
+
+func errValidation(msg string) error {
+	return apperr.New(apperr.KindInvalid, apperr.CodeValidation, msg)
+}
+
+func readingListAudit(r entity.ReadingList) map[string]any {
+	var books = make([]int, 0, len(r.Books))
+	for _, b := range r.Books {
+		books = append(books, b.IdBook)
+	}
+	return map[string]any{
+		"title":       r.Title,
+		"description": r.Description,
+		"class":       r.Class,
+		"sub_class":   r.SubClass,
+		"major":       r.Major,
+		"starts_at":   r.StartsAt.Format(time.DateOnly),
+		"due_at":      r.DueAt.Format(time.DateOnly),
+		"books":       books,
+	}
+}
+
+// today is the current date of the library as stored in a DATE column.
+func (rs *readingListService) today() time.Time {
+	today, _ := time.Parse(time.DateOnly, rs.clock.Now().In(rs.clock.Location()).Format(time.DateOnly))
+	return today
+}
+
+// fill copies the request into the list, starts_at is kept when it is not
+// sent and defaults to today on a new list.
+func (rs *readingListService) fill(ctx context.Context, list *entity.ReadingList, data dto.SaveReadingList) error {
+	if data.Class == "XIII" && data.Major != "" && data.Major != "IOP" && data.Major != "SIJA" {
+		return errValidation("class XIII is only available for IOP/SIJA")
+	}
+	if data.StartsAt != "" {
+		list.StartsAt, _ = time.Parse(time.DateOnly, data.StartsAt)
+	} else if list.StartsAt.IsZero() {
+		list.StartsAt = rs.today()
+	}
+	list.DueAt, _ = time.Parse(time.DateOnly, data.DueAt)
+	if list.DueAt.Before(list.StartsAt) {
+		return errValidation("due_at must not be before starts_at")
+	}
+	count, err := rs.readingListRepository.CountBooks(ctx, data.Books)
+	if err != nil {
+		return err
+	}
+	if count != int64(len(data.Books)) {
+		return apperr.ErrReferenceNotFound
+	}
+	list.Title = strings.TrimSpace(data.Title)
+	list.Description = strings.TrimSpace(data.Description)
+	list.Class, list.SubClass, list.Major = data.Class, data.SubClass, data.Major
+	list.Books = make([]entity.ReadingListBook, 0, len(data.Books))
+	for i, id := range data.Books {
+		list.Books = append(list.Books, entity.ReadingListBook{IdList: list.ID, IdBook: id, Position: i + 1})
+	}
+	return nil
+}
+
+// owner loads a list the logged in teacher may change, a teacher can only
+// change the lists they created while an admin can change any of them.
+func (rs *readingListService) owner(ctx context.Context, id int) (entity.ReadingList, error) {
+	idUser, ok := ctx.Value(constanta.UI).(int)
+	if !ok {
+		return entity.ReadingList{}, apperr.ErrLoginRequired
+	}
+	list, err := rs.readingListRepository.GetReadingList(ctx, id)
+	if err != nil {
+		return entity.ReadingList{}, err
+	}
+	if role, _ := ctx.Value(string(constanta.RL)).(string); role != "admin" && list.CreatedBy != idUser {
+		return entity.ReadingList{}, errNotOwner
+	}
+	return list, nil
+}
+
+// AddReadingList creates a reading list owned by the logged in teacher or
+// admin, the books are read in the order they are sent.
+func (rs *readingListService) AddReadingList(ctx context.Context, data dto.SaveReadingList) (*dto.ReadingList, error) {
+	const errMsg = "service - add_reading_list: %w"
+	idUser, ok := ctx.Value(constanta.UI).(int)
+	if !ok {
+		return nil, apperr.ErrLoginRequired
+	}
+	var list = entity.ReadingList{CreatedBy: idUser}
+	err := rs.readingListRepository.WithTx(ctx, func(ctx context.Context) error {
+		if err := rs.fill(ctx, &list, data); err != nil {
+			return err
+		}
+		if err := rs.readingListRepository.AddReadingList(ctx, &list); err != nil {
+			return err
+		}
+		if err := rs.readingListRepository.SetReadingListBooks(ctx, list.ID, list.Books); err != nil {
+			return err
+		}
+		if err := rs.record(ctx, audit.ActionCreate, "reading_list", strconv.Itoa(list.ID), nil, readingListAudit(list)); err != nil {
+			return err
+		}
+		var err error
+		list, err = rs.readingListRepository.GetReadingList(ctx, list.ID)
+		return err
+	})
+	if err != nil {
+		return nil, utils.ValidateErrTw(err, errMsg)
+	}
+	result := utils.ReadingListMapper(list)
+	return &result, nil
+}
+
+// UpdateReadingList replaces a reading list and its books, loans already
+// made keep counting as long as they were made from the new starts_at on.
+func (rs *readingListService) UpdateReadingList(ctx context.Context, id int, data dto.SaveReadingList) (*dto.ReadingList, error) {
+	const errMsg = "service - update_reading_list: %w"
+	var list entity.ReadingList
+	err := rs.readingListRepository.WithTx(ctx, func(ctx context.Context) error {
+		before, err := rs.owner(ctx, id)
+		if err != nil {
+			return err
+		}
+		list = before
+		if err := rs.fill(ctx, &list, data); err != nil {
+			return err
+		}
+		if err := rs.readingListRepository.UpdateReadingList(ctx, &list); err != nil {
+			return err
+		}
+		if err := rs.readingListRepository.SetReadingListBooks(ctx, id, list.Books); err != nil {
+			return err
+		}
+		if err := rs.record(ctx, audit.ActionUpdate, "reading_list", strconv.Itoa(id), readingListAudit(before), readingListAudit(list)); err != nil {
+			return err
+		}
+		list, err = rs.readingListRepository.GetReadingList(ctx, id)
+		return err
+	})
+	if err != nil {
+		return nil, utils.ValidateErrTw(err, errMsg)
+	}
+	result := utils.ReadingListMapper(list)
+	return &result, nil
+}
+
+func (rs *readingListService) DeleteReadingList(ctx context.Context, id int) error {
+	const errMsg = "service - delete_reading_list: %w"
+	err := rs.readingListRepository.WithTx(ctx, func(ctx context.Context) error {
+		before, err := rs.owner(ctx, id)
+		if err != nil {
+			return err
+		}
+		if _, err := rs.readingListRepository.DeleteReadingList(ctx, id); err != nil {
+			return err
+		}
+		return rs.record(ctx, audit.ActionDelete, "reading_list", strconv.Itoa(id), readingListAudit(before), nil)
+	})
+	if err != nil {
+		return utils.ValidateErrTw(err, errMsg)
+	}
+	return nil
+}
+
+func (rs *readingListService) GetReadingList(ctx context.Context, id int) (*dto.ReadingList, error) {
+	const errMsg = "service - get_reading_list: %w"
+	list, err := rs.readingListRepository.GetReadingList(ctx, id)
+	if err != nil {
+		return nil, utils.ValidateErrTw(err, errMsg)
+	}
+	result := utils.ReadingListMapper(list)
+	return &result, nil
+}
+
+// GetReadingLists lists the reading lists of every class, newest first.
+func (rs *readingListService) GetReadingLists(ctx context.Context, filter dto.ReadingListFilter) ([]dto.ReadingList, *dto.Meta, error) {
+	const errMsg = "service - get_reading_lists: %w"
+	page, err := pagination.New(filter.Cursor, filter.PageSize, pagination.SortNewest)
+	if err != nil {
+		return nil, nil, err
+	}
+	var query = entity.ReadingListFilter{Class: filter.Class, Major: filter.Major}
+	if filter.Mine {
+		idUser, ok := ctx.Value(constanta.UI).(int)
+		if !ok {
+			return nil, nil, apperr.ErrLoginRequired
+		}
+		query.CreatedBy = idUser
+	}
+	data, total, err := rs.readingListRepository.GetReadingLists(ctx, query, page)
+	if err != nil {
+		return nil, nil, utils.ValidateErrTw(err, errMsg)
+	}
+	data, next := pagination.Trim(data, page, utils.ReadingListKey)
+	return utils.ReadingListsMapper(data), utils.NewMeta(page, next, total), nil
+}
+
+// progress groups the loans of a reading list by student then by book, a
+// book without a return is still borrowed.
+func progress(rows []entity.ReadingProgress) map[int]map[int]*time.Time {
+	var users = make(map[int]map[int]*time.Time)
+	for _, r := range rows {
+		if users[r.IdUser] == nil {
+			users[r.IdUser] = make(map[int]*time.Time)
+		}
+		users[r.IdUser][r.IdBook] = r.ReturnedAt
+	}
+	return users
+}
+
+// reading is how far a student got with a list. The list is completed when
+// every book was returned, completedAt being the last of those returns, and
+// late when it was not completed by the end of due_at.
+func (rs *readingListService) reading(list entity.ReadingList, loans map[int]*time.Time) ([]dto.ReadingBook, dto.ReadingProgress, *time.Time) {
+	var (
+		books       = make([]dto.ReadingBook, 0, len(list.Books))
+		result      = dto.ReadingProgress{Total: len(list.Books)}
+		completedAt *time.Time
+	)
+	for _, b := range list.Books {
+		var book = dto.ReadingBook{
+			ReadingListBook: dto.ReadingListBook{ID: b.IdBook, Position: b.Position, Name: b.Name, Author: b.Author},
+			Status:          entity.ReadingTodo,
+		}
+		if returnedAt, ok := loans[b.IdBook]; ok {
+			book.Status = entity.ReadingBorrowed
+			if returnedAt != nil {
+				book.Status, book.ReturnedAt = entity.ReadingRead, returnedAt
+				result.Read++
+				if completedAt == nil || returnedAt.After(*completedAt) {
+					completedAt = returnedAt
+				}
+			}
+		}
+		books = append(books, book)
+	}
+	if result.Total > 0 {
+		result.Percent = result.Read * 100 / result.Total
+	}
+	result.Completed = result.Read == result.Total
+	if !result.Completed {
+		completedAt = nil
+	}
+	var due = list.DueAt.Format(time.DateOnly)
+	if completedAt != nil {
+		result.Overdue = completedAt.In(rs.clock.Location()).Format(time.DateOnly) > due
+	} else {
+		result.Overdue = rs.today().Format(time.DateOnly) > due
+	}
+	return books, result, completedAt
+}
+
+// GetReport is the completion report of a reading list, the students it is
+// assigned to are grouped per class like "XI RPL A".
+func (rs *readingListService) GetReport(ctx context.Context, id int) (*dto.ReadingListReport, error) {
+	const errMsg = "service - get_reading_list_report: %w"
+	list, err := rs.readingListRepository.GetReadingList(ctx, id)
+	if err != nil {
+		return nil, utils.ValidateErrTw(err, errMsg)
+	}
+	students, err := rs.readingListRepository.GetStudents(ctx, list)
+	if err != nil {
+		return nil, utils.ValidateErrTw(err, errMsg)
+	}
+	var ids = make([]int, 0, len(students))
+	for _, s := range students {
+		ids = append(ids, s.ID)
+	}
+	rows, err := rs.readingListRepository.GetProgress(ctx, list, ids)
+	if err != nil {
+		return nil, utils.ValidateErrTw(err, errMsg)
+	}
+	var (
+		loans  = progress(rows)
+		report = dto.ReadingListReport{ReadingList: utils.ReadingListMapper(list), Total: len(students), Classes: []dto.ClassReading{}}
+		index  = make(map[string]int)
+	)
+	for _, s := range students {
+		var name = fmt.Sprintf("%s %s %s", s.Class, s.Major, s.SubClass)
+		i, ok := index[name]
+		if !ok {
+			i = len(report.Classes)
+			index[name] = i
+			report.Classes = append(report.Classes, dto.ClassReading{Class: name})
+		}
+		_, result, completedAt := rs.reading(list, loans[s.ID])
+		class := &report.Classes[i]
+		class.TotalStudents++
+		class.AveragePercent += result.Percent
+		if result.Completed {
+			class.Completed++
+			report.Completed++
+		}
+		class.Students = append(class.Students, dto.StudentReading{
+			ID:          s.ID,
+			NIS:         s.NIS,
+			Name:        s.Name,
+			Read:        result.Read,
+			Percent:     result.Percent,
+			Completed:   result.Completed,
+			CompletedAt: completedAt,
+			Late:        result.Overdue,
+		})
+	}
+	for i := range report.Classes {
+		report.Classes[i].AveragePercent /= report.Classes[i].TotalStudents
+	}
+	return &report, nil
+}
+
+// GetStudentReadingLists lists the reading lists assigned to the class, sub
+// class and major of the logged in student with their progress, the closest
+// due first.
+func (rs *readingListService) GetStudentReadingLists(ctx context.Context) ([]dto.StudentReadingList, error) {
+	const errMsg = "service - get_student_reading_lists: %w"
+	idUser, ok := ctx.Value(constanta.UI).(int)
+	if !ok {
+		return nil, apperr.ErrLoginRequired
+	}
+	student, err := rs.readingListRepository.GetStudent(ctx, idUser)
+	if err != nil {
+		return nil, utils.ValidateErrTw(err, errMsg)
+	}
+	lists, err := rs.readingListRepository.GetStudentReadingLists(ctx, student)
+	if err != nil {
+		return nil, utils.ValidateErrTw(err, errMsg)
+	}
+	var result = make([]dto.StudentReadingList, 0, len(lists))
+	for _, list := range lists {
+		rows, err := rs.readingListRepository.GetProgress(ctx, list, []int{idUser})
+		if err != nil {
+			return nil, utils.ValidateErrTw(err, errMsg)
+		}
+		books, state, _ := rs.reading(list, progress(rows)[idUser])
+		result = append(result, dto.StudentReadingList{
+			ID:          list.ID,
+			Title:       list.Title,
+			Description: list.Description,
+			StartsAt:    list.StartsAt.Format(time.DateOnly),
+			DueAt:       list.DueAt.Format(time.DateOnly),
+			Books:       books,
+			Progress:    state,
+		})
+	}
+	return result, nil
+}
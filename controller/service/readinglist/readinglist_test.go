@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"stmnplibrary/apperr"
+	"stmnplibrary/clock"
+	"stmnplibrary/constanta"
+	"stmnplibrary/domain/entity"
+	"stmnplibrary/dto"
+	"stmnplibrary/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	jakarta = time.FixedZone("WIB", 7*3600)
+	now     = time.Date(2026, 10, 19, 23, 30, 0, 0, jakarta)
+)
+
+func setup(t *testing.T) (*mocks.ReadingListRepository, *mocks.AuditRepository, *readingListService) {
+	repo := mocks.NewReadingListRepository(t)
+	auditRepo := mocks.NewAuditRepository(t)
+	svc := newReadingListService(repo, auditRepo, clock.NewFake(now))
+	repo.On("WithTx", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	}).Maybe()
+	return repo, auditRepo, svc
+}
+
+func staff(id int, role string) context.Context {
+	ctx := context.WithValue(context.Background(), constanta.UI, id)
+	return context.WithValue(ctx, string(constanta.RL), role)
+}
+
+func date(s string) time.Time {
+	t, _ := time.Parse(time.DateOnly, s)
+	return t
+}
+
+func TestAddReadingList_Cases(t *testing.T) {
+	ctx := staff(2, "teacher")
+	data := dto.SaveReadingList{Title: " Sastra ", Class: "XI", Major: "RPL", DueAt: "2026-12-18", Books: []int{8, 3}}
+
+	t.Run("Success", func(t *testing.T) {
+		repo, auditRepo, svc := setup(t)
+		repo.On("CountBooks", ctx, []int{8, 3}).Return(int64(2), nil).Once()
+		repo.On("AddReadingList", ctx, mock.MatchedBy(func(l *entity.ReadingList) bool {
+			return l.Title == "Sastra" && l.CreatedBy == 2 && l.StartsAt.Equal(date("2026-10-19"))
+		})).Run(func(args mock.Arguments) {
+			args.Get(1).(*entity.ReadingList).ID = 5
+		}).Return(nil).Once()
+		repo.On("SetReadingListBooks", ctx, 5, []entity.ReadingListBook{{IdBook: 8, Position: 1}, {IdBook: 3, Position: 2}}).Return(nil).Once()
+		auditRepo.On("Record", ctx, mock.MatchedBy(func(a entity.Audit) bool {
+			return a.Entity == "reading_list" && a.Action == "create" && a.EntityID == "5"
+		})).Return(nil).Once()
+		repo.On("GetReadingList", ctx, 5).Return(entity.ReadingList{ID: 5, Class: "XI", StartsAt: date("2026-10-19"), DueAt: date("2026-12-18")}, nil).Once()
+
+		result, err := svc.AddReadingList(ctx, data)
+		require.NoError(t, err)
+		assert.Equal(t, 5, result.ID)
+		assert.Equal(t, "2026-10-19", result.StartsAt)
+	})
+
+	t.Run("Due_Before_Start", func(t *testing.T) {
+		_, _, svc := setup(t)
+		bad := data
+		bad.StartsAt = "2027-01-01"
+
+		_, err := svc.AddReadingList(ctx, bad)
+		assert.Equal(t, apperr.KindInvalid, apperr.KindOf(err))
+	})
+
+	t.Run("Class_XIII_Major", func(t *testing.T) {
+		_, _, svc := setup(t)
+		bad := data
+		bad.Class = "XIII"
+
+		_, err := svc.AddReadingList(ctx, bad)
+		assert.Equal(t, apperr.CodeValidation, apperr.CodeOf(err))
+	})
+
+	t.Run("Unknown_Book", func(t *testing.T) {
+		repo, _, svc := setup(t)
+		repo.On("CountBooks", ctx, []int{8, 3}).Return(int64(1), nil).Once()
+
+		_, err := svc.AddReadingList(ctx, data)
+		assert.Equal(t, apperr.CodeReferenceNotFound, apperr.CodeOf(err))
+	})
+}
+
+func TestUpdateReadingList_Owner(t *testing.T) {
+	data := dto.SaveReadingList{Title: "Sastra", Class: "XI", DueAt: "2026-12-18", Books: []int{3}}
+	list := entity.ReadingList{ID: 5, Title: "Lama", Class: "XI", CreatedBy: 2, StartsAt: date("2026-07-13"), DueAt: date("2026-12-01")}
+
+	t.Run("Other_Teacher", func(t *testing.T) {
+		ctx := staff(9, "teacher")
+		repo, _, svc := setup(t)
+		repo.On("GetReadingList", ctx, 5).Return(list, nil).Once()
+
+		_, err := svc.UpdateReadingList(ctx, 5, data)
+		assert.Equal(t, apperr.KindForbidden, apperr.KindOf(err))
+	})
+
+	t.Run("Admin_Keeps_Start", func(t *testing.T) {
+		ctx := staff(1, "admin")
+		repo, auditRepo, svc := setup(t)
+		repo.On("GetReadingList", ctx, 5).Return(list, nil).Once()
+		repo.On("CountBooks", ctx, []int{3}).Return(int64(1), nil).Once()
+		repo.On("UpdateReadingList", ctx, mock.MatchedBy(func(l *entity.ReadingList) bool {
+			return l.Title == "Sastra" && l.CreatedBy == 2 && l.StartsAt.Equal(date("2026-07-13"))
+		})).Return(nil).Once()
+		repo.On("SetReadingListBooks", ctx, 5, mock.Anything).Return(nil).Once()
+		auditRepo.On("Record", ctx, mock.MatchedBy(func(a entity.Audit) bool {
+			return a.Action == "update" && a.ActorRole == "admin"
+		})).Return(nil).Once()
+		repo.On("GetReadingList", ctx, 5).Return(list, nil).Once()
+
+		_, err := svc.UpdateReadingList(ctx, 5, data)
+		require.NoError(t, err)
+	})
+}
+
+func TestDeleteReadingList_Other_Teacher(t *testing.T) {
+	ctx := staff(9, "teacher")
+	repo, _, svc := setup(t)
+	repo.On("GetReadingList", ctx, 5).Return(entity.ReadingList{ID: 5, CreatedBy: 2}, nil).Once()
+
+	err := svc.DeleteReadingList(ctx, 5)
+	assert.Equal(t, apperr.KindForbidden, apperr.KindOf(err))
+}
+
+func TestGetReport(t *testing.T) {
+	ctx := staff(2, "teacher")
+	repo, _, svc := setup(t)
+	list := entity.ReadingList{ID: 5, Class: "XI", StartsAt: date("2026-07-13"), DueAt: date("2026-10-01"), Books: []entity.ReadingListBook{{IdBook: 3, Position: 1}, {IdBook: 8, Position: 2}}}
+	students := []entity.StudentData{
+		{ID: 10, NIS: 20010, Class: "XI", Major: "RPL", SubClass: "A"},
+		{ID: 11, NIS: 20011, Class: "XI", Major: "RPL", SubClass: "A"},
+		{ID: 12, NIS: 20012, Class: "XI", Major: "TEI", SubClass: "B"},
+	}
+	onTime := time.Date(2026, 9, 30, 10, 0, 0, 0, time.UTC)
+	// returned on the 1st in UTC but already the 2nd in the library timezone
+	late := time.Date(2026, 10, 1, 20, 0, 0, 0, time.UTC)
+	repo.On("GetReadingList", ctx, 5).Return(list, nil).Once()
+	repo.On("GetStudents", ctx, list).Return(students, nil).Once()
+	repo.On("GetProgress", ctx, list, []int{10, 11, 12}).Return([]entity.ReadingProgress{
+		{IdUser: 10, IdBook: 3, ReturnedAt: &onTime},
+		{IdUser: 10, IdBook: 8, ReturnedAt: &onTime},
+		{IdUser: 11, IdBook: 3, ReturnedAt: &onTime},
+		{IdUser: 12, IdBook: 3, ReturnedAt: &onTime},
+		{IdUser: 12, IdBook: 8, ReturnedAt: &late},
+	}, nil).Once()
+
+	report, err := svc.GetReport(ctx, 5)
+	require.NoError(t, err)
+	assert.Equal(t, 3, report.Total)
+	assert.Equal(t, 2, report.Completed)
+	require.Len(t, report.Classes, 2)
+
+	rpl := report.Classes[0]
+	assert.Equal(t, "XI RPL A", rpl.Class)
+	assert.Equal(t, 2, rpl.TotalStudents)
+	assert.Equal(t, 1, rpl.Completed)
+	assert.Equal(t, 75, rpl.AveragePercent)
+	assert.False(t, rpl.Students[0].Late)
+	assert.Equal(t, &onTime, rpl.Students[0].CompletedAt)
+	assert.True(t, rpl.Students[1].Late)
+	assert.Nil(t, rpl.Students[1].CompletedAt)
+
+	tei := report.Classes[1]
+	assert.Equal(t, "XI TEI B", tei.Class)
+	assert.True(t, tei.Students[0].Completed)
+	assert.True(t, tei.Students[0].Late)
+}
+
+func TestGetStudentReadingLists(t *testing.T) {
+	ctx := context.WithValue(context.Background(), constanta.UI, 10)
+	repo, _, svc := setup(t)
+	student := entity.StudentData{ID: 10, Class: "XI", Major: "RPL", SubClass: "A"}
+	list := entity.ReadingList{ID: 5, Class: "XI", StartsAt: date("2026-07-13"), DueAt: date("2026-12-18"), Books: []entity.ReadingListBook{
+		{IdBook: 3, Position: 1}, {IdBook: 8, Position: 2}, {IdBook: 12, Position: 3},
+	}}
+	returned := time.Date(2026, 9, 30, 10, 0, 0, 0, time.UTC)
+	repo.On("GetStudent", ctx, 10).Return(student, nil).Once()
+	repo.On("GetStudentReadingLists", ctx, student).Return([]entity.ReadingList{list}, nil).Once()
+	repo.On("GetProgress", ctx, list, []int{10}).Return([]entity.ReadingProgress{
+		{IdUser: 10, IdBook: 3, ReturnedAt: &returned},
+		{IdUser: 10, IdBook: 8},
+	}, nil).Once()
+
+	lists, err := svc.GetStudentReadingLists(ctx)
+	require.NoError(t, err)
+	require.Len(t, lists, 1)
+	books := lists[0].Books
+	assert.Equal(t, entity.ReadingRead, books[0].Status)
+	assert.Equal(t, entity.ReadingBorrowed, books[1].Status)
+	assert.Equal(t, entity.ReadingTodo, books[2].Status)
+	assert.Equal(t, dto.ReadingProgress{Read: 1, Total: 3, Percent: 33}, lists[0].Progress)
+}
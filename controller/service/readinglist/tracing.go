@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+
+	"stmnplibrary/domain/interface/service"
+	"stmnplibrary/dto"
+	"stmnplibrary/tracing"
+)
+
+// tracedReadingListService wraps every ReadingListService method in a span, failed calls are recorded on it.
+type tracedReadingListService struct {
+	next service.ReadingListService
+}
+
+func (t *tracedReadingListService) AddReadingList(ctx context.Context, data dto.SaveReadingList) (result *dto.ReadingList, err error) {
+	ctx, span := tracing.Start(ctx, "ReadingListService.AddReadingList")
+	defer func() { tracing.End(span, err) }()
+	return t.next.AddReadingList(ctx, data)
+}
+
+func (t *tracedReadingListService) UpdateReadingList(ctx context.Context, id int, data dto.SaveReadingList) (result *dto.ReadingList, err error) {
+	ctx, span := tracing.Start(ctx, "ReadingListService.UpdateReadingList")
+	defer func() { tracing.End(span, err) }()
+	return t.next.UpdateReadingList(ctx, id, data)
+}
+
+func (t *tracedReadingListService) DeleteReadingList(ctx context.Context, id int) (err error) {
+	ctx, span := tracing.Start(ctx, "ReadingListService.DeleteReadingList")
+	defer func() { tracing.End(span, err) }()
+	return t.next.DeleteReadingList(ctx, id)
+}
+
+func (t *tracedReadingListService) GetReadingList(ctx context.Context, id int) (result *dto.ReadingList, err error) {
+	ctx, span := tracing.Start(ctx, "ReadingListService.GetReadingList")
+	defer func() { tracing.End(span, err) }()
+	return t.next.GetReadingList(ctx, id)
+}
+
+func (t *tracedReadingListService) GetReadingLists(ctx context.Context, filter dto.ReadingListFilter) (result []dto.ReadingList, meta *dto.Meta, err error) {
+	ctx, span := tracing.Start(ctx, "ReadingListService.GetReadingLists")
+	defer func() { tracing.End(span, err) }()
+	return t.next.GetReadingLists(ctx, filter)
+}
+
+func (t *tracedReadingListService) GetReport(ctx context.Context, id int) (result *dto.ReadingListReport, err error) {
+	ctx, span := tracing.Start(ctx, "ReadingListService.GetReport")
+	defer func() { tracing.End(span, err) }()
+	return t.next.GetReport(ctx, id)
+}
+
+func (t *tracedReadingListService) GetStudentReadingLists(ctx context.Context) (result []dto.StudentReadingList, err error) {
+	ctx, span := tracing.Start(ctx, "ReadingListService.GetStudentReadingLists")
+	defer func() { tracing.End(span, err) }()
+	return t.next.GetStudentReadingLists(ctx)
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"stmnplibrary/apperr"
+	"stmnplibrary/clock"
+	"stmnplibrary/config"
+	"stmnplibrary/constanta"
+	"stmnplibrary/controller/service/utils"
+	"stmnplibrary/domain/entity"
+	"stmnplibrary/domain/interface/repository"
+	"stmnplibrary/domain/interface/service"
+	"stmnplibrary/dto"
+	"stmnplibrary/log"
+	"stmnplibrary/metrics"
+	"sync/atomic"
+)
+
+const keyRecommendations = "stmnplibrary:recommendations:%d"
+
+type recommendationService struct {
+	recommendationRepository repository.RecommendationRepository
+	cfg                      config.Recommendation
+	clock                    clock.Clock
+	// after is the last student of the run in progress, 0 between runs
+	after atomic.Int64
+}
+
+func FnRecommendationService(repository repository.RecommendationRepository, cfg config.Recommendation, clock clock.Clock) service.RecommendationService {
+	return &tracedRecommendationService{
+		next: newRecommendationService(repository, cfg, clock),
+	}
+}
+
+func newRecommendationService(repository repository.RecommendationRepository, cfg config.Recommendation, clock clock.Clock) *recommendationService {
+	return &recommendationService{
+		recommendationRepository: repository,
+		cfg:                      cfg,
+		clock:                    clock,
+	}
+}
+
+func (rs *recommendationService) compute(ctx context.Context, student entity.StudentData) (*dto.Recommendations, error) {
+	var now = rs.clock.Now()
+	candidates, err := rs.recommendationRepository.GetCandidates(ctx, student, now.Add(-rs.cfg.Window), rs.cfg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.Recommendations{
+		Books:      utils.RecommendationsMapper(candidates),
+		ComputedAt: now,
+	}, nil
+}
+
+func (rs *recommendationService) store(ctx context.Context, idUser int, data *dto.Recommendations) error {
+	val, err := utils.Marshal(data)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	return rs.recommendationRepository.RedisSet(ctx, fmt.Sprintf(keyRecommendations, idUser), val, rs.cfg.TTL)
+}
+
+// GetRecommendations serves the recommendations of the logged in student
+// from redis. A student the job hasn't reached yet gets them computed right
+// away, while redis fails they are computed on every request.
+func (rs *recommendationService) GetRecommendations(ctx context.Context) (*dto.Recommendations, error) {
+	const errMsg = "service - get_recommendations: %w"
+	idUser, ok := ctx.Value(constanta.UI).(int)
+	if !ok {
+		return nil, apperr.ErrLoginRequired
+	}
+	val, err := rs.recommendationRepository.RedisGet(ctx, fmt.Sprintf(keyRecommendations, idUser))
+	if err == nil {
+		var cached dto.Recommendations
+		if utils.UnMarshal(val, &cached) == nil {
+			metrics.CacheResult("recommendation", true)
+			return &cached, nil
+		}
+	}
+	metrics.CacheResult("recommendation", false)
+	var degraded = err != nil && !errors.Is(err, apperr.ErrNotFound)
+	if degraded {
+		metrics.Degraded.WithLabelValues("recommendation_cache", "open").Inc()
+		log.LogDegraded(ctx, "recommendation_cache", "open", err)
+	}
+	student, err := rs.recommendationRepository.GetStudent(ctx, idUser)
+	if err != nil {
+		return nil, utils.ValidateErrTw(err, errMsg)
+	}
+	result, err := rs.compute(ctx, student)
+	if err != nil {
+		return nil, utils.ValidateErrTw(err, errMsg)
+	}
+	if !degraded {
+		rs.store(ctx, idUser, result)
+	}
+	return result, nil
+}
+
+// Refresh computes the recommendations of the next batch of students. A run
+// goes through every active student, a failed batch is retried by the next
+// call and a short batch ends the run.
+func (rs *recommendationService) Refresh(ctx context.Context) (int, error) {
+	const errMsg = "service - refresh_recommendations: %w"
+	students, err := rs.recommendationRepository.GetStudents(ctx, int(rs.after.Load()), rs.cfg.BatchSize)
+	if err != nil {
+		return 0, utils.ValidateErrTw(err, errMsg)
+	}
+	for _, s := range students {
+		result, err := rs.compute(ctx, s)
+		if err != nil {
+			return 0, utils.ValidateErrTw(err, errMsg)
+		}
+		if err := rs.store(ctx, s.ID, result); err != nil {
+			return 0, utils.ValidateErrTw(err, errMsg)
+		}
+		rs.after.Store(int64(s.ID))
+	}
+	if len(students) < rs.cfg.BatchSize {
+		rs.after.Store(0)
+	}
+	return len(students), nil
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"stmnplibrary/apperr"
+	"stmnplibrary/clock"
+	"stmnplibrary/config"
+	"stmnplibrary/constanta"
+	"stmnplibrary/domain/entity"
+	"stmnplibrary/dto"
+	"stmnplibrary/log"
+	"stmnplibrary/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+var testNow = time.Date(2026, 5, 4, 9, 0, 0, 0, time.UTC)
+
+var testCfg = config.Recommendation{Interval: time.Hour, BatchSize: 2, Limit: 20, TTL: 3 * time.Hour, Window: 24 * time.Hour}
+
+func setup(t *testing.T) (*mocks.RecommendationRepository, *recommendationService) {
+	repo := mocks.NewRecommendationRepository(t)
+	svc := newRecommendationService(repo, testCfg, clock.NewFake(testNow))
+	return repo, svc
+}
+
+var student = entity.StudentData{ID: 7, Class: "XI", Major: "RPL"}
+
+var candidates = []entity.Candidate{
+	{ID: 3, Name: "Laskar Pelangi", CoBorrowed: 2, Popular: 1},
+	{ID: 5, Name: "Bumi", SharedCategories: 1},
+}
+
+func TestGetRecommendations_Cases(t *testing.T) {
+	ctx := context.WithValue(context.Background(), constanta.UI, 7)
+
+	t.Run("Cached", func(t *testing.T) {
+		repo, svc := setup(t)
+		repo.On("RedisGet", ctx, "stmnplibrary:recommendations:7").Return([]byte(`{"books":[{"book_id":3,"score":7}],"computed_at":"2026-05-04T08:00:00Z"}`), nil).Once()
+
+		result, err := svc.GetRecommendations(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 3, result.Books[0].ID)
+	})
+
+	t.Run("Not_Computed_Yet", func(t *testing.T) {
+		repo, svc := setup(t)
+		repo.On("RedisGet", ctx, "stmnplibrary:recommendations:7").Return(nil, apperr.ErrNotFound).Once()
+		repo.On("GetStudent", ctx, 7).Return(student, nil).Once()
+		repo.On("GetCandidates", ctx, student, testNow.Add(-24*time.Hour), 20).Return(candidates, nil).Once()
+		repo.On("RedisSet", ctx, "stmnplibrary:recommendations:7", mock.Anything, 3*time.Hour).Return(nil).Once()
+
+		result, err := svc.GetRecommendations(ctx)
+		require.NoError(t, err)
+		require.Len(t, result.Books, 2)
+		assert.Equal(t, dto.Recommendation{ID: 3, Name: "Laskar Pelangi", Score: 7, Reasons: []string{entity.ReasonCoBorrowed, entity.ReasonPopular}}, result.Books[0])
+		assert.Equal(t, testNow, result.ComputedAt)
+	})
+
+	t.Run("Redis_Down", func(t *testing.T) {
+		log.LogInit(zap.NewNop())
+		repo, svc := setup(t)
+		repo.On("RedisGet", ctx, "stmnplibrary:recommendations:7").Return(nil, apperr.Unavailable(errors.New("circuit open"))).Once()
+		repo.On("GetStudent", ctx, 7).Return(student, nil).Once()
+		repo.On("GetCandidates", ctx, student, mock.Anything, 20).Return(candidates, nil).Once()
+
+		result, err := svc.GetRecommendations(ctx)
+		require.NoError(t, err)
+		assert.Len(t, result.Books, 2)
+	})
+
+	t.Run("Not_Logged_In", func(t *testing.T) {
+		_, svc := setup(t)
+		_, err := svc.GetRecommendations(context.Background())
+		assert.Equal(t, apperr.KindUnauthorized, apperr.KindOf(err))
+	})
+}
+
+func TestRefresh_Walks_Students(t *testing.T) {
+	ctx := context.Background()
+	repo, svc := setup(t)
+	repo.On("GetCandidates", ctx, mock.Anything, mock.Anything, 20).Return(candidates, nil)
+	repo.On("RedisSet", ctx, mock.Anything, mock.Anything, 3*time.Hour).Return(nil)
+
+	repo.On("GetStudents", ctx, 0, 2).Return([]entity.StudentData{{ID: 1}, {ID: 4}}, nil).Once()
+	n, err := svc.Refresh(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	// the short batch ends the run, the next one starts over
+	repo.On("GetStudents", ctx, 4, 2).Return([]entity.StudentData{{ID: 9}}, nil).Once()
+	n, err = svc.Refresh(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, int64(0), svc.after.Load())
+	repo.AssertNumberOfCalls(t, "RedisSet", 3)
+}
+
+func TestRefresh_Fail_Keeps_Position(t *testing.T) {
+	ctx := context.Background()
+	repo, svc := setup(t)
+	repo.On("GetStudents", ctx, 0, 2).Return([]entity.StudentData{{ID: 1}, {ID: 4}}, nil).Once()
+	repo.On("GetCandidates", ctx, entity.StudentData{ID: 1}, mock.Anything, 20).Return(candidates, nil).Once()
+	repo.On("GetCandidates", ctx, entity.StudentData{ID: 4}, mock.Anything, 20).Return(nil, apperr.Internal(errors.New("db down"))).Once()
+	repo.On("RedisSet", ctx, "stmnplibrary:recommendations:1", mock.Anything, 3*time.Hour).Return(nil).Once()
+
+	_, err := svc.Refresh(ctx)
+	assert.Equal(t, apperr.KindInternal, apperr.KindOf(err))
+	assert.Equal(t, int64(1), svc.after.Load())
+}
@@ -0,0 +1,26 @@
+package service
+
+import (
+	"context"
+
+	"stmnplibrary/domain/interface/service"
+	"stmnplibrary/dto"
+	"stmnplibrary/tracing"
+)
+
+// tracedRecommendationService wraps every RecommendationService method in a span, failed calls are recorded on it.
+type tracedRecommendationService struct {
+	next service.RecommendationService
+}
+
+func (t *tracedRecommendationService) GetRecommendations(ctx context.Context) (result *dto.Recommendations, err error) {
+	ctx, span := tracing.Start(ctx, "RecommendationService.GetRecommendations")
+	defer func() { tracing.End(span, err) }()
+	return t.next.GetRecommendations(ctx)
+}
+
+func (t *tracedRecommendationService) Refresh(ctx context.Context) (result int, err error) {
+	ctx, span := tracing.Start(ctx, "RecommendationService.Refresh")
+	defer func() { tracing.End(span, err) }()
+	return t.next.Refresh(ctx)
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"stmnplibrary/apperr"
+	"stmnplibrary/audit"
+	"stmnplibrary/constanta"
+	"stmnplibrary/controller/service/utils"
+	"stmnplibrary/domain/entity"
+	"stmnplibrary/domain/interface/repository"
+	"stmnplibrary/domain/interface/service"
+	"stmnplibrary/dto"
+	"stmnplibrary/pagination"
+	"strconv"
+	"strings"
+)
+
+var errNotReturned = apperr.New(apperr.KindForbidden, apperr.CodeNotReturned, "you can only review a book you have borrowed and returned")
+
+type reviewService struct {
+	reviewRepository repository.ReviewRepository
+	auditRepository  repository.AuditRepository
+}
+
+func FnReviewService(repository repository.ReviewRepository, auditRepository repository.AuditRepository) service.ReviewService {
+	return &tracedReviewService{
+		next: newReviewService(repository, auditRepository),
+	}
+}
+
+func newReviewService(repository repository.ReviewRepository, auditRepository repository.AuditRepository) *reviewService {
+	return &reviewService{
+		reviewRepository: repository,
+		auditRepository:  auditRepository,
+	}
+}
+
+func (rs *reviewService) record(ctx context.Context, action string, entityName string, entityID string, before any, after any) error {
+	data, err := utils.NewAudit(ctx, action, entityName, entityID, before, after)
+	if err != nil {
+		return err
+	}
+	return rs.auditRepository.Record(ctx, data)
+}
+
+func reviewAudit(r entity.Review) map[string]any {
+	return map[string]any{
+		"id_user":       r.IdUser,
+		"id_book":       r.IdBook,
+		"rating":        r.Rating,
+		"body":          r.Body,
+		"status":        r.Status,
+		"hidden_reason": r.HiddenReason,
+	}
+}
+
+// PostReview adds the review of the logged in student or replaces the one
+// they posted before. Only a student who has returned the book may review it
+// and the rating of the book is computed again in the same transaction.
+func (rs *reviewService) PostReview(ctx context.Context, idBook int, data dto.PostReview) (*dto.Review, error) {
+	const errMsg = "service - post_review: %w"
+	idUser, ok := ctx.Value(constanta.UI).(int)
+	if !ok {
+		return nil, apperr.ErrLoginRequired
+	}
+	var review = entity.Review{
+		IdUser: idUser,
+		IdBook: idBook,
+		Rating: data.Rating,
+		Body:   strings.TrimSpace(data.Body),
+		Status: entity.ReviewVisible,
+	}
+	err := rs.reviewRepository.WithTx(ctx, func(ctx context.Context) error {
+		if err := rs.reviewRepository.LockBook(ctx, idBook); err != nil {
+			return err
+		}
+		returned, err := rs.reviewRepository.HasReturnedLoan(ctx, idUser, idBook)
+		if err != nil {
+			return err
+		}
+		if !returned {
+			return errNotReturned
+		}
+		var (
+			action = audit.ActionCreate
+			before map[string]any
+		)
+		old, err := rs.reviewRepository.GetReviewByUser(ctx, idUser, idBook)
+		switch {
+		case err == nil:
+			action, before = audit.ActionUpdate, reviewAudit(old)
+		case !errors.Is(err, apperr.ErrNotFound):
+			return err
+		}
+		if err := rs.reviewRepository.UpsertReview(ctx, &review); err != nil {
+			return err
+		}
+		if _, err := rs.reviewRepository.UpdateBookRating(ctx, idBook); err != nil {
+			return err
+		}
+		return rs.record(ctx, action, "review", strconv.Itoa(review.ID), before, reviewAudit(review))
+	})
+	if err != nil {
+		return nil, utils.ValidateErrTw(err, errMsg)
+	}
+	result := utils.ReviewMapper(entity.ReviewData{Review: review})
+	return &result, nil
+}
+
+// GetBookReviews lists the visible reviews of a book, newest first.
+func (rs *reviewService) GetBookReviews(ctx context.Context, idBook int, query dto.PageQuery) ([]dto.Review, *dto.Meta, error) {
+	return rs.getReviews(ctx, "service - get_book_reviews: %w", entity.ReviewFilter{IdBook: idBook, Status: entity.ReviewVisible}, query)
+}
+
+// GetReviews lists the reviews of every book for the moderators, hidden ones
+// included unless filtered out.
+func (rs *reviewService) GetReviews(ctx context.Context, filter dto.ReviewFilter) ([]dto.Review, *dto.Meta, error) {
+	return rs.getReviews(ctx, "service - get_reviews: %w", entity.ReviewFilter{IdBook: filter.BookID, Status: filter.Status}, filter.PageQuery)
+}
+
+func (rs *reviewService) getReviews(ctx context.Context, errMsg string, filter entity.ReviewFilter, query dto.PageQuery) ([]dto.Review, *dto.Meta, error) {
+	page, err := pagination.New(query.Cursor, query.PageSize, pagination.SortNewest)
+	if err != nil {
+		return nil, nil, err
+	}
+	data, total, err := rs.reviewRepository.GetReviews(ctx, filter, page)
+	if err != nil {
+		return nil, nil, utils.ValidateErrTw(err, errMsg)
+	}
+	data, next := pagination.Trim(data, page, utils.ReviewKey)
+	return utils.ReviewsMapper(data), utils.NewMeta(page, next, total), nil
+}
+
+// HideReview hides a review from the students and leaves it out of the
+// rating of the book, the reason is kept for the moderators.
+func (rs *reviewService) HideReview(ctx context.Context, id int, data dto.HideReview) (*dto.Review, error) {
+	return rs.setStatus(ctx, "service - hide_review: %w", audit.ActionHide, id, entity.ReviewHidden, strings.TrimSpace(data.Reason))
+}
+
+func (rs *reviewService) UnhideReview(ctx context.Context, id int) (*dto.Review, error) {
+	return rs.setStatus(ctx, "service - unhide_review: %w", audit.ActionUnhide, id, entity.ReviewVisible, "")
+}
+
+func (rs *reviewService) setStatus(ctx context.Context, errMsg string, action string, id int, status string, reason string) (*dto.Review, error) {
+	var review entity.Review
+	err := rs.reviewRepository.WithTx(ctx, func(ctx context.Context) error {
+		before, err := rs.reviewRepository.GetReview(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := rs.reviewRepository.LockBook(ctx, before.IdBook); err != nil {
+			return err
+		}
+		if review, err = rs.reviewRepository.SetReviewStatus(ctx, id, status, reason); err != nil {
+			return err
+		}
+		if _, err := rs.reviewRepository.UpdateBookRating(ctx, review.IdBook); err != nil {
+			return err
+		}
+		return rs.record(ctx, action, "review", strconv.Itoa(id), reviewAudit(before), reviewAudit(review))
+	})
+	if err != nil {
+		return nil, utils.ValidateErrTw(err, errMsg)
+	}
+	result := utils.ReviewMapper(entity.ReviewData{Review: review})
+	return &result, nil
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"stmnplibrary/apperr"
+	"stmnplibrary/constanta"
+	"stmnplibrary/domain/entity"
+	"stmnplibrary/dto"
+	"stmnplibrary/mocks"
+	"stmnplibrary/pagination"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setup(t *testing.T) (*mocks.ReviewRepository, *mocks.AuditRepository, *reviewService) {
+	repo := mocks.NewReviewRepository(t)
+	auditRepo := mocks.NewAuditRepository(t)
+	svc := newReviewService(repo, auditRepo)
+	repo.On("WithTx", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	}).Maybe()
+	return repo, auditRepo, svc
+}
+
+func TestPostReview_Cases(t *testing.T) {
+	ctx := context.WithValue(context.Background(), constanta.UI, 7)
+	data := dto.PostReview{Rating: 4, Body: " Seru "}
+
+	t.Run("Success", func(t *testing.T) {
+		repo, auditRepo, svc := setup(t)
+		repo.On("LockBook", ctx, 3).Return(nil).Once()
+		repo.On("HasReturnedLoan", ctx, 7, 3).Return(true, nil).Once()
+		repo.On("GetReviewByUser", ctx, 7, 3).Return(entity.Review{}, apperr.ErrNotFound).Once()
+		repo.On("UpsertReview", ctx, mock.MatchedBy(func(r *entity.Review) bool {
+			return r.IdUser == 7 && r.IdBook == 3 && r.Rating == 4 && r.Body == "Seru" && r.Status == entity.ReviewVisible
+		})).Run(func(args mock.Arguments) {
+			args.Get(1).(*entity.Review).ID = 11
+		}).Return(nil).Once()
+		repo.On("UpdateBookRating", ctx, 3).Return(entity.Rating{RatingAvg: 4, RatingCount: 1}, nil).Once()
+		auditRepo.On("Record", ctx, mock.MatchedBy(func(a entity.Audit) bool {
+			return a.Entity == "review" && a.Action == "create" && a.EntityID == "11"
+		})).Return(nil).Once()
+
+		result, err := svc.PostReview(ctx, 3, data)
+		require.NoError(t, err)
+		assert.Equal(t, 11, result.ID)
+		assert.Equal(t, 3, result.BookID)
+		assert.Equal(t, entity.ReviewVisible, result.Status)
+	})
+
+	t.Run("Replaces_Review", func(t *testing.T) {
+		repo, auditRepo, svc := setup(t)
+		repo.On("LockBook", ctx, 3).Return(nil).Once()
+		repo.On("HasReturnedLoan", ctx, 7, 3).Return(true, nil).Once()
+		repo.On("GetReviewByUser", ctx, 7, 3).Return(entity.Review{ID: 11, IdUser: 7, IdBook: 3, Rating: 2, Status: entity.ReviewVisible}, nil).Once()
+		repo.On("UpsertReview", ctx, mock.Anything).Return(nil).Once()
+		repo.On("UpdateBookRating", ctx, 3).Return(entity.Rating{RatingAvg: 4, RatingCount: 1}, nil).Once()
+		auditRepo.On("Record", ctx, mock.MatchedBy(func(a entity.Audit) bool {
+			return a.Action == "update" && a.Diff != "{}"
+		})).Return(nil).Once()
+
+		_, err := svc.PostReview(ctx, 3, data)
+		require.NoError(t, err)
+	})
+
+	t.Run("Not_Returned", func(t *testing.T) {
+		repo, _, svc := setup(t)
+		repo.On("LockBook", ctx, 3).Return(nil).Once()
+		repo.On("HasReturnedLoan", ctx, 7, 3).Return(false, nil).Once()
+
+		_, err := svc.PostReview(ctx, 3, data)
+		assert.Equal(t, apperr.KindForbidden, apperr.KindOf(err))
+		assert.Equal(t, apperr.CodeNotReturned, apperr.CodeOf(err))
+	})
+
+	t.Run("Book_Not_Found", func(t *testing.T) {
+		repo, _, svc := setup(t)
+		repo.On("LockBook", ctx, 3).Return(apperr.ErrNotFound).Once()
+
+		_, err := svc.PostReview(ctx, 3, data)
+		assert.True(t, errors.Is(err, apperr.ErrNotFound))
+	})
+
+	t.Run("Fail_Rating", func(t *testing.T) {
+		repo, _, svc := setup(t)
+		repo.On("LockBook", ctx, 3).Return(nil).Once()
+		repo.On("HasReturnedLoan", ctx, 7, 3).Return(true, nil).Once()
+		repo.On("GetReviewByUser", ctx, 7, 3).Return(entity.Review{}, apperr.ErrNotFound).Once()
+		repo.On("UpsertReview", ctx, mock.Anything).Return(nil).Once()
+		repo.On("UpdateBookRating", ctx, 3).Return(entity.Rating{}, apperr.Internal(errors.New("db down"))).Once()
+
+		_, err := svc.PostReview(ctx, 3, data)
+		assert.Equal(t, apperr.KindInternal, apperr.KindOf(err))
+	})
+
+	t.Run("Not_Logged_In", func(t *testing.T) {
+		_, _, svc := setup(t)
+		_, err := svc.PostReview(context.Background(), 3, data)
+		assert.Equal(t, apperr.KindUnauthorized, apperr.KindOf(err))
+	})
+}
+
+func TestGetBookReviews(t *testing.T) {
+	ctx := context.Background()
+	repo, _, svc := setup(t)
+	created := time.Date(2026, 5, 4, 9, 0, 0, 0, time.UTC)
+	rows := []entity.ReviewData{
+		{Review: entity.Review{ID: 2, IdBook: 3, Rating: 5, Status: entity.ReviewVisible, CreatedAt: created}, StudentName: "Budi"},
+		{Review: entity.Review{ID: 1, IdBook: 3, Rating: 3, Status: entity.ReviewVisible, CreatedAt: created.Add(-time.Hour)}, StudentName: "Sari"},
+	}
+	repo.On("GetReviews", ctx, entity.ReviewFilter{IdBook: 3, Status: entity.ReviewVisible}, mock.MatchedBy(func(p pagination.Page) bool {
+		return p.Sort == pagination.SortNewest && p.Size == 1
+	})).Return(rows, int64(2), nil).Once()
+
+	reviews, meta, err := svc.GetBookReviews(ctx, 3, dto.PageQuery{PageSize: 1})
+	require.NoError(t, err)
+	require.Len(t, reviews, 1)
+	assert.Equal(t, "Budi", reviews[0].StudentName)
+	assert.True(t, meta.HasNext)
+	assert.Equal(t, int64(2), meta.Total)
+}
+
+func TestHideReview_Cases(t *testing.T) {
+	ctx := context.Background()
+	visible := entity.Review{ID: 11, IdUser: 7, IdBook: 3, Rating: 1, Status: entity.ReviewVisible}
+
+	t.Run("Success", func(t *testing.T) {
+		repo, auditRepo, svc := setup(t)
+		hidden := visible
+		hidden.Status, hidden.HiddenReason = entity.ReviewHidden, "spam"
+		repo.On("GetReview", ctx, 11).Return(visible, nil).Once()
+		repo.On("LockBook", ctx, 3).Return(nil).Once()
+		repo.On("SetReviewStatus", ctx, 11, entity.ReviewHidden, "spam").Return(hidden, nil).Once()
+		repo.On("UpdateBookRating", ctx, 3).Return(entity.Rating{}, nil).Once()
+		auditRepo.On("Record", ctx, mock.MatchedBy(func(a entity.Audit) bool {
+			return a.Entity == "review" && a.Action == "hide" && a.EntityID == "11"
+		})).Return(nil).Once()
+
+		result, err := svc.HideReview(ctx, 11, dto.HideReview{Reason: "spam"})
+		require.NoError(t, err)
+		assert.Equal(t, entity.ReviewHidden, result.Status)
+		assert.Equal(t, "spam", result.HiddenReason)
+	})
+
+	t.Run("Not_Found", func(t *testing.T) {
+		repo, _, svc := setup(t)
+		repo.On("GetReview", ctx, 11).Return(entity.Review{}, apperr.ErrNotFound).Once()
+
+		_, err := svc.HideReview(ctx, 11, dto.HideReview{Reason: "spam"})
+		assert.Equal(t, apperr.KindNotFound, apperr.KindOf(err))
+	})
+}
+
+func TestUnhideReview(t *testing.T) {
+	ctx := context.Background()
+	repo, auditRepo, svc := setup(t)
+	hidden := entity.Review{ID: 11, IdBook: 3, Status: entity.ReviewHidden, HiddenReason: "spam"}
+	repo.On("GetReview", ctx, 11).Return(hidden, nil).Once()
+	repo.On("LockBook", ctx, 3).Return(nil).Once()
+	repo.On("SetReviewStatus", ctx, 11, entity.ReviewVisible, "").Return(entity.Review{ID: 11, IdBook: 3, Status: entity.ReviewVisible}, nil).Once()
+	repo.On("UpdateBookRating", ctx, 3).Return(entity.Rating{RatingAvg: 1, RatingCount: 1}, nil).Once()
+	auditRepo.On("Record", ctx, mock.MatchedBy(func(a entity.Audit) bool {
+		return a.Action == "unhide"
+	})).Return(nil).Once()
+
+	result, err := svc.UnhideReview(ctx, 11)
+	require.NoError(t, err)
+	assert.Equal(t, entity.ReviewVisible, result.Status)
+}
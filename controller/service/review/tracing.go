@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+
+	"stmnplibrary/domain/interface/service"
+	"stmnplibrary/dto"
+	"stmnplibrary/tracing"
+)
+
+// tracedReviewService wraps every ReviewService method in a span, failed calls are recorded on it.
+type tracedReviewService struct {
+	next service.ReviewService
+}
+
+func (t *tracedReviewService) PostReview(ctx context.Context, idBook int, data dto.PostReview) (result *dto.Review, err error) {
+	ctx, span := tracing.Start(ctx, "ReviewService.PostReview")
+	defer func() { tracing.End(span, err) }()
+	return t.next.PostReview(ctx, idBook, data)
+}
+
+func (t *tracedReviewService) GetBookReviews(ctx context.Context, idBook int, query dto.PageQuery) (result []dto.Review, meta *dto.Meta, err error) {
+	ctx, span := tracing.Start(ctx, "ReviewService.GetBookReviews")
+	defer func() { tracing.End(span, err) }()
+	return t.next.GetBookReviews(ctx, idBook, query)
+}
+
+func (t *tracedReviewService) GetReviews(ctx context.Context, filter dto.ReviewFilter) (result []dto.Review, meta *dto.Meta, err error) {
+	ctx, span := tracing.Start(ctx, "ReviewService.GetReviews")
+	defer func() { tracing.End(span, err) }()
+	return t.next.GetReviews(ctx, filter)
+}
+
+func (t *tracedReviewService) HideReview(ctx context.Context, id int, data dto.HideReview) (result *dto.Review, err error) {
+	ctx, span := tracing.Start(ctx, "ReviewService.HideReview")
+	defer func() { tracing.End(span, err) }()
+	return t.next.HideReview(ctx, id, data)
+}
+
+func (t *tracedReviewService) UnhideReview(ctx context.Context, id int) (result *dto.Review, err error) {
+	ctx, span := tracing.Start(ctx, "ReviewService.UnhideReview")
+	defer func() { tracing.End(span, err) }()
+	return t.next.UnhideReview(ctx, id)
+}
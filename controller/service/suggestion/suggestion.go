@@ -0,0 +1,314 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"stmnplibrary/apperr"
+	"stmnplibrary/audit"
+	"stmnplibrary/clock"
+	"stmnplibrary/config"
+	"stmnplibrary/constanta"
+	"stmnplibrary/controller/service/utils"
+	"stmnplibrary/domain/entity"
+	"stmnplibrary/domain/interface/repository"
+	"stmnplibrary/domain/interface/service"
+	"stmnplibrary/dto"
+	"stmnplibrary/outbox"
+	"stmnplibrary/pagination"
+	"strconv"
+	"strings"
+)
+
+var (
+	errInCatalog = apperr.New(apperr.KindConflict, apperr.CodeAlreadyInCatalog, "the book is already in the catalog")
+	errClosed    = apperr.New(apperr.KindConflict, apperr.CodeSuggestionClosed, "the suggestion is closed")
+)
+
+type suggestionService struct {
+	suggestionRepository repository.SuggestionRepository
+	auditRepository      repository.AuditRepository
+	outboxRepository     repository.OutboxRepository
+	adminService         service.AdminService
+	cfg                  config.Library
+	clock                clock.Clock
+}
+
+func FnSuggestionService(repository repository.SuggestionRepository, auditRepository repository.AuditRepository, outboxRepository repository.OutboxRepository, adminService service.AdminService, cfg config.Library, clock clock.Clock) service.SuggestionService {
+	return &tracedSuggestionService{
+		next: newSuggestionService(repository, auditRepository, outboxRepository, adminService, cfg, clock),
+	}
+}
+
+func newSuggestionService(repository repository.SuggestionRepository, auditRepository repository.AuditRepository, outboxRepository repository.OutboxRepository, adminService service.AdminService, cfg config.Library, clock clock.Clock) *suggestionService {
+	return &suggestionService{
+		suggestionRepository: repository,
+		auditRepository:      auditRepository,
+		outboxRepository:     outboxRepository,
+		adminService:         adminService,
+		cfg:                  cfg,
+		clock:                clock,
+	}
+}
+
+func (ss *suggestionService) record(ctx context.Context, action string, entityName string, entityID string, before any, after any) error {
+	data, err := utils.NewAudit(ctx, action, entityName, entityID, before, after)
+	if err != nil {
+		return err
+	}
+	return ss.auditRepository.Record(ctx, data)
+}
+
+func (ss *suggestionService) publish(ctx context.Context, eventType string, aggregate string, aggregateID string, payload any) error {
+	data, err := utils.NewEvent(ctx, eventType, aggregate, aggregateID, payload)
+	if err != nil {
+		return err
+	}
+	return ss.outboxRepository.Add(ctx, data)
+}
+
+func suggestionAudit(s entity.Suggestion) map[string]any {
+	return map[string]any{
+		"id_user": s.IdUser,
+		"title":   s.Title,
+		"author":  s.Author,
+		"isbn":    s.ISBN,
+		"status":  s.Status,
+		"note":    s.Note,
+		"id_book": s.IdBook,
+	}
+}
+
+// cleanISBN drops the hyphens and spaces an isbn is often written with.
+func cleanISBN(isbn string) string {
+	return strings.NewReplacer("-", "", " ", "").Replace(isbn)
+}
+
+// AddSuggestion records a book the logged in student wants the library to
+// buy, their vote included. A book already in the catalog or suggested and
+// not closed yet can't be suggested again, the student votes for it instead.
+func (ss *suggestionService) AddSuggestion(ctx context.Context, data dto.AddSuggestion) (*dto.Suggestion, error) {
+	const errMsg = "service - add_suggestion: %w"
+	idUser, ok := ctx.Value(constanta.UI).(int)
+	if !ok {
+		return nil, apperr.ErrLoginRequired
+	}
+	var suggestion = entity.Suggestion{
+		IdUser: idUser,
+		Title:  strings.TrimSpace(data.Title),
+		Author: strings.TrimSpace(data.Author),
+		ISBN:   cleanISBN(data.ISBN),
+		Status: entity.SuggestionSubmitted,
+		Votes:  1,
+	}
+	err := ss.suggestionRepository.WithTx(ctx, func(ctx context.Context) error {
+		if suggestion.ISBN != "" {
+			if _, err := ss.suggestionRepository.GetBookID(ctx, suggestion.ISBN); !errors.Is(err, apperr.ErrNotFound) {
+				if err != nil {
+					return err
+				}
+				return errInCatalog
+			}
+			open, err := ss.suggestionRepository.GetOpenSuggestion(ctx, suggestion.ISBN)
+			switch {
+			case err == nil:
+				return apperr.New(apperr.KindConflict, apperr.CodeAlreadySuggested, fmt.Sprintf("the book is already suggested, vote for suggestion %d instead", open.ID))
+			case !errors.Is(err, apperr.ErrNotFound):
+				return err
+			}
+		}
+		if err := ss.suggestionRepository.AddSuggestion(ctx, &suggestion); err != nil {
+			return err
+		}
+		if _, err := ss.suggestionRepository.AddVote(ctx, suggestion.ID, idUser); err != nil {
+			return err
+		}
+		return ss.record(ctx, audit.ActionCreate, "suggestion", strconv.Itoa(suggestion.ID), nil, suggestionAudit(suggestion))
+	})
+	if err != nil {
+		return nil, utils.ValidateErrTw(err, errMsg)
+	}
+	result := utils.SuggestionMapper(entity.SuggestionData{Suggestion: suggestion, Voted: true})
+	return &result, nil
+}
+
+// GetSuggestions lists the suggestions, the most voted first unless sorted
+// by newest.
+func (ss *suggestionService) GetSuggestions(ctx context.Context, filter dto.SuggestionFilter) ([]dto.Suggestion, *dto.Meta, error) {
+	const errMsg = "service - get_suggestions: %w"
+	var sort = filter.Sort
+	if sort == "" {
+		sort = pagination.SortVotes
+	}
+	page, err := pagination.New(filter.Cursor, filter.PageSize, sort)
+	if err != nil {
+		return nil, nil, err
+	}
+	idUser, _ := ctx.Value(constanta.UI).(int)
+	data, total, err := ss.suggestionRepository.GetSuggestions(ctx, entity.SuggestionFilter{Status: filter.Status, Voter: idUser}, page)
+	if err != nil {
+		return nil, nil, utils.ValidateErrTw(err, errMsg)
+	}
+	data, next := pagination.Trim(data, page, utils.SuggestionKey(sort))
+	return utils.SuggestionsMapper(data), utils.NewMeta(page, next, total), nil
+}
+
+// VoteSuggestion adds the vote of the logged in student to a suggestion not
+// closed yet, voting again changes nothing.
+func (ss *suggestionService) VoteSuggestion(ctx context.Context, id int) (*dto.Suggestion, error) {
+	const errMsg = "service - vote_suggestion: %w"
+	idUser, ok := ctx.Value(constanta.UI).(int)
+	if !ok {
+		return nil, apperr.ErrLoginRequired
+	}
+	var suggestion entity.Suggestion
+	err := ss.suggestionRepository.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		if suggestion, err = ss.suggestionRepository.LockSuggestion(ctx, id); err != nil {
+			return err
+		}
+		if !suggestion.Open() {
+			return errClosed
+		}
+		added, err := ss.suggestionRepository.AddVote(ctx, id, idUser)
+		if err != nil || !added {
+			return err
+		}
+		if suggestion.Votes, err = ss.suggestionRepository.CountVotes(ctx, id); err != nil {
+			return err
+		}
+		return ss.suggestionRepository.UpdateSuggestion(ctx, &suggestion)
+	})
+	if err != nil {
+		return nil, utils.ValidateErrTw(err, errMsg)
+	}
+	result := utils.SuggestionMapper(entity.SuggestionData{Suggestion: suggestion, Voted: true})
+	return &result, nil
+}
+
+func errTransition(from string, to string) error {
+	return apperr.New(apperr.KindConflict, apperr.CodeInvalidTransition, fmt.Sprintf("a suggestion %s can't be %s", from, to))
+}
+
+// MoveSuggestion moves a suggestion under review, ordered or rejected,
+// received goes through ReceiveSuggestion.
+func (ss *suggestionService) MoveSuggestion(ctx context.Context, id int, data dto.MoveSuggestion) (*dto.Suggestion, error) {
+	const errMsg = "service - move_suggestion: %w"
+	var suggestion entity.Suggestion
+	err := ss.suggestionRepository.WithTx(ctx, func(ctx context.Context) error {
+		before, err := ss.suggestionRepository.LockSuggestion(ctx, id)
+		if err != nil {
+			return err
+		}
+		if !before.CanMove(data.Status) {
+			return errTransition(before.Status, data.Status)
+		}
+		suggestion = before
+		suggestion.Status, suggestion.Note = data.Status, strings.TrimSpace(data.Note)
+		if err := ss.suggestionRepository.UpdateSuggestion(ctx, &suggestion); err != nil {
+			return err
+		}
+		return ss.record(ctx, audit.ActionUpdate, "suggestion", strconv.Itoa(id), suggestionAudit(before), suggestionAudit(suggestion))
+	})
+	if err != nil {
+		return nil, utils.ValidateErrTw(err, errMsg)
+	}
+	result := utils.SuggestionMapper(entity.SuggestionData{Suggestion: suggestion})
+	return &result, nil
+}
+
+// ReceiveSuggestion turns an ordered suggestion into a book of the catalog
+// through AddBook, a book already added under the isbn is used as is. The
+// requesters are notified with a SuggestionReceived event each and the
+// first of them, as many as the available copies, get a hold on the book.
+// Everything happens in one transaction.
+func (ss *suggestionService) ReceiveSuggestion(ctx context.Context, id int, data dto.ReceiveSuggestion) (*dto.ReceivedSuggestion, error) {
+	const errMsg = "service - receive_suggestion: %w"
+	var (
+		suggestion entity.Suggestion
+		requesters []int
+		holds      []entity.Hold
+		expiresAt  = ss.clock.Now().Add(ss.cfg.HoldTTL)
+	)
+	err := ss.suggestionRepository.WithTx(ctx, func(ctx context.Context) error {
+		before, err := ss.suggestionRepository.LockSuggestion(ctx, id)
+		if err != nil {
+			return err
+		}
+		if !before.CanMove(entity.SuggestionReceived) {
+			return errTransition(before.Status, entity.SuggestionReceived)
+		}
+		suggestion = before
+		if isbn := cleanISBN(data.ISBN); isbn != "" {
+			suggestion.ISBN = isbn
+		}
+		if suggestion.ISBN == "" {
+			return apperr.New(apperr.KindInvalid, apperr.CodeValidation, "isbn is required, the suggestion has none")
+		}
+		idBook, err := ss.suggestionRepository.GetBookID(ctx, suggestion.ISBN)
+		if errors.Is(err, apperr.ErrNotFound) {
+			if err = ss.adminService.AddBook(ctx, dto.BookData{
+				ISBN:           suggestion.ISBN,
+				Name:           suggestion.Title,
+				Author:         suggestion.Author,
+				Publisher:      data.Publisher,
+				Description:    data.Description,
+				Stock:          data.Stock,
+				AvailableStock: data.Stock,
+				Price:          data.Price,
+				IDCategory:     data.IDCategory,
+			}); err != nil {
+				return err
+			}
+			idBook, err = ss.suggestionRepository.GetBookID(ctx, suggestion.ISBN)
+		}
+		if err != nil {
+			return err
+		}
+		available, err := ss.suggestionRepository.LockBookStock(ctx, idBook)
+		if err != nil {
+			return err
+		}
+		if requesters, err = ss.suggestionRepository.GetRequesters(ctx, id); err != nil {
+			return err
+		}
+		for _, idUser := range requesters[:min(available, len(requesters))] {
+			holds = append(holds, entity.Hold{IdUser: idUser, IdBook: idBook, IdSuggestion: &suggestion.ID, ExpiresAt: expiresAt})
+		}
+		if err := ss.suggestionRepository.AddHolds(ctx, holds); err != nil {
+			return err
+		}
+		suggestion.Status, suggestion.IdBook = entity.SuggestionReceived, &idBook
+		if err := ss.suggestionRepository.UpdateSuggestion(ctx, &suggestion); err != nil {
+			return err
+		}
+		if err := ss.record(ctx, audit.ActionUpdate, "suggestion", strconv.Itoa(id), suggestionAudit(before), suggestionAudit(suggestion)); err != nil {
+			return err
+		}
+		for i, idUser := range requesters {
+			var payload = map[string]any{
+				"id_suggestion": id,
+				"id_user":       idUser,
+				"id_book":       idBook,
+				"title":         suggestion.Title,
+				"hold":          i < len(holds),
+			}
+			if i < len(holds) {
+				payload["hold_expires_at"] = expiresAt
+			}
+			if err := ss.publish(ctx, outbox.EventSuggestionReceived, "suggestion", fmt.Sprintf("%d:%d", id, idUser), payload); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, utils.ValidateErrTw(err, errMsg)
+	}
+	return &dto.ReceivedSuggestion{
+		Suggestion:    utils.SuggestionMapper(entity.SuggestionData{Suggestion: suggestion}),
+		Requesters:    len(requesters),
+		Holds:         len(holds),
+		HoldExpiresAt: expiresAt,
+	}, nil
+}
@@ -0,0 +1,267 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"stmnplibrary/apperr"
+	"stmnplibrary/clock"
+	"stmnplibrary/config"
+	"stmnplibrary/constanta"
+	"stmnplibrary/domain/entity"
+	"stmnplibrary/domain/interface/service"
+	"stmnplibrary/dto"
+	"stmnplibrary/mocks"
+	"stmnplibrary/outbox"
+	"stmnplibrary/pagination"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var now = time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+
+// adminService records the books added through AddBook, the other methods
+// aren't used by the suggestions.
+type adminService struct {
+	service.AdminService
+	added []dto.BookData
+	err   error
+}
+
+func (a *adminService) AddBook(ctx context.Context, data dto.BookData) error {
+	a.added = append(a.added, data)
+	return a.err
+}
+
+func setup(t *testing.T) (*mocks.SuggestionRepository, *mocks.AuditRepository, *mocks.OutboxRepository, *adminService, *suggestionService) {
+	repo := mocks.NewSuggestionRepository(t)
+	auditRepo := mocks.NewAuditRepository(t)
+	outboxRepo := mocks.NewOutboxRepository(t)
+	admin := &adminService{}
+	svc := newSuggestionService(repo, auditRepo, outboxRepo, admin, config.Library{HoldTTL: 72 * time.Hour}, clock.NewFake(now))
+	repo.On("WithTx", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	}).Maybe()
+	return repo, auditRepo, outboxRepo, admin, svc
+}
+
+func TestAddSuggestion_Cases(t *testing.T) {
+	ctx := context.WithValue(context.Background(), constanta.UI, 7)
+	data := dto.AddSuggestion{Title: " Laskar Pelangi ", Author: "Andrea Hirata", ISBN: "978-979-3062-79-2"}
+
+	t.Run("Success", func(t *testing.T) {
+		repo, auditRepo, _, _, svc := setup(t)
+		repo.On("GetBookID", ctx, "9789793062792").Return(0, apperr.ErrNotFound).Once()
+		repo.On("GetOpenSuggestion", ctx, "9789793062792").Return(entity.Suggestion{}, apperr.ErrNotFound).Once()
+		repo.On("AddSuggestion", ctx, mock.MatchedBy(func(s *entity.Suggestion) bool {
+			return s.Title == "Laskar Pelangi" && s.Status == entity.SuggestionSubmitted && s.Votes == 1
+		})).Run(func(args mock.Arguments) {
+			args.Get(1).(*entity.Suggestion).ID = 4
+		}).Return(nil).Once()
+		repo.On("AddVote", ctx, 4, 7).Return(true, nil).Once()
+		auditRepo.On("Record", ctx, mock.MatchedBy(func(a entity.Audit) bool {
+			return a.Entity == "suggestion" && a.Action == "create" && a.EntityID == "4"
+		})).Return(nil).Once()
+
+		result, err := svc.AddSuggestion(ctx, data)
+		require.NoError(t, err)
+		assert.Equal(t, 4, result.ID)
+		assert.True(t, result.Voted)
+	})
+
+	t.Run("In_Catalog", func(t *testing.T) {
+		repo, _, _, _, svc := setup(t)
+		repo.On("GetBookID", ctx, "9789793062792").Return(12, nil).Once()
+
+		_, err := svc.AddSuggestion(ctx, data)
+		assert.Equal(t, apperr.CodeAlreadyInCatalog, apperr.CodeOf(err))
+	})
+
+	t.Run("Already_Suggested", func(t *testing.T) {
+		repo, _, _, _, svc := setup(t)
+		repo.On("GetBookID", ctx, "9789793062792").Return(0, apperr.ErrNotFound).Once()
+		repo.On("GetOpenSuggestion", ctx, "9789793062792").Return(entity.Suggestion{ID: 2}, nil).Once()
+
+		_, err := svc.AddSuggestion(ctx, data)
+		assert.Equal(t, apperr.KindConflict, apperr.KindOf(err))
+		assert.Equal(t, apperr.CodeAlreadySuggested, apperr.CodeOf(err))
+	})
+
+	t.Run("Without_ISBN", func(t *testing.T) {
+		repo, auditRepo, _, _, svc := setup(t)
+		repo.On("AddSuggestion", ctx, mock.Anything).Return(nil).Once()
+		repo.On("AddVote", ctx, 0, 7).Return(true, nil).Once()
+		auditRepo.On("Record", ctx, mock.Anything).Return(nil).Once()
+
+		_, err := svc.AddSuggestion(ctx, dto.AddSuggestion{Title: "Bumi", Author: "Tere Liye"})
+		require.NoError(t, err)
+	})
+}
+
+func TestGetSuggestions_Sort(t *testing.T) {
+	ctx := context.WithValue(context.Background(), constanta.UI, 7)
+	repo, _, _, _, svc := setup(t)
+	rows := []entity.SuggestionData{
+		{Suggestion: entity.Suggestion{ID: 2, Votes: 9}, Voted: true},
+		{Suggestion: entity.Suggestion{ID: 5, Votes: 3}},
+	}
+	repo.On("GetSuggestions", ctx, entity.SuggestionFilter{Voter: 7}, mock.MatchedBy(func(p pagination.Page) bool {
+		return p.Sort == pagination.SortVotes && p.Size == 1
+	})).Return(rows, int64(2), nil).Once()
+
+	result, meta, err := svc.GetSuggestions(ctx, dto.SuggestionFilter{PageQuery: dto.PageQuery{PageSize: 1}})
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	assert.True(t, result[0].Voted)
+	assert.NotEmpty(t, meta.NextCursor)
+}
+
+func TestVoteSuggestion_Cases(t *testing.T) {
+	ctx := context.WithValue(context.Background(), constanta.UI, 7)
+
+	t.Run("Success", func(t *testing.T) {
+		repo, _, _, _, svc := setup(t)
+		repo.On("LockSuggestion", ctx, 4).Return(entity.Suggestion{ID: 4, Status: entity.SuggestionOrdered, Votes: 2}, nil).Once()
+		repo.On("AddVote", ctx, 4, 7).Return(true, nil).Once()
+		repo.On("CountVotes", ctx, 4).Return(3, nil).Once()
+		repo.On("UpdateSuggestion", ctx, mock.MatchedBy(func(s *entity.Suggestion) bool { return s.Votes == 3 })).Return(nil).Once()
+
+		result, err := svc.VoteSuggestion(ctx, 4)
+		require.NoError(t, err)
+		assert.Equal(t, 3, result.Votes)
+	})
+
+	t.Run("Voted_Twice", func(t *testing.T) {
+		repo, _, _, _, svc := setup(t)
+		repo.On("LockSuggestion", ctx, 4).Return(entity.Suggestion{ID: 4, Status: entity.SuggestionSubmitted, Votes: 3}, nil).Once()
+		repo.On("AddVote", ctx, 4, 7).Return(false, nil).Once()
+
+		result, err := svc.VoteSuggestion(ctx, 4)
+		require.NoError(t, err)
+		assert.Equal(t, 3, result.Votes)
+	})
+
+	t.Run("Closed", func(t *testing.T) {
+		repo, _, _, _, svc := setup(t)
+		repo.On("LockSuggestion", ctx, 4).Return(entity.Suggestion{ID: 4, Status: entity.SuggestionRejected}, nil).Once()
+
+		_, err := svc.VoteSuggestion(ctx, 4)
+		assert.Equal(t, apperr.CodeSuggestionClosed, apperr.CodeOf(err))
+	})
+}
+
+func TestMoveSuggestion_Cases(t *testing.T) {
+	ctx := context.WithValue(context.Background(), constanta.UI, 1)
+
+	t.Run("Reject", func(t *testing.T) {
+		repo, auditRepo, _, _, svc := setup(t)
+		repo.On("LockSuggestion", ctx, 4).Return(entity.Suggestion{ID: 4, Status: entity.SuggestionUnderReview}, nil).Once()
+		repo.On("UpdateSuggestion", ctx, mock.MatchedBy(func(s *entity.Suggestion) bool {
+			return s.Status == entity.SuggestionRejected && s.Note == "out of print"
+		})).Return(nil).Once()
+		auditRepo.On("Record", ctx, mock.MatchedBy(func(a entity.Audit) bool {
+			return a.Entity == "suggestion" && a.Action == "update"
+		})).Return(nil).Once()
+
+		result, err := svc.MoveSuggestion(ctx, 4, dto.MoveSuggestion{Status: entity.SuggestionRejected, Note: " out of print "})
+		require.NoError(t, err)
+		assert.Equal(t, entity.SuggestionRejected, result.Status)
+	})
+
+	t.Run("Skips_Review", func(t *testing.T) {
+		repo, _, _, _, svc := setup(t)
+		repo.On("LockSuggestion", ctx, 4).Return(entity.Suggestion{ID: 4, Status: entity.SuggestionSubmitted}, nil).Once()
+
+		_, err := svc.MoveSuggestion(ctx, 4, dto.MoveSuggestion{Status: entity.SuggestionOrdered})
+		assert.Equal(t, apperr.CodeInvalidTransition, apperr.CodeOf(err))
+	})
+}
+
+func TestReceiveSuggestion_Cases(t *testing.T) {
+	ctx := context.WithValue(context.Background(), constanta.UI, 1)
+	data := dto.ReceiveSuggestion{Publisher: "Bentang Pustaka", Stock: 2, IDCategory: []int{3}}
+	ordered := entity.Suggestion{ID: 4, Title: "Laskar Pelangi", Author: "Andrea Hirata", ISBN: "9789793062792", Status: entity.SuggestionOrdered}
+
+	t.Run("Success", func(t *testing.T) {
+		repo, auditRepo, outboxRepo, admin, svc := setup(t)
+		repo.On("LockSuggestion", ctx, 4).Return(ordered, nil).Once()
+		repo.On("GetBookID", ctx, "9789793062792").Return(0, apperr.ErrNotFound).Once()
+		repo.On("GetBookID", ctx, "9789793062792").Return(30, nil).Once()
+		repo.On("LockBookStock", ctx, 30).Return(2, nil).Once()
+		repo.On("GetRequesters", ctx, 4).Return([]int{7, 8, 9}, nil).Once()
+		repo.On("AddHolds", ctx, mock.MatchedBy(func(h []entity.Hold) bool {
+			return len(h) == 2 && h[0].IdUser == 7 && h[1].IdUser == 8 && h[0].ExpiresAt.Equal(now.Add(72*time.Hour))
+		})).Return(nil).Once()
+		repo.On("UpdateSuggestion", ctx, mock.MatchedBy(func(s *entity.Suggestion) bool {
+			return s.Status == entity.SuggestionReceived && *s.IdBook == 30
+		})).Return(nil).Once()
+		auditRepo.On("Record", ctx, mock.Anything).Return(nil).Once()
+		var events []entity.Outbox
+		outboxRepo.On("Add", ctx, mock.MatchedBy(func(e entity.Outbox) bool {
+			return e.EventType == outbox.EventSuggestionReceived
+		})).Run(func(args mock.Arguments) {
+			events = append(events, args.Get(1).(entity.Outbox))
+		}).Return(nil).Times(3)
+
+		result, err := svc.ReceiveSuggestion(ctx, 4, data)
+		require.NoError(t, err)
+		require.Len(t, admin.added, 1)
+		assert.Equal(t, "Laskar Pelangi", admin.added[0].Name)
+		assert.Equal(t, 2, admin.added[0].AvailableStock)
+		assert.Equal(t, 3, result.Requesters)
+		assert.Equal(t, 2, result.Holds)
+		assert.Equal(t, 30, *result.BookID)
+		require.Len(t, events, 3)
+		assert.Equal(t, "4:9", events[2].AggregateID)
+		assert.Contains(t, events[0].Payload, `"hold":true`)
+		assert.Contains(t, events[2].Payload, `"hold":false`)
+	})
+
+	t.Run("Book_Already_Added", func(t *testing.T) {
+		repo, auditRepo, outboxRepo, admin, svc := setup(t)
+		repo.On("LockSuggestion", ctx, 4).Return(ordered, nil).Once()
+		repo.On("GetBookID", ctx, "9789793062792").Return(30, nil).Once()
+		repo.On("LockBookStock", ctx, 30).Return(0, nil).Once()
+		repo.On("GetRequesters", ctx, 4).Return([]int{7}, nil).Once()
+		repo.On("AddHolds", ctx, []entity.Hold(nil)).Return(nil).Once()
+		repo.On("UpdateSuggestion", ctx, mock.Anything).Return(nil).Once()
+		auditRepo.On("Record", ctx, mock.Anything).Return(nil).Once()
+		outboxRepo.On("Add", ctx, mock.Anything).Return(nil).Once()
+
+		result, err := svc.ReceiveSuggestion(ctx, 4, data)
+		require.NoError(t, err)
+		assert.Empty(t, admin.added)
+		assert.Equal(t, 0, result.Holds)
+	})
+
+	t.Run("Missing_ISBN", func(t *testing.T) {
+		repo, _, _, _, svc := setup(t)
+		noISBN := ordered
+		noISBN.ISBN = ""
+		repo.On("LockSuggestion", ctx, 4).Return(noISBN, nil).Once()
+
+		_, err := svc.ReceiveSuggestion(ctx, 4, data)
+		assert.Equal(t, apperr.KindInvalid, apperr.KindOf(err))
+	})
+
+	t.Run("Not_Ordered", func(t *testing.T) {
+		repo, _, _, _, svc := setup(t)
+		repo.On("LockSuggestion", ctx, 4).Return(entity.Suggestion{ID: 4, Status: entity.SuggestionUnderReview}, nil).Once()
+
+		_, err := svc.ReceiveSuggestion(ctx, 4, data)
+		assert.Equal(t, apperr.CodeInvalidTransition, apperr.CodeOf(err))
+	})
+
+	t.Run("AddBook_Fails", func(t *testing.T) {
+		repo, _, _, admin, svc := setup(t)
+		admin.err = apperr.ErrReferenceNotFound
+		repo.On("LockSuggestion", ctx, 4).Return(ordered, nil).Once()
+		repo.On("GetBookID", ctx, "9789793062792").Return(0, apperr.ErrNotFound).Once()
+
+		_, err := svc.ReceiveSuggestion(ctx, 4, data)
+		assert.Equal(t, apperr.CodeReferenceNotFound, apperr.CodeOf(err))
+	})
+}
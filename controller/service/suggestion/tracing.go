@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+
+	"stmnplibrary/domain/interface/service"
+	"stmnplibrary/dto"
+	"stmnplibrary/tracing"
+)
+
+// tracedSuggestionService wraps every SuggestionService method in a span, failed calls are recorded on it.
+type tracedSuggestionService struct {
+	next service.SuggestionService
+}
+
+func (t *tracedSuggestionService) AddSuggestion(ctx context.Context, data dto.AddSuggestion) (result *dto.Suggestion, err error) {
+	ctx, span := tracing.Start(ctx, "SuggestionService.AddSuggestion")
+	defer func() { tracing.End(span, err) }()
+	return t.next.AddSuggestion(ctx, data)
+}
+
+func (t *tracedSuggestionService) GetSuggestions(ctx context.Context, filter dto.SuggestionFilter) (result []dto.Suggestion, meta *dto.Meta, err error) {
+	ctx, span := tracing.Start(ctx, "SuggestionService.GetSuggestions")
+	defer func() { tracing.End(span, err) }()
+	return t.next.GetSuggestions(ctx, filter)
+}
+
+func (t *tracedSuggestionService) VoteSuggestion(ctx context.Context, id int) (result *dto.Suggestion, err error) {
+	ctx, span := tracing.Start(ctx, "SuggestionService.VoteSuggestion")
+	defer func() { tracing.End(span, err) }()
+	return t.next.VoteSuggestion(ctx, id)
+}
+
+func (t *tracedSuggestionService) MoveSuggestion(ctx context.Context, id int, data dto.MoveSuggestion) (result *dto.Suggestion, err error) {
+	ctx, span := tracing.Start(ctx, "SuggestionService.MoveSuggestion")
+	defer func() { tracing.End(span, err) }()
+	return t.next.MoveSuggestion(ctx, id, data)
+}
+
+func (t *tracedSuggestionService) ReceiveSuggestion(ctx context.Context, id int, data dto.ReceiveSuggestion) (result *dto.ReceivedSuggestion, err error) {
+	ctx, span := tracing.Start(ctx, "SuggestionService.ReceiveSuggestion")
+	defer func() { tracing.End(span, err) }()
+	return t.next.ReceiveSuggestion(ctx, id, data)
+}
@@ -1,18 +1,30 @@
 package service
 
 import (
+	"stmnplibrary/apperr"
+	"stmnplibrary/audit"
+	"stmnplibrary/calendar"
+	"stmnplibrary/clock"
+	"stmnplibrary/config"
 	"stmnplibrary/constanta"
+	"stmnplibrary/controller/redis/fallback"
 	"stmnplibrary/controller/service/utils"
 	"stmnplibrary/domain/entity"
 	"stmnplibrary/domain/interface/repository"
 	"stmnplibrary/domain/interface/service"
 	"stmnplibrary/dto"
+	"stmnplibrary/log"
+	"stmnplibrary/metrics"
+	"stmnplibrary/outbox"
+	"stmnplibrary/pagination"
 	"stmnplibrary/security"
+	token "stmnplibrary/security/jwt"
 	"strings"
 
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"strconv"
 	"time"
 
@@ -20,41 +32,123 @@ import (
 )
 
 const keyReTk = "stmnplibrary:accesstoken:id:%d"
-const keyBook = "stmnplibrary:book:id:%v"
+const keyBooks = "stmnplibrary:books:%s:%s"
 const keyBlcklist = "blacklist:accesstoken:%s"
 
+// bookPage is the cached form of one page of books.
+type bookPage struct {
+	Books []dto.Books `json:"books"`
+	Meta  dto.Meta    `json:"meta"`
+}
+
 type userService struct {
-	userRepository    repository.UserRepository
-	singleFlightGroup *singleflight.Group
+	userRepository     repository.UserRepository
+	auditRepository    repository.AuditRepository
+	outboxRepository   repository.OutboxRepository
+	calendarRepository repository.CalendarRepository
+	clock              clock.Clock
+	singleFlightGroup  *singleflight.Group
+	degrade            config.Degrade
+	local              *fallback.LRU[bookPage]
+	jwt                *token.JWT
+}
+
+func FnUserService(repo repository.UserRepository, auditRepo repository.AuditRepository, outboxRepo repository.OutboxRepository, calendarRepo repository.CalendarRepository, clock clock.Clock, degrade config.Degrade, jwt *token.JWT) service.UserService {
+	return &tracedUserService{
+		next: newUserService(repo, auditRepo, outboxRepo, calendarRepo, clock, degrade, jwt),
+	}
 }
 
-func FnUserService(repo repository.UserRepository) service.UserService {
+func newUserService(repo repository.UserRepository, auditRepo repository.AuditRepository, outboxRepo repository.OutboxRepository, calendarRepo repository.CalendarRepository, clock clock.Clock, degrade config.Degrade, jwt *token.JWT) *userService {
 	return &userService{
-		userRepository:    repo,
-		singleFlightGroup: &singleflight.Group{},
+		userRepository:     repo,
+		auditRepository:    auditRepo,
+		outboxRepository:   outboxRepo,
+		calendarRepository: calendarRepo,
+		clock:              clock,
+		singleFlightGroup:  &singleflight.Group{},
+		degrade:            degrade,
+		local:              fallback.NewLRU[bookPage](degrade.LocalCacheSize, degrade.LocalCacheTTL),
+		jwt:                jwt,
 	}
 }
 
-func (us *userService) getBook(ctx context.Context, key string, offset int, stop int) []dto.Books {
-	id, _ := us.userRepository.RedisZR(ctx, key, offset, stop)
-	var resltRds = make([]dto.Books, len(id))
-	rsl, _ := us.userRepository.RedisWp(ctx, func(ctx context.Context) (interface{}, error) {
-		for z, i := range id{
-			us.userRepository.RedisHGetAll(ctx, keyBook + i, &resltRds[z])
-		}
-		return resltRds, nil
-	})
-	return rsl.([]dto.Books)
+func (us *userService) record(ctx context.Context, action string, entityName string, entityID string, before any, after any) error {
+	data, err := utils.NewAudit(ctx, action, entityName, entityID, before, after)
+	if err != nil {
+		return err
+	}
+	return us.auditRepository.Record(ctx, data)
+}
+
+func (us *userService) publish(ctx context.Context, eventType string, aggregate string, aggregateID string, payload any) error {
+	data, err := utils.NewEvent(ctx, eventType, aggregate, aggregateID, payload)
+	if err != nil {
+		return err
+	}
+	return us.outboxRepository.Add(ctx, data)
+}
+
+func (us *userService) getBook(ctx context.Context, key string) (bookPage, bool) {
+	val, err := us.userRepository.RedisGet(ctx, key)
+	if err != nil && !errors.Is(err, apperr.ErrNotFound) {
+		return us.getLocalBook(ctx, key, err)
+	}
+	var page bookPage
+	ok := err == nil && utils.UnMarshal(val, &page) == nil
+	metrics.CacheResult("book", ok)
+	return page, ok
 }
 
-func (us *userService) setBook(ctx context.Context, keyIdx string, books []dto.Books) {
-	us.userRepository.RedisWp(ctx, func(ctx context.Context) (interface{}, error) {
-		for _, i := range books {
-			us.userRepository.RedisZS(ctx, keyIdx, float64(time.Now().UnixNano()), i.ID)
-			us.userRepository.RedisHSET(ctx, keyBook + strconv.Itoa(i.ID), i)
+// getLocalBook serves the local LRU while redis fails, the cache fails open so
+// a miss falls through to postgres.
+func (us *userService) getLocalBook(ctx context.Context, key string, err error) (bookPage, bool) {
+	metrics.Degraded.WithLabelValues("book_cache", "open").Inc()
+	log.LogDegraded(ctx, "book_cache", "open", err)
+	page, ok := us.local.Get(key)
+	metrics.CacheResult("book_local", ok)
+	return page, ok
+}
+
+func (us *userService) setBook(ctx context.Context, key string, page bookPage) {
+	us.local.Add(key, page)
+	if val, err := utils.Marshal(page); err == nil {
+		us.userRepository.RedisSet(ctx, key, val, 5*time.Minute)
+	}
+}
+
+// listBooks serves one keyset page of books. Pages are cached by scope, sort
+// and cursor and concurrent identical requests share one query.
+func (us *userService) listBooks(ctx context.Context, op string, errMsg string, scope string, query dto.BookQuery, fetch func(context.Context, pagination.Page) ([]entity.Book, int64, error)) ([]dto.Books, *dto.Meta, error) {
+	if query.Sort == "" {
+		query.Sort = pagination.SortTitle
+	}
+	page, err := pagination.New(query.Cursor, query.PageSize, query.Sort)
+	if err != nil {
+		return nil, nil, err
+	}
+	var key = fmt.Sprintf(keyBooks, scope, page.Key())
+	result, err, shared := us.singleFlightGroup.Do(key, func() (interface{}, error) {
+		if cached, ok := us.getBook(ctx, key); ok {
+			return cached, nil
+		}
+		rows, total, err := fetch(ctx, page)
+		if err != nil {
+			return nil, utils.ValidateErrTw(err, errMsg)
+		}
+		rows, next := pagination.Trim(rows, page, utils.BookKey(page.Sort))
+		books := bookPage{Books: utils.BooksMapper(rows), Meta: *utils.NewMeta(page, next, total)}
+		if len(books.Books) > 0 {
+			us.setBook(ctx, key, books)
 		}
-		return nil, nil
+		return books, nil
 	})
+	metrics.Singleflight(op, shared)
+	if err != nil {
+		return nil, nil, err
+	}
+	books := result.(bookPage)
+	return books.Books, &books.Meta, nil
 }
 
 func (us *userService) RateLimiter(ctx context.Context, ip string) error {
@@ -69,45 +163,65 @@ func (us *userService) CheckAccTkn(ctx context.Context, acc string) error {
 	var keyBlck = fmt.Sprintf(keyBlcklist, acc)
 	rslt, err := us.userRepository.RedisGet(ctx, keyBlck)
 	if err != nil {
-		if strings.Contains(err.Error(), "no data found") {
+		if errors.Is(err, apperr.ErrNotFound) {
 			return nil
 		}
-		return fmt.Errorf("internal server error. something wrong: %w", err)
+		// blacklist checks fail closed unless configured otherwise, a logged out
+		// token must not be accepted just because redis is down
+		var policy = us.degrade.BlacklistPolicy
+		metrics.Degraded.WithLabelValues("blacklist", policy).Inc()
+		log.LogDegraded(ctx, "blacklist", policy, err)
+		if policy == "open" {
+			return nil
+		}
+		return apperr.Unavailable(err)
 	}
 	if rslt != nil {
-		return fmt.Errorf("this access token has been blacklist")
+		return apperr.New(apperr.KindUnauthorized, apperr.CodeRevokedToken, "this access token has been blacklist")
 	}
 	return nil
 }
 
 func (us *userService) Logout(ctx context.Context) error {
 	const errIntrnl = "service - logout: %w"
-	var (
-		msg      = "please login"
-		duration = time.Now().Add(3 * time.Minute)
-		ttl      = time.Until(duration)
-	)
 	userId, ok := ctx.Value(constanta.UI).(int)
 	if !ok {
-		return errors.New(msg)
+		return apperr.ErrLoginRequired
 	}
-	token, ok := ctx.Value(constanta.TokenA).(string)
+	accToken, ok := ctx.Value(constanta.TokenA).(string)
 	if !ok {
-		return errors.New(msg)
+		return apperr.ErrLoginRequired
+	}
+	// the token is blacklisted until it expires by itself, one that expired
+	// meanwhile needs no entry
+	var ttl time.Duration
+	if cls, err := us.jwt.ValidateToken(accToken); err == nil && cls.ExpiresAt != nil {
+		ttl = time.Until(cls.ExpiresAt.Time)
 	}
 	var (
 		keyDel = fmt.Sprintf(keyReTk, userId)
-		keySet = fmt.Sprintf(keyBlcklist, token)
+		keySet = fmt.Sprintf(keyBlcklist, accToken)
 	)
-	return us.userRepository.RedisWtx(ctx, func(ctx context.Context) error {
+	if err := us.userRepository.RedisWtx(ctx, func(ctx context.Context) error {
 		if err := us.userRepository.RedisDel(ctx, keyDel); err != nil {
 			return utils.ValidateErrTw(err, errIntrnl)
 		}
-		if err := us.userRepository.RedisSet(ctx, keySet, []byte(token), ttl); err != nil {
+		if ttl <= 0 {
+			return nil
+		}
+		if err := us.userRepository.RedisSet(ctx, keySet, []byte(accToken), ttl); err != nil {
 			return utils.ValidateErrTw(err, errIntrnl)
 		}
 		return nil
-	})
+	}); err != nil {
+		return err
+	}
+	// the session is already gone, failing here would tell the client the
+	// logout didn't happen and skip clearing its cookies
+	if err := us.record(ctx, audit.ActionLogout, "session", strconv.Itoa(userId), nil, nil); err != nil {
+		log.LogAudit(ctx, audit.ActionLogout, err)
+	}
+	return nil
 }
 
 func (us *userService) Register(ctx context.Context, data *dto.Students) ([]string, error) {
@@ -129,12 +243,12 @@ func (us *userService) Register(ctx context.Context, data *dto.Students) ([]stri
 		},
 	}
 	if errNis := us.userRepository.GetNIS(ctx, realData.NIS); errNis != nil {
-		if errValN := utils.ValidateErr(errNis, "registered", &errMsg); errValN != nil {
+		if errValN := utils.ValidateErr(errNis, apperr.CodeNISRegistered, &errMsg); errValN != nil {
 			return nil, errValN
 		}
 	}
 	if errEma := us.userRepository.GetEmail(ctx, realData.PersonalInfo.Email); errEma != nil {
-		if errValE := utils.ValidateErr(errEma, "used", &errMsg); errValE != nil {
+		if errValE := utils.ValidateErr(errEma, apperr.CodeEmailUsed, &errMsg); errValE != nil {
 			return nil, errValE
 		}
 	}
@@ -145,129 +259,49 @@ func (us *userService) Register(ctx context.Context, data *dto.Students) ([]stri
 	}
 	hashPass, errPass := security.HashPassword(realData.Password)
 	if errPass != nil {
-		return nil, fmt.Errorf(errIntrnl, errPass)
+		return nil, utils.ValidateErrTw(errPass, errIntrnl)
 	}
 	realData.Password = hashPass
-	if err := us.userRepository.Register(ctx, realData); err != nil {
-		return nil, utils.ValidateErrTw(err, errIntrnl)
-	}
-	return nil, nil
-}
-
-func (us *userService) GetBooks(ctx context.Context, page int) ([]dto.Books, error) {
-	const keyBooks = "stmnplibrary:books:all:page:%d"
-	var (
-		limit  = 35
-		offset = ((page - 1) * limit)
-		stop   = offset + limit - 1
-	)
-
-	result, err, _ := us.singleFlightGroup.Do(keyBooks, func() (interface{}, error) {
-		rsl := us.getBook(ctx, fmt.Sprintf(keyBooks, page), offset, stop)
-		if rsl != nil && len(rsl) > 0 {
-			return rsl, nil
+	if err := us.userRepository.WithContext(ctx, func(ctx context.Context) error {
+		if err := us.userRepository.Register(ctx, realData); err != nil {
+			return utils.ValidateErrTw(err, errIntrnl)
 		}
-		result, err := us.userRepository.GetBooks(ctx, offset)
-		if err != nil {
-			return nil, utils.ValidateErrTw(err, "service - get_books: %w")
+		if err := us.record(ctx, audit.ActionCreate, "student", strconv.Itoa(realData.NIS), nil, map[string]any{
+			"personal_info": realData.PersonalInfo,
+			"academic_info": realData.AcademicInfo,
+		}); err != nil {
+			return utils.ValidateErrTw(err, errIntrnl)
 		}
-		books := utils.BooksMapper(result)
-		us.setBook(ctx, keyBooks, books)
-		return books, nil
-	})
-	if err != nil {
+		return nil
+	}); err != nil {
 		return nil, err
 	}
-	return result.([]dto.Books), nil
+	return nil, nil
 }
 
-func (us *userService) GetBooksByAuthor(ctx context.Context, author string, page int) ([]dto.Books, error) {
-	const keyA = "stmnplibrary:getbooks:author:%s:page:%d"
-	var (
-		limit     = 35
-		offset    = (page - 1) * limit
-		stop      = offset + limit - 1
-		keyAuthor = keyA + author + strconv.Itoa(page)
-	)
-
-	rsl := us.getBook(ctx, keyAuthor, offset, stop)
-	if rsl != nil && len(rsl) > 0 {
-		return rsl, nil
-	}
-
-	result, err := us.userRepository.GetBooksByAuthor(ctx, author, offset)
-	if err != nil {
-		return nil, utils.ValidateErrTw(err, "service - get_books_by_author: %w")
-	}
-	books := utils.BooksMapper(result)
-
-	us.setBook(ctx, fmt.Sprintf(keyAuthor, author), books)
-
-	return books, nil
+func (us *userService) GetBooks(ctx context.Context, query dto.BookQuery) ([]dto.Books, *dto.Meta, error) {
+	return us.listBooks(ctx, "get_books", "service - get_books: %w", "all", query, us.userRepository.GetBooks)
 }
 
-func (us *userService) GetBooksByCategory(ctx context.Context, category []string, page int) ([]dto.Books, error) {
-	const keyCategory = "stmnplibrary:category:%s:page:%d"
-	var (
-		limit       = 35
-		offset      = (page - 1) * limit
-		stop        = offset + limit - 1
-		id          []string
-		encountered = map[string]interface{}{}
-	)
-
-	result, err, _ := us.singleFlightGroup.Do(keyCategory, func() (interface{}, error) {
-		x, _ := us.userRepository.RedisWp(ctx, func(ctx context.Context) (interface{}, error) {
-			for _, c := range category {
-				idb, _ := us.userRepository.RedisZR(ctx, keyCategory + c + strconv.Itoa(page), offset, stop)
-				for _, i := range idb {
-					if _, ok := encountered[i]; !ok {
-						id = append(id, i)
-						encountered[i] = struct{}{}
-					}
-				}
-			}
-			var resltRds = make([]dto.Books, len(id))
-			for z, i := range id {
-				us.userRepository.RedisHGetAll(ctx, keyBook + i, &resltRds[z])
-			}
-			return resltRds, nil
-		})
-		rsl, _ := x.([]dto.Books)
-		if rsl != nil && len(rsl) > 0 && rsl[0].ID != 0 {
-			fmt.Printf("ini dari cache: debug")
-			return rsl, nil
-		}
-
-		result, err := us.userRepository.GetBooksByCategory(ctx, category, offset)
-		if err != nil {
-			return nil, utils.ValidateErrTw(err, "service - get_books_by_category: %w")
-		}
-		books := utils.BooksMapper(result)
+func (us *userService) GetBooksByAuthor(ctx context.Context, author string, query dto.BookQuery) ([]dto.Books, *dto.Meta, error) {
+	return us.listBooks(ctx, "get_books_by_author", "service - get_books_by_author: %w", "author:"+author, query, func(ctx context.Context, page pagination.Page) ([]entity.Book, int64, error) {
+		return us.userRepository.GetBooksByAuthor(ctx, author, page)
+	})
+}
 
-		us.userRepository.RedisWp(ctx, func(ctx context.Context) (interface{}, error) {
-			for _, i := range books {
-				for _, c := range category {
-					us.userRepository.RedisZS(ctx, keyCategory + c + strconv.Itoa(page), float64(time.Now().UnixNano()), i.ID)
-				}
-				us.userRepository.RedisHSET(ctx, keyBook + strconv.Itoa(i.ID), i)
-			}
-			return nil, nil
-		})
-		
-		return books, nil
+func (us *userService) GetBooksByCategory(ctx context.Context, category []string, query dto.BookQuery) ([]dto.Books, *dto.Meta, error) {
+	var sorted = slices.Clone(category)
+	slices.Sort(sorted)
+	return us.listBooks(ctx, "get_books_by_category", "service - get_books_by_category: %w", "category:"+strings.Join(sorted, ","), query, func(ctx context.Context, page pagination.Page) ([]entity.Book, int64, error) {
+		return us.userRepository.GetBooksByCategory(ctx, category, page)
 	})
-	if err != nil {
-		return nil, err
-	}
-	return result.([]dto.Books), nil
 }
 
 func (us *userService) Loan(ctx context.Context, loanInfo dto.Loan) error {
 	const errIntrnl = "service - loan: %w"
 	idUser, ok := ctx.Value(constanta.UI).(int)
 	if !ok {
-		return fmt.Errorf("please login")
+		return apperr.ErrLoginRequired
 	}
 	return us.userRepository.WithContext(ctx, func(ctx context.Context) error {
 		if err := us.userRepository.CheckLoan(ctx, loanInfo.ID, idUser); err != nil {
@@ -277,21 +311,44 @@ func (us *userService) Loan(ctx context.Context, loanInfo dto.Loan) error {
 			IdUser: idUser,
 			IdBook: loanInfo.ID,
 		}
-		if err := entityLoanData.ValidateDateFormat(loanInfo.ReturnedAt); err != nil {
+		if err := entityLoanData.ValidateDateFormat(loanInfo.ReturnedAt, us.clock.Location()); err != nil {
 			return err
 		}
-		if err := entityLoanData.ValidateDate(); err != nil {
+		if err := entityLoanData.ValidateDate(us.clock.Now()); err != nil {
 			return err
 		}
+		cal, err := calendar.Load(ctx, us.calendarRepository, entityLoanData.MustReturnedAt, entityLoanData.MustReturnedAt.AddDate(0, 0, calendar.Horizon))
+		if err != nil {
+			return utils.ValidateErrTw(err, errIntrnl)
+		}
+		entityLoanData.PushToOpenDay(cal)
 		if err := us.userRepository.CreateLoan(ctx, *entityLoanData); err != nil {
 			return utils.ValidateErrLoan(err, "")
 		}
-		if err := us.userRepository.UpdateBookStock(ctx, loanInfo.ID); err != nil {
+		if err := us.userRepository.UpdateBookStock(ctx, loanInfo.ID, idUser, us.clock.Now()); err != nil {
 			return utils.ValidateErrLoan(err, "book")
 		}
+		if err := us.userRepository.FulfillHold(ctx, loanInfo.ID, idUser, us.clock.Now()); err != nil {
+			return utils.ValidateErrTw(err, errIntrnl)
+		}
 		if err := us.userRepository.UpdateLimitLoan(ctx, idUser); err != nil {
 			return utils.ValidateErrLoan(err, "user")
 		}
+		if err := us.record(ctx, audit.ActionLoan, "loan", fmt.Sprintf("%d:%d", idUser, loanInfo.ID), nil, map[string]any{
+			"is_returned":      false,
+			"must_returned_at": entityLoanData.MustReturnedAt,
+			"stock_change":     -1,
+			"max_book_change":  1,
+		}); err != nil {
+			return utils.ValidateErrTw(err, errIntrnl)
+		}
+		if err := us.publish(ctx, outbox.EventLoanCreated, "loan", fmt.Sprintf("%d:%d", idUser, loanInfo.ID), map[string]any{
+			"id_user":          idUser,
+			"id_book":          loanInfo.ID,
+			"must_returned_at": entityLoanData.MustReturnedAt,
+		}); err != nil {
+			return utils.ValidateErrTw(err, errIntrnl)
+		}
 		return nil
 	})
 }
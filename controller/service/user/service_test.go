@@ -6,26 +6,47 @@ import (
 	"testing"
 	"time"
 
+	"stmnplibrary/apperr"
+	"stmnplibrary/clock"
+	"stmnplibrary/config"
 	"stmnplibrary/domain/entity"
 	"stmnplibrary/domain/interface/service"
 	"stmnplibrary/dto"
+	"stmnplibrary/log"
 	"stmnplibrary/constanta"
 	"stmnplibrary/mocks"
+	"stmnplibrary/outbox"
+	"stmnplibrary/pagination"
 	token "stmnplibrary/security/jwt"
-	"stmnplibrary/security"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
 )
 
-func setupUser(t *testing.T) (*mocks.UserRepository, service.UserService) {
+// testNow is the time of the fake clock, 22:00 in Jakarta.
+var testNow = time.Date(2026, 8, 14, 22, 0, 0, 0, time.FixedZone("WIB", 7*60*60))
+
+var testJWT = token.FnJWT(config.JWT{SecretKey: "secret", AccessTTL: 10 * time.Minute, RefreshTTL: 120 * time.Hour})
+
+func setupUser(t *testing.T) (*mocks.UserRepository, *mocks.AuditRepository, *mocks.OutboxRepository, service.UserService) {
 	repo := mocks.NewUserRepository(t)
-	svc := FnUserService(repo)
-	return repo, svc
+	auditRepo := mocks.NewAuditRepository(t)
+	outboxRepo := mocks.NewOutboxRepository(t)
+	svc := newUserService(repo, auditRepo, outboxRepo, openCalendar(t), clock.NewFake(testNow), config.Degrade{RateLimitPolicy: "fallback", BlacklistPolicy: "closed", LocalCacheSize: 10, LocalCacheTTL: time.Minute}, testJWT)
+	return repo, auditRepo, outboxRepo, svc
+}
+
+// openCalendar is a library open every day.
+func openCalendar(t *testing.T) *mocks.CalendarRepository {
+	calRepo := mocks.NewCalendarRepository(t)
+	calRepo.On("GetOpeningHours", mock.Anything).Return([]entity.OpeningHours(nil), nil).Maybe()
+	calRepo.On("GetClosures", mock.Anything, mock.Anything, mock.Anything).Return([]entity.Closure(nil), nil).Maybe()
+	return calRepo
 }
 
 func TestRegister(t *testing.T) {
-	repo, svc := setupUser(t)
+	repo, auditRepo, _, svc := setupUser(t)
 	ctx := context.Background()
 
 	tests := []struct {
@@ -45,7 +66,11 @@ func TestRegister(t *testing.T) {
 			mockSetup: func() {
 				repo.On("GetNIS", ctx, 111).Return(nil).Once()
 				repo.On("GetEmail", ctx, "u@m.com").Return(nil).Once()
+				repo.On("WithContext", ctx, mock.Anything).Return(func(ctx context.Context, fn func(context.Context) error) error {
+					return fn(ctx)
+				}).Once()
 				repo.On("Register", ctx, mock.Anything).Return(nil).Once()
+				auditRepo.On("Record", ctx, mock.Anything).Return(nil).Once()
 			},
 			expectMsg: false,
 			expectErr: false,
@@ -72,83 +97,100 @@ func TestRegister(t *testing.T) {
 	}
 }
 
-func TestLogin(t *testing.T) {
-	repo, svc := setupUser(t)
-	ctx := context.Background()
-
-	t.Run("Success", func(t *testing.T) {
-		password := "password123"
-		hashedPassword, _ := security.HashPassword(password)
-
-		repo.On("GetId", ctx, 123).Return(1, nil).Once()
-		repo.On("GetPassword", ctx, 123).Return(hashedPassword, nil).Once()
-		repo.On("RedisSet", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
-
-		res, err := svc.Login(ctx, dto.Login{NIS: 123, Password: password})
-		
-		assert.NoError(t, err)
-		assert.NotNil(t, res)
-	})
-}
-
 func TestGetBooks(t *testing.T) {
-	repo, svc := setupUser(t)
+	repo, _, _, svc := setupUser(t)
 	ctx := context.Background()
+	first := pagination.Page{Sort: pagination.SortTitle, Size: pagination.DefaultSize}
+	key := "stmnplibrary:books:all:" + first.Key()
 
 	t.Run("Cache_Hit", func(t *testing.T) {
-		repo.On("RedisZR", ctx, mock.Anything, 0, 34).Return([]string{"1"}, nil).Once()
-		repo.On("RedisWp", ctx, mock.Anything).Return([]dto.Books{{ID: 1}}, nil).Once()
+		cached := []byte(`{"books":[{"book_id":1}],"meta":{"page_size":35,"sort":"title","total":36,"has_next":true,"next_cursor":"abc"}}`)
+		repo.On("RedisGet", ctx, key).Return(cached, nil).Once()
 
-		res, err := svc.GetBooks(ctx, 1)
+		res, meta, err := svc.GetBooks(ctx, dto.BookQuery{})
 		assert.NoError(t, err)
 		assert.Len(t, res, 1)
+		assert.Equal(t, &dto.Meta{PageSize: 35, Sort: "title", Total: 36, HasNext: true, NextCursor: "abc"}, meta)
 	})
 
 	t.Run("Cache_Miss_DB_Success", func(t *testing.T) {
-		repo.On("RedisZR", ctx, mock.Anything, 0, 34).Return([]string{}, nil).Once()
-		repo.On("RedisWp", ctx, mock.Anything).Return([]dto.Books{}, nil).Once()
-		repo.On("GetBooks", ctx, 0).Return([]entity.Book{{ID: 10}}, nil).Once()
-		repo.On("RedisWp", ctx, mock.Anything).Return(nil, nil).Once()
+		repo.On("RedisGet", ctx, key).Return(nil, apperr.ErrNotFound).Once()
+		repo.On("GetBooks", ctx, first).Return([]entity.Book{{ID: 10}}, int64(1), nil).Once()
+		repo.On("RedisSet", ctx, key, mock.Anything, 5*time.Minute).Return(nil).Once()
+
+		res, meta, err := svc.GetBooks(ctx, dto.BookQuery{})
+		assert.NoError(t, err)
+		assert.Equal(t, 10, res[0].ID)
+		assert.Equal(t, &dto.Meta{PageSize: 35, Sort: "title", Total: 1, HasNext: false}, meta)
+	})
 
-		res, err := svc.GetBooks(ctx, 1)
+	t.Run("Redis_Down_Local_Cache", func(t *testing.T) {
+		log.LogInit(zap.NewNop())
+		repo.On("RedisGet", ctx, key).Return(nil, apperr.Unavailable(errors.New("redis circuit breaker is open"))).Once()
+
+		res, meta, err := svc.GetBooks(ctx, dto.BookQuery{})
 		assert.NoError(t, err)
 		assert.Equal(t, 10, res[0].ID)
+		assert.Equal(t, int64(1), meta.Total)
+	})
+
+	t.Run("Next_Cursor", func(t *testing.T) {
+		page := pagination.Page{Sort: pagination.SortAvailability, Size: 2}
+		key := "stmnplibrary:books:all:" + page.Key()
+		repo.On("RedisGet", ctx, key).Return(nil, apperr.ErrNotFound).Once()
+		repo.On("GetBooks", ctx, page).Return([]entity.Book{{ID: 3, AvailableStock: 9}, {ID: 1, AvailableStock: 5}, {ID: 2, AvailableStock: 5}}, int64(3), nil).Once()
+		repo.On("RedisSet", ctx, key, mock.Anything, 5*time.Minute).Return(nil).Once()
+
+		res, meta, err := svc.GetBooks(ctx, dto.BookQuery{PageQuery: dto.PageQuery{PageSize: 2}, Sort: pagination.SortAvailability})
+		assert.NoError(t, err)
+		assert.Len(t, res, 2)
+		assert.True(t, meta.HasNext)
+		next, err := pagination.Decode(meta.NextCursor)
+		assert.NoError(t, err)
+		assert.Equal(t, &pagination.Cursor{Sort: pagination.SortAvailability, Key: "5", ID: 1}, next)
+	})
+
+	t.Run("Cursor_Of_Other_Sort", func(t *testing.T) {
+		cursor := pagination.Cursor{Sort: pagination.SortNewest, Key: "2025-01-01T00:00:00Z", ID: 1}.Encode()
+		_, _, err := svc.GetBooks(ctx, dto.BookQuery{PageQuery: dto.PageQuery{Cursor: cursor}, Sort: pagination.SortTitle})
+		assert.ErrorIs(t, err, pagination.ErrInvalidCursor)
 	})
 }
 
 func TestGetBooksByAuthor(t *testing.T) {
-	repo, svc := setupUser(t)
+	repo, _, _, svc := setupUser(t)
 	ctx := context.Background()
+	first := pagination.Page{Sort: pagination.SortTitle, Size: pagination.DefaultSize}
 
 	t.Run("Success_DB", func(t *testing.T) {
-		repo.On("RedisZR", ctx, mock.Anything, 0, 34).Return([]string{}, nil).Once()
-		repo.On("RedisWp", ctx, mock.Anything).Return([]dto.Books{}, nil).Once()
-		repo.On("GetBooksByAuthor", ctx, "Author A", 0).Return([]entity.Book{{ID: 5}}, nil).Once()
-		repo.On("RedisWp", ctx, mock.Anything).Return(nil, nil).Once()
+		repo.On("RedisGet", ctx, "stmnplibrary:books:author:Author A:"+first.Key()).Return(nil, apperr.ErrNotFound).Once()
+		repo.On("GetBooksByAuthor", ctx, "Author A", first).Return([]entity.Book{{ID: 5}}, int64(1), nil).Once()
+		repo.On("RedisSet", ctx, mock.Anything, mock.Anything, 5*time.Minute).Return(nil).Once()
 
-		res, err := svc.GetBooksByAuthor(ctx, "Author A", 1)
+		res, _, err := svc.GetBooksByAuthor(ctx, "Author A", dto.BookQuery{})
 		assert.NoError(t, err)
 		assert.Equal(t, 5, res[0].ID)
 	})
 }
 
 func TestGetBooksByCategory(t *testing.T) {
-	repo, svc := setupUser(t)
+	repo, _, _, svc := setupUser(t)
 	ctx := context.Background()
+	first := pagination.Page{Sort: pagination.SortTitle, Size: pagination.DefaultSize}
 
 	t.Run("Success", func(t *testing.T) {
-		repo.On("RedisWp", ctx, mock.Anything).Return([]dto.Books{}, nil).Once()
-		repo.On("GetBooksByCategory", ctx, []string{"Sains"}, 0).Return([]entity.Book{{ID: 9}}, nil).Once()
-		repo.On("RedisWp", ctx, mock.Anything).Return(nil, nil).Once()
+		repo.On("RedisGet", ctx, "stmnplibrary:books:category:Fiksi,Sains:"+first.Key()).Return(nil, apperr.ErrNotFound).Once()
+		repo.On("GetBooksByCategory", ctx, []string{"Sains", "Fiksi"}, first).Return([]entity.Book{{ID: 9}}, int64(1), nil).Once()
+		repo.On("RedisSet", ctx, mock.Anything, mock.Anything, 5*time.Minute).Return(nil).Once()
 
-		res, err := svc.GetBooksByCategory(ctx, []string{"Sains"}, 1)
+		res, _, err := svc.GetBooksByCategory(ctx, []string{"Sains", "Fiksi"}, dto.BookQuery{})
 		assert.NoError(t, err)
 		assert.NotEmpty(t, res)
 	})
 }
 
 func TestLoan(t *testing.T) {
-	repo, svc := setupUser(t)
+	repo, auditRepo, outboxRepo, svc := setupUser(t)
 	ctx := context.WithValue(context.Background(), constanta.UI, 1)
 
 	tests := []struct {
@@ -159,15 +201,20 @@ func TestLoan(t *testing.T) {
 	}{
 		{
 			name: "Success",
-			input: dto.Loan{ID: 10, ReturnedAt: time.Now().AddDate(0, 0, 2).Format("02-01-2006")},
+			input: dto.Loan{ID: 10, ReturnedAt: testNow.AddDate(0, 0, 2).Format("02-01-2006")},
 			mockSetup: func() {
 				repo.On("WithContext", ctx, mock.Anything).Return(func(ctx context.Context, fn func(context.Context) error) error {
 					return fn(ctx)
 				}).Once()
 				repo.On("CheckLoan", ctx, 10, 1).Return(nil).Once()
 				repo.On("CreateLoan", ctx, mock.Anything).Return(nil).Once()
-				repo.On("UpdateBookStock", ctx, 10).Return(nil).Once()
+				repo.On("UpdateBookStock", ctx, 10, 1, mock.Anything).Return(nil).Once()
+				repo.On("FulfillHold", ctx, 10, 1, mock.Anything).Return(nil).Once()
 				repo.On("UpdateLimitLoan", ctx, 1).Return(nil).Once()
+				auditRepo.On("Record", ctx, mock.Anything).Return(nil).Once()
+				outboxRepo.On("Add", ctx, mock.MatchedBy(func(e entity.Outbox) bool {
+					return e.EventType == outbox.EventLoanCreated && e.AggregateID == "1:10"
+				})).Return(nil).Once()
 			},
 			expectErr: false,
 		},
@@ -193,47 +240,115 @@ func TestLoan(t *testing.T) {
 	}
 }
 
+func TestLoan_Due_On_Holiday(t *testing.T) {
+	repo, auditRepo, outboxRepo := mocks.NewUserRepository(t), mocks.NewAuditRepository(t), mocks.NewOutboxRepository(t)
+	calRepo := mocks.NewCalendarRepository(t)
+	svc := newUserService(repo, auditRepo, outboxRepo, calRepo, clock.NewFake(testNow), config.Degrade{LocalCacheSize: 10, LocalCacheTTL: time.Minute}, testJWT)
+	ctx := context.WithValue(context.Background(), constanta.UI, 1)
+	due := clock.EndOfDay(testNow.AddDate(0, 0, 2))
+
+	calRepo.On("GetOpeningHours", mock.Anything).Return([]entity.OpeningHours(nil), nil).Once()
+	calRepo.On("GetClosures", mock.Anything, due, due.AddDate(0, 0, 62)).Return([]entity.Closure{
+		{Name: "Hari Raya", Kind: entity.ClosureHoliday, StartDate: due, EndDate: due.AddDate(0, 0, 1)},
+	}, nil).Once()
+	repo.On("WithContext", ctx, mock.Anything).Return(func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	}).Once()
+	repo.On("CheckLoan", ctx, 10, 1).Return(nil).Once()
+	repo.On("CreateLoan", ctx, mock.MatchedBy(func(l entity.Loan) bool {
+		return l.MustReturnedAt.Equal(due.AddDate(0, 0, 2))
+	})).Return(nil).Once()
+	repo.On("UpdateBookStock", ctx, 10, 1, mock.Anything).Return(nil).Once()
+	repo.On("FulfillHold", ctx, 10, 1, mock.Anything).Return(nil).Once()
+	repo.On("UpdateLimitLoan", ctx, 1).Return(nil).Once()
+	auditRepo.On("Record", ctx, mock.Anything).Return(nil).Once()
+	outboxRepo.On("Add", ctx, mock.Anything).Return(nil).Once()
+
+	err := svc.Loan(ctx, dto.Loan{ID: 10, ReturnedAt: due.Format(time.DateOnly)})
+	assert.NoError(t, err)
+}
+
+func TestLoan_Due_Today(t *testing.T) {
+	repo, auditRepo, outboxRepo, svc := setupUser(t)
+	ctx := context.WithValue(context.Background(), constanta.UI, 1)
+
+	// 22:00 in Jakarta is 15:00 in UTC, today ends at 23:59:59 in Jakarta
+	// which is 16:59:59 in UTC
+	repo.On("WithContext", ctx, mock.Anything).Return(func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	}).Once()
+	repo.On("CheckLoan", ctx, 10, 1).Return(nil).Once()
+	repo.On("CreateLoan", ctx, mock.MatchedBy(func(l entity.Loan) bool {
+		return l.MustReturnedAt.Equal(time.Date(2026, 8, 14, 16, 59, 59, 0, time.UTC))
+	})).Return(nil).Once()
+	repo.On("UpdateBookStock", ctx, 10, 1, mock.Anything).Return(nil).Once()
+	repo.On("FulfillHold", ctx, 10, 1, mock.Anything).Return(nil).Once()
+	repo.On("UpdateLimitLoan", ctx, 1).Return(nil).Once()
+	auditRepo.On("Record", ctx, mock.Anything).Return(nil).Once()
+	outboxRepo.On("Add", ctx, mock.Anything).Return(nil).Once()
+
+	err := svc.Loan(ctx, dto.Loan{ID: 10, ReturnedAt: "14-08-2026"})
+	assert.NoError(t, err)
+}
+
+// TestLoan_Copies_Held refuses the loan when every available copy is held for
+// other students, the hold check makes UpdateBookStock change no row.
+func TestLoan_Copies_Held(t *testing.T) {
+	repo, _, _, svc := setupUser(t)
+	ctx := context.WithValue(context.Background(), constanta.UI, 1)
+
+	repo.On("WithContext", ctx, mock.Anything).Return(func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	}).Once()
+	repo.On("CheckLoan", ctx, 10, 1).Return(nil).Once()
+	repo.On("CreateLoan", ctx, mock.Anything).Return(nil).Once()
+	repo.On("UpdateBookStock", ctx, 10, 1, testNow).Return(apperr.ErrNoDataAffected).Once()
+
+	err := svc.Loan(ctx, dto.Loan{ID: 10, ReturnedAt: "14-08-2026"})
+	assert.Equal(t, apperr.KindInvalid, apperr.KindOf(err))
+	assert.Equal(t, apperr.CodeOutOfStock, apperr.CodeOf(err))
+	repo.AssertNotCalled(t, "FulfillHold", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	repo.AssertNotCalled(t, "UpdateLimitLoan", mock.Anything, mock.Anything)
+}
+
 func TestLogout(t *testing.T) {
-	repo, svc := setupUser(t)
+	repo, auditRepo, _, svc := setupUser(t)
+	tkn, _ := testJWT.GenerateToken(1, "students")
 	ctx := context.WithValue(context.Background(), constanta.UI, 1)
-	ctx = context.WithValue(ctx, constanta.TokenA, "token-string")
+	ctx = context.WithValue(ctx, constanta.TokenA, tkn.AccessToken)
 
 	t.Run("Success", func(t *testing.T) {
 		repo.On("RedisWtx", ctx, mock.Anything).Return(func(ctx context.Context, fn func(context.Context) error) error {
 			return fn(ctx)
 		}).Once()
 		repo.On("RedisDel", ctx, mock.Anything).Return(nil).Once()
-		repo.On("RedisSet", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+		// blacklisted for what is left of the configured 10 minutes, not a
+		// fixed 3
+		repo.On("RedisSet", ctx, "blacklist:accesstoken:"+tkn.AccessToken, mock.Anything, mock.MatchedBy(func(ttl time.Duration) bool {
+			return ttl > 9*time.Minute && ttl <= 10*time.Minute
+		})).Return(nil).Once()
+		auditRepo.On("Record", ctx, mock.Anything).Return(nil).Once()
 
 		err := svc.Logout(ctx)
 		assert.NoError(t, err)
 	})
-}
 
-func TestRefresh(t *testing.T) {
-	repo, svc := setupUser(t)
-	ctx := context.Background()
-
-	t.Run("Success", func(t *testing.T) {
-		repo.On("RedisGet", ctx, mock.Anything).Return([]byte("valid-refresh-token"), nil).Once()
+	t.Run("Audit_Failed", func(t *testing.T) {
+		log.LogInit(zap.NewNop())
+		repo.On("RedisWtx", ctx, mock.Anything).Return(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		}).Once()
+		repo.On("RedisDel", ctx, mock.Anything).Return(nil).Once()
 		repo.On("RedisSet", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+		auditRepo.On("Record", ctx, mock.Anything).Return(apperr.Internal(errors.New("db down"))).Once()
 
-		validToken, _ := token.GenerateToken(1)
-		res, err := svc.Refresh(ctx, validToken.RefreshToken)
-
+		err := svc.Logout(ctx)
 		assert.NoError(t, err)
-		assert.NotNil(t, res)
-	})
-
-	t.Run("Fail_Invalid_Token", func(t *testing.T) {
-		res, err := svc.Refresh(ctx, "invalid")
-		assert.Error(t, err)
-		assert.Nil(t, res)
 	})
 }
 
 func TestCheckAccTkn(t *testing.T) {
-	repo, svc := setupUser(t)
+	repo, _, _, svc := setupUser(t)
 	ctx := context.Background()
 	t.Run("Blacklisted", func(t *testing.T) {
 		repo.On("RedisGet", ctx, mock.Anything).Return([]byte("blacklisted"), nil).Once()
@@ -243,4 +358,16 @@ func TestCheckAccTkn(t *testing.T) {
 		repo.On("RedisGet", ctx, mock.Anything).Return(nil, nil).Once()
 		err := svc.CheckAccTkn(ctx, "tkn"); assert.NoError(t, err)
 	})
+	t.Run("Redis_Down_Fail_Closed", func(t *testing.T) {
+		log.LogInit(zap.NewNop())
+		repo.On("RedisGet", ctx, mock.Anything).Return(nil, apperr.Unavailable(errors.New("redis circuit breaker is open"))).Once()
+		err := svc.CheckAccTkn(ctx, "tkn"); assert.ErrorContains(t, err, "service unavailable")
+	})
+	t.Run("Redis_Down_Fail_Open", func(t *testing.T) {
+		log.LogInit(zap.NewNop())
+		repo := mocks.NewUserRepository(t)
+		svc := newUserService(repo, mocks.NewAuditRepository(t), mocks.NewOutboxRepository(t), openCalendar(t), clock.NewFake(testNow), config.Degrade{BlacklistPolicy: "open", LocalCacheSize: 10, LocalCacheTTL: time.Minute}, testJWT)
+		repo.On("RedisGet", ctx, mock.Anything).Return(nil, apperr.Internal(errors.New("dial tcp: connection refused"))).Once()
+		err := svc.CheckAccTkn(ctx, "tkn"); assert.NoError(t, err)
+	})
 }
\ No newline at end of file
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+
+	"stmnplibrary/domain/interface/service"
+	"stmnplibrary/dto"
+	"stmnplibrary/tracing"
+)
+
+// tracedUserService wraps every UserService method in a span, failed calls are recorded on it.
+type tracedUserService struct {
+	next service.UserService
+}
+
+func (t *tracedUserService) RateLimiter(ctx context.Context, ip string) (err error) {
+	ctx, span := tracing.Start(ctx, "UserService.RateLimiter")
+	defer func() { tracing.End(span, err) }()
+	return t.next.RateLimiter(ctx, ip)
+}
+
+func (t *tracedUserService) CheckAccTkn(ctx context.Context, acc string) (err error) {
+	ctx, span := tracing.Start(ctx, "UserService.CheckAccTkn")
+	defer func() { tracing.End(span, err) }()
+	return t.next.CheckAccTkn(ctx, acc)
+}
+
+func (t *tracedUserService) Register(ctx context.Context, data *dto.Students) (result []string, err error) {
+	ctx, span := tracing.Start(ctx, "UserService.Register")
+	defer func() { tracing.End(span, err) }()
+	return t.next.Register(ctx, data)
+}
+
+func (t *tracedUserService) Logout(ctx context.Context) (err error) {
+	ctx, span := tracing.Start(ctx, "UserService.Logout")
+	defer func() { tracing.End(span, err) }()
+	return t.next.Logout(ctx)
+}
+
+func (t *tracedUserService) GetBooks(ctx context.Context, query dto.BookQuery) (result []dto.Books, meta *dto.Meta, err error) {
+	ctx, span := tracing.Start(ctx, "UserService.GetBooks")
+	defer func() { tracing.End(span, err) }()
+	return t.next.GetBooks(ctx, query)
+}
+
+func (t *tracedUserService) GetBooksByAuthor(ctx context.Context, author string, query dto.BookQuery) (result []dto.Books, meta *dto.Meta, err error) {
+	ctx, span := tracing.Start(ctx, "UserService.GetBooksByAuthor")
+	defer func() { tracing.End(span, err) }()
+	return t.next.GetBooksByAuthor(ctx, author, query)
+}
+
+func (t *tracedUserService) GetBooksByCategory(ctx context.Context, category []string, query dto.BookQuery) (result []dto.Books, meta *dto.Meta, err error) {
+	ctx, span := tracing.Start(ctx, "UserService.GetBooksByCategory")
+	defer func() { tracing.End(span, err) }()
+	return t.next.GetBooksByCategory(ctx, category, query)
+}
+
+func (t *tracedUserService) Loan(ctx context.Context, loanInfo dto.Loan) (err error) {
+	ctx, span := tracing.Start(ctx, "UserService.Loan")
+	defer func() { tracing.End(span, err) }()
+	return t.next.Loan(ctx, loanInfo)
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"stmnplibrary/clock"
+	"stmnplibrary/config"
+	"stmnplibrary/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracedUserService(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	repo := mocks.NewUserRepository(t)
+	svc := FnUserService(repo, mocks.NewAuditRepository(t), mocks.NewOutboxRepository(t), mocks.NewCalendarRepository(t), clock.NewFake(testNow), config.Degrade{RateLimitPolicy: "fallback", LocalCacheSize: 10, LocalCacheTTL: time.Minute}, testJWT)
+	// the repository runs under the span of the service call
+	traced := mock.MatchedBy(func(ctx context.Context) bool {
+		return trace.SpanFromContext(ctx).SpanContext().IsValid()
+	})
+
+	repo.On("RateLimiter", traced, "rate-limiter:ip:10.0.0.1").Return(nil).Once()
+	assert.NoError(t, svc.RateLimiter(context.Background(), "10.0.0.1"))
+	repo.On("RedisGet", traced, "blacklist:accesstoken:tkn").Return([]byte("blacklisted"), nil).Once()
+	assert.Error(t, svc.CheckAccTkn(context.Background(), "tkn"))
+
+	ended := recorder.Ended()
+	require.Len(t, ended, 2)
+	assert.Equal(t, "UserService.RateLimiter", ended[0].Name())
+	assert.Equal(t, codes.Unset, ended[0].Status().Code)
+	assert.Equal(t, "UserService.CheckAccTkn", ended[1].Name())
+	assert.Equal(t, codes.Error, ended[1].Status().Code)
+}
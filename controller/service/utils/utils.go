@@ -1,40 +1,102 @@
 package utils
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"stmnplibrary/apperr"
+	"stmnplibrary/audit"
+	"stmnplibrary/constanta"
 	"stmnplibrary/domain/entity"
 	"stmnplibrary/dto"
+	"stmnplibrary/pagination"
+	"strconv"
 	"time"
 
 	"fmt"
-	"strings"
 )
 
-func ValidateErr(err error, subStr string, errMsg *[]string) error {
-	if strings.Contains(err.Error(), subStr) {
-		*errMsg = append(*errMsg, err.Error())
+// ValidateErr collects the public message of an error carrying code so it can
+// be reported next to the other validation messages.
+func ValidateErr(err error, code string, errMsg *[]string) error {
+	if e := apperr.From(err); e.Code == code {
+		*errMsg = append(*errMsg, e.Message)
 		return nil
 	}
 	return err
 }
 
+// ValidateErrTw adds the service operation to errors that end up in the logs,
+// client errors are returned untouched.
 func ValidateErrTw(err error, errMsg string) error {
-	if strings.Contains(err.Error(), "internal server error: ") || strings.Contains(err.Error(), "operation" ) {
+	switch apperr.KindOf(err) {
+	case apperr.KindInternal, apperr.KindUnavailable:
 		return fmt.Errorf(errMsg, err)
 	}
 	return err
 }
 
 func ValidateErrLoan(err error, opt string) error {
-	if strings.Contains(err.Error(), "no data affected") && opt == "user" {
-		return fmt.Errorf("has reached the limit")
+	if errors.Is(err, apperr.ErrNoDataAffected) && opt == "user" {
+		return apperr.New(apperr.KindInvalid, apperr.CodeLoanLimitReached, "has reached the limit")
 	}
-	if strings.Contains(err.Error(), "no data affected") && opt == "book" {
-		return fmt.Errorf("out of stock")
+	if errors.Is(err, apperr.ErrNoDataAffected) && opt == "book" {
+		return apperr.New(apperr.KindInvalid, apperr.CodeOutOfStock, "out of stock")
 	}
 	return ValidateErrTw(err, "service - loan: %w")
 }
 
+// NewMeta describes a keyset page, next is nil on the last page.
+func NewMeta(page pagination.Page, next *pagination.Cursor, total int64) *dto.Meta {
+	var meta = &dto.Meta{
+		PageSize: page.Size,
+		Sort:     page.Sort,
+		Total:    total,
+		HasNext:  next != nil,
+	}
+	if next != nil {
+		meta.NextCursor = next.Encode()
+	}
+	return meta
+}
+
+// BookKey returns the cursor key of a book for the sort of the page.
+func BookKey(sort string) func(entity.Book) (string, int) {
+	return func(b entity.Book) (string, int) {
+		switch sort {
+		case pagination.SortAuthor:
+			return b.Author, b.ID
+		case pagination.SortNewest:
+			return b.CreatedAt.Format(time.RFC3339Nano), b.ID
+		case pagination.SortAvailability:
+			return strconv.Itoa(b.AvailableStock), b.ID
+		case pagination.SortRating:
+			return strconv.FormatFloat(b.RatingAvg, 'f', 2, 64), b.ID
+		}
+		return b.Name, b.ID
+	}
+}
+
+func LoanKey(l entity.LoanData) (string, int) {
+	return l.BorrowAt.Format(time.RFC3339Nano), l.ID
+}
+
+func StudentKey(s entity.StudentData) (string, int) {
+	return strconv.Itoa(s.NIS), s.ID
+}
+
+func AuditKey(a entity.Audit) (string, int) {
+	return a.CreatedAt.Format(time.RFC3339Nano), a.ID
+}
+
+func DeliveryKey(d entity.WebhookDelivery) (string, int) {
+	return d.CreatedAt.Format(time.RFC3339Nano), d.ID
+}
+
+func ReviewKey(r entity.ReviewData) (string, int) {
+	return r.CreatedAt.Format(time.RFC3339Nano), r.ID
+}
+
 func BooksMapper(result []entity.Book) []dto.Books {
 	var books []dto.Books
 	for _, v := range result {
@@ -53,6 +115,8 @@ func BooksMapper(result []entity.Book) []dto.Books {
 			Description:    v.Description,
 			Categories:     categories,
 			AvailableStock: v.AvailableStock,
+			RatingAvg:      v.RatingAvg,
+			RatingCount:    v.RatingCount,
 		})
 	}
 	return books
@@ -62,17 +126,38 @@ func LoanDataMapper(ld []entity.LoanData) []dto.LoanData {
 	var loanData = make([]dto.LoanData, 0, 35)
 	for _, i := range ld {
 		var b dto.LoanData
+		b.ID = i.ID
 		b.BookName = i.BookName
 		b.BorrowAt = i.BorrowAt
 		b.MustReturnedAt = i.MustReturnedAt
 		b.ReturnedAt = i.ReturnedAt
 		b.Sanctions = i.Sanctions
+		b.Outcome = i.Outcome
+		b.Fee = i.Fee
 		b.StudentName = i.StudentName
 		loanData = append(loanData, b)
 	}
 	return loanData
 }
 
+func StudentDataMapper(sd []entity.StudentData) []dto.StudentData {
+	var students = make([]dto.StudentData, 0, len(sd))
+	for _, i := range sd {
+		students = append(students, dto.StudentData{
+			NIS:         i.NIS,
+			Name:        i.Name,
+			PhoneNumber: i.PhoneNumber,
+			Email:       i.Email,
+			Class:       i.Class,
+			SubClass:    i.SubClass,
+			Major:       i.Major,
+			Batch:       i.Batch,
+			IsActive:    i.IsActive,
+		})
+	}
+	return students
+}
+
 func UnMarshal(dataByte []byte, data any) error {
 	if err := json.Unmarshal(dataByte, data); err != nil {
 		return fmt.Errorf("failed unmarshal: %w", err)
@@ -88,14 +173,245 @@ func Marshal(data any) ([]byte, error) {
 	return val, nil
 }
 
-func InitLD(data *entity.LdUpdate) entity.LdUpdate {
+// InitLD starts the return of a loan at now, the due date is read in the
+// location of now so late days are counted in the library timezone.
+func InitLD(data *entity.LdUpdate, now time.Time) entity.LdUpdate {
 	var (
-		rA = time.Now()
+		rA = now
 		sT int64 = 0
 	)
 	return entity.LdUpdate{
-		MustReturnedAt: data.MustReturnedAt,
+		MustReturnedAt: data.MustReturnedAt.In(now.Location()),
 		Sanctions: &sT,
 		ReturnedAt: &rA,
 	}
-}
\ No newline at end of file
+}
+func NewAudit(ctx context.Context, action string, entityName string, entityID string, before any, after any) (entity.Audit, error) {
+	actor, _ := ctx.Value(constanta.UI).(int)
+	role, _ := ctx.Value(string(constanta.RL)).(string)
+	traceID, _ := ctx.Value(constanta.TI).(string)
+	b, a, d, err := audit.Diff(before, after)
+	if err != nil {
+		return entity.Audit{}, apperr.Internal(err)
+	}
+	return entity.Audit{
+		ActorID:   actor,
+		ActorRole: role,
+		Action:    action,
+		Entity:    entityName,
+		EntityID:  entityID,
+		Before:    b,
+		After:     a,
+		Diff:      d,
+		TraceID:   traceID,
+		IP:        audit.ClientIP(ctx),
+	}, nil
+}
+
+// NewEvent builds the outbox row of a domain event, it is written in the same
+// transaction as the change it describes.
+func NewEvent(ctx context.Context, eventType string, aggregate string, aggregateID string, payload any) (entity.Outbox, error) {
+	traceID, _ := ctx.Value(constanta.TI).(string)
+	p, err := Marshal(payload)
+	if err != nil {
+		return entity.Outbox{}, apperr.Internal(err)
+	}
+	return entity.Outbox{
+		EventType:   eventType,
+		Aggregate:   aggregate,
+		AggregateID: aggregateID,
+		Payload:     string(p),
+		TraceID:     traceID,
+		AvailableAt: time.Now(),
+	}, nil
+}
+
+func AuditMapper(data []entity.Audit) []dto.Audit {
+	var audits = make([]dto.Audit, 0, len(data))
+	for _, i := range data {
+		audits = append(audits, dto.Audit{
+			ID:        i.ID,
+			ActorID:   i.ActorID,
+			ActorRole: i.ActorRole,
+			Action:    i.Action,
+			Entity:    i.Entity,
+			EntityID:  i.EntityID,
+			Before:    json.RawMessage(i.Before),
+			After:     json.RawMessage(i.After),
+			Diff:      json.RawMessage(i.Diff),
+			TraceID:   i.TraceID,
+			IP:        i.IP,
+			CreatedAt: i.CreatedAt,
+		})
+	}
+	return audits
+}
+
+func WebhookMapper(data entity.WebhookSubscription) dto.Webhook {
+	var eventTypes []string
+	_ = json.Unmarshal([]byte(data.EventTypes), &eventTypes)
+	return dto.Webhook{
+		ID:         data.ID,
+		URL:        data.URL,
+		EventTypes: eventTypes,
+		Active:     data.Active,
+		CreatedAt:  data.CreatedAt,
+	}
+}
+
+func DeliveryMapper(data []entity.WebhookDelivery) []dto.WebhookDelivery {
+	var deliveries = make([]dto.WebhookDelivery, 0, len(data))
+	for _, i := range data {
+		deliveries = append(deliveries, dto.WebhookDelivery{
+			ID:             i.ID,
+			SubscriptionID: i.SubscriptionID,
+			EventID:        i.EventID,
+			EventType:      i.EventType,
+			Status:         i.Status,
+			Attempts:       i.Attempts,
+			ResponseStatus: i.ResponseStatus,
+			ResponseBody:   i.ResponseBody,
+			LastError:      i.LastError,
+			NextAttemptAt:  i.NextAttemptAt,
+			DeliveredAt:    i.DeliveredAt,
+			CreatedAt:      i.CreatedAt,
+		})
+	}
+	return deliveries
+}
+
+func OpeningHoursMapper(data []entity.OpeningHours) []dto.OpeningHours {
+	var hours = make([]dto.OpeningHours, 0, len(data))
+	for _, i := range data {
+		hours = append(hours, dto.OpeningHours{
+			Weekday:  i.Weekday,
+			IsOpen:   i.IsOpen,
+			OpensAt:  i.OpensAt,
+			ClosesAt: i.ClosesAt,
+		})
+	}
+	return hours
+}
+
+func ClosureMapper(data entity.Closure) dto.Closure {
+	return dto.Closure{
+		ID:        data.ID,
+		Name:      data.Name,
+		Kind:      data.Kind,
+		StartDate: data.StartDate.Format(time.DateOnly),
+		EndDate:   data.EndDate.Format(time.DateOnly),
+		Imported:  data.UID != nil,
+	}
+}
+
+func ReviewMapper(data entity.ReviewData) dto.Review {
+	return dto.Review{
+		ID:           data.ID,
+		BookID:       data.IdBook,
+		BookName:     data.BookName,
+		StudentName:  data.StudentName,
+		Rating:       data.Rating,
+		Body:         data.Body,
+		Status:       data.Status,
+		HiddenReason: data.HiddenReason,
+		CreatedAt:    data.CreatedAt,
+		UpdatedAt:    data.UpdatedAt,
+	}
+}
+
+func ReviewsMapper(data []entity.ReviewData) []dto.Review {
+	var reviews = make([]dto.Review, 0, len(data))
+	for _, r := range data {
+		reviews = append(reviews, ReviewMapper(r))
+	}
+	return reviews
+}
+
+func RecommendationsMapper(data []entity.Candidate) []dto.Recommendation {
+	var books = make([]dto.Recommendation, 0, len(data))
+	for _, c := range data {
+		books = append(books, dto.Recommendation{
+			ID:             c.ID,
+			Name:           c.Name,
+			Author:         c.Author,
+			Publisher:      c.Publisher,
+			AvailableStock: c.AvailableStock,
+			RatingAvg:      c.RatingAvg,
+			Score:          c.Score(),
+			Reasons:        c.Reasons(),
+		})
+	}
+	return books
+}
+
+func ReadingListKey(r entity.ReadingList) (string, int) {
+	return r.CreatedAt.Format(time.RFC3339Nano), r.ID
+}
+
+func ReadingListMapper(data entity.ReadingList) dto.ReadingList {
+	var books = make([]dto.ReadingListBook, 0, len(data.Books))
+	for _, b := range data.Books {
+		books = append(books, dto.ReadingListBook{
+			ID:       b.IdBook,
+			Position: b.Position,
+			Name:     b.Name,
+			Author:   b.Author,
+		})
+	}
+	return dto.ReadingList{
+		ID:          data.ID,
+		Title:       data.Title,
+		Description: data.Description,
+		Class:       data.Class,
+		SubClass:    data.SubClass,
+		Major:       data.Major,
+		StartsAt:    data.StartsAt.Format(time.DateOnly),
+		DueAt:       data.DueAt.Format(time.DateOnly),
+		CreatedBy:   data.CreatedBy,
+		Books:       books,
+		CreatedAt:   data.CreatedAt,
+		UpdatedAt:   data.UpdatedAt,
+	}
+}
+
+func ReadingListsMapper(data []entity.ReadingList) []dto.ReadingList {
+	var lists = make([]dto.ReadingList, 0, len(data))
+	for _, r := range data {
+		lists = append(lists, ReadingListMapper(r))
+	}
+	return lists
+}
+
+func SuggestionKey(sort string) func(s entity.SuggestionData) (string, int) {
+	return func(s entity.SuggestionData) (string, int) {
+		if sort == pagination.SortNewest {
+			return s.CreatedAt.Format(time.RFC3339Nano), s.ID
+		}
+		return strconv.Itoa(s.Votes), s.ID
+	}
+}
+
+func SuggestionMapper(data entity.SuggestionData) dto.Suggestion {
+	return dto.Suggestion{
+		ID:          data.ID,
+		Title:       data.Title,
+		Author:      data.Author,
+		ISBN:        data.ISBN,
+		Status:      data.Status,
+		Votes:       data.Votes,
+		Voted:       data.Voted,
+		Note:        data.Note,
+		BookID:      data.IdBook,
+		StudentName: data.StudentName,
+		CreatedAt:   data.CreatedAt,
+		UpdatedAt:   data.UpdatedAt,
+	}
+}
+
+func SuggestionsMapper(data []entity.SuggestionData) []dto.Suggestion {
+	var suggestions = make([]dto.Suggestion, 0, len(data))
+	for _, s := range data {
+		suggestions = append(suggestions, SuggestionMapper(s))
+	}
+	return suggestions
+}
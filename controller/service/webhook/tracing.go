@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+
+	"stmnplibrary/domain/interface/service"
+	"stmnplibrary/dto"
+	"stmnplibrary/tracing"
+)
+
+// tracedWebhookService wraps every WebhookService method in a span, failed calls are recorded on it.
+type tracedWebhookService struct {
+	next service.WebhookService
+}
+
+func (t *tracedWebhookService) AddWebhook(ctx context.Context, data dto.AddWebhook) (result *dto.Webhook, err error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.AddWebhook")
+	defer func() { tracing.End(span, err) }()
+	return t.next.AddWebhook(ctx, data)
+}
+
+func (t *tracedWebhookService) GetWebhooks(ctx context.Context) (result []dto.Webhook, err error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.GetWebhooks")
+	defer func() { tracing.End(span, err) }()
+	return t.next.GetWebhooks(ctx)
+}
+
+func (t *tracedWebhookService) DeleteWebhook(ctx context.Context, id int) (err error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.DeleteWebhook")
+	defer func() { tracing.End(span, err) }()
+	return t.next.DeleteWebhook(ctx, id)
+}
+
+func (t *tracedWebhookService) GetDeliveries(ctx context.Context, id int, filter dto.DeliveryFilter) (result []dto.WebhookDelivery, meta *dto.Meta, err error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.GetDeliveries")
+	defer func() { tracing.End(span, err) }()
+	return t.next.GetDeliveries(ctx, id, filter)
+}
+
+func (t *tracedWebhookService) ReplayDelivery(ctx context.Context, id int) (err error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.ReplayDelivery")
+	defer func() { tracing.End(span, err) }()
+	return t.next.ReplayDelivery(ctx, id)
+}
+
+func (t *tracedWebhookService) Consume(ctx context.Context) (result int, err error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.Consume")
+	defer func() { tracing.End(span, err) }()
+	return t.next.Consume(ctx)
+}
+
+func (t *tracedWebhookService) Dispatch(ctx context.Context) (result int, err error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.Dispatch")
+	defer func() { tracing.End(span, err) }()
+	return t.next.Dispatch(ctx)
+}
@@ -0,0 +1,333 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"stmnplibrary/apperr"
+	"stmnplibrary/audit"
+	"stmnplibrary/config"
+	"stmnplibrary/constanta"
+	"stmnplibrary/controller/service/utils"
+	"stmnplibrary/domain/entity"
+	"stmnplibrary/domain/interface/repository"
+	"stmnplibrary/domain/interface/service"
+	"stmnplibrary/dto"
+	"stmnplibrary/metrics"
+	"stmnplibrary/pagination"
+	"stmnplibrary/webhook"
+	"stmnplibrary/worker"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// maxResponseBody is how much of a receiver response is kept in the log.
+const maxResponseBody = 1024
+
+type webhookService struct {
+	webhookRepository repository.WebhookRepository
+	auditRepository   repository.AuditRepository
+	stream            string
+	cfg               config.Webhook
+	consumer          string
+	client            *http.Client
+	groupReady        atomic.Bool
+	now               func() time.Time
+}
+
+func FnWebhookService(repository repository.WebhookRepository, auditRepository repository.AuditRepository, outboxCfg config.Outbox, cfg config.Webhook) service.WebhookService {
+	return &tracedWebhookService{
+		next: newWebhookService(repository, auditRepository, outboxCfg, cfg),
+	}
+}
+
+func newWebhookService(repository repository.WebhookRepository, auditRepository repository.AuditRepository, outboxCfg config.Outbox, cfg config.Webhook) *webhookService {
+	consumer := cfg.Consumer
+	if consumer == "" {
+		consumer, _ = os.Hostname()
+	}
+	return &webhookService{
+		webhookRepository: repository,
+		auditRepository:   auditRepository,
+		stream:            outboxCfg.Stream,
+		cfg:               cfg,
+		consumer:          consumer,
+		client:            &http.Client{Timeout: cfg.Timeout},
+		now:               time.Now,
+	}
+}
+
+func (ws *webhookService) record(ctx context.Context, action string, entityName string, entityID string, before any, after any) error {
+	data, err := utils.NewAudit(ctx, action, entityName, entityID, before, after)
+	if err != nil {
+		return err
+	}
+	return ws.auditRepository.Record(ctx, data)
+}
+
+func newSecret() (string, error) {
+	var b = make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", apperr.Internal(err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func (ws *webhookService) AddWebhook(ctx context.Context, data dto.AddWebhook) (*dto.Webhook, error) {
+	const errMsg = "service - add_webhook: %w"
+	var secret = data.Secret
+	if secret == "" {
+		var err error
+		if secret, err = newSecret(); err != nil {
+			return nil, utils.ValidateErrTw(err, errMsg)
+		}
+	}
+	eventTypes, err := utils.Marshal(slices.Compact(slices.Sorted(slices.Values(data.EventTypes))))
+	if err != nil {
+		return nil, utils.ValidateErrTw(apperr.Internal(err), errMsg)
+	}
+	createdBy, _ := ctx.Value(constanta.UI).(int)
+	var entityData = entity.WebhookSubscription{
+		URL:        data.URL,
+		Secret:     secret,
+		EventTypes: string(eventTypes),
+		Active:     true,
+		CreatedBy:  createdBy,
+	}
+	err = ws.webhookRepository.WithTx(ctx, func(ctx context.Context) error {
+		if err := ws.webhookRepository.AddSubscription(ctx, &entityData); err != nil {
+			return err
+		}
+		// the secret stays out of the audit log
+		return ws.record(ctx, audit.ActionCreate, "webhook", strconv.Itoa(entityData.ID), nil, utils.WebhookMapper(entityData))
+	})
+	if err != nil {
+		return nil, utils.ValidateErrTw(err, errMsg)
+	}
+	result := utils.WebhookMapper(entityData)
+	result.Secret = secret
+	return &result, nil
+}
+
+func (ws *webhookService) GetWebhooks(ctx context.Context) ([]dto.Webhook, error) {
+	data, err := ws.webhookRepository.GetSubscriptions(ctx, false)
+	if err != nil {
+		return nil, utils.ValidateErrTw(err, "service - get_webhooks: %w")
+	}
+	var webhooks = make([]dto.Webhook, 0, len(data))
+	for _, v := range data {
+		webhooks = append(webhooks, utils.WebhookMapper(v))
+	}
+	return webhooks, nil
+}
+
+// DeleteWebhook deactivates the subscription, its delivery log is kept and
+// pending deliveries are no longer sent.
+func (ws *webhookService) DeleteWebhook(ctx context.Context, id int) error {
+	const errMsg = "service - delete_webhook: %w"
+	return ws.webhookRepository.WithTx(ctx, func(ctx context.Context) error {
+		if err := ws.webhookRepository.DeactivateSubscription(ctx, id); err != nil {
+			return utils.ValidateErrTw(err, errMsg)
+		}
+		if err := ws.record(ctx, audit.ActionDeactivate, "webhook", strconv.Itoa(id), map[string]any{"active": true}, map[string]any{"active": false}); err != nil {
+			return utils.ValidateErrTw(err, errMsg)
+		}
+		return nil
+	})
+}
+
+func (ws *webhookService) GetDeliveries(ctx context.Context, id int, filter dto.DeliveryFilter) ([]dto.WebhookDelivery, *dto.Meta, error) {
+	page, err := pagination.New(filter.Cursor, filter.PageSize, pagination.SortNewest)
+	if err != nil {
+		return nil, nil, err
+	}
+	data, total, err := ws.webhookRepository.GetDeliveries(ctx, id, filter.Status, page)
+	if err != nil {
+		return nil, nil, utils.ValidateErrTw(err, "service - get_deliveries: %w")
+	}
+	data, next := pagination.Trim(data, page, utils.DeliveryKey)
+	return utils.DeliveryMapper(data), utils.NewMeta(page, next, total), nil
+}
+
+// ReplayDelivery sends a delivery again from its first attempt, whatever its
+// status, with the body it was first sent with.
+func (ws *webhookService) ReplayDelivery(ctx context.Context, id int) error {
+	const errMsg = "service - replay_delivery: %w"
+	return ws.webhookRepository.WithTx(ctx, func(ctx context.Context) error {
+		if err := ws.webhookRepository.ResetDelivery(ctx, id); err != nil {
+			return utils.ValidateErrTw(err, errMsg)
+		}
+		if err := ws.record(ctx, audit.ActionReplay, "webhook_delivery", strconv.Itoa(id), nil, map[string]any{"status": webhook.StatusPending}); err != nil {
+			return utils.ValidateErrTw(err, errMsg)
+		}
+		return nil
+	})
+}
+
+// Consume reads one batch of outbox events from the stream and adds a
+// delivery for every active subscription to the event type. Events are only
+// acked once their deliveries are stored, the events of a consumer that died
+// are claimed by another one after ClaimIdle.
+func (ws *webhookService) Consume(ctx context.Context) (int, error) {
+	if !ws.groupReady.Load() {
+		if err := ws.webhookRepository.CreateGroup(ctx, ws.stream, ws.cfg.Group); err != nil {
+			return 0, err
+		}
+		ws.groupReady.Store(true)
+	}
+	events, err := ws.webhookRepository.ReadEvents(ctx, ws.stream, ws.cfg.Group, ws.consumer, ws.cfg.BatchSize, ws.cfg.ClaimIdle)
+	if err != nil || len(events) == 0 {
+		return 0, err
+	}
+	subscriptions, err := ws.webhookRepository.GetSubscriptions(ctx, true)
+	if err != nil {
+		return 0, err
+	}
+	var (
+		deliveries = make([]entity.WebhookDelivery, 0, len(events))
+		ids        = make([]string, 0, len(events))
+		now        = ws.now()
+	)
+	for _, event := range events {
+		ids = append(ids, event.StreamID)
+		body, err := eventBody(event)
+		if err != nil {
+			return 0, err
+		}
+		for _, s := range subscriptions {
+			if !subscribed(s, event.Type) {
+				continue
+			}
+			deliveries = append(deliveries, entity.WebhookDelivery{
+				SubscriptionID: s.ID,
+				EventID:        event.ID,
+				EventType:      event.Type,
+				Payload:        string(body),
+				Status:         webhook.StatusPending,
+				NextAttemptAt:  now,
+			})
+		}
+	}
+	if len(deliveries) > 0 {
+		if err := ws.webhookRepository.AddDeliveries(ctx, deliveries); err != nil {
+			return 0, err
+		}
+	}
+	if err := ws.webhookRepository.AckEvents(ctx, ws.stream, ws.cfg.Group, ids); err != nil {
+		return 0, err
+	}
+	return len(events), nil
+}
+
+func eventBody(event entity.StreamEvent) ([]byte, error) {
+	var data = json.RawMessage(event.Payload)
+	if !json.Valid(data) {
+		data = json.RawMessage("null")
+	}
+	body, err := utils.Marshal(dto.WebhookEvent{
+		ID:          event.ID,
+		Type:        event.Type,
+		Aggregate:   event.Aggregate,
+		AggregateID: event.AggregateID,
+		CreatedAt:   event.CreatedAt,
+		Data:        data,
+	})
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return body, nil
+}
+
+func subscribed(s entity.WebhookSubscription, eventType string) bool {
+	var eventTypes []string
+	if err := json.Unmarshal([]byte(s.EventTypes), &eventTypes); err != nil {
+		return false
+	}
+	return slices.Contains(eventTypes, eventType)
+}
+
+// Dispatch sends one batch of due deliveries. The claim holds them for long
+// enough that every request of the batch can time out, so a delivery is only
+// sent twice when a dispatcher dies in the middle of a batch.
+func (ws *webhookService) Dispatch(ctx context.Context) (int, error) {
+	lease := ws.cfg.Timeout * time.Duration(ws.cfg.BatchSize+1)
+	deliveries, err := ws.webhookRepository.ClaimDeliveries(ctx, ws.cfg.BatchSize, lease)
+	if err != nil {
+		return 0, err
+	}
+	for _, d := range deliveries {
+		if ctx.Err() != nil {
+			// the lease runs out and the next dispatcher takes it
+			break
+		}
+		result := ws.attempt(ctx, d)
+		if err := ws.webhookRepository.SaveAttempt(ctx, result); err != nil {
+			return 0, err
+		}
+	}
+	return len(deliveries), nil
+}
+
+// attempt posts the delivery once and returns it with the outcome, a failed
+// one is retried with backoff and marked dead after MaxAttempts.
+func (ws *webhookService) attempt(ctx context.Context, d entity.DueDelivery) entity.WebhookDelivery {
+	var (
+		result = d.WebhookDelivery
+		now    = ws.now()
+	)
+	result.Attempts++
+	status, body, err := ws.send(ctx, d, now)
+	result.ResponseStatus = status
+	result.ResponseBody = body
+	if err == nil && status >= 200 && status < 300 {
+		result.Status = webhook.StatusSucceeded
+		result.LastError = ""
+		result.DeliveredAt = &now
+		metrics.WebhookDeliveries.WithLabelValues(d.EventType, result.Status).Inc()
+		return result
+	}
+	if err != nil {
+		result.LastError = err.Error()
+	} else {
+		result.LastError = fmt.Sprintf("receiver responded %d", status)
+	}
+	result.Status = webhook.StatusFailed
+	result.NextAttemptAt = now.Add(worker.Backoff(ws.cfg.RetryBackoff, ws.cfg.RetryBackoffMax, result.Attempts))
+	if result.Attempts >= ws.cfg.MaxAttempts {
+		result.Status = webhook.StatusDead
+	}
+	metrics.WebhookDeliveries.WithLabelValues(d.EventType, result.Status).Inc()
+	return result
+}
+
+func (ws *webhookService) send(ctx context.Context, d entity.DueDelivery, now time.Time) (int, string, error) {
+	var (
+		body      = []byte(d.Payload)
+		timestamp = strconv.FormatInt(now.Unix(), 10)
+	)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "stmnplibrary-webhook")
+	req.Header.Set(webhook.HeaderID, strconv.Itoa(d.ID))
+	req.Header.Set(webhook.HeaderEvent, d.EventType)
+	req.Header.Set(webhook.HeaderTimestamp, timestamp)
+	req.Header.Set(webhook.HeaderSignature, webhook.Sign(d.Secret, timestamp, body))
+	res, err := ws.client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer res.Body.Close()
+	resBody, _ := io.ReadAll(io.LimitReader(res.Body, maxResponseBody))
+	return res.StatusCode, string(resBody), nil
+}
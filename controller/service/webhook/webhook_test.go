@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"stmnplibrary/config"
+	"stmnplibrary/domain/entity"
+	"stmnplibrary/dto"
+	"stmnplibrary/mocks"
+	"stmnplibrary/webhook"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var testCfg = config.Webhook{
+	Group:           "webhooks",
+	Consumer:        "test",
+	ClaimIdle:       time.Minute,
+	BatchSize:       10,
+	Timeout:         time.Second,
+	MaxAttempts:     3,
+	RetryBackoff:    time.Second,
+	RetryBackoffMax: time.Minute,
+}
+
+func setup(t *testing.T) (*mocks.WebhookRepository, *mocks.AuditRepository, *webhookService, time.Time) {
+	repo := mocks.NewWebhookRepository(t)
+	auditRepo := mocks.NewAuditRepository(t)
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	svc := newWebhookService(repo, auditRepo, config.Outbox{Stream: "events"}, testCfg)
+	svc.now = func() time.Time { return now }
+	repo.On("WithTx", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	}).Maybe()
+	return repo, auditRepo, svc, now
+}
+
+// receiver is a school system checking the signature of every delivery and
+// answering with the given statuses in turn.
+func receiver(t *testing.T, secret string, statuses ...int) (*httptest.Server, *atomic.Int32) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.True(t, webhook.Verify(secret, r.Header.Get(webhook.HeaderTimestamp), body, r.Header.Get(webhook.HeaderSignature)))
+		assert.Equal(t, "LoanReturned", r.Header.Get(webhook.HeaderEvent))
+		var event dto.WebhookEvent
+		assert.NoError(t, json.Unmarshal(body, &event))
+		n := int(calls.Add(1))
+		w.WriteHeader(statuses[min(n, len(statuses))-1])
+		_, _ = w.Write([]byte("ok"))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func TestAddWebhook(t *testing.T) {
+	ctx := context.Background()
+	repo, auditRepo, svc, _ := setup(t)
+	repo.On("AddSubscription", mock.Anything, mock.MatchedBy(func(s *entity.WebhookSubscription) bool {
+		return s.Active && len(s.Secret) == 64 && s.EventTypes == `["LoanReturned","SanctionIssued"]`
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*entity.WebhookSubscription).ID = 7
+	}).Return(nil).Once()
+	auditRepo.On("Record", mock.Anything, mock.MatchedBy(func(a entity.Audit) bool {
+		return a.Entity == "webhook" && a.EntityID == "7"
+	})).Return(nil).Once()
+
+	result, err := svc.AddWebhook(ctx, dto.AddWebhook{
+		URL:        "https://school.example/hooks",
+		EventTypes: []string{"SanctionIssued", "LoanReturned", "LoanReturned"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 7, result.ID)
+	assert.Len(t, result.Secret, 64)
+	assert.Equal(t, []string{"LoanReturned", "SanctionIssued"}, result.EventTypes)
+}
+
+func TestConsume(t *testing.T) {
+	ctx := context.Background()
+	repo, _, svc, now := setup(t)
+	repo.On("CreateGroup", ctx, "events", "webhooks").Return(nil).Once()
+	repo.On("ReadEvents", ctx, "events", "webhooks", "test", 10, time.Minute).Return([]entity.StreamEvent{
+		{StreamID: "1-0", ID: 1, Type: "LoanReturned", Payload: `{"id_loan":3}`},
+		{StreamID: "2-0", ID: 2, Type: "BookAdded", Payload: `{"id_book":4}`},
+	}, nil).Once()
+	repo.On("GetSubscriptions", ctx, true).Return([]entity.WebhookSubscription{
+		{ID: 1, EventTypes: `["LoanReturned"]`},
+		{ID: 2, EventTypes: `["LoanReturned","BookAdded"]`},
+	}, nil).Once()
+	repo.On("AddDeliveries", ctx, mock.MatchedBy(func(d []entity.WebhookDelivery) bool {
+		return len(d) == 3 &&
+			d[0].SubscriptionID == 1 && d[0].EventID == 1 &&
+			d[1].SubscriptionID == 2 && d[1].EventID == 1 &&
+			d[2].SubscriptionID == 2 && d[2].EventID == 2 &&
+			d[0].Status == webhook.StatusPending && d[0].NextAttemptAt.Equal(now)
+	})).Return(nil).Once()
+	repo.On("AckEvents", ctx, "events", "webhooks", []string{"1-0", "2-0"}).Return(nil).Once()
+
+	n, err := svc.Consume(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.True(t, svc.groupReady.Load())
+}
+
+func TestDispatch_Cases(t *testing.T) {
+	ctx := context.Background()
+	const secret = "0123456789abcdef"
+	due := func(url string, attempts int) []entity.DueDelivery {
+		return []entity.DueDelivery{{
+			WebhookDelivery: entity.WebhookDelivery{ID: 5, EventID: 1, EventType: "LoanReturned", Payload: `{"id":1,"type":"LoanReturned","data":{"id_loan":3}}`, Status: webhook.StatusPending, Attempts: attempts},
+			URL:             url,
+			Secret:          secret,
+		}}
+	}
+	lease := testCfg.Timeout * time.Duration(testCfg.BatchSize+1)
+
+	t.Run("Succeeded", func(t *testing.T) {
+		repo, _, svc, now := setup(t)
+		srv, calls := receiver(t, secret, http.StatusNoContent)
+		repo.On("ClaimDeliveries", ctx, 10, lease).Return(due(srv.URL, 0), nil).Once()
+		repo.On("SaveAttempt", ctx, mock.MatchedBy(func(d entity.WebhookDelivery) bool {
+			return d.Status == webhook.StatusSucceeded && d.Attempts == 1 && d.ResponseStatus == http.StatusNoContent && d.DeliveredAt != nil && d.DeliveredAt.Equal(now)
+		})).Return(nil).Once()
+
+		n, err := svc.Dispatch(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, n)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("Failed_Is_Retried_With_Backoff", func(t *testing.T) {
+		repo, _, svc, now := setup(t)
+		srv, _ := receiver(t, secret, http.StatusServiceUnavailable)
+		repo.On("ClaimDeliveries", ctx, 10, lease).Return(due(srv.URL, 1), nil).Once()
+		repo.On("SaveAttempt", ctx, mock.MatchedBy(func(d entity.WebhookDelivery) bool {
+			return d.Status == webhook.StatusFailed && d.Attempts == 2 && d.ResponseStatus == http.StatusServiceUnavailable &&
+				d.ResponseBody == "ok" && d.LastError == "receiver responded "+strconv.Itoa(http.StatusServiceUnavailable) &&
+				d.NextAttemptAt.Equal(now.Add(2*time.Second)) && d.DeliveredAt == nil
+		})).Return(nil).Once()
+
+		_, err := svc.Dispatch(ctx)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Dead_After_Max_Attempts", func(t *testing.T) {
+		repo, _, svc, _ := setup(t)
+		srv, _ := receiver(t, secret, http.StatusInternalServerError)
+		repo.On("ClaimDeliveries", ctx, 10, lease).Return(due(srv.URL, 2), nil).Once()
+		repo.On("SaveAttempt", ctx, mock.MatchedBy(func(d entity.WebhookDelivery) bool {
+			return d.Status == webhook.StatusDead && d.Attempts == 3
+		})).Return(nil).Once()
+
+		_, err := svc.Dispatch(ctx)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Unreachable_Receiver", func(t *testing.T) {
+		repo, _, svc, _ := setup(t)
+		srv, _ := receiver(t, secret, http.StatusOK)
+		srv.Close()
+		repo.On("ClaimDeliveries", ctx, 10, lease).Return(due(srv.URL, 0), nil).Once()
+		repo.On("SaveAttempt", ctx, mock.MatchedBy(func(d entity.WebhookDelivery) bool {
+			return d.Status == webhook.StatusFailed && d.ResponseStatus == 0 && d.LastError != ""
+		})).Return(nil).Once()
+
+		_, err := svc.Dispatch(ctx)
+		assert.NoError(t, err)
+	})
+}
+
+func TestReplayDelivery(t *testing.T) {
+	ctx := context.Background()
+	repo, auditRepo, svc, _ := setup(t)
+	repo.On("ResetDelivery", ctx, 5).Return(nil).Once()
+	auditRepo.On("Record", ctx, mock.MatchedBy(func(a entity.Audit) bool {
+		return a.Action == "replay" && a.Entity == "webhook_delivery" && a.EntityID == "5"
+	})).Return(nil).Once()
+
+	assert.NoError(t, svc.ReplayDelivery(ctx, 5))
+}
@@ -15,33 +15,34 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/admin/add/book": {
-            "post": {
-                "description": "Add new book to database",
-                "consumes": [
-                    "application/json"
-                ],
+        "/admin/loan": {
+            "get": {
+                "description": "Get all loan data, whether it has been returned or not",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Admin"
                 ],
-                "summary": "Add book",
+                "summary": "Get loan data",
+                "deprecated": true,
                 "parameters": [
                     {
-                        "description": "Book data",
-                        "name": "book",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.BookData"
-                        }
+                        "type": "string",
+                        "description": "Cursor, the next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 35, max 100)",
+                        "name": "page_size",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully add new book",
+                        "description": "Successfully get loan data",
                         "schema": {
                             "$ref": "#/definitions/dto.Response"
                         }
@@ -61,9 +62,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/add/category": {
+        "/admin/loan/confirm": {
             "post": {
-                "description": "Add new category to database",
+                "description": "Confirm book loan, with an optional outcome to return a damaged or lost book",
                 "consumes": [
                     "application/json"
                 ],
@@ -73,21 +74,28 @@ const docTemplate = `{
                 "tags": [
                     "Admin"
                 ],
-                "summary": "Add category",
+                "summary": "Confirm",
+                "deprecated": true,
                 "parameters": [
                     {
-                        "description": "Category name",
-                        "name": "category",
+                        "description": "Student NIS and book ISBN",
+                        "name": "confirm",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.Category"
+                            "$ref": "#/definitions/dto.Confirm"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key replaying the first response for retries",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully add new category",
+                        "description": "Successfully confirm book loan",
                         "schema": {
                             "$ref": "#/definitions/dto.Response"
                         }
@@ -107,9 +115,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/loan": {
+        "/admin/loan/done": {
             "get": {
-                "description": "Get all loan data, whether it has been returned or not",
+                "description": "Get all loan data that has been returned",
                 "produces": [
                     "application/json"
                 ],
@@ -117,13 +125,19 @@ const docTemplate = `{
                     "Admin"
                 ],
                 "summary": "Get loan data",
+                "deprecated": true,
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cursor, the next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
-                        "description": "Page",
-                        "name": "page",
-                        "in": "query",
-                        "required": true
+                        "description": "Page size (default 35, max 100)",
+                        "name": "page_size",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -148,33 +162,34 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/loan/confirm": {
-            "post": {
-                "description": "Confirm book loan",
-                "consumes": [
-                    "application/json"
-                ],
+        "/admin/loan/dont": {
+            "get": {
+                "description": "Get all loan data that has not been returned",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Admin"
                 ],
-                "summary": "Confirm",
+                "summary": "Get loan data",
+                "deprecated": true,
                 "parameters": [
                     {
-                        "description": "Student and book data",
-                        "name": "confirm",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.Confirm"
-                        }
+                        "type": "string",
+                        "description": "Cursor, the next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 35, max 100)",
+                        "name": "page_size",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully confirm book loan",
+                        "description": "Successfully get loan data",
                         "schema": {
                             "$ref": "#/definitions/dto.Response"
                         }
@@ -194,28 +209,71 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/loan/done": {
+        "/api/v1/audit-logs": {
             "get": {
-                "description": "Get all loan data that has been returned",
+                "description": "Get state-changing operations, newest first, filtered by actor, entity and date",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Admin"
                 ],
-                "summary": "Get loan data",
+                "summary": "Get audit log",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Page",
-                        "name": "page",
-                        "in": "query",
-                        "required": true
+                        "description": "Actor (user id)",
+                        "name": "actor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "book",
+                            "category",
+                            "clearance",
+                            "closure",
+                            "loan",
+                            "opening_hours",
+                            "reading_list",
+                            "review",
+                            "student",
+                            "suggestion",
+                            "webhook",
+                            "webhook_delivery"
+                        ],
+                        "type": "string",
+                        "description": "Entity",
+                        "name": "entity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "From date (dd-mm-yyyy)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "To date, inclusive (dd-mm-yyyy)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor, the next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 35, max 100)",
+                        "name": "page_size",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully get loan data",
+                        "description": "Successfully get audit log",
                         "schema": {
                             "$ref": "#/definitions/dto.Response"
                         }
@@ -235,28 +293,33 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/loan/dont": {
-            "get": {
-                "description": "Get all loan data that has not been returned",
+        "/api/v1/auth/login": {
+            "post": {
+                "description": "Login with NIK \u0026 Password",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Admin"
+                    "Authentication"
                 ],
-                "summary": "Get loan data",
+                "summary": "Login for access library API",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Page",
-                        "name": "page",
-                        "in": "query",
-                        "required": true
+                        "description": "Data for login",
+                        "name": "loginData",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.Login"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully get loan data",
+                        "description": "Successfully Login",
                         "schema": {
                             "$ref": "#/definitions/dto.Response"
                         }
@@ -276,33 +339,19 @@ const docTemplate = `{
                 }
             }
         },
-        "/login": {
+        "/api/v1/auth/logout": {
             "post": {
-                "description": "Login with NIK \u0026 Password",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Log out of account",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Authentication"
-                ],
-                "summary": "Login for access library API",
-                "parameters": [
-                    {
-                        "description": "Data for login",
-                        "name": "loginData",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.Login"
-                        }
-                    }
+                    "student"
                 ],
+                "summary": "Logout",
                 "responses": {
                     "200": {
-                        "description": "Successfully Login",
+                        "description": "Successfully confirm logout",
                         "schema": {
                             "$ref": "#/definitions/dto.Response"
                         }
@@ -322,8 +371,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/refresh": {
-            "get": {
+        "/api/v1/auth/refresh": {
+            "post": {
                 "description": "Get the refresh token in the cookie and if it is valid, generate a new access and refresh token.",
                 "produces": [
                     "application/json"
@@ -354,7 +403,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/register": {
+        "/api/v1/auth/register": {
             "post": {
                 "description": "Create account",
                 "consumes": [
@@ -400,33 +449,62 @@ const docTemplate = `{
                 }
             }
         },
-        "/student/book/loan": {
-            "post": {
-                "description": "Borrow books from the database",
-                "consumes": [
-                    "application/json"
-                ],
+        "/api/v1/books": {
+            "get": {
+                "description": "Get books, filtered by author or by categories",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "student"
                 ],
-                "summary": "Loan book",
+                "summary": "Get books",
                 "parameters": [
                     {
-                        "description": "Loan data, with book id and returned time as a value",
-                        "name": "loan",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.Loan"
-                        }
+                        "type": "string",
+                        "description": "Author",
+                        "name": "author",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "List category",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor, the next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 35, max 100)",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "title",
+                            "author",
+                            "newest",
+                            "availability",
+                            "rating"
+                        ],
+                        "type": "string",
+                        "description": "Sort order (default title)",
+                        "name": "sort",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully loan a book",
+                        "description": "Successfully get books",
                         "schema": {
                             "$ref": "#/definitions/dto.Response"
                         }
@@ -444,30 +522,40 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/student/books": {
-            "get": {
-                "description": "Get all books from db",
+            },
+            "post": {
+                "description": "Add new book to database",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "student"
+                    "Admin"
                 ],
-                "summary": "Get books",
+                "summary": "Add book",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Page",
-                        "name": "page",
-                        "in": "query",
+                        "description": "Book data",
+                        "name": "book",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.BookData"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key replaying the first response for retries",
+                        "name": "Idempotency-Key",
+                        "in": "header",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully get books",
+                        "description": "Successfully add new book",
                         "schema": {
                             "$ref": "#/definitions/dto.Response"
                         }
@@ -478,6 +566,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/dto.Response"
                         }
                     },
+                    "409": {
+                        "description": "Same idempotency key still in progress",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Idempotency key reused with a different body",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -487,35 +587,46 @@ const docTemplate = `{
                 }
             }
         },
-        "/student/books/author": {
-            "get": {
-                "description": "Get books with author as filter",
+        "/api/v1/books/{id}/loans": {
+            "post": {
+                "description": "Borrow a book until the end of the due date in the library timezone, from today to 7 days ahead. A due date on a closed day is pushed to the next open day",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "student"
                 ],
-                "summary": "Get books",
+                "summary": "Loan book",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Page",
-                        "name": "page",
-                        "in": "query",
+                        "description": "Book id",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     },
+                    {
+                        "description": "Due date, dd-mm-yyyy or yyyy-mm-dd",
+                        "name": "loan",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.LoanBook"
+                        }
+                    },
                     {
                         "type": "string",
-                        "description": "Author",
-                        "name": "author",
-                        "in": "query",
-                        "required": true
+                        "description": "Key replaying the first response for retries",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Successfully get books by author",
+                    "201": {
+                        "description": "Successfully loan a book",
                         "schema": {
                             "$ref": "#/definitions/dto.Response"
                         }
@@ -535,39 +646,40 @@ const docTemplate = `{
                 }
             }
         },
-        "/student/books/category": {
+        "/api/v1/books/{id}/reviews": {
             "get": {
-                "description": "Get books with category as filter",
+                "description": "Get the visible reviews of a book, newest first",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "student"
                 ],
-                "summary": "Get books",
+                "summary": "Get book reviews",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Page",
-                        "name": "page",
-                        "in": "query",
+                        "description": "Book id",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     },
                     {
-                        "type": "array",
-                        "items": {
-                            "type": "string"
-                        },
-                        "collectionFormat": "multi",
-                        "description": "List category",
-                        "name": "category",
-                        "in": "query",
-                        "required": true
+                        "type": "string",
+                        "description": "Cursor, the next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 35, max 100)",
+                        "name": "page_size",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully get books by category",
+                        "description": "Successfully get book reviews",
                         "schema": {
                             "$ref": "#/definitions/dto.Response"
                         }
@@ -585,21 +697,2628 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/student/logout": {
+            },
+            "post": {
+                "description": "Rate a book from 1 to 5 stars with an optional text, only a book the student has borrowed and returned can be reviewed. Posting again replaces the review, a hidden review stays hidden",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "student"
+                ],
+                "summary": "Review book",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Book id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Rating and text",
+                        "name": "review",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PostReview"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key replaying the first response for retries",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Successfully review book",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect client input",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Book not returned by the student",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Book not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/calendar": {
+            "get": {
+                "description": "Get the opening hours of every day of the week and the holidays and term breaks from from to to (the coming year by default), a book is never due on a closed day and closed days are not fined",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Calendar"
+                ],
+                "summary": "Get calendar",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "First day, yyyy-mm-dd",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day, yyyy-mm-dd",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully get calendar",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect client input",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/calendar/closures": {
+            "post": {
+                "description": "Close the library for a holiday or a term break, loans already due in it are not moved but are not fined for it",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Add closure",
+                "parameters": [
+                    {
+                        "description": "Holiday or term break",
+                        "name": "closure",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.AddClosure"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key replaying the first response for retries",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Successfully add closure",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect client input",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/calendar/closures/{id}": {
+            "delete": {
+                "description": "Open the library again on the days of a holiday or a term break",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Delete closure",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Closure ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully delete closure",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect client input",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Closure not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/calendar/import": {
+            "post": {
+                "description": "Import the all-day events of an iCal file, like the Indonesian public holidays calendar, as closures. Importing the same file again updates the closures instead of adding them twice",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Import iCal",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "iCal file (.ics), at most 1 MiB",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "holiday",
+                            "term_break"
+                        ],
+                        "type": "string",
+                        "description": "Closure kind (default holiday)",
+                        "name": "kind",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key replaying the first response for retries",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully import iCal",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect client input",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/calendar/opening-hours": {
+            "put": {
+                "description": "Set the opening hours of some days of the week, the days not sent are kept, a closed day needs no hours",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Set opening hours",
+                "parameters": [
+                    {
+                        "description": "Opening hours",
+                        "name": "hours",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SetOpeningHours"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully set opening hours",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect client input",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/categories": {
+            "post": {
+                "description": "Add new category to database",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Add category",
+                "parameters": [
+                    {
+                        "description": "Category name",
+                        "name": "category",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.Category"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key replaying the first response for retries",
+                        "name": "Idempotency-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully add new category",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect client input",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Same idempotency key still in progress",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Idempotency key reused with a different body",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/clearances/batch": {
+            "post": {
+                "description": "Issue the clearance of every student of a batch, a blocked student doesn't stop the others and is listed with what keeps them from being cleared",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Issue batch clearance",
+                "parameters": [
+                    {
+                        "description": "Batch and clearance purpose",
+                        "name": "clearance",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.IssueBatchClearance"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key replaying the first response for retries",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully issue batch clearance",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect client input",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "No student in batch",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/clearances/{number}": {
+            "get": {
+                "description": "Get an issued clearance by its number",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get clearance",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Clearance number",
+                        "name": "number",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully get clearance",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Clearance not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/clearances/{number}/pdf": {
+            "get": {
+                "description": "Download the clearance certificate as a PDF with a QR code pointing to its verification page",
+                "produces": [
+                    "application/pdf"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get clearance certificate",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Clearance number",
+                        "name": "number",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Clearance certificate",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "404": {
+                        "description": "Clearance not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/clearances/{number}/verify": {
+            "get": {
+                "description": "Public page behind the QR code of a certificate, the clearance is only shown when the signature is valid",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Clearance"
+                ],
+                "summary": "Verify clearance",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Clearance number",
+                        "name": "number",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Signature from the QR code",
+                        "name": "sig",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully verify clearance",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Clearance not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/loans": {
+            "get": {
+                "description": "Get loan data, all of them or only the returned / active ones",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get loan data",
+                "parameters": [
+                    {
+                        "enum": [
+                            "all",
+                            "returned",
+                            "active"
+                        ],
+                        "type": "string",
+                        "description": "Loan status (default all)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor, the next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 35, max 100)",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully get loan data",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect client input",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/loans/{id}/return": {
+            "post": {
+                "description": "Confirm that the book of an active loan has been returned. A damaged book is charged its repair fee, a lost one its replacement price (the book price unless a fee is sent) and leaves the stock for good, the fee is added to the sanctions",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Return loan",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Loan id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Return outcome, a normal return when empty",
+                        "name": "outcome",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReturnOutcome"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key replaying the first response for retries",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully return loan",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect client input",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "No active loan with that id",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/log/level": {
+            "get": {
+                "description": "Get the current log level",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get log level",
+                "responses": {
+                    "200": {
+                        "description": "Successfully get log level",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.LogLevel"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Change the log level at runtime, it resets to LOG_LEVEL on restart",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Set log level",
+                "parameters": [
+                    {
+                        "description": "Log level",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.LogLevel"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully set log level",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.LogLevel"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect client input",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/reading-lists": {
+            "get": {
+                "description": "Get the reading lists of every class, newest first, mine only keeps the lists created by the logged in teacher",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Staff"
+                ],
+                "summary": "Get reading lists",
+                "parameters": [
+                    {
+                        "enum": [
+                            "X",
+                            "XI",
+                            "XII",
+                            "XIII"
+                        ],
+                        "type": "string",
+                        "description": "Class",
+                        "name": "class",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "RPL",
+                            "SIJA",
+                            "PSPT",
+                            "TPTU",
+                            "TEI",
+                            "MEKA",
+                            "TOI",
+                            "TEK",
+                            "IOP"
+                        ],
+                        "type": "string",
+                        "description": "Major",
+                        "name": "major",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only my reading lists",
+                        "name": "mine",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor, the next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 35, max 100)",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully get reading lists",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect client input",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Assign books, in reading order, to a class until due_at. Leaving sub_class or major empty targets every sub class or major of the class, starts_at defaults to today",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Staff"
+                ],
+                "summary": "Add reading list",
+                "parameters": [
+                    {
+                        "description": "Reading list",
+                        "name": "reading_list",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SaveReadingList"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key replaying the first response for retries",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Successfully add reading list",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect client input or unknown book",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/reading-lists/{id}": {
+            "get": {
+                "description": "Get a reading list with its books in reading order",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Staff"
+                ],
+                "summary": "Get reading list",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Reading list id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully get reading list",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect client input",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Reading list not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replace a reading list and its books, starts_at is kept when left empty. A teacher can only update the lists they created",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Staff"
+                ],
+                "summary": "Update reading list",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Reading list id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reading list",
+                        "name": "reading_list",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SaveReadingList"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully update reading list",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect client input or unknown book",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Reading list of another teacher",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Reading list not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a reading list, a teacher can only delete the lists they created",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Staff"
+                ],
+                "summary": "Delete reading list",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Reading list id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully delete reading list",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect client input",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Reading list of another teacher",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Reading list not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/reading-lists/{id}/report": {
+            "get": {
+                "description": "Get the completion of a reading list by every active student it is assigned to, grouped per class. A book is read once a loan of it made since starts_at is returned",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Staff"
+                ],
+                "summary": "Get reading list report",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Reading list id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully get reading list report",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect client input",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Reading list not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/reviews": {
+            "get": {
+                "description": "Get the reviews of every book for moderation, newest first, filtered by book and status",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get reviews",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Book id",
+                        "name": "book_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "visible",
+                            "hidden"
+                        ],
+                        "type": "string",
+                        "description": "Status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor, the next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 35, max 100)",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully get reviews",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect client input",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/reviews/{id}/hide": {
+            "post": {
+                "description": "Hide a review from the students and leave it out of the rating of the book",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Hide review",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Review id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Why the review is hidden",
+                        "name": "reason",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.HideReview"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully hide review",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect client input",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Review not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/reviews/{id}/unhide": {
+            "post": {
+                "description": "Show a hidden review to the students again and count it in the rating of the book",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Unhide review",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Review id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully unhide review",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect client input",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Review not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/student/reading-lists": {
+            "get": {
+                "description": "Get the reading lists assigned to the class, sub class and major of the logged in student with the progress on every book, the closest due first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "student"
+                ],
+                "summary": "Get my reading lists",
+                "responses": {
+                    "200": {
+                        "description": "Successfully get reading lists",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Student not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/student/recommendations": {
+            "get": {
+                "description": "Get books the student hasn't borrowed yet, borrowed by the students who borrowed the same books, sharing their categories or authors, or popular in their class and major. They are computed in the background and may be up to an hour old",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "student"
+                ],
+                "summary": "Get recommendations",
+                "responses": {
+                    "200": {
+                        "description": "Successfully get recommendations",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Not logged in",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/students": {
+            "get": {
+                "description": "Get students, filtered by nis, name, class, major or batch",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get students",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "NIS",
+                        "name": "nis",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Class",
+                        "name": "class",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Major",
+                        "name": "major",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Batch",
+                        "name": "batch",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor, the next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 35, max 100)",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully get students",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect client input",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/students/{nis}": {
+            "get": {
+                "description": "Get student detail with active loans, loan history, max book usage and sanctions",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get student profile",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "NIS",
+                        "name": "nis",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully get student",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect client input",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Student not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Update personal and academic data of an active student",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Update student",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "NIS",
+                        "name": "nis",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to update",
+                        "name": "student",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateStudent"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully update student",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect client input",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Student not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/students/{nis}/clearance": {
+            "post": {
+                "description": "Issue the library clearance (bebas pustaka) of a student with no active loans and no outstanding sanctions, a student cleared before for the same purpose gets that clearance back",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Issue clearance",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "NIS",
+                        "name": "nis",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Clearance purpose",
+                        "name": "clearance",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.IssueClearance"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key replaying the first response for retries",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Successfully issue clearance",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect client input",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Student not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Student still has active loans or outstanding sanctions",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/students/{nis}/deactivate": {
+            "post": {
+                "description": "Deactivate a student account, the student can no longer login",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Deactivate student",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "NIS",
+                        "name": "nis",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key replaying the first response for retries",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully deactivate student",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect client input",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/students/{nis}/role": {
+            "put": {
+                "description": "Promote an active student to teacher or move a teacher back to students, the account has to login again",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Update student role",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "NIS",
+                        "name": "nis",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New role",
+                        "name": "role",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateRole"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully update student role",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect client input",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Student not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/students/{nis}/sanctions/settle": {
+            "post": {
+                "description": "Mark every outstanding sanction of a student as paid",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Settle sanctions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "NIS",
+                        "name": "nis",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key replaying the first response for retries",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully settle sanctions",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect client input",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Student not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/suggestions": {
+            "get": {
+                "description": "Get the suggested books, the most voted first unless sorted by newest",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "student"
+                ],
+                "summary": "Get suggestions",
+                "parameters": [
+                    {
+                        "enum": [
+                            "submitted",
+                            "under_review",
+                            "ordered",
+                            "received",
+                            "rejected"
+                        ],
+                        "type": "string",
+                        "description": "Status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "votes",
+                            "newest"
+                        ],
+                        "type": "string",
+                        "description": "Sort",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor, the next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 35, max 100)",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully get suggestions",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect client input",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Ask the library to buy a book missing from the catalog, the student votes for it too. A book in the catalog or already suggested can't be suggested again",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "student"
+                ],
+                "summary": "Suggest book",
+                "parameters": [
+                    {
+                        "description": "Suggested book",
+                        "name": "suggestion",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.AddSuggestion"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key replaying the first response for retries",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Successfully suggest book",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect client input",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Book in the catalog or already suggested",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/suggestions/{id}/receive": {
+            "post": {
+                "description": "Add an ordered suggestion to the catalog as a book, or link the book already added under its isbn. Every requester is notified with a SuggestionReceived event and the first ones, as many as the available copies, get a hold on the book for LIBRARY_HOLD_TTL",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Receive suggestion",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Suggestion id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Book details",
+                        "name": "book",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReceiveSuggestion"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key replaying the first response for retries",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully receive suggestion",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect client input",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Suggestion not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Suggestion not ordered",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/suggestions/{id}/status": {
+            "post": {
+                "description": "Move a suggestion from submitted to under_review then ordered, or reject it with a note until it is received",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Move suggestion",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Suggestion id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Next status",
+                        "name": "status",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.MoveSuggestion"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully move suggestion",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect client input",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Suggestion not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Status not reachable from the current one",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/suggestions/{id}/votes": {
+            "post": {
+                "description": "Ask for a suggested book too, voting again changes nothing. Every voter is notified when the book is received and the first ones get a hold on it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "student"
+                ],
+                "summary": "Vote suggestion",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Suggestion id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key replaying the first response for retries",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully vote suggestion",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect client input",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Suggestion not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Suggestion closed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/webhook-deliveries/{id}/replay": {
+            "post": {
+                "description": "Send a delivery again from its first attempt with its original body, whatever its status",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Replay webhook delivery",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Delivery ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key replaying the first response for retries",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Successfully queue webhook delivery",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect client input",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Delivery not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/webhooks": {
+            "get": {
+                "description": "Get every webhook subscription, deleted ones are inactive",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get webhooks",
+                "responses": {
+                    "200": {
+                        "description": "Successfully get webhooks",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Subscribe a school system to loan, return and sanction events, every delivery is signed with HMAC-SHA256 of the secret in X-Webhook-Signature. The secret is generated when left empty and only returned here",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Add webhook",
+                "parameters": [
+                    {
+                        "description": "Webhook subscription",
+                        "name": "webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.AddWebhook"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key replaying the first response for retries",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Successfully add webhook",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect client input",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/webhooks/{id}": {
+            "delete": {
+                "description": "Deactivate a webhook subscription, its pending deliveries are no longer sent and its delivery log is kept",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Delete webhook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully delete webhook",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect client input",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook not found or already deleted",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/webhooks/{id}/deliveries": {
+            "get": {
+                "description": "Get the delivery log of a webhook, newest first, with the last response and error of every delivery",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get webhook deliveries",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "succeeded",
+                            "failed",
+                            "dead"
+                        ],
+                        "type": "string",
+                        "description": "Status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor, the next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 35, max 100)",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully get webhook deliveries",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect client input",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/healthz": {
+            "get": {
+                "description": "The process is up and serving HTTP, dependencies are not checked",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Liveness probe",
+                "responses": {
+                    "200": {
+                        "description": "Alive",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "Ping postgres and redis with a timeout and report their pool stats, not ready while shutting down",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Readiness probe",
+                "responses": {
+                    "200": {
+                        "description": "Ready",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.Readiness"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "503": {
+                        "description": "Not ready",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.Readiness"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/student/book/loan": {
+            "post": {
+                "description": "Borrow books from the database",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "student"
+                ],
+                "summary": "Loan book",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "description": "Loan data, with book id and returned time as a value",
+                        "name": "loan",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.Loan"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key replaying the first response for retries",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully loan a book",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect client input",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/student/books": {
+            "get": {
+                "description": "Get all books from db",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "student"
+                ],
+                "summary": "Get books",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cursor, the next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 35, max 100)",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "title",
+                            "author",
+                            "newest",
+                            "availability",
+                            "rating"
+                        ],
+                        "type": "string",
+                        "description": "Sort order (default title)",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully get books",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect client input",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/student/books/author": {
+            "get": {
+                "description": "Get books with author as filter",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "student"
+                ],
+                "summary": "Get books",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cursor, the next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 35, max 100)",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "title",
+                            "author",
+                            "newest",
+                            "availability",
+                            "rating"
+                        ],
+                        "type": "string",
+                        "description": "Sort order (default title)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Author",
+                        "name": "author",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully get books by author",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect client input",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/student/books/category": {
             "get": {
-                "description": "Log out of account",
+                "description": "Get books with category as filter",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "student"
                 ],
-                "summary": "Logout",
+                "summary": "Get books",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cursor, the next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 35, max 100)",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "title",
+                            "author",
+                            "newest",
+                            "availability",
+                            "rating"
+                        ],
+                        "type": "string",
+                        "description": "Sort order (default title)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "List category",
+                        "name": "category",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
                 "responses": {
                     "200": {
-                        "description": "Successfully confirm logout",
+                        "description": "Successfully get books by category",
                         "schema": {
                             "$ref": "#/definitions/dto.Response"
                         }
@@ -618,137 +3337,516 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "dto.Binding": {
+        }
+    },
+    "definitions": {
+        "dto.AddClosure": {
+            "type": "object",
+            "required": [
+                "kind",
+                "name",
+                "start_date"
+            ],
+            "properties": {
+                "end_date": {
+                    "type": "string",
+                    "example": "2027-01-01"
+                },
+                "kind": {
+                    "type": "string",
+                    "enum": [
+                        "holiday",
+                        "term_break"
+                    ],
+                    "example": "term_break"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Libur Semester Ganjil"
+                },
+                "start_date": {
+                    "type": "string",
+                    "example": "2026-12-21"
+                }
+            }
+        },
+        "dto.AddSuggestion": {
+            "type": "object",
+            "required": [
+                "author",
+                "title"
+            ],
+            "properties": {
+                "author": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Andrea Hirata"
+                },
+                "isbn": {
+                    "type": "string",
+                    "example": "9789793062792"
+                },
+                "title": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "example": "Laskar Pelangi"
+                }
+            }
+        },
+        "dto.AddWebhook": {
+            "type": "object",
+            "required": [
+                "event_types",
+                "url"
+            ],
+            "properties": {
+                "event_types": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "LoanReturned",
+                        "SanctionIssued"
+                    ]
+                },
+                "secret": {
+                    "type": "string",
+                    "maxLength": 128,
+                    "minLength": 16
+                },
+                "url": {
+                    "type": "string",
+                    "maxLength": 2048,
+                    "example": "https://academic.school.sch.id/hooks/library"
+                }
+            }
+        },
+        "dto.Binding": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.BookData": {
+            "type": "object",
+            "required": [
+                "author",
+                "available_stock",
+                "book_name",
+                "description",
+                "id_category",
+                "isbn",
+                "publisher",
+                "stock"
+            ],
+            "properties": {
+                "author": {
+                    "type": "string"
+                },
+                "available_stock": {
+                    "type": "integer"
+                },
+                "book_name": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 300,
+                    "minLength": 50
+                },
+                "id_category": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "isbn": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 85000
+                },
+                "publisher": {
+                    "type": "string"
+                },
+                "stock": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.Category": {
+            "type": "object",
+            "required": [
+                "category_name"
+            ],
+            "properties": {
+                "category_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.Confirm": {
+            "type": "object",
+            "required": [
+                "isbn",
+                "nis"
+            ],
+            "properties": {
+                "condition_note": {
+                    "type": "string",
+                    "maxLength": 300
+                },
+                "fee": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "isbn": {
+                    "type": "string"
+                },
+                "nis": {
+                    "type": "integer"
+                },
+                "outcome": {
+                    "type": "string",
+                    "enum": [
+                        "returned",
+                        "damaged",
+                        "lost"
+                    ],
+                    "example": "returned"
+                }
+            }
+        },
+        "dto.Errors": {
+            "type": "object",
+            "properties": {
+                "binding": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.Binding"
+                    }
+                },
+                "code": {
+                    "type": "string",
+                    "enum": [
+                        "internal_error",
+                        "timeout",
+                        "request_canceled",
+                        "service_unavailable",
+                        "not_found",
+                        "no_data_affected",
+                        "reference_not_found",
+                        "validation_failed",
+                        "invalid_cursor",
+                        "invalid_date",
+                        "loan_limit_reached",
+                        "out_of_stock",
+                        "already_borrowed",
+                        "nis_registered",
+                        "email_used",
+                        "student_inactive",
+                        "not_cleared",
+                        "not_returned",
+                        "already_suggested",
+                        "already_in_catalog",
+                        "suggestion_closed",
+                        "invalid_transition",
+                        "missing_idempotency_key",
+                        "duplicate_request",
+                        "idempotency_key_reused",
+                        "rate_limited",
+                        "login_required",
+                        "invalid_credentials",
+                        "invalid_token",
+                        "revoked_token",
+                        "forbidden"
+                    ]
+                },
+                "error": {
+                    "type": "string"
+                },
+                "service": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.Service"
+                    }
+                }
+            }
+        },
+        "dto.HealthCheck": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "latency": {
+                    "type": "string"
+                },
+                "pool": {
+                    "type": "object"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.HideReview": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "maxLength": 300,
+                    "example": "contains personal information"
+                }
+            }
+        },
+        "dto.IssueBatchClearance": {
+            "type": "object",
+            "required": [
+                "batch",
+                "purpose"
+            ],
+            "properties": {
+                "batch": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 2024
+                },
+                "purpose": {
+                    "type": "string",
+                    "enum": [
+                        "graduation",
+                        "transfer"
+                    ],
+                    "example": "graduation"
+                }
+            }
+        },
+        "dto.IssueClearance": {
+            "type": "object",
+            "required": [
+                "purpose"
+            ],
+            "properties": {
+                "purpose": {
+                    "type": "string",
+                    "enum": [
+                        "graduation",
+                        "transfer"
+                    ],
+                    "example": "graduation"
+                }
+            }
+        },
+        "dto.Loan": {
+            "type": "object",
+            "required": [
+                "book_id",
+                "returned_at"
+            ],
+            "properties": {
+                "book_id": {
+                    "type": "integer"
+                },
+                "returned_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.LoanBook": {
+            "type": "object",
+            "required": [
+                "returned_at"
+            ],
+            "properties": {
+                "returned_at": {
+                    "type": "string",
+                    "example": "2026-12-25"
+                }
+            }
+        },
+        "dto.LogLevel": {
             "type": "object",
+            "required": [
+                "level"
+            ],
             "properties": {
-                "field": {
-                    "type": "string"
-                },
-                "reason": {
-                    "type": "string"
+                "level": {
+                    "type": "string",
+                    "enum": [
+                        "debug",
+                        "info",
+                        "warn",
+                        "error"
+                    ]
                 }
             }
         },
-        "dto.BookData": {
+        "dto.Login": {
             "type": "object",
             "required": [
-                "author",
-                "available_stock",
-                "book_name",
-                "description",
-                "id_category",
-                "isbn",
-                "publisher",
-                "stock"
+                "nis",
+                "password"
             ],
             "properties": {
-                "author": {
-                    "type": "string"
-                },
-                "available_stock": {
+                "nis": {
                     "type": "integer"
                 },
-                "book_name": {
+                "password": {
                     "type": "string"
+                }
+            }
+        },
+        "dto.Meta": {
+            "type": "object",
+            "properties": {
+                "has_next": {
+                    "type": "boolean",
+                    "example": true
                 },
-                "description": {
+                "next_cursor": {
                     "type": "string",
-                    "maxLength": 300,
-                    "minLength": 50
-                },
-                "id_category": {
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    }
+                    "example": "eyJzIjoidGl0bGUiLCJrIjoiRHVuZSIsImkiOjQyfQ"
                 },
-                "isbn": {
-                    "type": "string"
+                "page_size": {
+                    "type": "integer",
+                    "example": 35
                 },
-                "publisher": {
-                    "type": "string"
+                "sort": {
+                    "type": "string",
+                    "example": "title"
                 },
-                "stock": {
-                    "type": "integer"
+                "total": {
+                    "type": "integer",
+                    "example": 120
                 }
             }
         },
-        "dto.Category": {
+        "dto.MoveSuggestion": {
             "type": "object",
             "required": [
-                "category_name"
+                "status"
             ],
             "properties": {
-                "category_name": {
-                    "type": "string"
+                "note": {
+                    "type": "string",
+                    "maxLength": 300
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "under_review",
+                        "ordered",
+                        "rejected"
+                    ],
+                    "example": "under_review"
                 }
             }
         },
-        "dto.Confirm": {
+        "dto.OpeningHours": {
             "type": "object",
-            "required": [
-                "isbn",
-                "student"
-            ],
             "properties": {
-                "isbn": {
-                    "type": "string"
+                "closes_at": {
+                    "type": "string",
+                    "example": "15:00"
                 },
-                "student": {
-                    "type": "string"
+                "is_open": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "opens_at": {
+                    "type": "string",
+                    "example": "07:00"
+                },
+                "weekday": {
+                    "type": "integer",
+                    "maximum": 6,
+                    "minimum": 0,
+                    "example": 1
                 }
             }
         },
-        "dto.Errors": {
+        "dto.PostReview": {
             "type": "object",
+            "required": [
+                "rating"
+            ],
             "properties": {
-                "binding": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/dto.Binding"
-                    }
-                },
-                "error": {
-                    "type": "string"
+                "body": {
+                    "type": "string",
+                    "maxLength": 1000,
+                    "example": "Ceritanya seru, cocok untuk kelas X"
                 },
-                "service": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/dto.Service"
-                    }
+                "rating": {
+                    "type": "integer",
+                    "maximum": 5,
+                    "minimum": 1,
+                    "example": 4
                 }
             }
         },
-        "dto.Loan": {
+        "dto.Readiness": {
             "type": "object",
-            "required": [
-                "book_id",
-                "returned_at"
-            ],
             "properties": {
-                "book_id": {
-                    "type": "integer"
+                "postgres": {
+                    "$ref": "#/definitions/dto.HealthCheck"
                 },
-                "returned_at": {
-                    "type": "string"
+                "redis": {
+                    "$ref": "#/definitions/dto.HealthCheck"
                 }
             }
         },
-        "dto.Login": {
+        "dto.ReceiveSuggestion": {
             "type": "object",
             "required": [
-                "nis",
-                "password"
+                "description",
+                "id_category",
+                "publisher",
+                "stock"
             ],
             "properties": {
-                "nis": {
-                    "type": "integer"
+                "description": {
+                    "type": "string",
+                    "maxLength": 300,
+                    "minLength": 50
                 },
-                "password": {
-                    "type": "string"
+                "id_category": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "isbn": {
+                    "type": "string",
+                    "example": "9789793062792"
+                },
+                "price": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 85000
+                },
+                "publisher": {
+                    "type": "string",
+                    "example": "Bentang Pustaka"
+                },
+                "stock": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 3
                 }
             }
         },
@@ -762,8 +3860,108 @@ const docTemplate = `{
                 "message": {
                     "type": "string"
                 },
-                "status": {
-                    "type": "string"
+                "meta": {
+                    "$ref": "#/definitions/dto.Meta"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.ReturnOutcome": {
+            "type": "object",
+            "properties": {
+                "condition_note": {
+                    "type": "string",
+                    "maxLength": 300
+                },
+                "fee": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "outcome": {
+                    "type": "string",
+                    "enum": [
+                        "returned",
+                        "damaged",
+                        "lost"
+                    ],
+                    "example": "returned"
+                }
+            }
+        },
+        "dto.SaveReadingList": {
+            "type": "object",
+            "required": [
+                "books",
+                "class",
+                "due_at",
+                "title"
+            ],
+            "properties": {
+                "books": {
+                    "type": "array",
+                    "maxItems": 50,
+                    "minItems": 1,
+                    "uniqueItems": true,
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        3,
+                        8,
+                        12
+                    ]
+                },
+                "class": {
+                    "type": "string",
+                    "enum": [
+                        "X",
+                        "XI",
+                        "XII",
+                        "XIII"
+                    ],
+                    "example": "XI"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "due_at": {
+                    "type": "string",
+                    "example": "2026-12-18"
+                },
+                "major": {
+                    "type": "string",
+                    "enum": [
+                        "RPL",
+                        "SIJA",
+                        "PSPT",
+                        "TPTU",
+                        "TEI",
+                        "MEKA",
+                        "TOI",
+                        "TEK",
+                        "IOP"
+                    ],
+                    "example": "RPL"
+                },
+                "starts_at": {
+                    "type": "string",
+                    "example": "2026-07-13"
+                },
+                "sub_class": {
+                    "type": "string",
+                    "enum": [
+                        "A",
+                        "B",
+                        "C"
+                    ]
+                },
+                "title": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Sastra Indonesia Semester Ganjil"
                 }
             }
         },
@@ -775,10 +3973,27 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.SetOpeningHours": {
+            "type": "object",
+            "required": [
+                "days"
+            ],
+            "properties": {
+                "days": {
+                    "type": "array",
+                    "maxItems": 7,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/dto.OpeningHours"
+                    }
+                }
+            }
+        },
         "dto.Students": {
             "type": "object",
             "required": [
                 "batch",
+                "class",
                 "email",
                 "major",
                 "name",
@@ -837,6 +4052,68 @@ const docTemplate = `{
                     ]
                 }
             }
+        },
+        "dto.UpdateRole": {
+            "type": "object",
+            "required": [
+                "role"
+            ],
+            "properties": {
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "students",
+                        "teacher"
+                    ]
+                }
+            }
+        },
+        "dto.UpdateStudent": {
+            "type": "object",
+            "properties": {
+                "batch": {
+                    "type": "integer"
+                },
+                "class": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string",
+                    "maxLength": 20,
+                    "minLength": 10
+                },
+                "major": {
+                    "type": "string",
+                    "enum": [
+                        "RPL",
+                        "SIJA",
+                        "PSPT",
+                        "TPTU",
+                        "TEI",
+                        "MEKA",
+                        "TOI",
+                        "TEK",
+                        "IOP"
+                    ]
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 30,
+                    "minLength": 5
+                },
+                "phone_number": {
+                    "type": "string",
+                    "maxLength": 15
+                },
+                "sub_class": {
+                    "type": "string",
+                    "enum": [
+                        "A",
+                        "B",
+                        "C"
+                    ]
+                }
+            }
         }
     },
     "securityDefinitions": {
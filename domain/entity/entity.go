@@ -1,10 +1,14 @@
 package entity
 
 import (
-	"fmt"
 	"regexp"
+	"stmnplibrary/apperr"
+	"stmnplibrary/clock"
+	"stmnplibrary/log/structure"
 	"strings"
 	"time"
+
+	"go.uber.org/zap/zapcore"
 )
 
 type Students struct {
@@ -27,6 +31,15 @@ type AcademicInfo struct {
 	Batch    int
 }
 
+// MarshalLogObject masks the phone number and email so a logged student never
+// leaks personal info.
+func (p PersonalInfo) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("name", p.Name)
+	enc.AddString("phone_number", structure.MaskPhone(p.PhoneNumber))
+	enc.AddString("email", structure.MaskEmail(p.Email))
+	return nil
+}
+
 var phoneRegex = regexp.MustCompile(`^\+?[0-9]+$`)
 
 func (p *PersonalInfo) ValidatePN(errMsg *[]string) {
@@ -77,6 +90,34 @@ func (Students) TableName() string {
 	return "students"
 }
 
+const MaxBookLimit = 3
+
+type StudentFilter struct {
+	NIS   int
+	Name  string
+	Class string
+	Major string
+	Batch int
+}
+
+type StudentData struct {
+	ID          int    `gorm:"column:id"`
+	NIS         int    `gorm:"column:nis"`
+	Name        string `gorm:"column:name"`
+	PhoneNumber string `gorm:"column:phone_number"`
+	Email       string `gorm:"column:email"`
+	Class       string `gorm:"column:class"`
+	SubClass    string `gorm:"column:sub_class"`
+	Major       string `gorm:"column:major"`
+	Batch       int    `gorm:"column:batch"`
+	MaxBook     int    `gorm:"column:max_book"`
+	IsActive    bool   `gorm:"column:is_active"`
+}
+
+func (StudentData) TableName() string {
+	return "students"
+}
+
 type Login struct {
 	NIS      int
 	Password string
@@ -111,6 +152,9 @@ type Book struct {
 	Description    string
 	Categories     []Categories `gorm:"many2many:connections;joinForeignKey:id_book;joinReferences:id_category"`
 	AvailableStock int
+	RatingAvg      float64
+	RatingCount    int
+	CreatedAt      time.Time
 }
 
 func (Book) TableName() string {
@@ -123,58 +167,90 @@ type Loan struct {
 	MustReturnedAt time.Time
 }
 
-func (l *Loan) ValidateDateFormat(date string) error {
-	const layout = "02-01-2006"
-	formattedTime, err := time.Parse(layout, date)
-	if err != nil {
-		return fmt.Errorf("wrong format make sure it is like this: dd-mm-yyyy")
+// dateLayouts are the due dates accepted, dd-mm-yyyy or ISO-8601.
+var dateLayouts = []string{"02-01-2006", time.DateOnly}
+
+// ValidateDateFormat reads the due date, the book is due at the end of that
+// day in loc. A full ISO-8601 timestamp is due at the end of its day in loc.
+func (l *Loan) ValidateDateFormat(date string, loc *time.Location) error {
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, date, loc); err == nil {
+			l.MustReturnedAt = clock.EndOfDay(t)
+			return nil
+		}
 	}
-	l.MustReturnedAt = formattedTime
-	return nil
+	if t, err := time.Parse(time.RFC3339, date); err == nil {
+		l.MustReturnedAt = clock.EndOfDay(t.In(loc))
+		return nil
+	}
+	return apperr.New(apperr.KindInvalid, apperr.CodeInvalidDate, "wrong format make sure it is like this: dd-mm-yyyy or yyyy-mm-dd")
 }
 
-func (l *Loan) ValidateDate() error {
-	limit := time.Now().AddDate(0, 0, 7)
-	if l.MustReturnedAt.After(limit) {
-		return fmt.Errorf("maximum loan limit is 7 days")
+// ValidateDate checks the due date against the day of now in the library
+// timezone, a book can be due today and at most 7 days from today.
+func (l *Loan) ValidateDate(now time.Time) error {
+	days := clock.Days(now, l.MustReturnedAt)
+	if days > 7 {
+		return apperr.New(apperr.KindInvalid, apperr.CodeInvalidDate, "maximum loan limit is 7 days")
 	}
-	if l.MustReturnedAt.Before(time.Now()) {
-		return fmt.Errorf("date cannot be in the past")
+	if days < 0 {
+		return apperr.New(apperr.KindInvalid, apperr.CodeInvalidDate, "date cannot be in the past")
 	}
 	return nil
 }
 
+// PushToOpenDay moves the due date to the next day the library is open, a
+// book can't be returned on a holiday or during a term break.
+func (l *Loan) PushToOpenDay(cal Calendar) {
+	l.MustReturnedAt = cal.NextOpen(l.MustReturnedAt)
+}
+
 func (Loan) TableName() string {
 	return "loan"
 }
 
 type Confirm struct {
-	Student string
+	NIS  int
 	ISBN string
 }
 
 type LoanData struct {
+	ID             int        `gorm:"column:id"`
 	BookName       string     `gorm:"column:book_name"`
 	StudentName    string     `gorm:"column:student_name"`
 	BorrowAt       time.Time  `gorm:"column:borrow_at"`
 	MustReturnedAt time.Time  `gorm:"column:must_returned_at"`
 	ReturnedAt     *time.Time `gorm:"column:returned_at"`
 	Sanctions      *int64     `gorm:"column:sanctions"`
+	Outcome        string     `gorm:"column:outcome"`
+	Fee            int64      `gorm:"column:fee"`
 }
 
 func (LoanData) TableName() string {
 	return "loan"
 }
 
+type Stats struct {
+	ActiveLoans  int64 `gorm:"column:active_loans"`
+	OverdueLoans int64 `gorm:"column:overdue_loans"`
+	OutOfStock   int64 `gorm:"column:out_of_stock"`
+}
+
 type LdUpdate struct {
 	MustReturnedAt time.Time `gorm:"column:must_returned_at"`
 	ReturnedAt *time.Time `gorm:"column:returned_at"`
 	Sanctions *int64 `gorm:"column:sanctions"`
 }
 
-func (ldu *LdUpdate) GiveSanctions() {
+// GiveSanctions fines every calendar day the book is late, counted in the
+// location of MustReturnedAt, the days the library is closed on cal are not
+// counted. A nil cal counts every day.
+func (ldu *LdUpdate) GiveSanctions(cal Calendar) {
 	if ldu.ReturnedAt.After(ldu.MustReturnedAt) {
-		d := (int64(time.Since(ldu.MustReturnedAt).Hours()) / 24)
+		d := clock.Days(ldu.MustReturnedAt, *ldu.ReturnedAt)
+		if cal != nil {
+			d = max(d-cal.ClosedDays(ldu.MustReturnedAt, *ldu.ReturnedAt), 0)
+		}
 		*ldu.Sanctions = d * 2000
 	}
 	if ldu.ReturnedAt.Before(ldu.MustReturnedAt) {
@@ -186,6 +262,22 @@ func (LdUpdate) TableName() string {
 	return "loan"
 }
 
+const (
+	OutcomeReturned = "returned"
+	OutcomeDamaged  = "damaged"
+	OutcomeLost     = "lost"
+)
+
+// LoanReturn closes a loan, Sanctions is the late fine plus the repair or
+// replacement Fee.
+type LoanReturn struct {
+	Outcome       string
+	ConditionNote string
+	Fee           int64
+	Sanctions     int64
+	ReturnedAt    time.Time
+}
+
 type BookData struct {
 	BookID         int `gorm:"column:id"`
 	ISBN           string
@@ -195,6 +287,7 @@ type BookData struct {
 	Description    string
 	Stock          int
 	AvailableStock int
+	Price          int64
 }
 
 func (BookData) TableName() string {
@@ -209,3 +302,449 @@ type Connections struct {
 func (Connections) TableName() string {
 	return "connections"
 }
+
+type Audit struct {
+	ID        int       `gorm:"primaryKey"`
+	ActorID   int       `gorm:"column:actor_id"`
+	ActorRole string    `gorm:"column:actor_role"`
+	Action    string    `gorm:"column:action"`
+	Entity    string    `gorm:"column:entity"`
+	EntityID  string    `gorm:"column:entity_id"`
+	Before    string    `gorm:"column:before;type:jsonb"`
+	After     string    `gorm:"column:after;type:jsonb"`
+	Diff      string    `gorm:"column:diff;type:jsonb"`
+	TraceID   string    `gorm:"column:trace_id"`
+	IP        string    `gorm:"column:ip"`
+	CreatedAt time.Time `gorm:"column:created_at"`
+}
+
+func (Audit) TableName() string {
+	return "audit_log"
+}
+
+type AuditFilter struct {
+	ActorID int
+	Entity  string
+	From    time.Time
+	To      time.Time
+}
+
+// Idempotency is the redis record of a request sent with an Idempotency-Key,
+// Done stays false while the first request is still running.
+type Idempotency struct {
+	Fingerprint string `json:"fingerprint"`
+	Done        bool   `json:"done"`
+	Status      int    `json:"status,omitzero"`
+	ContentType string `json:"content_type,omitzero"`
+	Body        []byte `json:"body,omitzero"`
+}
+
+// Outbox is a domain event written in the transaction of the change it
+// describes, the relay publishes it to redis once the transaction committed.
+type Outbox struct {
+	ID          int        `gorm:"primaryKey"`
+	EventType   string     `gorm:"column:event_type"`
+	Aggregate   string     `gorm:"column:aggregate"`
+	AggregateID string     `gorm:"column:aggregate_id"`
+	Payload     string     `gorm:"column:payload;type:jsonb"`
+	TraceID     string     `gorm:"column:trace_id"`
+	Attempts    int        `gorm:"column:attempts"`
+	LastError   string     `gorm:"column:last_error"`
+	AvailableAt time.Time  `gorm:"column:available_at"`
+	PublishedAt *time.Time `gorm:"column:published_at"`
+	DeadAt      *time.Time `gorm:"column:dead_at"`
+	CreatedAt   time.Time  `gorm:"column:created_at"`
+}
+
+func (Outbox) TableName() string {
+	return "outbox"
+}
+
+type WebhookSubscription struct {
+	ID         int       `gorm:"primaryKey"`
+	URL        string    `gorm:"column:url"`
+	Secret     string    `gorm:"column:secret"`
+	EventTypes string    `gorm:"column:event_types;type:jsonb"`
+	Active     bool      `gorm:"column:active"`
+	CreatedBy  int       `gorm:"column:created_by"`
+	CreatedAt  time.Time `gorm:"column:created_at"`
+}
+
+func (WebhookSubscription) TableName() string {
+	return "webhook_subscriptions"
+}
+
+// WebhookDelivery is one event sent to one subscription, it is also the
+// delivery log: the last response and error stay on the row.
+type WebhookDelivery struct {
+	ID             int        `gorm:"primaryKey"`
+	SubscriptionID int        `gorm:"column:subscription_id"`
+	EventID        int        `gorm:"column:event_id"`
+	EventType      string     `gorm:"column:event_type"`
+	Payload        string     `gorm:"column:payload;type:jsonb"`
+	Status         string     `gorm:"column:status"`
+	Attempts       int        `gorm:"column:attempts"`
+	ResponseStatus int        `gorm:"column:response_status"`
+	ResponseBody   string     `gorm:"column:response_body"`
+	LastError      string     `gorm:"column:last_error"`
+	NextAttemptAt  time.Time  `gorm:"column:next_attempt_at"`
+	DeliveredAt    *time.Time `gorm:"column:delivered_at"`
+	CreatedAt      time.Time  `gorm:"column:created_at"`
+}
+
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+// DueDelivery is a delivery claimed for sending with the target of its
+// subscription.
+type DueDelivery struct {
+	WebhookDelivery `gorm:"embedded"`
+	URL             string `gorm:"column:url"`
+	Secret          string `gorm:"column:secret"`
+}
+
+// StreamEvent is an outbox event read back from the redis stream.
+type StreamEvent struct {
+	StreamID    string
+	ID          int
+	Type        string
+	Aggregate   string
+	AggregateID string
+	Payload     string
+	TraceID     string
+	CreatedAt   string
+}
+
+// Clearance is a library clearance (bebas pustaka) issued to a student, the
+// student data is copied so the certificate stays as it was issued.
+type Clearance struct {
+	ID        int       `gorm:"primaryKey"`
+	Number    string    `gorm:"column:number"`
+	IdUser    int       `gorm:"column:id_user"`
+	NIS       int       `gorm:"column:nis"`
+	Name      string    `gorm:"column:name"`
+	Class     string    `gorm:"column:class"`
+	SubClass  string    `gorm:"column:sub_class"`
+	Major     string    `gorm:"column:major"`
+	Batch     int       `gorm:"column:batch"`
+	Purpose   string    `gorm:"column:purpose"`
+	Signature string    `gorm:"column:signature"`
+	IssuedBy  int       `gorm:"column:issued_by"`
+	IssuedAt  time.Time `gorm:"column:issued_at"`
+}
+
+func (Clearance) TableName() string {
+	return "clearances"
+}
+
+// ClearanceCheck is what keeps a student from being cleared.
+type ClearanceCheck struct {
+	ActiveLoans          int64 `gorm:"column:active_loans"`
+	OutstandingSanctions int64 `gorm:"column:outstanding_sanctions"`
+}
+
+func (c ClearanceCheck) Clear() bool {
+	return c.ActiveLoans == 0 && c.OutstandingSanctions == 0
+}
+
+// Calendar tells which days the library is open.
+type Calendar interface {
+	// NextOpen returns t moved to the first day from t on the library is open.
+	NextOpen(t time.Time) time.Time
+	// ClosedDays counts the days after from up to to the library is closed.
+	ClosedDays(from time.Time, to time.Time) int64
+}
+
+const (
+	ClosureHoliday   = "holiday"
+	ClosureTermBreak = "term_break"
+)
+
+// OpeningHours of the library on a day of the week, Weekday counts from
+// Sunday like time.Weekday.
+type OpeningHours struct {
+	Weekday  int    `gorm:"column:weekday;primaryKey"`
+	IsOpen   bool   `gorm:"column:is_open"`
+	OpensAt  string `gorm:"column:opens_at"`
+	ClosesAt string `gorm:"column:closes_at"`
+}
+
+func (OpeningHours) TableName() string {
+	return "opening_hours"
+}
+
+// Closure is a holiday or a term break, the library is closed from StartDate
+// to EndDate included. UID is only set on closures imported from iCal so an
+// import can run again without duplicating them.
+type Closure struct {
+	ID        int       `gorm:"primaryKey"`
+	Name      string    `gorm:"column:name"`
+	Kind      string    `gorm:"column:kind"`
+	StartDate time.Time `gorm:"column:start_date"`
+	EndDate   time.Time `gorm:"column:end_date"`
+	UID       *string   `gorm:"column:uid"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
+}
+
+func (Closure) TableName() string {
+	return "closures"
+}
+
+const (
+	ReviewVisible = "visible"
+	ReviewHidden  = "hidden"
+)
+
+// Review is what a student thought of a book they returned, one per student
+// and book. A hidden review is kept for the moderators but left out of the
+// listing and of the rating of the book.
+type Review struct {
+	ID           int       `gorm:"primaryKey"`
+	IdUser       int       `gorm:"column:id_user"`
+	IdBook       int       `gorm:"column:id_book"`
+	Rating       int       `gorm:"column:rating"`
+	Body         string    `gorm:"column:body"`
+	Status       string    `gorm:"column:status"`
+	HiddenReason string    `gorm:"column:hidden_reason"`
+	CreatedAt    time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt    time.Time `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (Review) TableName() string {
+	return "reviews"
+}
+
+// ReviewData is a review listed with the names of its student and book.
+type ReviewData struct {
+	Review
+	StudentName string `gorm:"column:student_name"`
+	BookName    string `gorm:"column:book_name"`
+}
+
+type ReviewFilter struct {
+	IdBook int
+	Status string
+}
+
+// Rating is the average of the visible reviews of a book.
+type Rating struct {
+	RatingAvg   float64 `gorm:"column:rating_avg"`
+	RatingCount int     `gorm:"column:rating_count"`
+}
+
+// Weights of what relates a candidate book to a student, a book borrowed by
+// the students who borrowed the same books weighs the most.
+const (
+	WeightCoBorrowed       = 3
+	WeightSharedCategories = 2
+	WeightSameAuthor       = 2
+	WeightPopular          = 1
+)
+
+// Reasons a book is recommended.
+const (
+	ReasonCoBorrowed = "co_borrowed"
+	ReasonCategory   = "category"
+	ReasonAuthor     = "author"
+	ReasonPopular    = "popular_in_class"
+)
+
+// Candidate is a book a student hasn't borrowed yet with how it relates to
+// what they and their classmates borrowed: the students who borrowed one of
+// their books and this one, the categories it shares with their books, their
+// books by the same author and the students of their class and major who
+// borrowed it.
+type Candidate struct {
+	ID               int     `gorm:"column:id"`
+	Name             string  `gorm:"column:name"`
+	Author           string  `gorm:"column:author"`
+	Publisher        string  `gorm:"column:publisher"`
+	AvailableStock   int     `gorm:"column:available_stock"`
+	RatingAvg        float64 `gorm:"column:rating_avg"`
+	CoBorrowed       int     `gorm:"column:co_borrowed"`
+	SharedCategories int     `gorm:"column:shared_categories"`
+	SameAuthor       int     `gorm:"column:same_author"`
+	Popular          int     `gorm:"column:popular"`
+}
+
+func (c Candidate) Score() int {
+	return WeightCoBorrowed*c.CoBorrowed + WeightSharedCategories*c.SharedCategories + WeightSameAuthor*c.SameAuthor + WeightPopular*c.Popular
+}
+
+// Reasons lists what relates the book to the student, strongest first.
+func (c Candidate) Reasons() []string {
+	var reasons = make([]string, 0, 4)
+	if c.CoBorrowed > 0 {
+		reasons = append(reasons, ReasonCoBorrowed)
+	}
+	if c.SharedCategories > 0 {
+		reasons = append(reasons, ReasonCategory)
+	}
+	if c.SameAuthor > 0 {
+		reasons = append(reasons, ReasonAuthor)
+	}
+	if c.Popular > 0 {
+		reasons = append(reasons, ReasonPopular)
+	}
+	return reasons
+}
+
+// ReadingList is the required reading of a class, SubClass and Major are
+// empty when every sub class or major of the class is targeted. The loans of
+// a book borrowed from StartsAt on count towards the list.
+type ReadingList struct {
+	ID          int               `gorm:"primaryKey"`
+	Title       string            `gorm:"column:title"`
+	Description string            `gorm:"column:description"`
+	Class       string            `gorm:"column:class"`
+	SubClass    string            `gorm:"column:sub_class"`
+	Major       string            `gorm:"column:major"`
+	StartsAt    time.Time         `gorm:"column:starts_at"`
+	DueAt       time.Time         `gorm:"column:due_at"`
+	CreatedBy   int               `gorm:"column:created_by"`
+	CreatedAt   time.Time         `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt   time.Time         `gorm:"column:updated_at;autoUpdateTime"`
+	Books       []ReadingListBook `gorm:"foreignKey:IdList"`
+}
+
+func (ReadingList) TableName() string {
+	return "reading_lists"
+}
+
+// Targets reports whether a student of the class, sub class and major has to
+// read the list.
+func (r ReadingList) Targets(s StudentData) bool {
+	return r.Class == s.Class && (r.SubClass == "" || r.SubClass == s.SubClass) && (r.Major == "" || r.Major == s.Major)
+}
+
+// ReadingListBook is a book of a reading list in reading order, the name and
+// author are read from the book.
+type ReadingListBook struct {
+	IdList   int    `gorm:"column:id_list;primaryKey"`
+	IdBook   int    `gorm:"column:id_book;primaryKey"`
+	Position int    `gorm:"column:position"`
+	Name     string `gorm:"column:name;->"`
+	Author   string `gorm:"column:author;->"`
+}
+
+func (ReadingListBook) TableName() string {
+	return "reading_list_books"
+}
+
+type ReadingListFilter struct {
+	Class     string
+	Major     string
+	CreatedBy int
+}
+
+// How far a student got with a book of a reading list.
+const (
+	ReadingRead     = "read"
+	ReadingBorrowed = "borrowed"
+	ReadingTodo     = "todo"
+)
+
+// ReadingProgress is how far a student got with a book of a reading list,
+// ReturnedAt is the first return of the book since the list started.
+type ReadingProgress struct {
+	IdUser     int        `gorm:"column:id_user"`
+	IdBook     int        `gorm:"column:id_book"`
+	ReturnedAt *time.Time `gorm:"column:returned_at"`
+}
+
+// Where a suggested book is in the acquisition, received and rejected close
+// the suggestion.
+const (
+	SuggestionSubmitted   = "submitted"
+	SuggestionUnderReview = "under_review"
+	SuggestionOrdered     = "ordered"
+	SuggestionReceived    = "received"
+	SuggestionRejected    = "rejected"
+)
+
+// suggestionNext is where a suggestion can move from each status, a
+// suggestion can be rejected until the book is received.
+var suggestionNext = map[string][]string{
+	SuggestionSubmitted:   {SuggestionUnderReview, SuggestionRejected},
+	SuggestionUnderReview: {SuggestionOrdered, SuggestionRejected},
+	SuggestionOrdered:     {SuggestionReceived, SuggestionRejected},
+}
+
+// Suggestion is a book a student asked the library to buy, Votes counts the
+// students asking for it, the one who suggested it included. IdBook is set
+// once the book is received.
+type Suggestion struct {
+	ID        int       `gorm:"primaryKey"`
+	IdUser    int       `gorm:"column:id_user"`
+	Title     string    `gorm:"column:title"`
+	Author    string    `gorm:"column:author"`
+	ISBN      string    `gorm:"column:isbn"`
+	Status    string    `gorm:"column:status"`
+	Votes     int       `gorm:"column:votes"`
+	Note      string    `gorm:"column:note"`
+	IdBook    *int      `gorm:"column:id_book"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt time.Time `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (Suggestion) TableName() string {
+	return "suggestions"
+}
+
+// CanMove reports whether the suggestion can go to status from where it is.
+func (s Suggestion) CanMove(status string) bool {
+	for _, next := range suggestionNext[s.Status] {
+		if next == status {
+			return true
+		}
+	}
+	return false
+}
+
+// Open reports whether students can still vote for the suggestion.
+func (s Suggestion) Open() bool {
+	return s.Status != SuggestionReceived && s.Status != SuggestionRejected
+}
+
+// SuggestionData is a suggestion listed with the name of the student who
+// made it and whether the student listing it voted for it.
+type SuggestionData struct {
+	Suggestion
+	StudentName string `gorm:"column:student_name;->"`
+	Voted       bool   `gorm:"column:voted;->"`
+}
+
+// SuggestionFilter narrows the suggestions, Voter is the student whose votes
+// are reported.
+type SuggestionFilter struct {
+	Status string
+	Voter  int
+}
+
+type SuggestionVote struct {
+	IdSuggestion int       `gorm:"column:id_suggestion;primaryKey"`
+	IdUser       int       `gorm:"column:id_user;primaryKey"`
+	CreatedAt    time.Time `gorm:"column:created_at;autoCreateTime"`
+}
+
+func (SuggestionVote) TableName() string {
+	return "suggestion_votes"
+}
+
+// Hold keeps a copy of a book for a student until ExpiresAt, it is fulfilled
+// when they borrow the book. Other students can only borrow the copies left
+// once the open holds are taken out.
+type Hold struct {
+	ID           int        `gorm:"primaryKey"`
+	IdUser       int        `gorm:"column:id_user"`
+	IdBook       int        `gorm:"column:id_book"`
+	IdSuggestion *int       `gorm:"column:id_suggestion"`
+	ExpiresAt    time.Time  `gorm:"column:expires_at"`
+	FulfilledAt  *time.Time `gorm:"column:fulfilled_at"`
+	CreatedAt    time.Time  `gorm:"column:created_at;autoCreateTime"`
+}
+
+func (Hold) TableName() string {
+	return "holds"
+}
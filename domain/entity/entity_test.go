@@ -69,36 +69,64 @@ func TestAcademicInfo_ValidateClass(t *testing.T) {
 }
 
 func TestLoan_ValidateDateFormat(t *testing.T) {
+	jakarta, _ := time.LoadLocation("Asia/Jakarta")
 	l := &Loan{}
 	t.Run("Valid_Format", func(t *testing.T) {
-		err := l.ValidateDateFormat("17-08-2025")
+		err := l.ValidateDateFormat("17-08-2025", jakarta)
 		assert.NoError(t, err)
-		assert.Equal(t, 2025, l.MustReturnedAt.Year())
+		assert.Equal(t, time.Date(2025, 8, 17, 23, 59, 59, 0, jakarta), l.MustReturnedAt)
+	})
+
+	t.Run("ISO_Date", func(t *testing.T) {
+		err := l.ValidateDateFormat("2025-08-17", jakarta)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2025, 8, 17, 23, 59, 59, 0, jakarta), l.MustReturnedAt)
+	})
+
+	t.Run("ISO_Timestamp_In_Library_Day", func(t *testing.T) {
+		err := l.ValidateDateFormat("2025-08-16T20:00:00Z", jakarta)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2025, 8, 17, 23, 59, 59, 0, jakarta), l.MustReturnedAt)
 	})
 
 	t.Run("Invalid_Format", func(t *testing.T) {
-		err := l.ValidateDateFormat("2025/08/17")
+		err := l.ValidateDateFormat("2025/08/17", jakarta)
 		assert.Error(t, err)
 	})
 }
 
 func TestLoan_ValidateDate(t *testing.T) {
+	jakarta, _ := time.LoadLocation("Asia/Jakarta")
+	now := time.Date(2026, 8, 14, 22, 0, 0, 0, jakarta)
+
 	t.Run("Date_Within_Limit", func(t *testing.T) {
-		l := &Loan{MustReturnedAt: time.Now().AddDate(0, 0, 3)}
-		err := l.ValidateDate()
+		l := &Loan{MustReturnedAt: now.AddDate(0, 0, 3)}
+		err := l.ValidateDate(now)
 		assert.NoError(t, err)
 	})
 
+	t.Run("Due_Today", func(t *testing.T) {
+		l := &Loan{}
+		assert.NoError(t, l.ValidateDateFormat("14-08-2026", jakarta))
+		assert.NoError(t, l.ValidateDate(now))
+	})
+
+	t.Run("Due_In_7_Days", func(t *testing.T) {
+		l := &Loan{}
+		assert.NoError(t, l.ValidateDateFormat("21-08-2026", jakarta))
+		assert.NoError(t, l.ValidateDate(now))
+	})
+
 	t.Run("Date_Over_Limit", func(t *testing.T) {
-		l := &Loan{MustReturnedAt: time.Now().AddDate(0, 0, 10)}
-		err := l.ValidateDate()
+		l := &Loan{MustReturnedAt: now.AddDate(0, 0, 10)}
+		err := l.ValidateDate(now)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "maximum loan limit")
 	})
 
 	t.Run("Date_In_Past", func(t *testing.T) {
-		l := &Loan{MustReturnedAt: time.Now().AddDate(0, 0, -1)}
-		err := l.ValidateDate()
+		l := &Loan{MustReturnedAt: now.AddDate(0, 0, -1)}
+		err := l.ValidateDate(now)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "past")
 	})
@@ -115,7 +143,7 @@ func TestLdUpdate_GiveSanctions(t *testing.T) {
 			ReturnedAt:     &returned,
 			Sanctions:      &sanction,
 		}
-		ldu.GiveSanctions()
+		ldu.GiveSanctions(nil)
 		assert.True(t, *ldu.Sanctions > 0)
 	})
 
@@ -129,9 +157,25 @@ func TestLdUpdate_GiveSanctions(t *testing.T) {
 			ReturnedAt:     &returned,
 			Sanctions:      &sanction,
 		}
-		ldu.GiveSanctions()
+		ldu.GiveSanctions(nil)
 		assert.Equal(t, int64(0), *ldu.Sanctions)
 	})
+
+	t.Run("Late_By_Local_Days", func(t *testing.T) {
+		jakarta, _ := time.LoadLocation("Asia/Jakarta")
+		// 00:30 in Jakarta the day after the due date is still the due date
+		// in UTC, the book is one day late all the same
+		returned := time.Date(2026, 8, 14, 17, 30, 0, 0, time.UTC)
+		var sanction int64
+
+		ldu := &LdUpdate{
+			MustReturnedAt: time.Date(2026, 8, 14, 23, 59, 59, 0, jakarta),
+			ReturnedAt:     &returned,
+			Sanctions:      &sanction,
+		}
+		ldu.GiveSanctions(nil)
+		assert.Equal(t, int64(2000), *ldu.Sanctions)
+	})
 }
 
 func TestTableNames(t *testing.T) {
@@ -140,4 +184,28 @@ func TestTableNames(t *testing.T) {
 	assert.Equal(t, "books", Book{}.TableName())
 	assert.Equal(t, "loan", Loan{}.TableName())
 	assert.Equal(t, "connections", Connections{}.TableName())
-}
\ No newline at end of file
+}
+func TestCandidate_Score(t *testing.T) {
+	c := Candidate{CoBorrowed: 2, SharedCategories: 1, Popular: 4}
+	assert.Equal(t, 12, c.Score())
+	assert.Equal(t, []string{ReasonCoBorrowed, ReasonCategory, ReasonPopular}, c.Reasons())
+
+	assert.Equal(t, 0, Candidate{}.Score())
+	assert.Empty(t, Candidate{}.Reasons())
+}
+
+func TestSuggestion_CanMove(t *testing.T) {
+	s := Suggestion{Status: SuggestionSubmitted}
+	assert.True(t, s.CanMove(SuggestionUnderReview))
+	assert.True(t, s.CanMove(SuggestionRejected))
+	assert.False(t, s.CanMove(SuggestionOrdered))
+	assert.False(t, s.CanMove(SuggestionReceived))
+
+	s.Status = SuggestionOrdered
+	assert.True(t, s.CanMove(SuggestionReceived))
+	assert.True(t, s.Open())
+
+	s.Status = SuggestionReceived
+	assert.False(t, s.CanMove(SuggestionRejected))
+	assert.False(t, s.Open())
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"stmnplibrary/domain/entity"
+	"stmnplibrary/pagination"
 )
 
 type AuthRepository interface {
@@ -16,14 +17,9 @@ type AuthRepository interface {
 
 type UserRepository interface {
 	RateLimiter(ctx context.Context, key string) error
-	RedisZR(ctx context.Context, key string, start int, stop int) ([]string, error)
-	RedisZS(ctx context.Context, key string, score float64, member interface{}) error
 	RedisWtx(ctx context.Context, fn func(ctx context.Context) error) error
-	RedisWp(ctx context.Context, fn func(ctx context.Context) (interface{}, error)) (interface{}, error)
-	RedisHGetAll(ctx context.Context, key string, dest any) error
-	RedisHSET(ctx context.Context, key string, value any) error
 	RedisSet(ctx context.Context, key string, data any, ttl time.Duration) error
-	RedisGet(ctx context.Context, key string) (any, error)
+	RedisGet(ctx context.Context, key string) ([]byte, error)
 	RedisDel(ctx context.Context, key string) error
 
 	WithContext(ctx context.Context, fn func(context.Context) error) error
@@ -32,36 +28,165 @@ type UserRepository interface {
 	GetNIS(ctx context.Context, nis int) error
 	GetEmail(ctx context.Context, email string) error
 
-	GetBooks(ctx context.Context, offset int) ([]entity.Book, error)
-	GetBooksByAuthor(ctx context.Context, author string, offset int) ([]entity.Book, error)
-	GetBooksByCategory(ctx context.Context, category []string, offset int) ([]entity.Book, error)
+	GetBooks(ctx context.Context, page pagination.Page) ([]entity.Book, int64, error)
+	GetBooksByAuthor(ctx context.Context, author string, page pagination.Page) ([]entity.Book, int64, error)
+	GetBooksByCategory(ctx context.Context, category []string, page pagination.Page) ([]entity.Book, int64, error)
 
 	CheckLoan(ctx context.Context, idBook int, idUser int) error
 	UpdateLimitLoan(ctx context.Context, id int) error
-	UpdateBookStock(ctx context.Context, idBook int) error
+	UpdateBookStock(ctx context.Context, idBook int, idUser int, now time.Time) error
+	FulfillHold(ctx context.Context, idBook int, idUser int, now time.Time) error
 	CreateLoan(ctx context.Context, loanData entity.Loan) error
 }
 
 type AdminRepository interface {
 	WithTx(ctx context.Context, fn func(ctx context.Context) error) error
 
-	GetLDDone(ctx context.Context, offset int) ([]entity.LoanData, error)
-	GetLDDont(ctx context.Context, offset int) ([]entity.LoanData, error)
+	GetLDDone(ctx context.Context, page pagination.Page) ([]entity.LoanData, int64, error)
+	GetLDDont(ctx context.Context, page pagination.Page) ([]entity.LoanData, int64, error)
 
-	GetLoanData(ctx context.Context, offset int) ([]entity.LoanData, error)
-	GetStudentId(ctx context.Context, studentName string) (int, error)
+	GetLoanData(ctx context.Context, page pagination.Page) ([]entity.LoanData, int64, error)
+	GetStudentId(ctx context.Context, nis int) (int, error)
 	GetBookId(ctx context.Context, isbn string) (int, error)
+	GetActiveLoan(ctx context.Context, id int) (entity.Loan, error)
 	GetStudentLoan(ctx context.Context, idUser int, idBook int) (entity.LdUpdate, error)
-	UpdateTabLoan(ctx context.Context, idUser int, idBook int, sanctions int64, returnedAt time.Time) error
+	UpdateTabLoan(ctx context.Context, idUser int, idBook int, data entity.LoanReturn) error
 	UpdateStock(ctx context.Context, idBook int) error
+	WriteOffStock(ctx context.Context, idBook int) error
+	GetBookPrice(ctx context.Context, idBook int) (int64, error)
 	UpdateMaxBook(ctx context.Context, id int) error
 
+	GetStudents(ctx context.Context, filter entity.StudentFilter, page pagination.Page) ([]entity.StudentData, int64, error)
+	GetStudent(ctx context.Context, nis int) (entity.StudentData, error)
+	GetStudentLoans(ctx context.Context, idUser int) ([]entity.LoanData, error)
+	UpdateStudent(ctx context.Context, nis int, data entity.StudentData) error
+	DeactivateStudent(ctx context.Context, nis int) error
+	UpdateStudentRole(ctx context.Context, nis int, from string, to string) error
+
+	GetStats(ctx context.Context) (entity.Stats, error)
+
 	AddCategory(ctx context.Context, data entity.Category) error
 	AddBook(ctx context.Context, data *entity.BookData) error
 	AddConnections(ctx context.Context, data []entity.Connections) error
 
-	RedisSETNX(ctx context.Context, key string, value string, ttl time.Duration) (bool, error)
 	RedisSet(ctx context.Context, key string, data any, ttl time.Duration) error
-	RedisGet(ctx context.Context, key string) (any, error)
+	RedisGet(ctx context.Context, key string) ([]byte, error)
 	RedisDel(ctx context.Context, key string) error
 }
+
+type OutboxRepository interface {
+	WithTx(ctx context.Context, fn func(ctx context.Context) error) error
+	Add(ctx context.Context, data entity.Outbox) error
+	GetPending(ctx context.Context, limit int) ([]entity.Outbox, error)
+	MarkPublished(ctx context.Context, ids []int) error
+	MarkRetry(ctx context.Context, id int, lastErr string, availableAt time.Time) error
+	MarkDead(ctx context.Context, id int, lastErr string) error
+	XAdd(ctx context.Context, stream string, maxLen int64, values map[string]any) error
+}
+
+type WebhookRepository interface {
+	WithTx(ctx context.Context, fn func(ctx context.Context) error) error
+	AddSubscription(ctx context.Context, data *entity.WebhookSubscription) error
+	GetSubscriptions(ctx context.Context, activeOnly bool) ([]entity.WebhookSubscription, error)
+	DeactivateSubscription(ctx context.Context, id int) error
+
+	AddDeliveries(ctx context.Context, data []entity.WebhookDelivery) error
+	ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]entity.DueDelivery, error)
+	SaveAttempt(ctx context.Context, data entity.WebhookDelivery) error
+	GetDeliveries(ctx context.Context, subscriptionID int, status string, page pagination.Page) ([]entity.WebhookDelivery, int64, error)
+	ResetDelivery(ctx context.Context, id int) error
+
+	CreateGroup(ctx context.Context, stream string, group string) error
+	ReadEvents(ctx context.Context, stream string, group string, consumer string, count int, minIdle time.Duration) ([]entity.StreamEvent, error)
+	AckEvents(ctx context.Context, stream string, group string, ids []string) error
+}
+
+type ClearanceRepository interface {
+	WithTx(ctx context.Context, fn func(ctx context.Context) error) error
+	LockStudent(ctx context.Context, nis int) (entity.StudentData, error)
+	GetStudentsByBatch(ctx context.Context, batch int) ([]entity.StudentData, error)
+	CheckClearance(ctx context.Context, idUser int) (entity.ClearanceCheck, error)
+	SettleSanctions(ctx context.Context, idUser int) (int64, error)
+
+	AddClearance(ctx context.Context, data *entity.Clearance) error
+	GetClearance(ctx context.Context, number string) (entity.Clearance, error)
+	GetClearanceByUser(ctx context.Context, idUser int, purpose string) (entity.Clearance, error)
+}
+
+type CalendarRepository interface {
+	WithTx(ctx context.Context, fn func(ctx context.Context) error) error
+	GetOpeningHours(ctx context.Context) ([]entity.OpeningHours, error)
+	SetOpeningHours(ctx context.Context, hours []entity.OpeningHours) error
+
+	GetClosures(ctx context.Context, from time.Time, to time.Time) ([]entity.Closure, error)
+	AddClosure(ctx context.Context, data *entity.Closure) error
+	UpsertClosures(ctx context.Context, data []entity.Closure) (int64, error)
+	DeleteClosure(ctx context.Context, id int) (entity.Closure, error)
+}
+
+type ReviewRepository interface {
+	WithTx(ctx context.Context, fn func(ctx context.Context) error) error
+	LockBook(ctx context.Context, idBook int) error
+	HasReturnedLoan(ctx context.Context, idUser int, idBook int) (bool, error)
+	UpdateBookRating(ctx context.Context, idBook int) (entity.Rating, error)
+
+	GetReview(ctx context.Context, id int) (entity.Review, error)
+	GetReviewByUser(ctx context.Context, idUser int, idBook int) (entity.Review, error)
+	GetReviews(ctx context.Context, filter entity.ReviewFilter, page pagination.Page) ([]entity.ReviewData, int64, error)
+	UpsertReview(ctx context.Context, data *entity.Review) error
+	SetReviewStatus(ctx context.Context, id int, status string, reason string) (entity.Review, error)
+}
+
+type RecommendationRepository interface {
+	GetStudent(ctx context.Context, id int) (entity.StudentData, error)
+	GetStudents(ctx context.Context, afterID int, limit int) ([]entity.StudentData, error)
+	GetCandidates(ctx context.Context, student entity.StudentData, since time.Time, limit int) ([]entity.Candidate, error)
+
+	RedisSet(ctx context.Context, key string, data any, ttl time.Duration) error
+	RedisGet(ctx context.Context, key string) ([]byte, error)
+}
+
+type ReadingListRepository interface {
+	WithTx(ctx context.Context, fn func(ctx context.Context) error) error
+	GetStudent(ctx context.Context, id int) (entity.StudentData, error)
+	GetStudents(ctx context.Context, list entity.ReadingList) ([]entity.StudentData, error)
+	CountBooks(ctx context.Context, ids []int) (int64, error)
+
+	AddReadingList(ctx context.Context, data *entity.ReadingList) error
+	UpdateReadingList(ctx context.Context, data *entity.ReadingList) error
+	SetReadingListBooks(ctx context.Context, idList int, books []entity.ReadingListBook) error
+	DeleteReadingList(ctx context.Context, id int) (entity.ReadingList, error)
+	GetReadingList(ctx context.Context, id int) (entity.ReadingList, error)
+	GetReadingLists(ctx context.Context, filter entity.ReadingListFilter, page pagination.Page) ([]entity.ReadingList, int64, error)
+	GetStudentReadingLists(ctx context.Context, student entity.StudentData) ([]entity.ReadingList, error)
+	GetProgress(ctx context.Context, list entity.ReadingList, idUsers []int) ([]entity.ReadingProgress, error)
+}
+
+type SuggestionRepository interface {
+	WithTx(ctx context.Context, fn func(ctx context.Context) error) error
+	GetBookID(ctx context.Context, isbn string) (int, error)
+	LockBookStock(ctx context.Context, idBook int) (int, error)
+
+	AddSuggestion(ctx context.Context, data *entity.Suggestion) error
+	GetOpenSuggestion(ctx context.Context, isbn string) (entity.Suggestion, error)
+	LockSuggestion(ctx context.Context, id int) (entity.Suggestion, error)
+	GetSuggestions(ctx context.Context, filter entity.SuggestionFilter, page pagination.Page) ([]entity.SuggestionData, int64, error)
+	UpdateSuggestion(ctx context.Context, data *entity.Suggestion) error
+
+	AddVote(ctx context.Context, idSuggestion int, idUser int) (bool, error)
+	CountVotes(ctx context.Context, idSuggestion int) (int, error)
+	GetRequesters(ctx context.Context, idSuggestion int) ([]int, error)
+	AddHolds(ctx context.Context, holds []entity.Hold) error
+}
+
+type IdempotencyRepository interface {
+	RedisSETNX(ctx context.Context, key string, data any, ttl time.Duration) (bool, error)
+	RedisSet(ctx context.Context, key string, data any, ttl time.Duration) error
+	RedisGet(ctx context.Context, key string) ([]byte, error)
+	RedisDel(ctx context.Context, key string) error
+}
+
+type AuditRepository interface {
+	Record(ctx context.Context, data entity.Audit) error
+	GetAudits(ctx context.Context, filter entity.AuditFilter, page pagination.Page) ([]entity.Audit, int64, error)
+}
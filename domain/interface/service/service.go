@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"io"
 
 	"stmnplibrary/dto"
 	"stmnplibrary/security/jwt/claims"
@@ -19,19 +20,99 @@ type UserService interface {
 	Register(ctx context.Context, data *dto.Students) ([]string, error)
 	Logout(ctx context.Context) error
 
-	GetBooks(ctx context.Context, page int) ([]dto.Books, error)
-	GetBooksByAuthor(ctx context.Context, author string, page int) ([]dto.Books, error)
-	GetBooksByCategory(ctx context.Context, category []string, page int) ([]dto.Books, error)
+	GetBooks(ctx context.Context, query dto.BookQuery) ([]dto.Books, *dto.Meta, error)
+	GetBooksByAuthor(ctx context.Context, author string, query dto.BookQuery) ([]dto.Books, *dto.Meta, error)
+	GetBooksByCategory(ctx context.Context, category []string, query dto.BookQuery) ([]dto.Books, *dto.Meta, error)
 	Loan(ctx context.Context, loanInfo dto.Loan) error
 }
 
 type AdminService interface {
-	GetLoanData(ctx context.Context, page int) ([]dto.LoanData, error)
-	GetLDDone(ctx context.Context, page int) ([]dto.LoanData, error)
-	GetLDDont(ctx context.Context, page int) ([]dto.LoanData, error)
+	GetLoanData(ctx context.Context, query dto.PageQuery) ([]dto.LoanData, *dto.Meta, error)
+	GetLDDone(ctx context.Context, query dto.PageQuery) ([]dto.LoanData, *dto.Meta, error)
+	GetLDDont(ctx context.Context, query dto.PageQuery) ([]dto.LoanData, *dto.Meta, error)
 	
 	Confirm(ctx context.Context, data dto.Confirm) error
-	
+	ReturnLoan(ctx context.Context, id int, data dto.ReturnOutcome) error
+
+	GetStudents(ctx context.Context, filter dto.StudentFilter) ([]dto.StudentData, *dto.Meta, error)
+	GetStudent(ctx context.Context, nis int) (*dto.StudentProfile, error)
+	UpdateStudent(ctx context.Context, nis int, data dto.UpdateStudent) ([]string, error)
+	DeactivateStudent(ctx context.Context, nis int) error
+	UpdateStudentRole(ctx context.Context, nis int, data dto.UpdateRole) error
+
 	AddCategory(ctx context.Context, data dto.Category) error
 	AddBook(ctx context.Context, data dto.BookData) error
 }
+
+type AuditService interface {
+	GetAudits(ctx context.Context, filter dto.AuditFilter) ([]dto.Audit, *dto.Meta, error)
+}
+
+type IdempotencyService interface {
+	Begin(ctx context.Context, key string, fingerprint string) (*dto.StoredResponse, error)
+	Complete(ctx context.Context, key string, fingerprint string, res dto.StoredResponse) error
+	Release(ctx context.Context, key string) error
+}
+
+type OutboxService interface {
+	Relay(ctx context.Context) (int, error)
+}
+
+type WebhookService interface {
+	AddWebhook(ctx context.Context, data dto.AddWebhook) (*dto.Webhook, error)
+	GetWebhooks(ctx context.Context) ([]dto.Webhook, error)
+	DeleteWebhook(ctx context.Context, id int) error
+	GetDeliveries(ctx context.Context, id int, filter dto.DeliveryFilter) ([]dto.WebhookDelivery, *dto.Meta, error)
+	ReplayDelivery(ctx context.Context, id int) error
+
+	Consume(ctx context.Context) (int, error)
+	Dispatch(ctx context.Context) (int, error)
+}
+
+type CalendarService interface {
+	GetCalendar(ctx context.Context, query dto.CalendarQuery) (*dto.Calendar, error)
+	SetOpeningHours(ctx context.Context, data dto.SetOpeningHours) ([]dto.OpeningHours, error)
+	AddClosure(ctx context.Context, data dto.AddClosure) (*dto.Closure, error)
+	DeleteClosure(ctx context.Context, id int) error
+	ImportICal(ctx context.Context, file io.Reader, kind string) (*dto.CalendarImport, error)
+}
+
+type ReadingListService interface {
+	AddReadingList(ctx context.Context, data dto.SaveReadingList) (*dto.ReadingList, error)
+	UpdateReadingList(ctx context.Context, id int, data dto.SaveReadingList) (*dto.ReadingList, error)
+	DeleteReadingList(ctx context.Context, id int) error
+	GetReadingList(ctx context.Context, id int) (*dto.ReadingList, error)
+	GetReadingLists(ctx context.Context, filter dto.ReadingListFilter) ([]dto.ReadingList, *dto.Meta, error)
+	GetReport(ctx context.Context, id int) (*dto.ReadingListReport, error)
+	GetStudentReadingLists(ctx context.Context) ([]dto.StudentReadingList, error)
+}
+
+type SuggestionService interface {
+	AddSuggestion(ctx context.Context, data dto.AddSuggestion) (*dto.Suggestion, error)
+	GetSuggestions(ctx context.Context, filter dto.SuggestionFilter) ([]dto.Suggestion, *dto.Meta, error)
+	VoteSuggestion(ctx context.Context, id int) (*dto.Suggestion, error)
+	MoveSuggestion(ctx context.Context, id int, data dto.MoveSuggestion) (*dto.Suggestion, error)
+	ReceiveSuggestion(ctx context.Context, id int, data dto.ReceiveSuggestion) (*dto.ReceivedSuggestion, error)
+}
+
+type ReviewService interface {
+	PostReview(ctx context.Context, idBook int, data dto.PostReview) (*dto.Review, error)
+	GetBookReviews(ctx context.Context, idBook int, query dto.PageQuery) ([]dto.Review, *dto.Meta, error)
+	GetReviews(ctx context.Context, filter dto.ReviewFilter) ([]dto.Review, *dto.Meta, error)
+	HideReview(ctx context.Context, id int, data dto.HideReview) (*dto.Review, error)
+	UnhideReview(ctx context.Context, id int) (*dto.Review, error)
+}
+
+type RecommendationService interface {
+	GetRecommendations(ctx context.Context) (*dto.Recommendations, error)
+	Refresh(ctx context.Context) (int, error)
+}
+
+type ClearanceService interface {
+	Issue(ctx context.Context, nis int, data dto.IssueClearance) (*dto.Clearance, error)
+	IssueBatch(ctx context.Context, data dto.IssueBatchClearance) (*dto.ClearanceBatch, error)
+	GetClearance(ctx context.Context, number string) (*dto.Clearance, error)
+	Verify(ctx context.Context, number string, signature string) (*dto.ClearanceVerification, error)
+	Render(ctx context.Context, number string) ([]byte, error)
+	SettleSanctions(ctx context.Context, nis int) (*dto.SettledSanctions, error)
+}
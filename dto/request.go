@@ -6,7 +6,7 @@ type Students struct {
 	PhoneNumber string `json:"phone_number" binding:"required,max=15"`
 	Email       string `json:"email" binding:"required,email,min=10,max=20"`
 	Password    string `json:"password" binding:"required"`
-	Class       string `json:"class" binding:"required`
+	Class       string `json:"class" binding:"required"`
 	SubClass    string `json:"sub_class" binding:"required,oneof=A B C"`
 	Major       string `json:"major" binding:"required,oneof=RPL SIJA PSPT TPTU TEI MEKA TOI TEK IOP"`
 	Batch       int    `json:"batch" binding:"required,number"`
@@ -22,6 +22,12 @@ type Loan struct {
 	ReturnedAt string `json:"returned_at" binding:"required"`
 }
 
+// LoanBook sets the day the book is due, dd-mm-yyyy or ISO-8601 like
+// 2026-12-25, it is due at the end of that day in the library timezone.
+type LoanBook struct {
+	ReturnedAt string `json:"returned_at" binding:"required" example:"2026-12-25"`
+}
+
 type Category struct {
 	Name string `json:"category_name" binding:"required"`
 }
@@ -34,10 +40,186 @@ type BookData struct {
 	Description    string `json:"description" binding:"required,min=50,max=300"`
 	Stock          int    `json:"stock" binding:"required,number"`
 	AvailableStock int    `json:"available_stock" binding:"required,number"`
+	Price          int64  `json:"price" binding:"min=0" example:"85000"`
 	IDCategory     []int  `json:"id_category" binding:"required,dive,gt=0"`
 }
 
+// ReturnOutcome is how a book came back. A damaged book needs a condition
+// note and is charged the repair fee, a lost book is charged fee or, when it
+// is empty, the replacement price of the book.
+type ReturnOutcome struct {
+	Outcome       string `json:"outcome" binding:"omitempty,oneof=returned damaged lost" example:"returned"`
+	ConditionNote string `json:"condition_note" binding:"required_if=Outcome damaged,max=300"`
+	Fee           int64  `json:"fee" binding:"min=0"`
+}
+
 type Confirm struct {
-	Student string `json:"student" binding:"required"`
+	NIS  int    `json:"nis" binding:"required,number"`
 	ISBN string `json:"isbn" binding:"required"`
-}
\ No newline at end of file
+	ReturnOutcome
+}
+
+// PageQuery selects a keyset page, cursor is the next_cursor of the previous
+// response and is empty for the first page.
+type PageQuery struct {
+	Cursor   string `form:"cursor" binding:"omitempty,max=512"`
+	PageSize int    `form:"page_size" binding:"omitempty,gt=0,lte=100"`
+}
+
+type BookQuery struct {
+	PageQuery
+	Sort string `form:"sort" binding:"omitempty,oneof=title author newest availability rating"`
+}
+
+type StudentFilter struct {
+	NIS   int    `form:"nis" binding:"omitempty,number"`
+	Name  string `form:"name" binding:"omitempty,max=30"`
+	Class string `form:"class" binding:"omitempty,oneof=X XI XII XIII"`
+	Major string `form:"major" binding:"omitempty,oneof=RPL SIJA PSPT TPTU TEI MEKA TOI TEK IOP"`
+	Batch int    `form:"batch" binding:"omitempty,number"`
+	PageQuery
+}
+
+type UpdateStudent struct {
+	Name        *string `json:"name" binding:"omitempty,min=5,max=30"`
+	PhoneNumber *string `json:"phone_number" binding:"omitempty,max=15"`
+	Email       *string `json:"email" binding:"omitempty,email,min=10,max=20"`
+	Class       *string `json:"class" binding:"omitempty"`
+	SubClass    *string `json:"sub_class" binding:"omitempty,oneof=A B C"`
+	Major       *string `json:"major" binding:"omitempty,oneof=RPL SIJA PSPT TPTU TEI MEKA TOI TEK IOP"`
+	Batch       *int    `json:"batch" binding:"omitempty,number"`
+}
+type UpdateRole struct {
+	Role string `json:"role" binding:"required,oneof=students teacher"`
+}
+type AuditFilter struct {
+	Actor  int    `form:"actor" binding:"omitempty,number"`
+	Entity string `form:"entity" binding:"omitempty,oneof=book category clearance closure loan opening_hours reading_list review student suggestion webhook webhook_delivery"`
+	From   string `form:"from" binding:"omitempty"`
+	To     string `form:"to" binding:"omitempty"`
+	PageQuery
+}
+
+// AddWebhook subscribes url to event_types, secret signs every delivery and
+// is generated when left empty.
+type AddWebhook struct {
+	URL        string   `json:"url" binding:"required,http_url,max=2048" example:"https://academic.school.sch.id/hooks/library"`
+	Secret     string   `json:"secret" binding:"omitempty,min=16,max=128"`
+	EventTypes []string `json:"event_types" binding:"required,min=1,dive,oneof=LoanCreated LoanReturned SanctionIssued BookAdded SuggestionReceived" example:"LoanReturned,SanctionIssued"`
+}
+
+type DeliveryFilter struct {
+	Status string `form:"status" binding:"omitempty,oneof=pending succeeded failed dead"`
+	PageQuery
+}
+
+type IssueClearance struct {
+	Purpose string `json:"purpose" binding:"required,oneof=graduation transfer" example:"graduation"`
+}
+
+type IssueBatchClearance struct {
+	Batch   int    `json:"batch" binding:"required,min=1" example:"2024"`
+	Purpose string `json:"purpose" binding:"required,oneof=graduation transfer" example:"graduation"`
+}
+
+// OpeningHours of a day of the week, weekday 0 is Sunday.
+type OpeningHours struct {
+	Weekday  int    `json:"weekday" binding:"min=0,max=6" example:"1"`
+	IsOpen   bool   `json:"is_open" example:"true"`
+	OpensAt  string `json:"opens_at" binding:"required_if=IsOpen true,omitempty,datetime=15:04" example:"07:00"`
+	ClosesAt string `json:"closes_at" binding:"required_if=IsOpen true,omitempty,datetime=15:04" example:"15:00"`
+}
+
+type SetOpeningHours struct {
+	Days []OpeningHours `json:"days" binding:"required,min=1,max=7,dive"`
+}
+
+// AddClosure closes the library from start_date to end_date included, a
+// single day when end_date is empty.
+type AddClosure struct {
+	Name      string `json:"name" binding:"required,max=100" example:"Libur Semester Ganjil"`
+	Kind      string `json:"kind" binding:"required,oneof=holiday term_break" example:"term_break"`
+	StartDate string `json:"start_date" binding:"required,datetime=2006-01-02" example:"2026-12-21"`
+	EndDate   string `json:"end_date" binding:"omitempty,datetime=2006-01-02" example:"2027-01-01"`
+}
+
+type CalendarQuery struct {
+	From string `form:"from" binding:"omitempty,datetime=2006-01-02"`
+	To   string `form:"to" binding:"omitempty,datetime=2006-01-02"`
+}
+
+type LogLevel struct {
+	Level string `json:"level" binding:"required,oneof=debug info warn error"`
+}
+
+// PostReview rates a returned book from 1 to 5 stars, posting again replaces
+// the review.
+type PostReview struct {
+	Rating int    `json:"rating" binding:"required,min=1,max=5" example:"4"`
+	Body   string `json:"body" binding:"omitempty,max=1000" example:"Ceritanya seru, cocok untuk kelas X"`
+}
+
+type ReviewFilter struct {
+	BookID int    `form:"book_id" binding:"omitempty,gt=0"`
+	Status string `form:"status" binding:"omitempty,oneof=visible hidden"`
+	PageQuery
+}
+
+type HideReview struct {
+	Reason string `json:"reason" binding:"required,max=300" example:"contains personal information"`
+}
+
+// SaveReadingList assigns books, in reading order, to a class until due_at.
+// starts_at defaults to today, sub_class and major left empty target every
+// sub class and major of the class.
+type SaveReadingList struct {
+	Title       string `json:"title" binding:"required,max=100" example:"Sastra Indonesia Semester Ganjil"`
+	Description string `json:"description" binding:"omitempty,max=500"`
+	Class       string `json:"class" binding:"required,oneof=X XI XII XIII" example:"XI"`
+	SubClass    string `json:"sub_class" binding:"omitempty,oneof=A B C"`
+	Major       string `json:"major" binding:"omitempty,oneof=RPL SIJA PSPT TPTU TEI MEKA TOI TEK IOP" example:"RPL"`
+	StartsAt    string `json:"starts_at" binding:"omitempty,datetime=2006-01-02" example:"2026-07-13"`
+	DueAt       string `json:"due_at" binding:"required,datetime=2006-01-02" example:"2026-12-18"`
+	Books       []int  `json:"books" binding:"required,min=1,max=50,unique,dive,gt=0" example:"3,8,12"`
+}
+
+// ReadingListFilter narrows the reading lists, mine only keeps the lists
+// created by the logged in teacher or admin.
+type ReadingListFilter struct {
+	Class string `form:"class" binding:"omitempty,oneof=X XI XII XIII"`
+	Major string `form:"major" binding:"omitempty,oneof=RPL SIJA PSPT TPTU TEI MEKA TOI TEK IOP"`
+	Mine  bool   `form:"mine"`
+	PageQuery
+}
+
+// AddSuggestion asks the library to buy a book missing from the catalog.
+type AddSuggestion struct {
+	Title  string `json:"title" binding:"required,max=200" example:"Laskar Pelangi"`
+	Author string `json:"author" binding:"required,max=100" example:"Andrea Hirata"`
+	ISBN   string `json:"isbn" binding:"omitempty,isbn" example:"9789793062792"`
+}
+
+type SuggestionFilter struct {
+	Status string `form:"status" binding:"omitempty,oneof=submitted under_review ordered received rejected"`
+	Sort   string `form:"sort" binding:"omitempty,oneof=votes newest"`
+	PageQuery
+}
+
+// MoveSuggestion moves a suggestion to its next status, a rejection needs a
+// note telling the students why.
+type MoveSuggestion struct {
+	Status string `json:"status" binding:"required,oneof=under_review ordered rejected" example:"under_review"`
+	Note   string `json:"note" binding:"required_if=Status rejected,max=300"`
+}
+
+// ReceiveSuggestion is the book a suggestion turned into, its title and
+// author come from the suggestion. isbn is required when the suggestion has
+// none and replaces it otherwise.
+type ReceiveSuggestion struct {
+	ISBN        string `json:"isbn" binding:"omitempty,isbn" example:"9789793062792"`
+	Publisher   string `json:"publisher" binding:"required" example:"Bentang Pustaka"`
+	Description string `json:"description" binding:"required,min=50,max=300"`
+	Stock       int    `json:"stock" binding:"required,min=1" example:"3"`
+	Price       int64  `json:"price" binding:"min=0" example:"85000"`
+	IDCategory  []int  `json:"id_category" binding:"required,min=1,dive,gt=0"`
+}
@@ -1,6 +1,9 @@
 package dto
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 type Binding struct {
 	Field  string `json:"field"`
@@ -12,16 +15,35 @@ type Service struct {
 }
 
 type Errors struct {
+	Code    string    `json:"code,omitzero" enums:"internal_error,timeout,request_canceled,service_unavailable,not_found,no_data_affected,reference_not_found,validation_failed,invalid_cursor,invalid_date,loan_limit_reached,out_of_stock,already_borrowed,nis_registered,email_used,student_inactive,not_cleared,not_returned,already_suggested,already_in_catalog,suggestion_closed,invalid_transition,missing_idempotency_key,duplicate_request,idempotency_key_reused,rate_limited,login_required,invalid_credentials,invalid_token,revoked_token,forbidden"`
 	Binding []Binding `json:"binding,omitzero"`
 	Service []Service `json:"service,omitzero"`
 	Error   string    `json:"error,omitzero"`
 }
 
+// Meta describes the page returned by a list endpoint, next_cursor is sent
+// back as cursor to get the following page.
+type Meta struct {
+	PageSize   int    `json:"page_size" example:"35"`
+	Sort       string `json:"sort" example:"title"`
+	Total      int64  `json:"total" example:"120"`
+	HasNext    bool   `json:"has_next" example:"true"`
+	NextCursor string `json:"next_cursor,omitzero" example:"eyJzIjoidGl0bGUiLCJrIjoiRHVuZSIsImkiOjQyfQ"`
+}
+
+// StoredResponse is the response replayed for a retried idempotent request.
+type StoredResponse struct {
+	Status      int
+	ContentType string
+	Body        []byte
+}
+
 type Response struct {
-	Status  string      `json:"status"`
+	Success bool        `json:"success"`
 	Message string      `json:"message,omitzero"`
 	Error   Errors      `json:"errors,omitzero"`
 	Data    interface{} `json:"data,omitzero"`
+	Meta    *Meta       `json:"meta,omitzero"`
 }
 
 type Categories struct {
@@ -37,13 +59,328 @@ type Books struct {
 	Description    string       `json:"description" redis:"description"`
 	Categories     []Categories `json:"categories" redis:"categories"`
 	AvailableStock int          `json:"available_stock" redis:"available_stock"`
+	RatingAvg      float64      `json:"rating_avg" redis:"rating_avg" example:"4.25"`
+	RatingCount    int          `json:"rating_count" redis:"rating_count" example:"12"`
 }
 
 type LoanData struct {
+	ID             int        `json:"id"`
 	BookName       string     `json:"book_name"`
 	StudentName    string     `json:"student_name"`
 	BorrowAt       time.Time  `json:"borrow_at"`
 	MustReturnedAt time.Time  `json:"must_returned_at"`
 	ReturnedAt     *time.Time `json:"returned_at"`
 	Sanctions      *int64     `json:"sanctions"`
+	Outcome        string     `json:"outcome,omitzero" enums:"returned,damaged,lost"`
+	Fee            int64      `json:"fee,omitzero"`
+}
+
+type StudentData struct {
+	NIS         int    `json:"nis"`
+	Name        string `json:"name"`
+	PhoneNumber string `json:"phone_number"`
+	Email       string `json:"email"`
+	Class       string `json:"class"`
+	SubClass    string `json:"sub_class"`
+	Major       string `json:"major"`
+	Batch       int    `json:"batch"`
+	IsActive    bool   `json:"is_active"`
+}
+
+type MaxBook struct {
+	Used      int `json:"used"`
+	Limit     int `json:"limit"`
+	Remaining int `json:"remaining"`
+}
+
+type StudentProfile struct {
+	Student     StudentData `json:"student"`
+	ActiveLoans []LoanData  `json:"active_loans"`
+	History     []LoanData  `json:"history"`
+	MaxBook     MaxBook     `json:"max_book"`
+	Sanctions   int64       `json:"total_sanctions"`
+}
+
+type Audit struct {
+	ID        int             `json:"id"`
+	ActorID   int             `json:"actor_id"`
+	ActorRole string          `json:"actor_role"`
+	Action    string          `json:"action"`
+	Entity    string          `json:"entity"`
+	EntityID  string          `json:"entity_id"`
+	Before    json.RawMessage `json:"before" swaggertype:"object"`
+	After     json.RawMessage `json:"after" swaggertype:"object"`
+	Diff      json.RawMessage `json:"diff" swaggertype:"object"`
+	TraceID   string          `json:"trace_id"`
+	IP        string          `json:"ip"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// Webhook is a subscription, the secret is only returned when it is created.
+type Webhook struct {
+	ID         int       `json:"id"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	Secret     string    `json:"secret,omitzero"`
+	Active     bool      `json:"active"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type WebhookDelivery struct {
+	ID             int        `json:"id"`
+	SubscriptionID int        `json:"subscription_id"`
+	EventID        int        `json:"event_id"`
+	EventType      string     `json:"event_type"`
+	Status         string     `json:"status" enums:"pending,succeeded,failed,dead"`
+	Attempts       int        `json:"attempts"`
+	ResponseStatus int        `json:"response_status,omitzero"`
+	ResponseBody   string     `json:"response_body,omitzero"`
+	LastError      string     `json:"last_error,omitzero"`
+	NextAttemptAt  time.Time  `json:"next_attempt_at"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitzero"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// WebhookEvent is the body posted to a subscription.
+type WebhookEvent struct {
+	ID          int             `json:"id"`
+	Type        string          `json:"type"`
+	Aggregate   string          `json:"aggregate"`
+	AggregateID string          `json:"aggregate_id"`
+	CreatedAt   string          `json:"created_at"`
+	Data        json.RawMessage `json:"data"`
+}
+
+// Clearance is an issued clearance certificate, VerifyURL is the address in
+// its QR code.
+type Clearance struct {
+	Number    string    `json:"number" example:"BP-2026-7K3M9Q2X"`
+	NIS       int       `json:"nis"`
+	Name      string    `json:"name"`
+	Class     string    `json:"class"`
+	SubClass  string    `json:"sub_class"`
+	Major     string    `json:"major"`
+	Batch     int       `json:"batch"`
+	Purpose   string    `json:"purpose" enums:"graduation,transfer"`
+	IssuedAt  time.Time `json:"issued_at"`
+	VerifyURL string    `json:"verify_url"`
+}
+
+type ClearanceVerification struct {
+	Valid     bool       `json:"valid"`
+	Clearance *Clearance `json:"clearance,omitzero"`
+}
+
+// ClearanceResult is the outcome for one student of a batch, a blocked
+// student lists what keeps them from being cleared.
+type ClearanceResult struct {
+	NIS                  int    `json:"nis"`
+	Name                 string `json:"name"`
+	Status               string `json:"status" enums:"issued,existing,blocked"`
+	Number               string `json:"number,omitzero"`
+	ActiveLoans          int64  `json:"active_loans,omitzero"`
+	OutstandingSanctions int64  `json:"outstanding_sanctions,omitzero"`
+}
+
+type ClearanceBatch struct {
+	Batch    int               `json:"batch"`
+	Issued   int               `json:"issued"`
+	Existing int               `json:"existing"`
+	Blocked  int               `json:"blocked"`
+	Results  []ClearanceResult `json:"results"`
+}
+
+type SettledSanctions struct {
+	Loans  int64 `json:"loans"`
+	Amount int64 `json:"amount"`
+}
+
+type HealthCheck struct {
+	Status  string      `json:"status"`
+	Latency string      `json:"latency"`
+	Error   string      `json:"error,omitzero"`
+	Pool    interface{} `json:"pool,omitzero" swaggertype:"object"`
+}
+
+type PostgresPool struct {
+	MaxOpen      int    `json:"max_open"`
+	Open         int    `json:"open"`
+	InUse        int    `json:"in_use"`
+	Idle         int    `json:"idle"`
+	WaitCount    int64  `json:"wait_count"`
+	WaitDuration string `json:"wait_duration"`
+}
+
+type RedisPool struct {
+	Total    uint32 `json:"total"`
+	Idle     uint32 `json:"idle"`
+	Stale    uint32 `json:"stale"`
+	Hits     uint32 `json:"hits"`
+	Misses   uint32 `json:"misses"`
+	Timeouts uint32 `json:"timeouts"`
+}
+
+type Readiness struct {
+	Postgres HealthCheck `json:"postgres"`
+	Redis    HealthCheck `json:"redis"`
+}
+
+// Closure is a holiday or a term break, both dates are included.
+type Closure struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	Kind      string `json:"kind" enums:"holiday,term_break"`
+	StartDate string `json:"start_date" example:"2026-12-21"`
+	EndDate   string `json:"end_date" example:"2027-01-01"`
+	Imported  bool   `json:"imported"`
+}
+
+type Calendar struct {
+	OpeningHours []OpeningHours `json:"opening_hours"`
+	Closures     []Closure      `json:"closures"`
+}
+
+type CalendarImport struct {
+	Events   int   `json:"events"`
+	Imported int64 `json:"imported"`
+}
+
+// Review of a book, hidden_reason is only set on hidden reviews.
+type Review struct {
+	ID           int       `json:"id"`
+	BookID       int       `json:"book_id"`
+	BookName     string    `json:"book_name,omitzero"`
+	StudentName  string    `json:"student_name,omitzero"`
+	Rating       int       `json:"rating" example:"4"`
+	Body         string    `json:"body"`
+	Status       string    `json:"status" enums:"visible,hidden"`
+	HiddenReason string    `json:"hidden_reason,omitzero"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// Recommendation is a book recommended to a student, reasons are what relate
+// it to the books they borrowed and to their classmates, strongest first.
+type Recommendation struct {
+	ID             int      `json:"book_id"`
+	Name           string   `json:"name"`
+	Author         string   `json:"author"`
+	Publisher      string   `json:"publisher"`
+	AvailableStock int      `json:"available_stock"`
+	RatingAvg      float64  `json:"rating_avg" example:"4.25"`
+	Score          int      `json:"score" example:"11"`
+	Reasons        []string `json:"reasons" enums:"co_borrowed,category,author,popular_in_class"`
+}
+
+type Recommendations struct {
+	Books      []Recommendation `json:"books"`
+	ComputedAt time.Time        `json:"computed_at"`
+}
+
+type ReadingListBook struct {
+	ID       int    `json:"book_id"`
+	Position int    `json:"position" example:"1"`
+	Name     string `json:"name"`
+	Author   string `json:"author"`
+}
+
+// ReadingList of a class, sub_class and major are left out when every sub
+// class or major of the class is targeted.
+type ReadingList struct {
+	ID          int               `json:"id"`
+	Title       string            `json:"title"`
+	Description string            `json:"description"`
+	Class       string            `json:"class" example:"XI"`
+	SubClass    string            `json:"sub_class,omitzero"`
+	Major       string            `json:"major,omitzero" example:"RPL"`
+	StartsAt    string            `json:"starts_at" example:"2026-07-13"`
+	DueAt       string            `json:"due_at" example:"2026-12-18"`
+	CreatedBy   int               `json:"created_by"`
+	Books       []ReadingListBook `json:"books"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+// ReadingBook is a book of a reading list with how far the student got, a
+// book is read once a loan of it made since starts_at is returned.
+type ReadingBook struct {
+	ReadingListBook
+	Status     string     `json:"status" enums:"read,borrowed,todo"`
+	ReturnedAt *time.Time `json:"returned_at,omitempty"`
+}
+
+type ReadingProgress struct {
+	Read      int  `json:"read" example:"2"`
+	Total     int  `json:"total" example:"3"`
+	Percent   int  `json:"percent" example:"66"`
+	Completed bool `json:"completed"`
+	Overdue   bool `json:"overdue"`
+}
+
+// StudentReadingList is a reading list assigned to the logged in student.
+type StudentReadingList struct {
+	ID          int             `json:"id"`
+	Title       string          `json:"title"`
+	Description string          `json:"description"`
+	StartsAt    string          `json:"starts_at" example:"2026-07-13"`
+	DueAt       string          `json:"due_at" example:"2026-12-18"`
+	Books       []ReadingBook   `json:"books"`
+	Progress    ReadingProgress `json:"progress"`
+}
+
+// StudentReading is the progress of one student in a completion report,
+// completed_at is when the last book of the list was returned.
+type StudentReading struct {
+	ID          int        `json:"id"`
+	NIS         int        `json:"nis"`
+	Name        string     `json:"name"`
+	Read        int        `json:"read" example:"2"`
+	Percent     int        `json:"percent" example:"66"`
+	Completed   bool       `json:"completed"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	Late        bool       `json:"late"`
+}
+
+// ClassReading groups the students of one class, like "XI RPL A", in a
+// completion report.
+type ClassReading struct {
+	Class          string           `json:"class" example:"XI RPL A"`
+	TotalStudents  int              `json:"total_students"`
+	Completed      int              `json:"completed_students"`
+	AveragePercent int              `json:"average_percent" example:"72"`
+	Students       []StudentReading `json:"students"`
+}
+
+type ReadingListReport struct {
+	ReadingList ReadingList    `json:"reading_list"`
+	Completed   int            `json:"completed_students"`
+	Total       int            `json:"total_students"`
+	Classes     []ClassReading `json:"classes"`
+}
+
+// Suggestion of a book to buy, voted tells whether the logged in student
+// asked for it and book_id is set once it is received.
+type Suggestion struct {
+	ID          int       `json:"id"`
+	Title       string    `json:"title"`
+	Author      string    `json:"author"`
+	ISBN        string    `json:"isbn,omitzero"`
+	Status      string    `json:"status" enums:"submitted,under_review,ordered,received,rejected"`
+	Votes       int       `json:"votes" example:"12"`
+	Voted       bool      `json:"voted"`
+	Note        string    `json:"note,omitzero"`
+	BookID      *int      `json:"book_id,omitempty"`
+	StudentName string    `json:"student_name,omitzero"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// ReceivedSuggestion is a suggestion turned into a book, every requester is
+// notified and the first ones, as many as the copies, get a hold on it until
+// hold_expires_at.
+type ReceivedSuggestion struct {
+	Suggestion
+	Requesters    int       `json:"requesters" example:"12"`
+	Holds         int       `json:"holds" example:"3"`
+	HoldExpiresAt time.Time `json:"hold_expires_at"`
 }
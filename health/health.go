@@ -0,0 +1,15 @@
+package health
+
+import "sync/atomic"
+
+var draining atomic.Bool
+
+// Drain marks the app as shutting down, /readyz reports not ready from now on
+// so the load balancer stops sending traffic before the server stops.
+func Drain() {
+	draining.Store(true)
+}
+
+func IsDraining() bool {
+	return draining.Load()
+}
@@ -2,28 +2,63 @@ package log
 
 import (
 	"context"
+	"stmnplibrary/config"
 	"stmnplibrary/constanta"
 	"stmnplibrary/log/structure"
+	"time"
 
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 var ZapLog *zap.Logger
 
+// Level is shared by the logger built in Init so it can be changed at runtime.
+var Level = zap.NewAtomicLevel()
+
 func LogInit(l *zap.Logger) {
 	ZapLog = l
 }
 
+// Init builds the logger from cfg, JSON in production mode and console in
+// development mode, and registers it with LogInit.
+func Init(cfg config.Log) (*zap.Logger, error) {
+	zl := zap.NewDevelopmentConfig()
+	if cfg.Mode == "production" {
+		zl = zap.NewProductionConfig()
+	}
+	level, err := zapcore.ParseLevel(cfg.Level)
+	if err != nil {
+		return nil, err
+	}
+	Level.SetLevel(level)
+	zl.Level = Level
+	zl.DisableStacktrace = true
+	zl.Sampling = nil
+	if cfg.SampleInitial > 0 {
+		zl.Sampling = &zap.SamplingConfig{
+			Initial:    cfg.SampleInitial,
+			Thereafter: cfg.SampleThereafter,
+		}
+	}
+	zapLog, err := zl.Build()
+	if err != nil {
+		return nil, err
+	}
+	LogInit(zapLog)
+	return zapLog, nil
+}
+
 func LogConfig(m string, s string, errr error) {
 	err := &structure.LogConfig{
 		Status:  false,
 		Service: s,
-		Error: errr.Error(),
+		Error: structure.Redact(errr.Error()),
 	}
 	ZapLog.Error(m, zap.Object("log_config: ", err))
 }
 
-func LogHSR(ctx context.Context, m string, s string, e string, me string, errStr string) {
+func getID(ctx context.Context) structure.ID {
 	uI, exists := ctx.Value(constanta.UI).(int)
 	if !exists {
 		uI = 0
@@ -32,18 +67,68 @@ func LogHSR(ctx context.Context, m string, s string, e string, me string, errStr
 	if !exists {
 		tI = "0"
 	}
+	return structure.ID{
+		TraceId: tI,
+		UserId:  uI,
+	}
+}
+
+func LogHSR(ctx context.Context, m string, s string, e string, me string, errStr string) {
 	err := &structure.LogHSR{
 		Status: false,
-		ID: structure.ID{
-			TraceId: tI,
-			UserId:  uI,
-		},
+		ID: getID(ctx),
 		Service: s,
 		Website: structure.Website{
 			Endpoint: e,
 			Method:   me,
 		},
-		Error: errStr,
+		Error: structure.Redact(errStr),
 	}
 	ZapLog.Error(m, zap.Object("log_hsr: ", err))
 }
+
+func LogAccess(ctx context.Context, route string, method string, status int, latency time.Duration, bytes int, clientIP string) {
+	access := &structure.LogAccess{
+		ID: getID(ctx),
+		Website: structure.Website{
+			Endpoint: route,
+			Method:   method,
+		},
+		Status:   status,
+		Latency:  latency,
+		Bytes:    bytes,
+		ClientIP: clientIP,
+	}
+	ZapLog.Info("request", zap.Object("log_access: ", access))
+}
+
+// LogDegraded warns that feature ran under its degraded policy because redis failed.
+func LogDegraded(ctx context.Context, feature string, policy string, errr error) {
+	id := getID(ctx)
+	ZapLog.Warn("redis degraded",
+		zap.String("feature", feature),
+		zap.String("policy", policy),
+		zap.String("trace_id", id.TraceId),
+		zap.String("error", structure.Redact(errr.Error())),
+	)
+}
+
+// LogAudit reports an audit entry that couldn't be stored for an operation
+// that already took effect.
+func LogAudit(ctx context.Context, action string, errr error) {
+	id := getID(ctx)
+	ZapLog.Error("audit record failed",
+		zap.String("action", action),
+		zap.String("trace_id", id.TraceId),
+		zap.Int("user_id", id.UserId),
+		zap.String("error", structure.Redact(errr.Error())),
+	)
+}
+
+// LogWorker reports a failed run of a background worker.
+func LogWorker(worker string, m string, errr error) {
+	ZapLog.Error(m,
+		zap.String("worker", worker),
+		zap.String("error", structure.Redact(errr.Error())),
+	)
+}
@@ -0,0 +1,36 @@
+package structure
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	emailRegex = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	phoneRegex = regexp.MustCompile(`(?:\+62|\b62|\b0)\d{7,13}\b`)
+)
+
+// MaskEmail keeps the first character of the local part and the domain,
+// "budi@mail.com" becomes "b***@mail.com".
+func MaskEmail(email string) string {
+	local, domain, ok := strings.Cut(email, "@")
+	if !ok || local == "" {
+		return "***"
+	}
+	return local[:1] + "***@" + domain
+}
+
+// MaskPhone keeps the last 3 digits, "+628123456789" becomes "*********789".
+func MaskPhone(phone string) string {
+	if len(phone) <= 3 {
+		return "***"
+	}
+	return strings.Repeat("*", len(phone)-3) + phone[len(phone)-3:]
+}
+
+// Redact masks every email and phone number found in s, used on free text
+// like database errors that may echo a student's personal info.
+func Redact(s string) string {
+	s = emailRegex.ReplaceAllStringFunc(s, MaskEmail)
+	return phoneRegex.ReplaceAllStringFunc(s, MaskPhone)
+}
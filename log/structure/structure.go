@@ -1,6 +1,8 @@
 package structure
 
 import (
+	"time"
+
 	"go.uber.org/zap/zapcore"
 )
 
@@ -35,7 +37,7 @@ type LogConfig struct {
 func (lc LogConfig) MarshalLogObject(enc zapcore.ObjectEncoder) error {
 	enc.AddBool("status", lc.Status)
 	enc.AddString("service", lc.Service)
-	enc.AddString("error", lc.Service)
+	enc.AddString("error", lc.Error)
 	return nil
 }
 
@@ -55,3 +57,22 @@ func (lhsr LogHSR) MarshalLogObject(enc zapcore.ObjectEncoder) error {
 	enc.AddString("error", lhsr.Error)
 	return nil
 }
+
+type LogAccess struct {
+	ID       ID
+	Website  Website
+	Status   int
+	Latency  time.Duration
+	Bytes    int
+	ClientIP string
+}
+
+func (la LogAccess) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddObject("id_id", la.ID)
+	enc.AddObject("website_website", la.Website)
+	enc.AddInt("status", la.Status)
+	enc.AddDuration("latency", la.Latency)
+	enc.AddInt("bytes", la.Bytes)
+	enc.AddString("client_ip", la.ClientIP)
+	return nil
+}
@@ -0,0 +1,36 @@
+package structure
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestLogConfig_MarshalLogObject(t *testing.T) {
+	enc := zapcore.NewMapObjectEncoder()
+	err := LogConfig{Service: "connect_db_gorm", Error: "connection refused"}.MarshalLogObject(enc)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "connect_db_gorm", enc.Fields["service"])
+	assert.Equal(t, "connection refused", enc.Fields["error"])
+}
+
+func TestMask(t *testing.T) {
+	assert.Equal(t, "b***@mail.com", MaskEmail("budi@mail.com"))
+	assert.Equal(t, "***", MaskEmail("invalid"))
+	assert.Equal(t, "**********789", MaskPhone("+628123456789"))
+	assert.Equal(t, "***", MaskPhone("12"))
+}
+
+func TestRedact(t *testing.T) {
+	cases := map[string]string{
+		`duplicate key value violates unique constraint "students_email_key" Key (email)=(budi@mail.com)`: `duplicate key value violates unique constraint "students_email_key" Key (email)=(b***@mail.com)`,
+		"Key (phone_number)=(+628123456789) already exists":                                               "Key (phone_number)=(**********789) already exists",
+		"phone 08123456789 taken":                     "phone ********789 taken",
+		"loan 2026-10-19 04:10:00 isbn 9786020324784": "loan 2026-10-19 04:10:00 isbn 9786020324784",
+	}
+	for in, want := range cases {
+		assert.Equal(t, want, Redact(in), in)
+	}
+}
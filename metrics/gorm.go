@@ -0,0 +1,102 @@
+package metrics
+
+import (
+	"errors"
+	"runtime"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	repositoryPkg = "stmnplibrary/controller/repository/"
+	startKey      = "metrics:start"
+)
+
+// GormPlugin records the duration of every query into DBQueryDuration,
+// labelled with the repository method that issued it.
+type GormPlugin struct{}
+
+func (GormPlugin) Name() string {
+	return "metrics"
+}
+
+func (GormPlugin) Initialize(db *gorm.DB) error {
+	var cb = db.Callback()
+	for _, err := range []error{
+		cb.Create().Before("gorm:create").Register("metrics:before_create", before),
+		cb.Create().After("gorm:create").Register("metrics:after_create", after("create")),
+		cb.Query().Before("gorm:query").Register("metrics:before_query", before),
+		cb.Query().After("gorm:query").Register("metrics:after_query", after("query")),
+		cb.Update().Before("gorm:update").Register("metrics:before_update", before),
+		cb.Update().After("gorm:update").Register("metrics:after_update", after("update")),
+		cb.Delete().Before("gorm:delete").Register("metrics:before_delete", before),
+		cb.Delete().After("gorm:delete").Register("metrics:after_delete", after("delete")),
+		cb.Row().Before("gorm:row").Register("metrics:before_row", before),
+		cb.Row().After("gorm:row").Register("metrics:after_row", after("row")),
+		cb.Raw().Before("gorm:raw").Register("metrics:before_raw", before),
+		cb.Raw().After("gorm:raw").Register("metrics:after_raw", after("raw")),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func before(db *gorm.DB) {
+	db.InstanceSet(startKey, time.Now())
+}
+
+func after(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		v, ok := db.InstanceGet(startKey)
+		if !ok {
+			return
+		}
+		start, ok := v.(time.Time)
+		if !ok {
+			return
+		}
+		var status = "ok"
+		if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+			status = "error"
+		}
+		DBQueryDuration.WithLabelValues(operation, caller(), status).Observe(time.Since(start).Seconds())
+	}
+}
+
+// caller walks the stack for the first repository frame and returns it as
+// "<package>.<Method>", e.g. "admin.GetStudents".
+func caller() string {
+	var pcs [32]uintptr
+	n := runtime.Callers(3, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if idx := strings.Index(frame.Function, repositoryPkg); idx >= 0 {
+			return repositoryMethod(frame.Function[idx+len(repositoryPkg):])
+		}
+		if !more {
+			return "unknown"
+		}
+	}
+}
+
+func repositoryMethod(fn string) string {
+	pkg, rest, ok := strings.Cut(fn, ".")
+	if !ok {
+		return fn
+	}
+	parts := strings.Split(rest, ".")
+	for len(parts) > 1 && isClosure(parts[len(parts)-1]) {
+		parts = parts[:len(parts)-1]
+	}
+	return pkg + "." + parts[len(parts)-1]
+}
+
+// isClosure reports whether a frame segment names a closure, e.g. "func1" or "2".
+func isClosure(segment string) bool {
+	return strings.TrimLeft(strings.TrimPrefix(segment, "func"), "0123456789") == ""
+}
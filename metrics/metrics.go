@@ -0,0 +1,118 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "stmnplibrary"
+
+// Registry holds every collector of the app, it is served on /metrics.
+var Registry = prometheus.NewRegistry()
+
+var factory = promauto.With(Registry)
+
+var (
+	HTTPRequests = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Total HTTP requests by method, route and status.",
+	}, []string{"method", "route", "status"})
+
+	HTTPDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP request latency by method and route.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	DBQueryDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "query_duration_seconds",
+		Help:      "GORM query latency by operation and repository method.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation", "method", "status"})
+
+	CacheRequests = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "cache",
+		Name:      "requests_total",
+		Help:      "Redis cache lookups by cache and result (hit / miss).",
+	}, []string{"cache", "result"})
+
+	SingleflightCalls = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "singleflight",
+		Name:      "calls_total",
+		Help:      "Singleflight calls by group, shared is true when the result came from another in-flight call.",
+	}, []string{"group", "shared"})
+
+	RedisCircuitOpen = factory.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "redis",
+		Name:      "circuit_open",
+		Help:      "1 while the redis circuit breaker is open or half-open, 0 when closed.",
+	})
+
+	Degraded = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "redis",
+		Name:      "degraded_total",
+		Help:      "Operations served by a degraded policy because redis failed, by feature and policy.",
+	}, []string{"feature", "policy"})
+
+	OutboxEvents = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "outbox",
+		Name:      "events_total",
+		Help:      "Outbox events handled by the relay by event type and result (published / retried / dead).",
+	}, []string{"event", "result"})
+
+	WebhookDeliveries = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "webhook",
+		Name:      "deliveries_total",
+		Help:      "Webhook delivery attempts by event type and result (succeeded / failed / dead).",
+	}, []string{"event", "result"})
+
+	RateLimitRejections = factory.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "rate_limiter",
+		Name:      "rejections_total",
+		Help:      "Requests rejected by the rate limiter.",
+	})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+func CacheResult(cache string, hit bool) {
+	var result = "miss"
+	if hit {
+		result = "hit"
+	}
+	CacheRequests.WithLabelValues(cache, result).Inc()
+}
+
+func Singleflight(group string, shared bool) {
+	SingleflightCalls.WithLabelValues(group, strconv.FormatBool(shared)).Inc()
+}
+
+// Handler serves Registry together with the business gauges of stats.
+func Handler(stats *Stats) http.Handler {
+	var business = prometheus.NewRegistry()
+	business.MustRegister(stats)
+	return promhttp.HandlerFor(prometheus.Gatherers{Registry, business}, promhttp.HandlerOpts{})
+}
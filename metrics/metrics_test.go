@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"stmnplibrary/domain/entity"
+	"stmnplibrary/log"
+	"stmnplibrary/mocks"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestRepositoryMethod(t *testing.T) {
+	cases := map[string]string{
+		"admin.(*adminRepository).GetStudents":  "admin.GetStudents",
+		"admin.(*adminRepository).WithTx.func1": "admin.WithTx",
+		"user.(*userRepository).Loan.func2.1":   "user.Loan",
+		"audit.(*auditRepository).Record":       "audit.Record",
+	}
+	for fn, want := range cases {
+		assert.Equal(t, want, repositoryMethod(fn), fn)
+	}
+}
+
+func TestCacheResult(t *testing.T) {
+	hit := testutil.ToFloat64(CacheRequests.WithLabelValues("test", "hit"))
+	miss := testutil.ToFloat64(CacheRequests.WithLabelValues("test", "miss"))
+
+	CacheResult("test", true)
+	CacheResult("test", false)
+	CacheResult("test", false)
+
+	assert.Equal(t, hit+1, testutil.ToFloat64(CacheRequests.WithLabelValues("test", "hit")))
+	assert.Equal(t, miss+2, testutil.ToFloat64(CacheRequests.WithLabelValues("test", "miss")))
+}
+
+func TestStats_Collect(t *testing.T) {
+	repo := mocks.NewAdminRepository(t)
+	repo.On("GetStats", mock.Anything).Return(entity.Stats{ActiveLoans: 7, OverdueLoans: 2, OutOfStock: 3}, nil).Once()
+
+	expected := `
+# HELP stmnplibrary_books_out_of_stock Books with no available stock.
+# TYPE stmnplibrary_books_out_of_stock gauge
+stmnplibrary_books_out_of_stock 3
+# HELP stmnplibrary_loans_active Loans not returned yet.
+# TYPE stmnplibrary_loans_active gauge
+stmnplibrary_loans_active 7
+# HELP stmnplibrary_loans_overdue Loans not returned yet and past must_returned_at.
+# TYPE stmnplibrary_loans_overdue gauge
+stmnplibrary_loans_overdue 2
+`
+	require.NoError(t, testutil.CollectAndCompare(FnStats(repo), strings.NewReader(expected)))
+}
+
+func TestStats_CollectError(t *testing.T) {
+	log.LogInit(zap.NewNop())
+	repo := mocks.NewAdminRepository(t)
+	repo.On("GetStats", mock.Anything).Return(entity.Stats{}, errors.New("internal server error: boom")).Once()
+
+	assert.Equal(t, 0, testutil.CollectAndCount(FnStats(repo)))
+}
@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"stmnplibrary/domain/interface/repository"
+	"stmnplibrary/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const statsTimeout = 5 * time.Second
+
+// Stats exposes the business gauges, they are read from postgres on every scrape.
+type Stats struct {
+	repo         repository.AdminRepository
+	activeLoans  *prometheus.Desc
+	overdueLoans *prometheus.Desc
+	outOfStock   *prometheus.Desc
+}
+
+func FnStats(repo repository.AdminRepository) *Stats {
+	return &Stats{
+		repo:         repo,
+		activeLoans:  prometheus.NewDesc(namespace+"_loans_active", "Loans not returned yet.", nil, nil),
+		overdueLoans: prometheus.NewDesc(namespace+"_loans_overdue", "Loans not returned yet and past must_returned_at.", nil, nil),
+		outOfStock:   prometheus.NewDesc(namespace+"_books_out_of_stock", "Books with no available stock.", nil, nil),
+	}
+}
+
+func (s *Stats) Describe(ch chan<- *prometheus.Desc) {
+	ch <- s.activeLoans
+	ch <- s.overdueLoans
+	ch <- s.outOfStock
+}
+
+func (s *Stats) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), statsTimeout)
+	defer cancel()
+	stats, err := s.repo.GetStats(ctx)
+	if err != nil {
+		log.LogConfig("failed collect business metrics", "metrics_stats", err)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(s.activeLoans, prometheus.GaugeValue, float64(stats.ActiveLoans))
+	ch <- prometheus.MustNewConstMetric(s.overdueLoans, prometheus.GaugeValue, float64(stats.OverdueLoans))
+	ch <- prometheus.MustNewConstMetric(s.outOfStock, prometheus.GaugeValue, float64(stats.OutOfStock))
+}
@@ -1,32 +1,62 @@
 package middleware
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"stmnplibrary/apperr"
+	"stmnplibrary/audit"
+	"stmnplibrary/config"
 	"net/http"
 	"stmnplibrary/constanta"
 	"stmnplibrary/domain/interface/service"
 	"stmnplibrary/dto"
 	"stmnplibrary/log"
+	"stmnplibrary/metrics"
 	token "stmnplibrary/security/jwt"
+	"stmnplibrary/tracing"
 
 	"context"
-	"strings"
+	"io"
 	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
+var errNoCookie = apperr.New(apperr.KindUnauthorized, apperr.CodeLoginRequired, "please login or maybe cookie are missing")
+
 type middle struct {
-	service service.UserService
+	service     service.UserService
+	idempotency service.IdempotencyService
+	jwt         *token.JWT
 }
 
-func FnNewMiddle(service service.UserService) *middle {
+func FnNewMiddle(service service.UserService, idempotency service.IdempotencyService, jwt *token.JWT) *middle {
 	return &middle{
-		service: service,
+		service:     service,
+		idempotency: idempotency,
+		jwt:         jwt,
 	}
 }
 
+// abortErr ends the request with the status and code of err, internal errors
+// are logged since the client only gets a generic message.
+func abortErr(c *gin.Context, service string, err error) {
+	e := apperr.From(err)
+	if e.Kind == apperr.KindInternal {
+		log.LogHSR(c.Request.Context(), e.Public(), service, c.Request.URL.Path, c.Request.Method, err.Error())
+	}
+	c.AbortWithStatusJSON(e.Kind.Status(), dto.Response{
+		Message: e.Public(),
+		Error:   dto.Errors{Code: e.Code},
+	})
+}
+
 func Recovery() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		defer func() {
@@ -38,8 +68,8 @@ func Recovery() gin.HandlerFunc {
 				)
 				log.LogHSR(c.Request.Context(), msg, "backend - library", c.Request.URL.Path, c.Request.Method, errStr)
 				c.AbortWithStatusJSON(http.StatusInternalServerError, dto.Response {
-					Status: "failed / false",
 					Message: msg,
+					Error: dto.Errors{Code: apperr.CodeInternal},
 				})
 				return
 			}
@@ -48,28 +78,53 @@ func Recovery() gin.HandlerFunc {
 	}
 }
 
-func GenerateUUID() gin.HandlerFunc {
+func Metrics() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		uuid := uuid.New().String()
-		ctx := context.WithValue(c.Request.Context(), constanta.TI, uuid)
-		c.Request = c.Request.WithContext(ctx)
+		var start = time.Now()
 		c.Next()
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		metrics.HTTPRequests.WithLabelValues(c.Request.Method, route, strconv.Itoa(c.Writer.Status())).Inc()
+		metrics.HTTPDuration.WithLabelValues(c.Request.Method, route).Observe(time.Since(start).Seconds())
 	}
 }
 
-func GetIdempotencyKey() gin.HandlerFunc {
+func AccessLog() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		var ctx = c.Request.Context()
-		key := c.GetHeader(string(constanta.IK))
-		if key == "" {
-			c.JSON(http.StatusBadRequest, dto.Response{
-				Status: "false / failed",
-				Message: "missing idempotency key",
-			})
-			c.Abort()
-			return 
+		var start = time.Now()
+		c.Next()
+		route := c.FullPath()
+		if route == "" {
+			route = c.Request.URL.Path
 		}
-		ctx = context.WithValue(ctx, string(constanta.IK), key)
+		bytes := c.Writer.Size()
+		if bytes < 0 {
+			bytes = 0
+		}
+		log.LogAccess(c.Request.Context(), route, c.Request.Method, c.Writer.Status(), time.Since(start), bytes, c.ClientIP())
+	}
+}
+
+// RequestID stores the trace id started by otelgin in the context for the
+// logs and echoes it in X-Request-ID, falling back to a uuid without a span.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := tracing.TraceID(c.Request.Context())
+		if id == "" {
+			id = uuid.New().String()
+		}
+		ctx := context.WithValue(c.Request.Context(), constanta.TI, id)
+		c.Request = c.Request.WithContext(ctx)
+		c.Header("X-Request-ID", id)
+		c.Next()
+	}
+}
+
+func ClientIP() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := audit.WithClientIP(c.Request.Context(), c.ClientIP())
 		c.Request = c.Request.WithContext(ctx)
 		c.Next()
 	}
@@ -80,19 +135,28 @@ func AdminAuth() gin.HandlerFunc {
 		var ctx = c.Request.Context()
 		role, ok := ctx.Value(string(constanta.RL)).(string)
 		if !ok {
-			c.JSON(http.StatusUnauthorized, dto.Response{
-				Status: "false/ failed authorization",
-				Message: "please login or maybe cookie are missing",
-			})
-			c.Abort()
+			abortErr(c, "admin auth", errNoCookie)
 			return
 		}
 		if role != "admin" {
-			c.JSON(http.StatusUnauthorized, dto.Response{
-				Status: "false/ failed authorization",
-				Message: "who are you?? must be admin",
-			})
-			c.Abort()
+			abortErr(c, "admin auth", apperr.New(apperr.KindForbidden, apperr.CodeForbidden, "who are you?? must be admin"))
+			return
+		}
+		c.Next()
+	}
+}
+
+// StaffAuth lets the admins and the teachers through.
+func StaffAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx = c.Request.Context()
+		role, ok := ctx.Value(string(constanta.RL)).(string)
+		if !ok {
+			abortErr(c, "staff auth", errNoCookie)
+			return
+		}
+		if role != "admin" && role != "teacher" {
+			abortErr(c, "staff auth", apperr.New(apperr.KindForbidden, apperr.CodeForbidden, "who are you?? must be admin or teacher"))
 			return
 		}
 		c.Next()
@@ -104,19 +168,11 @@ func StudentAuth() gin.HandlerFunc {
 		var ctx = c.Request.Context()
 		role, ok := ctx.Value(string(constanta.RL)).(string)
 		if !ok {
-			c.JSON(http.StatusUnauthorized, dto.Response{
-				Status: "false/ failed authorization",
-				Message: "please login or maybe cookie are missing",
-			})
-			c.Abort()
+			abortErr(c, "student auth", errNoCookie)
 			return
 		}
 		if role != "students" {
-			c.JSON(http.StatusUnauthorized, dto.Response{
-				Status: "false/ failed authorization",
-				Message: "who are you?? must be student",
-			})
-			c.Abort()
+			abortErr(c, "student auth", apperr.New(apperr.KindForbidden, apperr.CodeForbidden, "who are you?? must be student"))
 			return
 		}
 		c.Next()
@@ -127,21 +183,10 @@ func (m *middle) RateLimiter() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var ctx = c.Request.Context()
 		if err := m.service.RateLimiter(ctx, c.ClientIP()); err != nil {
-			var errMsg = "something happened"
-			if strings.Contains(err.Error(), "too many request") {
-				errMsg = err.Error()
-				c.JSON(http.StatusTooManyRequests, dto.Response{
-					Status: "false / failed",
-					Message: errMsg,
-				})
-				c.Abort()
-			} else {
-				c.JSON(http.StatusTooManyRequests, dto.Response{
-					Status: "false / failed",
-					Message: errMsg,
-				})
-				c.Abort()
+			if apperr.KindOf(err) == apperr.KindTooManyRequests {
+				metrics.RateLimitRejections.Inc()
 			}
+			abortErr(c, "rate limiter", err)
 		}
 	}
 }
@@ -150,59 +195,26 @@ func (m *middle) Auth() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		tkn, err := c.Cookie(string(constanta.TokenA))
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, dto.Response{
-				Status:  "false / failed Authentication",
-				Message: "please login or maybe cookie are missing",
-			})
-			c.Abort()
+			abortErr(c, "auth", errNoCookie)
 			return
 		}
 		if tkn == "" {
-			c.JSON(http.StatusUnauthorized, dto.Response{
-				Status:  "false / failed Authentication",
-				Message: "token not found",
-			})
-			c.Abort()
+			abortErr(c, "auth", apperr.New(apperr.KindUnauthorized, apperr.CodeLoginRequired, "token not found"))
 			return
 		}
 		ctx := context.WithValue(c.Request.Context(), constanta.TokenA, tkn)
-		data, err := token.ValidateToken(tkn)
+		data, err := m.jwt.ValidateToken(tkn)
 		if err != nil {
 			if c.Request.URL.Path == "/refresh" {
 				c.Next()
 				return
 			}
-			status := http.StatusUnauthorized
-			if !strings.Contains(err.Error(), "invalid") {
-				status = http.StatusInternalServerError
-			}
-			if strings.Contains(err.Error(), "internal server error") {
-				c.JSON(status, dto.Response{
-					Status:  "false / failed Authentication",
-					Message: "an error occured",
-				})
-			} else {
-				c.JSON(status, dto.Response{
-					Status:  "false / failed Authentication",
-					Message: err.Error(),
-				})
-			}
-			c.Abort()
+			abortErr(c, "validate token", err)
 			return
 		}
 		if err := m.service.CheckAccTkn(ctx, tkn); err != nil {
-			if strings.Contains(err.Error(), "blacklist") {
-				c.JSON(http.StatusUnauthorized, dto.Response{
-					Status:  "false / failed Authentication",
-					Message: err.Error(),
-				})
-			} else {
-				c.JSON(http.StatusInternalServerError, dto.Response{
-					Status:  "false / failed Authentication",
-					Message: err.Error(),
-				})
-			}
-			c.Abort()
+			abortErr(c, "check access token", err)
+			return
 		}
 		ctx = context.WithValue(ctx, string(constanta.RL), data.Role)
 		ctx = context.WithValue(ctx, constanta.UI, data.UserId)
@@ -210,3 +222,119 @@ func (m *middle) Auth() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// Deprecated marks the unversioned routes: Deprecation (RFC 9745) and Sunset
+// (RFC 8594) headers and a successor-version link to the /api/v1 route, path
+// params like :nis in successor are filled from the request.
+func Deprecated(api config.API) func(successor string) gin.HandlerFunc {
+	deprecatedAt, _ := time.Parse(time.DateOnly, api.LegacyDeprecatedAt)
+	sunset, _ := time.Parse(time.DateOnly, api.LegacySunset)
+	var (
+		deprecation = "@" + strconv.FormatInt(deprecatedAt.Unix(), 10)
+		sunsetAt    = sunset.Format(http.TimeFormat)
+	)
+	return func(successor string) gin.HandlerFunc {
+		return func(c *gin.Context) {
+			var link = successor
+			for _, p := range c.Params {
+				link = strings.ReplaceAll(link, ":"+p.Key, p.Value)
+			}
+			c.Header("Deprecation", deprecation)
+			c.Header("Sunset", sunsetAt)
+			c.Header("Link", "<"+link+">; rel=\"successor-version\"")
+			c.Next()
+		}
+	}
+}
+
+// recorder keeps a copy of the response body so it can be stored for replay.
+type recorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *recorder) WriteString(s string) (int, error) {
+	r.body.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
+
+// Idempotent makes a POST route safe to retry with an Idempotency-Key header:
+// the first response is stored and replayed for the same key and body, the key
+// reused with another body is rejected and a retry arriving while the first
+// request still runs gets a conflict. Keys are scoped per user and path, 5xx
+// responses free the key so the retry runs again. Without required the header
+// is optional and requests without it run as usual.
+func (m *middle) Idempotent(required bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx = c.Request.Context()
+		key := c.GetHeader(string(constanta.IK))
+		if key == "" {
+			if required {
+				abortErr(c, "idempotency", apperr.New(apperr.KindInvalid, apperr.CodeMissingIdempotencyKey, "missing idempotency key"))
+				return
+			}
+			c.Next()
+			return
+		}
+		if len(key) > 255 {
+			abortErr(c, "idempotency", apperr.New(apperr.KindInvalid, apperr.CodeValidation, "idempotency key must not be longer than 255 characters"))
+			return
+		}
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			abortErr(c, "idempotency", apperr.Wrap(err, apperr.KindInvalid, apperr.CodeValidation, "failed read request body"))
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		userID, _ := ctx.Value(constanta.UI).(int)
+		var (
+			sum         = sha256.Sum256(append([]byte(c.Request.URL.RawQuery+"\n"), body...))
+			fingerprint = hex.EncodeToString(sum[:])
+			scope       = strconv.Itoa(userID) + ":" + c.Request.Method + ":" + c.Request.URL.Path + ":" + key
+		)
+		stored, err := m.idempotency.Begin(ctx, scope, fingerprint)
+		if err != nil {
+			abortErr(c, "idempotency", err)
+			return
+		}
+		if stored != nil {
+			c.Header("Idempotent-Replayed", "true")
+			c.Data(stored.Status, stored.ContentType, stored.Body)
+			c.Abort()
+			return
+		}
+
+		var done bool
+		defer func() {
+			// a panicking handler must not hold the key until the lock expires
+			if !done {
+				m.idempotency.Release(ctx, scope)
+			}
+		}()
+		rec := &recorder{ResponseWriter: c.Writer}
+		c.Writer = rec
+		c.Next()
+		c.Writer = rec.ResponseWriter
+
+		done = true
+		if rec.Status() >= http.StatusInternalServerError {
+			if err := m.idempotency.Release(ctx, scope); err != nil {
+				log.LogHSR(ctx, "failed release idempotency key", "idempotency", c.Request.URL.Path, c.Request.Method, err.Error())
+			}
+			return
+		}
+		if err := m.idempotency.Complete(ctx, scope, fingerprint, dto.StoredResponse{
+			Status:      rec.Status(),
+			ContentType: rec.Header().Get("Content-Type"),
+			Body:        rec.body.Bytes(),
+		}); err != nil {
+			log.LogHSR(ctx, "failed store idempotent response", "idempotency", c.Request.URL.Path, c.Request.Method, err.Error())
+		}
+	}
+}
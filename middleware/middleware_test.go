@@ -0,0 +1,192 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"stmnplibrary/apperr"
+	"stmnplibrary/config"
+	"stmnplibrary/constanta"
+	"stmnplibrary/domain/entity"
+	"stmnplibrary/dto"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeprecated(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	legacy := Deprecated(config.API{LegacyDeprecatedAt: "2026-10-19", LegacySunset: "2027-04-19"})
+	tests := []struct {
+		name      string
+		method    string
+		route     string
+		successor string
+		path      string
+		link      string
+	}{
+		{"Path_Param", http.MethodGet, "/admin/students/:nis", "/api/v1/students/:nis", "/admin/students/1001", `</api/v1/students/1001>; rel="successor-version"`},
+		{"Confirm", http.MethodPost, "/admin/loan/confirm", "/api/v1/loans", "/admin/loan/confirm", `</api/v1/loans>; rel="successor-version"`},
+		{"Loan", http.MethodPost, "/student/book/loan", "/api/v1/books", "/student/book/loan", `</api/v1/books>; rel="successor-version"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Handle(tt.method, tt.route, legacy(tt.successor), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "@1792368000", w.Header().Get("Deprecation"))
+			assert.Equal(t, "Mon, 19 Apr 2027 00:00:00 GMT", w.Header().Get("Sunset"))
+			assert.Equal(t, tt.link, w.Header().Get("Link"))
+		})
+	}
+}
+
+// memIdempotency is an in memory IdempotencyService for the middleware tests.
+type memIdempotency struct {
+	records map[string]*entity.Idempotency
+}
+
+func (m *memIdempotency) Begin(ctx context.Context, key string, fingerprint string) (*dto.StoredResponse, error) {
+	r, ok := m.records[key]
+	if !ok {
+		m.records[key] = &entity.Idempotency{Fingerprint: fingerprint}
+		return nil, nil
+	}
+	if r.Fingerprint != fingerprint {
+		return nil, apperr.New(apperr.KindUnprocessable, apperr.CodeIdempotencyKeyReused, "reused")
+	}
+	if !r.Done {
+		return nil, apperr.New(apperr.KindConflict, apperr.CodeDuplicateRequest, "in progress")
+	}
+	return &dto.StoredResponse{Status: r.Status, ContentType: r.ContentType, Body: r.Body}, nil
+}
+
+func (m *memIdempotency) Complete(ctx context.Context, key string, fingerprint string, res dto.StoredResponse) error {
+	m.records[key] = &entity.Idempotency{Fingerprint: fingerprint, Done: true, Status: res.Status, ContentType: res.ContentType, Body: res.Body}
+	return nil
+}
+
+func (m *memIdempotency) Release(ctx context.Context, key string) error {
+	delete(m.records, key)
+	return nil
+}
+
+func TestIdempotent(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var (
+		store = &memIdempotency{records: map[string]*entity.Idempotency{}}
+		m     = FnNewMiddle(nil, store, nil)
+		calls int
+		fail  = true
+	)
+	router := gin.New()
+	router.POST("/books", m.Idempotent(true), func(c *gin.Context) {
+		calls++
+		body, _ := io.ReadAll(c.Request.Body)
+		if fail && string(body) == `{"isbn":"boom"}` {
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"call": calls, "body": string(body)})
+	})
+	var duplicate int
+	router.POST("/returns", m.Idempotent(true), func(c *gin.Context) {
+		// a retry arriving while this request still runs
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/returns", nil)
+		req.Header.Set("Idempotency-Key", c.GetHeader("Idempotency-Key"))
+		router.ServeHTTP(w, req)
+		duplicate = w.Code
+		c.Status(http.StatusOK)
+	})
+	router.POST("/loans", m.Idempotent(false), func(c *gin.Context) {
+		calls++
+		c.Status(http.StatusOK)
+	})
+	send := func(path string, key string, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		if key != "" {
+			req.Header.Set("Idempotency-Key", key)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	first := send("/books", "k1", `{"isbn":"1"}`)
+	assert.Equal(t, http.StatusCreated, first.Code)
+	assert.Equal(t, 1, calls)
+
+	replay := send("/books", "k1", `{"isbn":"1"}`)
+	assert.Equal(t, http.StatusCreated, replay.Code)
+	assert.Equal(t, first.Body.String(), replay.Body.String())
+	assert.Equal(t, "true", replay.Header().Get("Idempotent-Replayed"))
+	assert.Equal(t, 1, calls)
+
+	reused := send("/books", "k1", `{"isbn":"2"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, reused.Code)
+	assert.Contains(t, reused.Body.String(), apperr.CodeIdempotencyKeyReused)
+
+	inFlight := send("/returns", "k2", "")
+	assert.Equal(t, http.StatusOK, inFlight.Code)
+	assert.Equal(t, http.StatusConflict, duplicate)
+
+	missing := send("/books", "", `{"isbn":"1"}`)
+	assert.Equal(t, http.StatusBadRequest, missing.Code)
+	assert.Contains(t, missing.Body.String(), apperr.CodeMissingIdempotencyKey)
+
+	calls = 0
+	failed := send("/books", "k3", `{"isbn":"boom"}`)
+	assert.Equal(t, http.StatusInternalServerError, failed.Code)
+	assert.NotContains(t, store.records, "0:POST:/books:k3")
+	fail = false
+	retried := send("/books", "k3", `{"isbn":"boom"}`)
+	assert.Equal(t, http.StatusCreated, retried.Code)
+	assert.Equal(t, 2, calls)
+
+	send("/loans", "", "")
+	send("/loans", "", "")
+	assert.Equal(t, 4, calls)
+}
+
+func TestStaffAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name   string
+		role   any
+		status int
+	}{
+		{"Admin", "admin", http.StatusOK},
+		{"Teacher", "teacher", http.StatusOK},
+		{"Student", "students", http.StatusForbidden},
+		{"Not_Logged_In", nil, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/reading-lists", func(c *gin.Context) {
+				if tt.role != nil {
+					c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), string(constanta.RL), tt.role))
+				}
+			}, StaffAuth(), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/reading-lists", nil))
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
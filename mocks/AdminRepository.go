@@ -8,6 +8,8 @@ import (
 
 	mock "github.com/stretchr/testify/mock"
 
+	pagination "stmnplibrary/pagination"
+
 	time "time"
 )
 
@@ -70,6 +72,52 @@ func (_m *AdminRepository) AddConnections(ctx context.Context, data []entity.Con
 	return r0
 }
 
+// DeactivateStudent provides a mock function with given fields: ctx, nis
+func (_m *AdminRepository) DeactivateStudent(ctx context.Context, nis int) error {
+	ret := _m.Called(ctx, nis)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeactivateStudent")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, nis)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetActiveLoan provides a mock function with given fields: ctx, id
+func (_m *AdminRepository) GetActiveLoan(ctx context.Context, id int) (entity.Loan, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetActiveLoan")
+	}
+
+	var r0 entity.Loan
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (entity.Loan, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) entity.Loan); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(entity.Loan)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetBookId provides a mock function with given fields: ctx, isbn
 func (_m *AdminRepository) GetBookId(ctx context.Context, isbn string) (int, error) {
 	ret := _m.Called(ctx, isbn)
@@ -98,89 +146,194 @@ func (_m *AdminRepository) GetBookId(ctx context.Context, isbn string) (int, err
 	return r0, r1
 }
 
-// GetLDDone provides a mock function with given fields: ctx, offset
-func (_m *AdminRepository) GetLDDone(ctx context.Context, offset int) ([]entity.LoanData, error) {
-	ret := _m.Called(ctx, offset)
+// GetBookPrice provides a mock function with given fields: ctx, idBook
+func (_m *AdminRepository) GetBookPrice(ctx context.Context, idBook int) (int64, error) {
+	ret := _m.Called(ctx, idBook)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBookPrice")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (int64, error)); ok {
+		return rf(ctx, idBook)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) int64); ok {
+		r0 = rf(ctx, idBook)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, idBook)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLDDone provides a mock function with given fields: ctx, page
+func (_m *AdminRepository) GetLDDone(ctx context.Context, page pagination.Page) ([]entity.LoanData, int64, error) {
+	ret := _m.Called(ctx, page)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetLDDone")
 	}
 
 	var r0 []entity.LoanData
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, int) ([]entity.LoanData, error)); ok {
-		return rf(ctx, offset)
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, pagination.Page) ([]entity.LoanData, int64, error)); ok {
+		return rf(ctx, page)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int) []entity.LoanData); ok {
-		r0 = rf(ctx, offset)
+	if rf, ok := ret.Get(0).(func(context.Context, pagination.Page) []entity.LoanData); ok {
+		r0 = rf(ctx, page)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]entity.LoanData)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
-		r1 = rf(ctx, offset)
+	if rf, ok := ret.Get(1).(func(context.Context, pagination.Page) int64); ok {
+		r1 = rf(ctx, page)
 	} else {
-		r1 = ret.Error(1)
+		r1 = ret.Get(1).(int64)
 	}
 
-	return r0, r1
+	if rf, ok := ret.Get(2).(func(context.Context, pagination.Page) error); ok {
+		r2 = rf(ctx, page)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
 }
 
-// GetLDDont provides a mock function with given fields: ctx, offset
-func (_m *AdminRepository) GetLDDont(ctx context.Context, offset int) ([]entity.LoanData, error) {
-	ret := _m.Called(ctx, offset)
+// GetLDDont provides a mock function with given fields: ctx, page
+func (_m *AdminRepository) GetLDDont(ctx context.Context, page pagination.Page) ([]entity.LoanData, int64, error) {
+	ret := _m.Called(ctx, page)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetLDDont")
 	}
 
 	var r0 []entity.LoanData
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, int) ([]entity.LoanData, error)); ok {
-		return rf(ctx, offset)
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, pagination.Page) ([]entity.LoanData, int64, error)); ok {
+		return rf(ctx, page)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int) []entity.LoanData); ok {
-		r0 = rf(ctx, offset)
+	if rf, ok := ret.Get(0).(func(context.Context, pagination.Page) []entity.LoanData); ok {
+		r0 = rf(ctx, page)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]entity.LoanData)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
-		r1 = rf(ctx, offset)
+	if rf, ok := ret.Get(1).(func(context.Context, pagination.Page) int64); ok {
+		r1 = rf(ctx, page)
 	} else {
-		r1 = ret.Error(1)
+		r1 = ret.Get(1).(int64)
 	}
 
-	return r0, r1
+	if rf, ok := ret.Get(2).(func(context.Context, pagination.Page) error); ok {
+		r2 = rf(ctx, page)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
 }
 
-// GetLoanData provides a mock function with given fields: ctx, offset
-func (_m *AdminRepository) GetLoanData(ctx context.Context, offset int) ([]entity.LoanData, error) {
-	ret := _m.Called(ctx, offset)
+// GetLoanData provides a mock function with given fields: ctx, page
+func (_m *AdminRepository) GetLoanData(ctx context.Context, page pagination.Page) ([]entity.LoanData, int64, error) {
+	ret := _m.Called(ctx, page)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetLoanData")
 	}
 
 	var r0 []entity.LoanData
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, int) ([]entity.LoanData, error)); ok {
-		return rf(ctx, offset)
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, pagination.Page) ([]entity.LoanData, int64, error)); ok {
+		return rf(ctx, page)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int) []entity.LoanData); ok {
-		r0 = rf(ctx, offset)
+	if rf, ok := ret.Get(0).(func(context.Context, pagination.Page) []entity.LoanData); ok {
+		r0 = rf(ctx, page)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]entity.LoanData)
 		}
 	}
 
+	if rf, ok := ret.Get(1).(func(context.Context, pagination.Page) int64); ok {
+		r1 = rf(ctx, page)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, pagination.Page) error); ok {
+		r2 = rf(ctx, page)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetStats provides a mock function with given fields: ctx
+func (_m *AdminRepository) GetStats(ctx context.Context) (entity.Stats, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStats")
+	}
+
+	var r0 entity.Stats
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (entity.Stats, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) entity.Stats); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(entity.Stats)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetStudent provides a mock function with given fields: ctx, nis
+func (_m *AdminRepository) GetStudent(ctx context.Context, nis int) (entity.StudentData, error) {
+	ret := _m.Called(ctx, nis)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStudent")
+	}
+
+	var r0 entity.StudentData
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (entity.StudentData, error)); ok {
+		return rf(ctx, nis)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) entity.StudentData); ok {
+		r0 = rf(ctx, nis)
+	} else {
+		r0 = ret.Get(0).(entity.StudentData)
+	}
+
 	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
-		r1 = rf(ctx, offset)
+		r1 = rf(ctx, nis)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -188,9 +341,9 @@ func (_m *AdminRepository) GetLoanData(ctx context.Context, offset int) ([]entit
 	return r0, r1
 }
 
-// GetStudentId provides a mock function with given fields: ctx, studentName
-func (_m *AdminRepository) GetStudentId(ctx context.Context, studentName string) (int, error) {
-	ret := _m.Called(ctx, studentName)
+// GetStudentId provides a mock function with given fields: ctx, nis
+func (_m *AdminRepository) GetStudentId(ctx context.Context, nis int) (int, error) {
+	ret := _m.Called(ctx, nis)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetStudentId")
@@ -198,17 +351,17 @@ func (_m *AdminRepository) GetStudentId(ctx context.Context, studentName string)
 
 	var r0 int
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (int, error)); ok {
-		return rf(ctx, studentName)
+	if rf, ok := ret.Get(0).(func(context.Context, int) (int, error)); ok {
+		return rf(ctx, nis)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) int); ok {
-		r0 = rf(ctx, studentName)
+	if rf, ok := ret.Get(0).(func(context.Context, int) int); ok {
+		r0 = rf(ctx, nis)
 	} else {
 		r0 = ret.Get(0).(int)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, studentName)
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, nis)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -244,6 +397,73 @@ func (_m *AdminRepository) GetStudentLoan(ctx context.Context, idUser int, idBoo
 	return r0, r1
 }
 
+// GetStudentLoans provides a mock function with given fields: ctx, idUser
+func (_m *AdminRepository) GetStudentLoans(ctx context.Context, idUser int) ([]entity.LoanData, error) {
+	ret := _m.Called(ctx, idUser)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStudentLoans")
+	}
+
+	var r0 []entity.LoanData
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]entity.LoanData, error)); ok {
+		return rf(ctx, idUser)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []entity.LoanData); ok {
+		r0 = rf(ctx, idUser)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.LoanData)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, idUser)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetStudents provides a mock function with given fields: ctx, filter, page
+func (_m *AdminRepository) GetStudents(ctx context.Context, filter entity.StudentFilter, page pagination.Page) ([]entity.StudentData, int64, error) {
+	ret := _m.Called(ctx, filter, page)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStudents")
+	}
+
+	var r0 []entity.StudentData
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.StudentFilter, pagination.Page) ([]entity.StudentData, int64, error)); ok {
+		return rf(ctx, filter, page)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.StudentFilter, pagination.Page) []entity.StudentData); ok {
+		r0 = rf(ctx, filter, page)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.StudentData)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.StudentFilter, pagination.Page) int64); ok {
+		r1 = rf(ctx, filter, page)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, entity.StudentFilter, pagination.Page) error); ok {
+		r2 = rf(ctx, filter, page)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // RedisDel provides a mock function with given fields: ctx, key
 func (_m *AdminRepository) RedisDel(ctx context.Context, key string) error {
 	ret := _m.Called(ctx, key)
@@ -263,23 +483,23 @@ func (_m *AdminRepository) RedisDel(ctx context.Context, key string) error {
 }
 
 // RedisGet provides a mock function with given fields: ctx, key
-func (_m *AdminRepository) RedisGet(ctx context.Context, key string) (interface{}, error) {
+func (_m *AdminRepository) RedisGet(ctx context.Context, key string) ([]byte, error) {
 	ret := _m.Called(ctx, key)
 
 	if len(ret) == 0 {
 		panic("no return value specified for RedisGet")
 	}
 
-	var r0 interface{}
+	var r0 []byte
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (interface{}, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]byte, error)); ok {
 		return rf(ctx, key)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) interface{}); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string) []byte); ok {
 		r0 = rf(ctx, key)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(interface{})
+			r0 = ret.Get(0).([]byte)
 		}
 	}
 
@@ -292,34 +512,6 @@ func (_m *AdminRepository) RedisGet(ctx context.Context, key string) (interface{
 	return r0, r1
 }
 
-// RedisSETNX provides a mock function with given fields: ctx, key, value, ttl
-func (_m *AdminRepository) RedisSETNX(ctx context.Context, key string, value string, ttl time.Duration) (bool, error) {
-	ret := _m.Called(ctx, key, value, ttl)
-
-	if len(ret) == 0 {
-		panic("no return value specified for RedisSETNX")
-	}
-
-	var r0 bool
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Duration) (bool, error)); ok {
-		return rf(ctx, key, value, ttl)
-	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Duration) bool); ok {
-		r0 = rf(ctx, key, value, ttl)
-	} else {
-		r0 = ret.Get(0).(bool)
-	}
-
-	if rf, ok := ret.Get(1).(func(context.Context, string, string, time.Duration) error); ok {
-		r1 = rf(ctx, key, value, ttl)
-	} else {
-		r1 = ret.Error(1)
-	}
-
-	return r0, r1
-}
-
 // RedisSet provides a mock function with given fields: ctx, key, data, ttl
 func (_m *AdminRepository) RedisSet(ctx context.Context, key string, data interface{}, ttl time.Duration) error {
 	ret := _m.Called(ctx, key, data, ttl)
@@ -374,17 +566,53 @@ func (_m *AdminRepository) UpdateStock(ctx context.Context, idBook int) error {
 	return r0
 }
 
-// UpdateTabLoan provides a mock function with given fields: ctx, idUser, idBook, sanctions, returnedAt
-func (_m *AdminRepository) UpdateTabLoan(ctx context.Context, idUser int, idBook int, sanctions int64, returnedAt time.Time) error {
-	ret := _m.Called(ctx, idUser, idBook, sanctions, returnedAt)
+// UpdateStudent provides a mock function with given fields: ctx, nis, data
+func (_m *AdminRepository) UpdateStudent(ctx context.Context, nis int, data entity.StudentData) error {
+	ret := _m.Called(ctx, nis, data)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateStudent")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, entity.StudentData) error); ok {
+		r0 = rf(ctx, nis, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateStudentRole provides a mock function with given fields: ctx, nis, from, to
+func (_m *AdminRepository) UpdateStudentRole(ctx context.Context, nis int, from string, to string) error {
+	ret := _m.Called(ctx, nis, from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateStudentRole")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, string) error); ok {
+		r0 = rf(ctx, nis, from, to)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateTabLoan provides a mock function with given fields: ctx, idUser, idBook, data
+func (_m *AdminRepository) UpdateTabLoan(ctx context.Context, idUser int, idBook int, data entity.LoanReturn) error {
+	ret := _m.Called(ctx, idUser, idBook, data)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateTabLoan")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, int, int, int64, time.Time) error); ok {
-		r0 = rf(ctx, idUser, idBook, sanctions, returnedAt)
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, entity.LoanReturn) error); ok {
+		r0 = rf(ctx, idUser, idBook, data)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -410,6 +638,24 @@ func (_m *AdminRepository) WithTx(ctx context.Context, fn func(context.Context)
 	return r0
 }
 
+// WriteOffStock provides a mock function with given fields: ctx, idBook
+func (_m *AdminRepository) WriteOffStock(ctx context.Context, idBook int) error {
+	ret := _m.Called(ctx, idBook)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WriteOffStock")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, idBook)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewAdminRepository creates a new instance of AdminRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewAdminRepository(t interface {
@@ -0,0 +1,86 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entity "stmnplibrary/domain/entity"
+
+	mock "github.com/stretchr/testify/mock"
+
+	pagination "stmnplibrary/pagination"
+)
+
+// AuditRepository is an autogenerated mock type for the AuditRepository type
+type AuditRepository struct {
+	mock.Mock
+}
+
+// GetAudits provides a mock function with given fields: ctx, filter, page
+func (_m *AuditRepository) GetAudits(ctx context.Context, filter entity.AuditFilter, page pagination.Page) ([]entity.Audit, int64, error) {
+	ret := _m.Called(ctx, filter, page)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAudits")
+	}
+
+	var r0 []entity.Audit
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.AuditFilter, pagination.Page) ([]entity.Audit, int64, error)); ok {
+		return rf(ctx, filter, page)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.AuditFilter, pagination.Page) []entity.Audit); ok {
+		r0 = rf(ctx, filter, page)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Audit)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.AuditFilter, pagination.Page) int64); ok {
+		r1 = rf(ctx, filter, page)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, entity.AuditFilter, pagination.Page) error); ok {
+		r2 = rf(ctx, filter, page)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Record provides a mock function with given fields: ctx, data
+func (_m *AuditRepository) Record(ctx context.Context, data entity.Audit) error {
+	ret := _m.Called(ctx, data)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Record")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.Audit) error); ok {
+		r0 = rf(ctx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewAuditRepository creates a new instance of AuditRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAuditRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AuditRepository {
+	mock := &AuditRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
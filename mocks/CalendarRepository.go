@@ -0,0 +1,201 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entity "stmnplibrary/domain/entity"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// CalendarRepository is an autogenerated mock type for the CalendarRepository type
+type CalendarRepository struct {
+	mock.Mock
+}
+
+// AddClosure provides a mock function with given fields: ctx, data
+func (_m *CalendarRepository) AddClosure(ctx context.Context, data *entity.Closure) error {
+	ret := _m.Called(ctx, data)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddClosure")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Closure) error); ok {
+		r0 = rf(ctx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteClosure provides a mock function with given fields: ctx, id
+func (_m *CalendarRepository) DeleteClosure(ctx context.Context, id int) (entity.Closure, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteClosure")
+	}
+
+	var r0 entity.Closure
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (entity.Closure, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) entity.Closure); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(entity.Closure)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetClosures provides a mock function with given fields: ctx, from, to
+func (_m *CalendarRepository) GetClosures(ctx context.Context, from time.Time, to time.Time) ([]entity.Closure, error) {
+	ret := _m.Called(ctx, from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetClosures")
+	}
+
+	var r0 []entity.Closure
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time) ([]entity.Closure, error)); ok {
+		return rf(ctx, from, to)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time) []entity.Closure); ok {
+		r0 = rf(ctx, from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Closure)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOpeningHours provides a mock function with given fields: ctx
+func (_m *CalendarRepository) GetOpeningHours(ctx context.Context) ([]entity.OpeningHours, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOpeningHours")
+	}
+
+	var r0 []entity.OpeningHours
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]entity.OpeningHours, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []entity.OpeningHours); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.OpeningHours)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetOpeningHours provides a mock function with given fields: ctx, hours
+func (_m *CalendarRepository) SetOpeningHours(ctx context.Context, hours []entity.OpeningHours) error {
+	ret := _m.Called(ctx, hours)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetOpeningHours")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []entity.OpeningHours) error); ok {
+		r0 = rf(ctx, hours)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpsertClosures provides a mock function with given fields: ctx, data
+func (_m *CalendarRepository) UpsertClosures(ctx context.Context, data []entity.Closure) (int64, error) {
+	ret := _m.Called(ctx, data)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpsertClosures")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []entity.Closure) (int64, error)); ok {
+		return rf(ctx, data)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []entity.Closure) int64); ok {
+		r0 = rf(ctx, data)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []entity.Closure) error); ok {
+		r1 = rf(ctx, data)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WithTx provides a mock function with given fields: ctx, fn
+func (_m *CalendarRepository) WithTx(ctx context.Context, fn func(context.Context) error) error {
+	ret := _m.Called(ctx, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithTx")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, func(context.Context) error) error); ok {
+		r0 = rf(ctx, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewCalendarRepository creates a new instance of CalendarRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewCalendarRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *CalendarRepository {
+	mock := &CalendarRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,235 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entity "stmnplibrary/domain/entity"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ClearanceRepository is an autogenerated mock type for the ClearanceRepository type
+type ClearanceRepository struct {
+	mock.Mock
+}
+
+// AddClearance provides a mock function with given fields: ctx, data
+func (_m *ClearanceRepository) AddClearance(ctx context.Context, data *entity.Clearance) error {
+	ret := _m.Called(ctx, data)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddClearance")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Clearance) error); ok {
+		r0 = rf(ctx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CheckClearance provides a mock function with given fields: ctx, idUser
+func (_m *ClearanceRepository) CheckClearance(ctx context.Context, idUser int) (entity.ClearanceCheck, error) {
+	ret := _m.Called(ctx, idUser)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CheckClearance")
+	}
+
+	var r0 entity.ClearanceCheck
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (entity.ClearanceCheck, error)); ok {
+		return rf(ctx, idUser)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) entity.ClearanceCheck); ok {
+		r0 = rf(ctx, idUser)
+	} else {
+		r0 = ret.Get(0).(entity.ClearanceCheck)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, idUser)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetClearance provides a mock function with given fields: ctx, number
+func (_m *ClearanceRepository) GetClearance(ctx context.Context, number string) (entity.Clearance, error) {
+	ret := _m.Called(ctx, number)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetClearance")
+	}
+
+	var r0 entity.Clearance
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (entity.Clearance, error)); ok {
+		return rf(ctx, number)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) entity.Clearance); ok {
+		r0 = rf(ctx, number)
+	} else {
+		r0 = ret.Get(0).(entity.Clearance)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, number)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetClearanceByUser provides a mock function with given fields: ctx, idUser, purpose
+func (_m *ClearanceRepository) GetClearanceByUser(ctx context.Context, idUser int, purpose string) (entity.Clearance, error) {
+	ret := _m.Called(ctx, idUser, purpose)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetClearanceByUser")
+	}
+
+	var r0 entity.Clearance
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) (entity.Clearance, error)); ok {
+		return rf(ctx, idUser, purpose)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) entity.Clearance); ok {
+		r0 = rf(ctx, idUser, purpose)
+	} else {
+		r0 = ret.Get(0).(entity.Clearance)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, string) error); ok {
+		r1 = rf(ctx, idUser, purpose)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetStudentsByBatch provides a mock function with given fields: ctx, batch
+func (_m *ClearanceRepository) GetStudentsByBatch(ctx context.Context, batch int) ([]entity.StudentData, error) {
+	ret := _m.Called(ctx, batch)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStudentsByBatch")
+	}
+
+	var r0 []entity.StudentData
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]entity.StudentData, error)); ok {
+		return rf(ctx, batch)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []entity.StudentData); ok {
+		r0 = rf(ctx, batch)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.StudentData)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, batch)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LockStudent provides a mock function with given fields: ctx, nis
+func (_m *ClearanceRepository) LockStudent(ctx context.Context, nis int) (entity.StudentData, error) {
+	ret := _m.Called(ctx, nis)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LockStudent")
+	}
+
+	var r0 entity.StudentData
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (entity.StudentData, error)); ok {
+		return rf(ctx, nis)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) entity.StudentData); ok {
+		r0 = rf(ctx, nis)
+	} else {
+		r0 = ret.Get(0).(entity.StudentData)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, nis)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SettleSanctions provides a mock function with given fields: ctx, idUser
+func (_m *ClearanceRepository) SettleSanctions(ctx context.Context, idUser int) (int64, error) {
+	ret := _m.Called(ctx, idUser)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SettleSanctions")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (int64, error)); ok {
+		return rf(ctx, idUser)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) int64); ok {
+		r0 = rf(ctx, idUser)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, idUser)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WithTx provides a mock function with given fields: ctx, fn
+func (_m *ClearanceRepository) WithTx(ctx context.Context, fn func(context.Context) error) error {
+	ret := _m.Called(ctx, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithTx")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, func(context.Context) error) error); ok {
+		r0 = rf(ctx, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewClearanceRepository creates a new instance of ClearanceRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewClearanceRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ClearanceRepository {
+	mock := &ClearanceRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
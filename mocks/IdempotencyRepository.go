@@ -0,0 +1,124 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// IdempotencyRepository is an autogenerated mock type for the IdempotencyRepository type
+type IdempotencyRepository struct {
+	mock.Mock
+}
+
+// RedisDel provides a mock function with given fields: ctx, key
+func (_m *IdempotencyRepository) RedisDel(ctx context.Context, key string) error {
+	ret := _m.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RedisDel")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RedisGet provides a mock function with given fields: ctx, key
+func (_m *IdempotencyRepository) RedisGet(ctx context.Context, key string) ([]byte, error) {
+	ret := _m.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RedisGet")
+	}
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]byte, error)); ok {
+		return rf(ctx, key)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []byte); ok {
+		r0 = rf(ctx, key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RedisSETNX provides a mock function with given fields: ctx, key, data, ttl
+func (_m *IdempotencyRepository) RedisSETNX(ctx context.Context, key string, data interface{}, ttl time.Duration) (bool, error) {
+	ret := _m.Called(ctx, key, data, ttl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RedisSETNX")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, interface{}, time.Duration) (bool, error)); ok {
+		return rf(ctx, key, data, ttl)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, interface{}, time.Duration) bool); ok {
+		r0 = rf(ctx, key, data, ttl)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, interface{}, time.Duration) error); ok {
+		r1 = rf(ctx, key, data, ttl)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RedisSet provides a mock function with given fields: ctx, key, data, ttl
+func (_m *IdempotencyRepository) RedisSet(ctx context.Context, key string, data interface{}, ttl time.Duration) error {
+	ret := _m.Called(ctx, key, data, ttl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RedisSet")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, interface{}, time.Duration) error); ok {
+		r0 = rf(ctx, key, data, ttl)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewIdempotencyRepository creates a new instance of IdempotencyRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewIdempotencyRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *IdempotencyRepository {
+	mock := &IdempotencyRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,169 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entity "stmnplibrary/domain/entity"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// OutboxRepository is an autogenerated mock type for the OutboxRepository type
+type OutboxRepository struct {
+	mock.Mock
+}
+
+// Add provides a mock function with given fields: ctx, data
+func (_m *OutboxRepository) Add(ctx context.Context, data entity.Outbox) error {
+	ret := _m.Called(ctx, data)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Add")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.Outbox) error); ok {
+		r0 = rf(ctx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetPending provides a mock function with given fields: ctx, limit
+func (_m *OutboxRepository) GetPending(ctx context.Context, limit int) ([]entity.Outbox, error) {
+	ret := _m.Called(ctx, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPending")
+	}
+
+	var r0 []entity.Outbox
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]entity.Outbox, error)); ok {
+		return rf(ctx, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []entity.Outbox); ok {
+		r0 = rf(ctx, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Outbox)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MarkDead provides a mock function with given fields: ctx, id, lastErr
+func (_m *OutboxRepository) MarkDead(ctx context.Context, id int, lastErr string) error {
+	ret := _m.Called(ctx, id, lastErr)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkDead")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) error); ok {
+		r0 = rf(ctx, id, lastErr)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MarkPublished provides a mock function with given fields: ctx, ids
+func (_m *OutboxRepository) MarkPublished(ctx context.Context, ids []int) error {
+	ret := _m.Called(ctx, ids)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkPublished")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []int) error); ok {
+		r0 = rf(ctx, ids)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MarkRetry provides a mock function with given fields: ctx, id, lastErr, availableAt
+func (_m *OutboxRepository) MarkRetry(ctx context.Context, id int, lastErr string, availableAt time.Time) error {
+	ret := _m.Called(ctx, id, lastErr, availableAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkRetry")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, time.Time) error); ok {
+		r0 = rf(ctx, id, lastErr, availableAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// WithTx provides a mock function with given fields: ctx, fn
+func (_m *OutboxRepository) WithTx(ctx context.Context, fn func(context.Context) error) error {
+	ret := _m.Called(ctx, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithTx")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, func(context.Context) error) error); ok {
+		r0 = rf(ctx, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// XAdd provides a mock function with given fields: ctx, stream, maxLen, values
+func (_m *OutboxRepository) XAdd(ctx context.Context, stream string, maxLen int64, values map[string]interface{}) error {
+	ret := _m.Called(ctx, stream, maxLen, values)
+
+	if len(ret) == 0 {
+		panic("no return value specified for XAdd")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, map[string]interface{}) error); ok {
+		r0 = rf(ctx, stream, maxLen, values)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewOutboxRepository creates a new instance of OutboxRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewOutboxRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *OutboxRepository {
+	mock := &OutboxRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,342 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entity "stmnplibrary/domain/entity"
+
+	mock "github.com/stretchr/testify/mock"
+
+	pagination "stmnplibrary/pagination"
+)
+
+// ReadingListRepository is an autogenerated mock type for the ReadingListRepository type
+type ReadingListRepository struct {
+	mock.Mock
+}
+
+// AddReadingList provides a mock function with given fields: ctx, data
+func (_m *ReadingListRepository) AddReadingList(ctx context.Context, data *entity.ReadingList) error {
+	ret := _m.Called(ctx, data)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddReadingList")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.ReadingList) error); ok {
+		r0 = rf(ctx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CountBooks provides a mock function with given fields: ctx, ids
+func (_m *ReadingListRepository) CountBooks(ctx context.Context, ids []int) (int64, error) {
+	ret := _m.Called(ctx, ids)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountBooks")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []int) (int64, error)); ok {
+		return rf(ctx, ids)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []int) int64); ok {
+		r0 = rf(ctx, ids)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []int) error); ok {
+		r1 = rf(ctx, ids)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteReadingList provides a mock function with given fields: ctx, id
+func (_m *ReadingListRepository) DeleteReadingList(ctx context.Context, id int) (entity.ReadingList, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteReadingList")
+	}
+
+	var r0 entity.ReadingList
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (entity.ReadingList, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) entity.ReadingList); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(entity.ReadingList)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetProgress provides a mock function with given fields: ctx, list, idUsers
+func (_m *ReadingListRepository) GetProgress(ctx context.Context, list entity.ReadingList, idUsers []int) ([]entity.ReadingProgress, error) {
+	ret := _m.Called(ctx, list, idUsers)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProgress")
+	}
+
+	var r0 []entity.ReadingProgress
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ReadingList, []int) ([]entity.ReadingProgress, error)); ok {
+		return rf(ctx, list, idUsers)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ReadingList, []int) []entity.ReadingProgress); ok {
+		r0 = rf(ctx, list, idUsers)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.ReadingProgress)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.ReadingList, []int) error); ok {
+		r1 = rf(ctx, list, idUsers)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReadingList provides a mock function with given fields: ctx, id
+func (_m *ReadingListRepository) GetReadingList(ctx context.Context, id int) (entity.ReadingList, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReadingList")
+	}
+
+	var r0 entity.ReadingList
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (entity.ReadingList, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) entity.ReadingList); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(entity.ReadingList)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReadingLists provides a mock function with given fields: ctx, filter, page
+func (_m *ReadingListRepository) GetReadingLists(ctx context.Context, filter entity.ReadingListFilter, page pagination.Page) ([]entity.ReadingList, int64, error) {
+	ret := _m.Called(ctx, filter, page)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReadingLists")
+	}
+
+	var r0 []entity.ReadingList
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ReadingListFilter, pagination.Page) ([]entity.ReadingList, int64, error)); ok {
+		return rf(ctx, filter, page)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ReadingListFilter, pagination.Page) []entity.ReadingList); ok {
+		r0 = rf(ctx, filter, page)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.ReadingList)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.ReadingListFilter, pagination.Page) int64); ok {
+		r1 = rf(ctx, filter, page)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, entity.ReadingListFilter, pagination.Page) error); ok {
+		r2 = rf(ctx, filter, page)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetStudent provides a mock function with given fields: ctx, id
+func (_m *ReadingListRepository) GetStudent(ctx context.Context, id int) (entity.StudentData, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStudent")
+	}
+
+	var r0 entity.StudentData
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (entity.StudentData, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) entity.StudentData); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(entity.StudentData)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetStudentReadingLists provides a mock function with given fields: ctx, student
+func (_m *ReadingListRepository) GetStudentReadingLists(ctx context.Context, student entity.StudentData) ([]entity.ReadingList, error) {
+	ret := _m.Called(ctx, student)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStudentReadingLists")
+	}
+
+	var r0 []entity.ReadingList
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.StudentData) ([]entity.ReadingList, error)); ok {
+		return rf(ctx, student)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.StudentData) []entity.ReadingList); ok {
+		r0 = rf(ctx, student)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.ReadingList)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.StudentData) error); ok {
+		r1 = rf(ctx, student)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetStudents provides a mock function with given fields: ctx, list
+func (_m *ReadingListRepository) GetStudents(ctx context.Context, list entity.ReadingList) ([]entity.StudentData, error) {
+	ret := _m.Called(ctx, list)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStudents")
+	}
+
+	var r0 []entity.StudentData
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ReadingList) ([]entity.StudentData, error)); ok {
+		return rf(ctx, list)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ReadingList) []entity.StudentData); ok {
+		r0 = rf(ctx, list)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.StudentData)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.ReadingList) error); ok {
+		r1 = rf(ctx, list)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetReadingListBooks provides a mock function with given fields: ctx, idList, books
+func (_m *ReadingListRepository) SetReadingListBooks(ctx context.Context, idList int, books []entity.ReadingListBook) error {
+	ret := _m.Called(ctx, idList, books)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetReadingListBooks")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, []entity.ReadingListBook) error); ok {
+		r0 = rf(ctx, idList, books)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateReadingList provides a mock function with given fields: ctx, data
+func (_m *ReadingListRepository) UpdateReadingList(ctx context.Context, data *entity.ReadingList) error {
+	ret := _m.Called(ctx, data)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateReadingList")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.ReadingList) error); ok {
+		r0 = rf(ctx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// WithTx provides a mock function with given fields: ctx, fn
+func (_m *ReadingListRepository) WithTx(ctx context.Context, fn func(context.Context) error) error {
+	ret := _m.Called(ctx, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithTx")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, func(context.Context) error) error); ok {
+		r0 = rf(ctx, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewReadingListRepository creates a new instance of ReadingListRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewReadingListRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ReadingListRepository {
+	mock := &ReadingListRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,167 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entity "stmnplibrary/domain/entity"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// RecommendationRepository is an autogenerated mock type for the RecommendationRepository type
+type RecommendationRepository struct {
+	mock.Mock
+}
+
+// GetCandidates provides a mock function with given fields: ctx, student, since, limit
+func (_m *RecommendationRepository) GetCandidates(ctx context.Context, student entity.StudentData, since time.Time, limit int) ([]entity.Candidate, error) {
+	ret := _m.Called(ctx, student, since, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCandidates")
+	}
+
+	var r0 []entity.Candidate
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.StudentData, time.Time, int) ([]entity.Candidate, error)); ok {
+		return rf(ctx, student, since, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.StudentData, time.Time, int) []entity.Candidate); ok {
+		r0 = rf(ctx, student, since, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Candidate)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.StudentData, time.Time, int) error); ok {
+		r1 = rf(ctx, student, since, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetStudent provides a mock function with given fields: ctx, id
+func (_m *RecommendationRepository) GetStudent(ctx context.Context, id int) (entity.StudentData, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStudent")
+	}
+
+	var r0 entity.StudentData
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (entity.StudentData, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) entity.StudentData); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(entity.StudentData)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetStudents provides a mock function with given fields: ctx, afterID, limit
+func (_m *RecommendationRepository) GetStudents(ctx context.Context, afterID int, limit int) ([]entity.StudentData, error) {
+	ret := _m.Called(ctx, afterID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStudents")
+	}
+
+	var r0 []entity.StudentData
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]entity.StudentData, error)); ok {
+		return rf(ctx, afterID, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []entity.StudentData); ok {
+		r0 = rf(ctx, afterID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.StudentData)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, afterID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RedisGet provides a mock function with given fields: ctx, key
+func (_m *RecommendationRepository) RedisGet(ctx context.Context, key string) ([]byte, error) {
+	ret := _m.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RedisGet")
+	}
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]byte, error)); ok {
+		return rf(ctx, key)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []byte); ok {
+		r0 = rf(ctx, key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RedisSet provides a mock function with given fields: ctx, key, data, ttl
+func (_m *RecommendationRepository) RedisSet(ctx context.Context, key string, data interface{}, ttl time.Duration) error {
+	ret := _m.Called(ctx, key, data, ttl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RedisSet")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, interface{}, time.Duration) error); ok {
+		r0 = rf(ctx, key, data, ttl)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewRecommendationRepository creates a new instance of RecommendationRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRecommendationRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RecommendationRepository {
+	mock := &RecommendationRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,262 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entity "stmnplibrary/domain/entity"
+
+	mock "github.com/stretchr/testify/mock"
+
+	pagination "stmnplibrary/pagination"
+)
+
+// ReviewRepository is an autogenerated mock type for the ReviewRepository type
+type ReviewRepository struct {
+	mock.Mock
+}
+
+// GetReview provides a mock function with given fields: ctx, id
+func (_m *ReviewRepository) GetReview(ctx context.Context, id int) (entity.Review, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReview")
+	}
+
+	var r0 entity.Review
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (entity.Review, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) entity.Review); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(entity.Review)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReviewByUser provides a mock function with given fields: ctx, idUser, idBook
+func (_m *ReviewRepository) GetReviewByUser(ctx context.Context, idUser int, idBook int) (entity.Review, error) {
+	ret := _m.Called(ctx, idUser, idBook)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReviewByUser")
+	}
+
+	var r0 entity.Review
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (entity.Review, error)); ok {
+		return rf(ctx, idUser, idBook)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) entity.Review); ok {
+		r0 = rf(ctx, idUser, idBook)
+	} else {
+		r0 = ret.Get(0).(entity.Review)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, idUser, idBook)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReviews provides a mock function with given fields: ctx, filter, page
+func (_m *ReviewRepository) GetReviews(ctx context.Context, filter entity.ReviewFilter, page pagination.Page) ([]entity.ReviewData, int64, error) {
+	ret := _m.Called(ctx, filter, page)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReviews")
+	}
+
+	var r0 []entity.ReviewData
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ReviewFilter, pagination.Page) ([]entity.ReviewData, int64, error)); ok {
+		return rf(ctx, filter, page)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ReviewFilter, pagination.Page) []entity.ReviewData); ok {
+		r0 = rf(ctx, filter, page)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.ReviewData)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.ReviewFilter, pagination.Page) int64); ok {
+		r1 = rf(ctx, filter, page)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, entity.ReviewFilter, pagination.Page) error); ok {
+		r2 = rf(ctx, filter, page)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// HasReturnedLoan provides a mock function with given fields: ctx, idUser, idBook
+func (_m *ReviewRepository) HasReturnedLoan(ctx context.Context, idUser int, idBook int) (bool, error) {
+	ret := _m.Called(ctx, idUser, idBook)
+
+	if len(ret) == 0 {
+		panic("no return value specified for HasReturnedLoan")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (bool, error)); ok {
+		return rf(ctx, idUser, idBook)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) bool); ok {
+		r0 = rf(ctx, idUser, idBook)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, idUser, idBook)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LockBook provides a mock function with given fields: ctx, idBook
+func (_m *ReviewRepository) LockBook(ctx context.Context, idBook int) error {
+	ret := _m.Called(ctx, idBook)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LockBook")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, idBook)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetReviewStatus provides a mock function with given fields: ctx, id, status, reason
+func (_m *ReviewRepository) SetReviewStatus(ctx context.Context, id int, status string, reason string) (entity.Review, error) {
+	ret := _m.Called(ctx, id, status, reason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetReviewStatus")
+	}
+
+	var r0 entity.Review
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, string) (entity.Review, error)); ok {
+		return rf(ctx, id, status, reason)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, string) entity.Review); ok {
+		r0 = rf(ctx, id, status, reason)
+	} else {
+		r0 = ret.Get(0).(entity.Review)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, string, string) error); ok {
+		r1 = rf(ctx, id, status, reason)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateBookRating provides a mock function with given fields: ctx, idBook
+func (_m *ReviewRepository) UpdateBookRating(ctx context.Context, idBook int) (entity.Rating, error) {
+	ret := _m.Called(ctx, idBook)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateBookRating")
+	}
+
+	var r0 entity.Rating
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (entity.Rating, error)); ok {
+		return rf(ctx, idBook)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) entity.Rating); ok {
+		r0 = rf(ctx, idBook)
+	} else {
+		r0 = ret.Get(0).(entity.Rating)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, idBook)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpsertReview provides a mock function with given fields: ctx, data
+func (_m *ReviewRepository) UpsertReview(ctx context.Context, data *entity.Review) error {
+	ret := _m.Called(ctx, data)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpsertReview")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Review) error); ok {
+		r0 = rf(ctx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// WithTx provides a mock function with given fields: ctx, fn
+func (_m *ReviewRepository) WithTx(ctx context.Context, fn func(context.Context) error) error {
+	ret := _m.Called(ctx, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithTx")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, func(context.Context) error) error); ok {
+		r0 = rf(ctx, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewReviewRepository creates a new instance of ReviewRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewReviewRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ReviewRepository {
+	mock := &ReviewRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
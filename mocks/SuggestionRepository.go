@@ -0,0 +1,338 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entity "stmnplibrary/domain/entity"
+
+	mock "github.com/stretchr/testify/mock"
+
+	pagination "stmnplibrary/pagination"
+)
+
+// SuggestionRepository is an autogenerated mock type for the SuggestionRepository type
+type SuggestionRepository struct {
+	mock.Mock
+}
+
+// AddHolds provides a mock function with given fields: ctx, holds
+func (_m *SuggestionRepository) AddHolds(ctx context.Context, holds []entity.Hold) error {
+	ret := _m.Called(ctx, holds)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddHolds")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []entity.Hold) error); ok {
+		r0 = rf(ctx, holds)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AddSuggestion provides a mock function with given fields: ctx, data
+func (_m *SuggestionRepository) AddSuggestion(ctx context.Context, data *entity.Suggestion) error {
+	ret := _m.Called(ctx, data)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddSuggestion")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Suggestion) error); ok {
+		r0 = rf(ctx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AddVote provides a mock function with given fields: ctx, idSuggestion, idUser
+func (_m *SuggestionRepository) AddVote(ctx context.Context, idSuggestion int, idUser int) (bool, error) {
+	ret := _m.Called(ctx, idSuggestion, idUser)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddVote")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (bool, error)); ok {
+		return rf(ctx, idSuggestion, idUser)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) bool); ok {
+		r0 = rf(ctx, idSuggestion, idUser)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, idSuggestion, idUser)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CountVotes provides a mock function with given fields: ctx, idSuggestion
+func (_m *SuggestionRepository) CountVotes(ctx context.Context, idSuggestion int) (int, error) {
+	ret := _m.Called(ctx, idSuggestion)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountVotes")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (int, error)); ok {
+		return rf(ctx, idSuggestion)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) int); ok {
+		r0 = rf(ctx, idSuggestion)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, idSuggestion)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBookID provides a mock function with given fields: ctx, isbn
+func (_m *SuggestionRepository) GetBookID(ctx context.Context, isbn string) (int, error) {
+	ret := _m.Called(ctx, isbn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBookID")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (int, error)); ok {
+		return rf(ctx, isbn)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) int); ok {
+		r0 = rf(ctx, isbn)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, isbn)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOpenSuggestion provides a mock function with given fields: ctx, isbn
+func (_m *SuggestionRepository) GetOpenSuggestion(ctx context.Context, isbn string) (entity.Suggestion, error) {
+	ret := _m.Called(ctx, isbn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOpenSuggestion")
+	}
+
+	var r0 entity.Suggestion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (entity.Suggestion, error)); ok {
+		return rf(ctx, isbn)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) entity.Suggestion); ok {
+		r0 = rf(ctx, isbn)
+	} else {
+		r0 = ret.Get(0).(entity.Suggestion)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, isbn)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRequesters provides a mock function with given fields: ctx, idSuggestion
+func (_m *SuggestionRepository) GetRequesters(ctx context.Context, idSuggestion int) ([]int, error) {
+	ret := _m.Called(ctx, idSuggestion)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRequesters")
+	}
+
+	var r0 []int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]int, error)); ok {
+		return rf(ctx, idSuggestion)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []int); ok {
+		r0 = rf(ctx, idSuggestion)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]int)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, idSuggestion)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSuggestions provides a mock function with given fields: ctx, filter, page
+func (_m *SuggestionRepository) GetSuggestions(ctx context.Context, filter entity.SuggestionFilter, page pagination.Page) ([]entity.SuggestionData, int64, error) {
+	ret := _m.Called(ctx, filter, page)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSuggestions")
+	}
+
+	var r0 []entity.SuggestionData
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.SuggestionFilter, pagination.Page) ([]entity.SuggestionData, int64, error)); ok {
+		return rf(ctx, filter, page)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.SuggestionFilter, pagination.Page) []entity.SuggestionData); ok {
+		r0 = rf(ctx, filter, page)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.SuggestionData)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.SuggestionFilter, pagination.Page) int64); ok {
+		r1 = rf(ctx, filter, page)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, entity.SuggestionFilter, pagination.Page) error); ok {
+		r2 = rf(ctx, filter, page)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// LockBookStock provides a mock function with given fields: ctx, idBook
+func (_m *SuggestionRepository) LockBookStock(ctx context.Context, idBook int) (int, error) {
+	ret := _m.Called(ctx, idBook)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LockBookStock")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (int, error)); ok {
+		return rf(ctx, idBook)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) int); ok {
+		r0 = rf(ctx, idBook)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, idBook)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LockSuggestion provides a mock function with given fields: ctx, id
+func (_m *SuggestionRepository) LockSuggestion(ctx context.Context, id int) (entity.Suggestion, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LockSuggestion")
+	}
+
+	var r0 entity.Suggestion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (entity.Suggestion, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) entity.Suggestion); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(entity.Suggestion)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateSuggestion provides a mock function with given fields: ctx, data
+func (_m *SuggestionRepository) UpdateSuggestion(ctx context.Context, data *entity.Suggestion) error {
+	ret := _m.Called(ctx, data)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateSuggestion")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Suggestion) error); ok {
+		r0 = rf(ctx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// WithTx provides a mock function with given fields: ctx, fn
+func (_m *SuggestionRepository) WithTx(ctx context.Context, fn func(context.Context) error) error {
+	ret := _m.Called(ctx, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithTx")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, func(context.Context) error) error); ok {
+		r0 = rf(ctx, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewSuggestionRepository creates a new instance of SuggestionRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSuggestionRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SuggestionRepository {
+	mock := &SuggestionRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
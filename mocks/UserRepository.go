@@ -8,6 +8,8 @@ import (
 
 	mock "github.com/stretchr/testify/mock"
 
+	pagination "stmnplibrary/pagination"
+
 	time "time"
 )
 
@@ -52,94 +54,133 @@ func (_m *UserRepository) CreateLoan(ctx context.Context, loanData entity.Loan)
 	return r0
 }
 
-// GetBooks provides a mock function with given fields: ctx, offset
-func (_m *UserRepository) GetBooks(ctx context.Context, offset int) ([]entity.Book, error) {
-	ret := _m.Called(ctx, offset)
+// FulfillHold provides a mock function with given fields: ctx, idBook, idUser, now
+func (_m *UserRepository) FulfillHold(ctx context.Context, idBook int, idUser int, now time.Time) error {
+	ret := _m.Called(ctx, idBook, idUser, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FulfillHold")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, time.Time) error); ok {
+		r0 = rf(ctx, idBook, idUser, now)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetBooks provides a mock function with given fields: ctx, page
+func (_m *UserRepository) GetBooks(ctx context.Context, page pagination.Page) ([]entity.Book, int64, error) {
+	ret := _m.Called(ctx, page)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetBooks")
 	}
 
 	var r0 []entity.Book
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, int) ([]entity.Book, error)); ok {
-		return rf(ctx, offset)
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, pagination.Page) ([]entity.Book, int64, error)); ok {
+		return rf(ctx, page)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int) []entity.Book); ok {
-		r0 = rf(ctx, offset)
+	if rf, ok := ret.Get(0).(func(context.Context, pagination.Page) []entity.Book); ok {
+		r0 = rf(ctx, page)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]entity.Book)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
-		r1 = rf(ctx, offset)
+	if rf, ok := ret.Get(1).(func(context.Context, pagination.Page) int64); ok {
+		r1 = rf(ctx, page)
 	} else {
-		r1 = ret.Error(1)
+		r1 = ret.Get(1).(int64)
 	}
 
-	return r0, r1
+	if rf, ok := ret.Get(2).(func(context.Context, pagination.Page) error); ok {
+		r2 = rf(ctx, page)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
 }
 
-// GetBooksByAuthor provides a mock function with given fields: ctx, author, offset
-func (_m *UserRepository) GetBooksByAuthor(ctx context.Context, author string, offset int) ([]entity.Book, error) {
-	ret := _m.Called(ctx, author, offset)
+// GetBooksByAuthor provides a mock function with given fields: ctx, author, page
+func (_m *UserRepository) GetBooksByAuthor(ctx context.Context, author string, page pagination.Page) ([]entity.Book, int64, error) {
+	ret := _m.Called(ctx, author, page)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetBooksByAuthor")
 	}
 
 	var r0 []entity.Book
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, int) ([]entity.Book, error)); ok {
-		return rf(ctx, author, offset)
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, pagination.Page) ([]entity.Book, int64, error)); ok {
+		return rf(ctx, author, page)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, int) []entity.Book); ok {
-		r0 = rf(ctx, author, offset)
+	if rf, ok := ret.Get(0).(func(context.Context, string, pagination.Page) []entity.Book); ok {
+		r0 = rf(ctx, author, page)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]entity.Book)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
-		r1 = rf(ctx, author, offset)
+	if rf, ok := ret.Get(1).(func(context.Context, string, pagination.Page) int64); ok {
+		r1 = rf(ctx, author, page)
 	} else {
-		r1 = ret.Error(1)
+		r1 = ret.Get(1).(int64)
 	}
 
-	return r0, r1
+	if rf, ok := ret.Get(2).(func(context.Context, string, pagination.Page) error); ok {
+		r2 = rf(ctx, author, page)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
 }
 
-// GetBooksByCategory provides a mock function with given fields: ctx, category, offset
-func (_m *UserRepository) GetBooksByCategory(ctx context.Context, category []string, offset int) ([]entity.Book, error) {
-	ret := _m.Called(ctx, category, offset)
+// GetBooksByCategory provides a mock function with given fields: ctx, category, page
+func (_m *UserRepository) GetBooksByCategory(ctx context.Context, category []string, page pagination.Page) ([]entity.Book, int64, error) {
+	ret := _m.Called(ctx, category, page)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetBooksByCategory")
 	}
 
 	var r0 []entity.Book
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, []string, int) ([]entity.Book, error)); ok {
-		return rf(ctx, category, offset)
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string, pagination.Page) ([]entity.Book, int64, error)); ok {
+		return rf(ctx, category, page)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, []string, int) []entity.Book); ok {
-		r0 = rf(ctx, category, offset)
+	if rf, ok := ret.Get(0).(func(context.Context, []string, pagination.Page) []entity.Book); ok {
+		r0 = rf(ctx, category, page)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]entity.Book)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, []string, int) error); ok {
-		r1 = rf(ctx, category, offset)
+	if rf, ok := ret.Get(1).(func(context.Context, []string, pagination.Page) int64); ok {
+		r1 = rf(ctx, category, page)
 	} else {
-		r1 = ret.Error(1)
+		r1 = ret.Get(1).(int64)
 	}
 
-	return r0, r1
+	if rf, ok := ret.Get(2).(func(context.Context, []string, pagination.Page) error); ok {
+		r2 = rf(ctx, category, page)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
 }
 
 // GetEmail provides a mock function with given fields: ctx, email
@@ -215,23 +256,23 @@ func (_m *UserRepository) RedisDel(ctx context.Context, key string) error {
 }
 
 // RedisGet provides a mock function with given fields: ctx, key
-func (_m *UserRepository) RedisGet(ctx context.Context, key string) (interface{}, error) {
+func (_m *UserRepository) RedisGet(ctx context.Context, key string) ([]byte, error) {
 	ret := _m.Called(ctx, key)
 
 	if len(ret) == 0 {
 		panic("no return value specified for RedisGet")
 	}
 
-	var r0 interface{}
+	var r0 []byte
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (interface{}, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]byte, error)); ok {
 		return rf(ctx, key)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) interface{}); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string) []byte); ok {
 		r0 = rf(ctx, key)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(interface{})
+			r0 = ret.Get(0).([]byte)
 		}
 	}
 
@@ -244,42 +285,6 @@ func (_m *UserRepository) RedisGet(ctx context.Context, key string) (interface{}
 	return r0, r1
 }
 
-// RedisHGetAll provides a mock function with given fields: ctx, key, dest
-func (_m *UserRepository) RedisHGetAll(ctx context.Context, key string, dest interface{}) error {
-	ret := _m.Called(ctx, key, dest)
-
-	if len(ret) == 0 {
-		panic("no return value specified for RedisHGetAll")
-	}
-
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, interface{}) error); ok {
-		r0 = rf(ctx, key, dest)
-	} else {
-		r0 = ret.Error(0)
-	}
-
-	return r0
-}
-
-// RedisHSET provides a mock function with given fields: ctx, key, value
-func (_m *UserRepository) RedisHSET(ctx context.Context, key string, value interface{}) error {
-	ret := _m.Called(ctx, key, value)
-
-	if len(ret) == 0 {
-		panic("no return value specified for RedisHSET")
-	}
-
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, interface{}) error); ok {
-		r0 = rf(ctx, key, value)
-	} else {
-		r0 = ret.Error(0)
-	}
-
-	return r0
-}
-
 // RedisSet provides a mock function with given fields: ctx, key, data, ttl
 func (_m *UserRepository) RedisSet(ctx context.Context, key string, data interface{}, ttl time.Duration) error {
 	ret := _m.Called(ctx, key, data, ttl)
@@ -298,36 +303,6 @@ func (_m *UserRepository) RedisSet(ctx context.Context, key string, data interfa
 	return r0
 }
 
-// RedisWp provides a mock function with given fields: ctx, fn
-func (_m *UserRepository) RedisWp(ctx context.Context, fn func(context.Context) (interface{}, error)) (interface{}, error) {
-	ret := _m.Called(ctx, fn)
-
-	if len(ret) == 0 {
-		panic("no return value specified for RedisWp")
-	}
-
-	var r0 interface{}
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, func(context.Context) (interface{}, error)) (interface{}, error)); ok {
-		return rf(ctx, fn)
-	}
-	if rf, ok := ret.Get(0).(func(context.Context, func(context.Context) (interface{}, error)) interface{}); ok {
-		r0 = rf(ctx, fn)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(interface{})
-		}
-	}
-
-	if rf, ok := ret.Get(1).(func(context.Context, func(context.Context) (interface{}, error)) error); ok {
-		r1 = rf(ctx, fn)
-	} else {
-		r1 = ret.Error(1)
-	}
-
-	return r0, r1
-}
-
 // RedisWtx provides a mock function with given fields: ctx, fn
 func (_m *UserRepository) RedisWtx(ctx context.Context, fn func(context.Context) error) error {
 	ret := _m.Called(ctx, fn)
@@ -346,54 +321,6 @@ func (_m *UserRepository) RedisWtx(ctx context.Context, fn func(context.Context)
 	return r0
 }
 
-// RedisZR provides a mock function with given fields: ctx, key, start, stop
-func (_m *UserRepository) RedisZR(ctx context.Context, key string, start int, stop int) ([]string, error) {
-	ret := _m.Called(ctx, key, start, stop)
-
-	if len(ret) == 0 {
-		panic("no return value specified for RedisZR")
-	}
-
-	var r0 []string
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, int, int) ([]string, error)); ok {
-		return rf(ctx, key, start, stop)
-	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, int, int) []string); ok {
-		r0 = rf(ctx, key, start, stop)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]string)
-		}
-	}
-
-	if rf, ok := ret.Get(1).(func(context.Context, string, int, int) error); ok {
-		r1 = rf(ctx, key, start, stop)
-	} else {
-		r1 = ret.Error(1)
-	}
-
-	return r0, r1
-}
-
-// RedisZS provides a mock function with given fields: ctx, key, score, member
-func (_m *UserRepository) RedisZS(ctx context.Context, key string, score float64, member interface{}) error {
-	ret := _m.Called(ctx, key, score, member)
-
-	if len(ret) == 0 {
-		panic("no return value specified for RedisZS")
-	}
-
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, float64, interface{}) error); ok {
-		r0 = rf(ctx, key, score, member)
-	} else {
-		r0 = ret.Error(0)
-	}
-
-	return r0
-}
-
 // Register provides a mock function with given fields: ctx, data
 func (_m *UserRepository) Register(ctx context.Context, data *entity.Students) error {
 	ret := _m.Called(ctx, data)
@@ -412,17 +339,17 @@ func (_m *UserRepository) Register(ctx context.Context, data *entity.Students) e
 	return r0
 }
 
-// UpdateBookStock provides a mock function with given fields: ctx, idBook
-func (_m *UserRepository) UpdateBookStock(ctx context.Context, idBook int) error {
-	ret := _m.Called(ctx, idBook)
+// UpdateBookStock provides a mock function with given fields: ctx, idBook, idUser, now
+func (_m *UserRepository) UpdateBookStock(ctx context.Context, idBook int, idUser int, now time.Time) error {
+	ret := _m.Called(ctx, idBook, idUser, now)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateBookStock")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
-		r0 = rf(ctx, idBook)
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, time.Time) error); ok {
+		r0 = rf(ctx, idBook, idUser, now)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -0,0 +1,304 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entity "stmnplibrary/domain/entity"
+
+	mock "github.com/stretchr/testify/mock"
+
+	pagination "stmnplibrary/pagination"
+
+	time "time"
+)
+
+// WebhookRepository is an autogenerated mock type for the WebhookRepository type
+type WebhookRepository struct {
+	mock.Mock
+}
+
+// AckEvents provides a mock function with given fields: ctx, stream, group, ids
+func (_m *WebhookRepository) AckEvents(ctx context.Context, stream string, group string, ids []string) error {
+	ret := _m.Called(ctx, stream, group, ids)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AckEvents")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, []string) error); ok {
+		r0 = rf(ctx, stream, group, ids)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AddDeliveries provides a mock function with given fields: ctx, data
+func (_m *WebhookRepository) AddDeliveries(ctx context.Context, data []entity.WebhookDelivery) error {
+	ret := _m.Called(ctx, data)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddDeliveries")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []entity.WebhookDelivery) error); ok {
+		r0 = rf(ctx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AddSubscription provides a mock function with given fields: ctx, data
+func (_m *WebhookRepository) AddSubscription(ctx context.Context, data *entity.WebhookSubscription) error {
+	ret := _m.Called(ctx, data)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddSubscription")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.WebhookSubscription) error); ok {
+		r0 = rf(ctx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ClaimDeliveries provides a mock function with given fields: ctx, limit, lease
+func (_m *WebhookRepository) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]entity.DueDelivery, error) {
+	ret := _m.Called(ctx, limit, lease)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimDeliveries")
+	}
+
+	var r0 []entity.DueDelivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Duration) ([]entity.DueDelivery, error)); ok {
+		return rf(ctx, limit, lease)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Duration) []entity.DueDelivery); ok {
+		r0 = rf(ctx, limit, lease)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.DueDelivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, time.Duration) error); ok {
+		r1 = rf(ctx, limit, lease)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateGroup provides a mock function with given fields: ctx, stream, group
+func (_m *WebhookRepository) CreateGroup(ctx context.Context, stream string, group string) error {
+	ret := _m.Called(ctx, stream, group)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateGroup")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, stream, group)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeactivateSubscription provides a mock function with given fields: ctx, id
+func (_m *WebhookRepository) DeactivateSubscription(ctx context.Context, id int) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeactivateSubscription")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetDeliveries provides a mock function with given fields: ctx, subscriptionID, status, page
+func (_m *WebhookRepository) GetDeliveries(ctx context.Context, subscriptionID int, status string, page pagination.Page) ([]entity.WebhookDelivery, int64, error) {
+	ret := _m.Called(ctx, subscriptionID, status, page)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDeliveries")
+	}
+
+	var r0 []entity.WebhookDelivery
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, pagination.Page) ([]entity.WebhookDelivery, int64, error)); ok {
+		return rf(ctx, subscriptionID, status, page)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, pagination.Page) []entity.WebhookDelivery); ok {
+		r0 = rf(ctx, subscriptionID, status, page)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.WebhookDelivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, string, pagination.Page) int64); ok {
+		r1 = rf(ctx, subscriptionID, status, page)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int, string, pagination.Page) error); ok {
+		r2 = rf(ctx, subscriptionID, status, page)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetSubscriptions provides a mock function with given fields: ctx, activeOnly
+func (_m *WebhookRepository) GetSubscriptions(ctx context.Context, activeOnly bool) ([]entity.WebhookSubscription, error) {
+	ret := _m.Called(ctx, activeOnly)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSubscriptions")
+	}
+
+	var r0 []entity.WebhookSubscription
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, bool) ([]entity.WebhookSubscription, error)); ok {
+		return rf(ctx, activeOnly)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, bool) []entity.WebhookSubscription); ok {
+		r0 = rf(ctx, activeOnly)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.WebhookSubscription)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, bool) error); ok {
+		r1 = rf(ctx, activeOnly)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReadEvents provides a mock function with given fields: ctx, stream, group, consumer, count, minIdle
+func (_m *WebhookRepository) ReadEvents(ctx context.Context, stream string, group string, consumer string, count int, minIdle time.Duration) ([]entity.StreamEvent, error) {
+	ret := _m.Called(ctx, stream, group, consumer, count, minIdle)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReadEvents")
+	}
+
+	var r0 []entity.StreamEvent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, int, time.Duration) ([]entity.StreamEvent, error)); ok {
+		return rf(ctx, stream, group, consumer, count, minIdle)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, int, time.Duration) []entity.StreamEvent); ok {
+		r0 = rf(ctx, stream, group, consumer, count, minIdle)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.StreamEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, int, time.Duration) error); ok {
+		r1 = rf(ctx, stream, group, consumer, count, minIdle)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ResetDelivery provides a mock function with given fields: ctx, id
+func (_m *WebhookRepository) ResetDelivery(ctx context.Context, id int) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResetDelivery")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveAttempt provides a mock function with given fields: ctx, data
+func (_m *WebhookRepository) SaveAttempt(ctx context.Context, data entity.WebhookDelivery) error {
+	ret := _m.Called(ctx, data)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveAttempt")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.WebhookDelivery) error); ok {
+		r0 = rf(ctx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// WithTx provides a mock function with given fields: ctx, fn
+func (_m *WebhookRepository) WithTx(ctx context.Context, fn func(context.Context) error) error {
+	ret := _m.Called(ctx, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithTx")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, func(context.Context) error) error); ok {
+		r0 = rf(ctx, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewWebhookRepository creates a new instance of WebhookRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewWebhookRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *WebhookRepository {
+	mock := &WebhookRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,27 @@
+package outbox
+
+import (
+	"stmnplibrary/config"
+	"stmnplibrary/domain/interface/service"
+	"stmnplibrary/worker"
+)
+
+const (
+	EventLoanCreated        = "LoanCreated"
+	EventLoanReturned       = "LoanReturned"
+	EventBookAdded          = "BookAdded"
+	EventSanctionIssued     = "SanctionIssued"
+	EventSuggestionReceived = "SuggestionReceived"
+)
+
+// Relay runs the outbox service every interval until it is stopped.
+type Relay struct {
+	*worker.Worker
+}
+
+// FnRelay starts the relay, the returned cleanup stops it and waits for the
+// batch in progress.
+func FnRelay(service service.OutboxService, cfg config.Outbox) (*Relay, func()) {
+	w := worker.Start("outbox relay", cfg.Interval, cfg.BatchSize, service.Relay)
+	return &Relay{Worker: w}, w.Stop
+}
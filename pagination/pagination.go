@@ -0,0 +1,104 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+
+	"stmnplibrary/apperr"
+)
+
+// Sort orders of the listings, books and suggestions can be sorted by the
+// client, loans, audits and reviews are always newest first and students
+// follow their nis.
+const (
+	SortTitle        = "title"
+	SortAuthor       = "author"
+	SortNewest       = "newest"
+	SortAvailability = "availability"
+	SortRating       = "rating"
+	SortVotes        = "votes"
+	SortNIS          = "nis"
+)
+
+const (
+	DefaultSize = 35
+	MaxSize     = 100
+)
+
+var ErrInvalidCursor = apperr.New(apperr.KindInvalid, apperr.CodeInvalidCursor, "invalid cursor")
+
+// Cursor points right after the last row of a page: the value of the sort
+// column and the id breaking ties. Clients only see it as an opaque token.
+type Cursor struct {
+	Sort string `json:"s"`
+	Key  string `json:"k"`
+	ID   int    `json:"i"`
+}
+
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// Decode reads a token issued by Encode, an empty token is the first page.
+func Decode(token string) (*Cursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, apperr.Wrap(err, apperr.KindInvalid, apperr.CodeInvalidCursor, "invalid cursor")
+	}
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.Sort == "" {
+		return nil, apperr.Wrap(err, apperr.KindInvalid, apperr.CodeInvalidCursor, "invalid cursor")
+	}
+	return &c, nil
+}
+
+// Page is one keyset page, After is nil for the first page.
+type Page struct {
+	Sort  string
+	Size  int
+	After *Cursor
+}
+
+// New builds the page requested by the client. The size is clamped to
+// MaxSize and a cursor only continues the sort it was issued for.
+func New(token string, size int, sort string) (Page, error) {
+	if size <= 0 {
+		size = DefaultSize
+	}
+	if size > MaxSize {
+		size = MaxSize
+	}
+	after, err := Decode(token)
+	if err != nil {
+		return Page{}, err
+	}
+	if after != nil && after.Sort != sort {
+		return Page{}, ErrInvalidCursor
+	}
+	return Page{Sort: sort, Size: size, After: after}, nil
+}
+
+// Key identifies the page inside cache keys.
+func (p Page) Key() string {
+	var after = "first"
+	if p.After != nil {
+		after = p.After.Encode()
+	}
+	return p.Sort + ":" + strconv.Itoa(p.Size) + ":" + after
+}
+
+// Trim drops the extra row fetched to detect a next page and returns the
+// cursor of the last row kept, nil when this is the last page.
+func Trim[T any](rows []T, page Page, key func(T) (string, int)) ([]T, *Cursor) {
+	if len(rows) <= page.Size {
+		return rows, nil
+	}
+	rows = rows[:page.Size]
+	k, id := key(rows[len(rows)-1])
+	return rows, &Cursor{Sort: page.Sort, Key: k, ID: id}
+}
@@ -0,0 +1,73 @@
+package pagination
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"stmnplibrary/apperr"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursor_RoundTrip(t *testing.T) {
+	c := Cursor{Sort: SortTitle, Key: "Laskar Pelangi", ID: 42}
+	decoded, err := Decode(c.Encode())
+	assert.NoError(t, err)
+	assert.Equal(t, &c, decoded)
+
+	first, err := Decode("")
+	assert.NoError(t, err)
+	assert.Nil(t, first)
+}
+
+func TestNew_Cases(t *testing.T) {
+	titleCursor := Cursor{Sort: SortTitle, Key: "A", ID: 1}.Encode()
+	tests := []struct {
+		name     string
+		token    string
+		size     int
+		sort     string
+		wantSize int
+		wantErr  bool
+	}{
+		{"Default_Size", "", 0, SortTitle, DefaultSize, false},
+		{"Clamped_Size", "", 1000, SortTitle, MaxSize, false},
+		{"Continue_Same_Sort", titleCursor, 10, SortTitle, 10, false},
+		{"Sort_Mismatch", titleCursor, 10, SortNewest, 0, true},
+		{"Garbage_Token", "not a cursor!", 10, SortTitle, 0, true},
+		{"Empty_Json", "e30", 10, SortTitle, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := New(tt.token, tt.size, tt.sort)
+			if tt.wantErr {
+				assert.True(t, errors.Is(err, ErrInvalidCursor))
+				assert.Equal(t, apperr.KindInvalid, apperr.KindOf(err))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantSize, page.Size)
+		})
+	}
+}
+
+func TestPage_Key(t *testing.T) {
+	first := Page{Sort: SortNewest, Size: 35}
+	next := Page{Sort: SortNewest, Size: 35, After: &Cursor{Sort: SortNewest, Key: "2024-01-01T00:00:00Z", ID: 7}}
+	assert.Equal(t, "newest:35:first", first.Key())
+	assert.NotEqual(t, first.Key(), next.Key())
+}
+
+func TestTrim(t *testing.T) {
+	key := func(i int) (string, int) { return strconv.Itoa(i * 10), i }
+	page := Page{Sort: SortNIS, Size: 2}
+
+	rows, next := Trim([]int{1, 2, 3}, page, key)
+	assert.Equal(t, []int{1, 2}, rows)
+	assert.Equal(t, &Cursor{Sort: SortNIS, Key: "20", ID: 2}, next)
+
+	rows, next = Trim([]int{1, 2}, page, key)
+	assert.Equal(t, []int{1, 2}, rows)
+	assert.Nil(t, next)
+}
@@ -0,0 +1,19 @@
+package recommendation
+
+import (
+	"stmnplibrary/config"
+	"stmnplibrary/domain/interface/service"
+	"stmnplibrary/worker"
+)
+
+// Job computes the recommendations of every student every interval.
+type Job struct {
+	*worker.Worker
+}
+
+// FnJob starts the job, the returned cleanup stops it and waits for the
+// batch in progress.
+func FnJob(service service.RecommendationService, cfg config.Recommendation) (*Job, func()) {
+	w := worker.Start("recommendations", cfg.Interval, cfg.BatchSize, service.Refresh)
+	return &Job{Worker: w}, w.Stop
+}
@@ -2,6 +2,7 @@ package security
 
 import (
 	"fmt"
+	"stmnplibrary/apperr"
 
 	"golang.org/x/crypto/bcrypt"
 )
@@ -9,14 +10,14 @@ import (
 func HashPassword(pass string) (string, error) {
 	hashPass, err := bcrypt.GenerateFromPassword([]byte(pass), 9)
 	if err != nil {
-		return "", fmt.Errorf("internal server error: failed hash password: %w", err)
+		return "", apperr.Internal(fmt.Errorf("failed hash password: %w", err))
 	}
 	return string(hashPass), nil
 }
 
 func UnHashPassword(pass string, hashPass string) error {
 	if err := bcrypt.CompareHashAndPassword([]byte(hashPass), []byte(pass)); err != nil {
-		return fmt.Errorf("invalid password")
+		return apperr.Wrap(err, apperr.KindUnauthorized, apperr.CodeInvalidCredentials, "invalid password")
 	}
 	return nil
 }
@@ -1,16 +1,25 @@
 package token
 
 import (
+	"stmnplibrary/apperr"
+	"stmnplibrary/config"
 	"stmnplibrary/security/jwt/claims"
 
 	"fmt"
-	"os"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
-func generateToken(userId int, ttl time.Duration, role string) (string, error) {
+type JWT struct {
+	cfg config.JWT
+}
+
+func FnJWT(cfg config.JWT) *JWT {
+	return &JWT{cfg: cfg}
+}
+
+func (j *JWT) generateToken(userId int, ttl time.Duration, role string) (string, error) {
 	data := &claims.JWTClaims{
 		UserId: userId,
 		Role:   role,
@@ -19,21 +28,19 @@ func generateToken(userId int, ttl time.Duration, role string) (string, error) {
 		},
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, data)
-	tokenStr, err := token.SignedString([]byte(os.Getenv("SecretKey")))
+	tokenStr, err := token.SignedString([]byte(j.cfg.SecretKey))
 	if err != nil {
-		return "", fmt.Errorf("internal server error: failed signed token: %w", err)
+		return "", apperr.Internal(fmt.Errorf("failed signed token: %w", err))
 	}
 	return tokenStr, nil
 }
 
-func GenerateToken(userId int, role string) (*claims.Token, error) {
-	const ttlAccToken = 3 * time.Minute
-	const ttlRefToken = 5 * 24 * time.Hour
-	accToken, err := generateToken(userId, ttlAccToken, role)
+func (j *JWT) GenerateToken(userId int, role string) (*claims.Token, error) {
+	accToken, err := j.generateToken(userId, j.cfg.AccessTTL, role)
 	if err != nil {
 		return nil, err
 	}
-	refToken, err := generateToken(userId, ttlRefToken, role)
+	refToken, err := j.generateToken(userId, j.cfg.RefreshTTL, role)
 	if err != nil {
 		return nil, err
 	}
@@ -44,17 +51,21 @@ func GenerateToken(userId int, role string) (*claims.Token, error) {
 	return token, nil
 }
 
-func ValidateToken(tokenStr string) (*claims.JWTClaims, error) {
+func (j *JWT) ValidateToken(tokenStr string) (*claims.JWTClaims, error) {
 	data := &claims.JWTClaims{}
 	token, err := jwt.ParseWithClaims(tokenStr, data,
 		func(token *jwt.Token) (interface{}, error) {
-			return []byte(os.Getenv("SecretKey")), nil
+			return []byte(j.cfg.SecretKey), nil
 		})
 	if err != nil {
-		return &claims.JWTClaims{}, fmt.Errorf("internal server error: failed parse token: %w", err)
+		return &claims.JWTClaims{}, apperr.Wrap(err, apperr.KindUnauthorized, apperr.CodeInvalidToken, "invalid token")
 	}
 	if !token.Valid {
-		return &claims.JWTClaims{}, fmt.Errorf("invalid token")
+		return &claims.JWTClaims{}, apperr.New(apperr.KindUnauthorized, apperr.CodeInvalidToken, "invalid token")
 	}
 	return data, nil
 }
+
+func (j *JWT) RefreshTTL() time.Duration {
+	return j.cfg.RefreshTTL
+}
@@ -0,0 +1,55 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"stmnplibrary/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestInit_ContinuesTraceparent(t *testing.T) {
+	stop, err := Init(context.Background(), config.Tracing{Exporter: "none", ServiceName: "test", SampleRatio: 1})
+	require.NoError(t, err)
+	defer stop()
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+
+	ctx := otel.GetTextMapPropagator().Extract(context.Background(), propagation.HeaderCarrier(req.Header))
+	ctx, span := Start(ctx, "child")
+	defer span.End()
+
+	assert.Equal(t, traceID, TraceID(ctx))
+}
+
+func TestEnd_RecordsError(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	_, span := Start(context.Background(), "ok")
+	End(span, nil)
+	_, span = Start(context.Background(), "failed")
+	End(span, errors.New("no data found"))
+
+	ended := recorder.Ended()
+	require.Len(t, ended, 2)
+	assert.Equal(t, codes.Unset, ended[0].Status().Code)
+	assert.Equal(t, codes.Error, ended[1].Status().Code)
+	assert.Equal(t, "no data found", ended[1].Status().Description)
+}
+
+func TestTraceID_Empty(t *testing.T) {
+	assert.Empty(t, TraceID(context.Background()))
+}
@@ -0,0 +1,62 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"stmnplibrary/config"
+	"stmnplibrary/domain/interface/service"
+	"stmnplibrary/worker"
+)
+
+const (
+	StatusPending   = "pending"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+	StatusDead      = "dead"
+)
+
+const (
+	HeaderID        = "X-Webhook-ID"
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// Sign returns the signature header of a delivery, an HMAC-SHA256 of the
+// unix timestamp and the body joined by a dot. Signing the timestamp lets a
+// receiver reject replayed requests.
+func Sign(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature was made by Sign with the same secret.
+func Verify(secret string, timestamp string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
+
+// Dispatcher turns outbox events into deliveries and sends the due ones,
+// each on its own worker.
+type Dispatcher struct {
+	consumer *worker.Worker
+	sender   *worker.Worker
+}
+
+// FnDispatcher starts the dispatcher, the returned cleanup stops both
+// workers and waits for the batches in progress.
+func FnDispatcher(service service.WebhookService, cfg config.Webhook) (*Dispatcher, func()) {
+	d := &Dispatcher{
+		consumer: worker.Start("webhook consumer", cfg.Interval, cfg.BatchSize, service.Consume),
+		sender:   worker.Start("webhook dispatcher", cfg.Interval, cfg.BatchSize, service.Dispatch),
+	}
+	return d, d.Stop
+}
+
+func (d *Dispatcher) Stop() {
+	d.consumer.Stop()
+	d.sender.Stop()
+}
@@ -0,0 +1,19 @@
+package webhook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"id":1}`)
+	signature := Sign("secret", "1700000000", body)
+
+	assert.Equal(t, "sha256=", signature[:7])
+	assert.Len(t, signature, 7+64)
+	assert.True(t, Verify("secret", "1700000000", body, signature))
+	assert.False(t, Verify("other", "1700000000", body, signature))
+	assert.False(t, Verify("secret", "1700000001", body, signature))
+	assert.False(t, Verify("secret", "1700000000", []byte(`{"id":2}`), signature))
+}
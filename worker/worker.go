@@ -0,0 +1,70 @@
+package worker
+
+import (
+	"context"
+	"stmnplibrary/log"
+	"time"
+)
+
+// Worker calls fn every interval until it is stopped, a full batch is
+// followed right away by the next one to drain a backlog.
+type Worker struct {
+	name      string
+	fn        func(ctx context.Context) (int, error)
+	interval  time.Duration
+	batchSize int
+	cancel    context.CancelFunc
+	done      chan struct{}
+}
+
+func Start(name string, interval time.Duration, batchSize int, fn func(ctx context.Context) (int, error)) *Worker {
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &Worker{
+		name:      name,
+		fn:        fn,
+		interval:  interval,
+		batchSize: batchSize,
+		cancel:    cancel,
+		done:      make(chan struct{}),
+	}
+	go w.run(ctx)
+	return w
+}
+
+func (w *Worker) run(ctx context.Context) {
+	defer close(w.done)
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		for ctx.Err() == nil {
+			n, err := w.fn(ctx)
+			if err != nil && ctx.Err() == nil {
+				log.LogWorker(w.name, "failed run "+w.name, err)
+			}
+			if err != nil || n < w.batchSize {
+				break
+			}
+		}
+	}
+}
+
+// Stop cancels the worker and waits for the batch in progress.
+func (w *Worker) Stop() {
+	w.cancel()
+	<-w.done
+}
+
+// Backoff is the delay after the given number of failed attempts, base
+// doubled on every failure up to max.
+func Backoff(base time.Duration, max time.Duration, attempts int) time.Duration {
+	var delay = base
+	for i := 1; i < attempts && delay < max; i++ {
+		delay *= 2
+	}
+	return min(delay, max)
+}
@@ -0,0 +1,33 @@
+package worker
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, time.Second, Backoff(time.Second, time.Minute, 1))
+	assert.Equal(t, 4*time.Second, Backoff(time.Second, time.Minute, 3))
+	assert.Equal(t, time.Minute, Backoff(time.Second, time.Minute, 20))
+}
+
+func TestWorker_Drains_Full_Batches(t *testing.T) {
+	var calls atomic.Int32
+	w := Start("test", time.Millisecond, 10, func(ctx context.Context) (int, error) {
+		// two full batches then an empty one
+		if calls.Add(1) <= 2 {
+			return 10, nil
+		}
+		return 0, nil
+	})
+	assert.Eventually(t, func() bool { return calls.Load() >= 3 }, time.Second, time.Millisecond)
+	w.Stop()
+
+	stopped := calls.Load()
+	time.Sleep(5 * time.Millisecond)
+	assert.Equal(t, stopped, calls.Load())
+}